@@ -28,6 +28,10 @@ echo "Method 3: Build Go components directly with macOS tags:"
 echo "  cd src"
 echo "  GOOS=darwin GOARCH=arm64 go build -tags darwin -o ../toolbox-darwin-arm64 ."
 echo "  GOOS=darwin GOARCH=amd64 go build -tags darwin -o ../toolbox-darwin-amd64 ."
+echo "  cd .. && shasum -a 256 toolbox-darwin-amd64 toolbox-darwin-arm64 > SHA256SUMS"
+echo ""
+echo "Publish SHA256SUMS with the binaries, because 'toolbox self-update' refuses"
+echo "binaries without a matching checksum."
 echo ""
 
 # Detect if we're on Linux and suggest Method 2
@@ -48,6 +48,18 @@ fi
 
 if [ -f /run/.containerenv ] \
    && [ -f /run/.toolboxenv ]; then
+    if [ "${TOOLBOX_NAME:-}" = "" ]; then
+        TOOLBOX_NAME=$(sed -n 's/^name="\(.*\)"$/\1/p' /run/.containerenv 2>/dev/null)
+    fi
+
+    # Prompt themes like starship and powerlevel10k can show TOOLBOX_NAME, or
+    # call toolbox_prompt_info from a custom segment.
+    export TOOLBOX_NAME
+
+    toolbox_prompt_info() {
+        [ "$TOOLBOX_NAME" != "" ] && printf "⬢ %s" "$TOOLBOX_NAME"
+    }
+
     [ "${BASH_VERSION:-}" != "" ] && PS1=$(printf "\[\033[35m\]⬢ \[\033[0m\]%s" "[\u@\h \W]\\$ ")
     [ "${ZSH_VERSION:-}" != "" ] && PS1=$(printf "\033[35m⬢ \033[0m%s" "[%n@%m]%~%# ")
 
@@ -83,6 +95,28 @@ if [ -f /run/.containerenv ] \
         esac
     fi
 
+    # On macOS, 'toolbox enter' sets the terminal title to TOOLBOX_TITLE, but
+    # the shell's own prompt often replaces it with user@host, so set it
+    # again before every prompt.
+    if [ "${TOOLBOX_TITLE:-}" != "" ]; then
+        toolbox_set_title() {
+            printf "\033]0;%s\007" "$TOOLBOX_TITLE"
+        }
+
+        if [ "${BASH_VERSION:-}" != "" ]; then
+            case "$PROMPT_COMMAND" in
+                *toolbox_set_title*)
+                    ;;
+                *)
+                    PROMPT_COMMAND="${PROMPT_COMMAND:+$PROMPT_COMMAND;}toolbox_set_title"
+                    ;;
+            esac
+        fi
+
+        # shellcheck disable=SC2016
+        [ "${ZSH_VERSION:-}" != "" ] && eval 'precmd_functions=(${precmd_functions:#toolbox_set_title} toolbox_set_title)'
+    fi
+
     if [ "$TERM" != "" ]; then
         error_message="Error: terminfo entry not found for $TERM"
         term_without_first_character="${TERM#?}"
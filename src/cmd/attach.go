@@ -0,0 +1,426 @@
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/containers/toolbox/pkg/podman"
+	"github.com/containers/toolbox/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// detachedSession is a command started with 'toolbox run --detach'.  Its
+// directory is inside the home directory, which the container shares, so that
+// the command writes its output and exit status there, and they outlive both
+// the terminal that started it and the container.
+type detachedSession struct {
+	command   string
+	container string
+	directory string
+	exitCode  int
+	finished  bool
+	name      string
+	started   time.Time
+}
+
+const (
+	detachedSessionNameRegexp = "^[a-zA-Z0-9][a-zA-Z0-9_.-]*$"
+
+	// detachedSessionScript runs the command in the background with its
+	// output going to the session's log, and ignoring the SIGHUP from the
+	// terminal going away, so that 'podman exec' can return right away.
+	detachedSessionScript = `directory="$1"
+shift
+(
+	trap '' HUP
+	"$@" >"$directory/log" 2>&1 </dev/null
+	echo $? >"$directory/exit-code"
+) &
+`
+)
+
+var attachCmd = &cobra.Command{
+	Use:               "attach",
+	Short:             "Follow a command started with 'toolbox run --detach'",
+	RunE:              attach,
+	ValidArgsFunction: completionDetachedSessionNames,
+}
+
+func init() {
+	attachCmd.SetHelpFunc(attachHelp)
+	rootCmd.AddCommand(attachCmd)
+}
+
+func attach(cmd *cobra.Command, args []string) error {
+	if utils.IsInsideContainer() {
+		if !utils.IsInsideToolboxContainer() {
+			return errors.New("this is not a Toolbx container")
+		}
+
+		exitCode, err := utils.ForwardToHost()
+		return &exitError{exitCode, err}
+	}
+
+	if len(args) > 1 {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "attach needs at most one session\n")
+		fmt.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	sessions, err := getDetachedSessions()
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 0 {
+		if len(sessions) == 0 {
+			showMessage("No detached sessions")
+			return nil
+		}
+
+		showDetachedSessions(sessions)
+		return nil
+	}
+
+	name := args[0]
+
+	for _, session := range sessions {
+		if session.name == name {
+			err := followDetachedSession(session)
+			return err
+		}
+	}
+
+	return fmt.Errorf("session %s not found", name)
+}
+
+func attachHelp(cmd *cobra.Command, args []string) {
+	if utils.IsInsideContainer() {
+		if !utils.IsInsideToolboxContainer() {
+			fmt.Fprintf(os.Stderr, "Error: this is not a Toolbx container\n")
+			return
+		}
+
+		if _, err := utils.ForwardToHost(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return
+		}
+
+		return
+	}
+
+	if err := showManual("toolbox-attach"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return
+	}
+}
+
+func completionDetachedSessionNames(_ *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+	if len(args) != 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var names []string
+	if sessions, err := getDetachedSessions(); err == nil {
+		for _, session := range sessions {
+			names = append(names, session.name)
+		}
+	}
+
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+// createDetachedSession sets up the directory of a new session for
+// container.  Without a name, one is made up from nameHint, eg., the command.
+func createDetachedSession(name, nameHint, container string) (detachedSession, error) {
+	if name == "" {
+		suffix := make([]byte, 2)
+		if _, err := rand.Read(suffix); err != nil {
+			return detachedSession{}, fmt.Errorf("failed to generate a name for the session: %w", err)
+		}
+
+		name = filepath.Base(nameHint) + "-" + hex.EncodeToString(suffix)
+	}
+
+	if matched, _ := regexp.MatchString(detachedSessionNameRegexp, name); !matched {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "invalid argument for '--name': %s\n", name)
+		fmt.Fprintf(&builder, "Session names must match '%s'.\n", detachedSessionNameRegexp)
+		fmt.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return detachedSession{}, errors.New(errMsg)
+	}
+
+	sessionsDirectory, err := getDetachedSessionsDirectory()
+	if err != nil {
+		return detachedSession{}, err
+	}
+
+	if err := os.MkdirAll(sessionsDirectory, 0700); err != nil {
+		return detachedSession{}, fmt.Errorf("failed to create directory %s: %w", sessionsDirectory, err)
+	}
+
+	directory := filepath.Join(sessionsDirectory, name)
+	if err := os.Mkdir(directory, 0700); err != nil {
+		if errors.Is(err, os.ErrExist) {
+			return detachedSession{}, fmt.Errorf("session %s already exists", name)
+		}
+
+		return detachedSession{}, fmt.Errorf("failed to create directory %s: %w", directory, err)
+	}
+
+	session := detachedSession{
+		container: container,
+		directory: directory,
+		name:      name,
+	}
+
+	return session, nil
+}
+
+// followDetachedSession shows the output of a session as it comes, until the
+// command exits, and then removes the session and exits the same way.
+// Interrupting it leaves the command running.
+func followDetachedSession(session detachedSession) error {
+	log, err := os.Open(filepath.Join(session.directory, "log"))
+	if err != nil {
+		return fmt.Errorf("failed to read the output of session %s: %w", session.name, err)
+	}
+
+	defer log.Close()
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt)
+	defer signal.Stop(signals)
+
+	ticker := time.NewTicker(250 * time.Millisecond)
+	defer ticker.Stop()
+
+	lastChecked := time.Now()
+
+	for {
+		if _, err := io.Copy(os.Stdout, log); err != nil {
+			return fmt.Errorf("failed to read the output of session %s: %w", session.name, err)
+		}
+
+		if session.finished {
+			break
+		}
+
+		select {
+		case <-signals:
+			fmt.Fprintf(os.Stderr, "\n")
+			showMessage("Detached from session %s, which is still running", session.name)
+			return nil
+		case <-ticker.C:
+		}
+
+		exitCode, finished := readDetachedSessionExitCode(session.directory)
+		if finished {
+			session.exitCode = exitCode
+			session.finished = true
+			continue
+		}
+
+		// The command can't write its exit status if the container
+		// was stopped under it.
+		if time.Since(lastChecked) > 5*time.Second {
+			lastChecked = time.Now()
+
+			if !isDetachedSessionContainerRunning(session) {
+				return fmt.Errorf("container %s stopped before session %s finished", session.container, session.name)
+			}
+		}
+	}
+
+	if err := os.RemoveAll(session.directory); err != nil {
+		logrus.Debugf("Removing %s failed: %s", session.directory, err)
+	}
+
+	if session.exitCode != 0 {
+		return &exitError{session.exitCode, nil}
+	}
+
+	return nil
+}
+
+// getDetachedSessionCommand records command in the directory of the session,
+// and returns the command that starts it there through runCommand.
+func getDetachedSessionCommand(session detachedSession, command []string) ([]string, error) {
+	files := map[string]string{
+		"command":   strings.Join(command, " "),
+		"container": session.container,
+		"log":       "",
+	}
+
+	for file, content := range files {
+		path := filepath.Join(session.directory, file)
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", path, err)
+		}
+	}
+
+	sessionCommand := []string{"/bin/sh", "-c", detachedSessionScript, "sh", session.directory}
+	sessionCommand = append(sessionCommand, command...)
+	return sessionCommand, nil
+}
+
+func getDetachedSessions() ([]detachedSession, error) {
+	sessionsDirectory, err := getDetachedSessionsDirectory()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(sessionsDirectory)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("failed to read directory %s: %w", sessionsDirectory, err)
+	}
+
+	var sessions []detachedSession
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		directory := filepath.Join(sessionsDirectory, entry.Name())
+
+		command, err := os.ReadFile(filepath.Join(directory, "command"))
+		if err != nil {
+			logrus.Debugf("Reading the command of session %s failed: %s", entry.Name(), err)
+			continue
+		}
+
+		container, err := os.ReadFile(filepath.Join(directory, "container"))
+		if err != nil {
+			logrus.Debugf("Reading the container of session %s failed: %s", entry.Name(), err)
+			continue
+		}
+
+		var started time.Time
+		if fileInfo, err := os.Stat(filepath.Join(directory, "command")); err == nil {
+			started = fileInfo.ModTime()
+		}
+
+		exitCode, finished := readDetachedSessionExitCode(directory)
+
+		session := detachedSession{
+			command:   string(command),
+			container: string(container),
+			directory: directory,
+			exitCode:  exitCode,
+			finished:  finished,
+			name:      entry.Name(),
+			started:   started,
+		}
+
+		sessions = append(sessions, session)
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].started.Before(sessions[j].started)
+	})
+
+	return sessions, nil
+}
+
+// getDetachedSessionsDirectory returns the directory with the sessions, which
+// is in the cache directory inside the home directory, like the scripts from
+// 'toolbox run --file'.
+func getDetachedSessionsDirectory() (string, error) {
+	cacheDirectory, err := os.UserCacheDir()
+	if err != nil {
+		logrus.Debugf("Getting the cache directory failed: %s", err)
+		return "", errors.New("failed to get the cache directory")
+	}
+
+	sessionsDirectory := filepath.Join(cacheDirectory, "toolbox", "detached")
+	return sessionsDirectory, nil
+}
+
+func isDetachedSessionContainerRunning(session detachedSession) bool {
+	containerObj, err := podman.InspectContainer(session.container)
+	if err != nil {
+		logrus.Debugf("Inspecting container %s failed: %s", session.container, err)
+		return false
+	}
+
+	return containerObj.EntryPointPID() > 0
+}
+
+func readDetachedSessionExitCode(directory string) (int, bool) {
+	data, err := os.ReadFile(filepath.Join(directory, "exit-code"))
+	if err != nil {
+		return 0, false
+	}
+
+	exitCode, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		// It's being written.
+		return 0, false
+	}
+
+	return exitCode, true
+}
+
+func showDetachedSessions(sessions []detachedSession) {
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\n", "NAME", "CONTAINER", "STARTED", "STATUS", "COMMAND")
+
+	for _, session := range sessions {
+		status := "running"
+		if session.finished {
+			status = fmt.Sprintf("exited (%d)", session.exitCode)
+		} else if !isDetachedSessionContainerRunning(session) {
+			status = "stopped"
+		}
+
+		started := utils.HumanDuration(session.started.Unix())
+
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\n",
+			session.name,
+			session.container,
+			started,
+			status,
+			session.command)
+	}
+
+	writer.Flush()
+}
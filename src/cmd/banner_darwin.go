@@ -0,0 +1,193 @@
+//go:build darwin
+
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/acobaugh/osrelease"
+	"github.com/containers/toolbox/pkg/podman"
+	"github.com/containers/toolbox/pkg/shell"
+	"github.com/containers/toolbox/pkg/term"
+	"github.com/containers/toolbox/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+// enterBannerFile is where 'toolbox init-container' leaves the part of the
+// banner that is only known inside the container.
+const enterBannerFile = "/run/toolbox/banner"
+
+// getEnterBannerMounts returns the mount points inside the container that
+// come from the Mac or from volumes, leaving out those that every container
+// has.
+func getEnterBannerMounts(mountInfo []byte) []string {
+	var mounts []string
+	seen := make(map[string]struct{})
+
+	scanner := bufio.NewScanner(bytes.NewReader(mountInfo))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 5 {
+			continue
+		}
+
+		mountPoint := strings.ReplaceAll(fields[4], `\040`, " ")
+
+		switch {
+		case mountPoint == "/":
+			continue
+		case mountPoint == "/usr/bin/toolbox":
+			continue
+		case strings.HasPrefix(mountPoint, "/dev"),
+			strings.HasPrefix(mountPoint, "/etc/"),
+			strings.HasPrefix(mountPoint, "/proc"),
+			strings.HasPrefix(mountPoint, "/run"),
+			strings.HasPrefix(mountPoint, "/sys"):
+			continue
+		}
+
+		if _, ok := seen[mountPoint]; ok {
+			continue
+		}
+
+		seen[mountPoint] = struct{}{}
+		mounts = append(mounts, mountPoint)
+	}
+
+	return mounts
+}
+
+// isEnterBannerEnabled checks the 'banner' option in the general section of
+// the configuration.  The banner is shown by default.
+func isEnterBannerEnabled() bool {
+	if !viper.IsSet("general.banner") {
+		return true
+	}
+
+	enabled := viper.GetBool("general.banner")
+	return enabled
+}
+
+// showEnterBanner tells users which environment 'toolbox enter' landed them
+// in.  Whatever can't be found out is left out, instead of delaying the
+// shell with errors.
+func showEnterBanner(containerObj podman.Container) {
+	if !isEnterBannerEnabled() || rootFlags.quiet || !term.IsTerminal(os.Stdout) {
+		return
+	}
+
+	container := containerObj.Name()
+
+	bannerCh := make(chan string, 1)
+
+	go func() {
+		var stdout strings.Builder
+
+		logLevelString := podman.LogLevel.String()
+		args := []string{"--log-level", logLevelString, "exec", container, "cat", enterBannerFile}
+
+		if err := shell.Run("podman", nil, &stdout, nil, args...); err != nil {
+			logrus.Debugf("Reading %s in container %s failed: %s", enterBannerFile, container, err)
+		}
+
+		bannerCh <- stdout.String()
+	}()
+
+	var imageLines []string
+
+	if details, err := podman.InspectContainerDetails(container); err != nil {
+		logrus.Debugf("Inspecting container %s failed: %s", container, err)
+	} else {
+		imageLines = getEnterBannerImageLines(details)
+	}
+
+	banner := <-bannerCh
+	if banner == "" && len(imageLines) == 0 {
+		return
+	}
+
+	fmt.Print(banner)
+
+	for _, line := range imageLines {
+		fmt.Println(line)
+	}
+
+	fmt.Println()
+}
+
+// getEnterBannerImageLines describes the image of the container, and whether
+// a newer one was pulled since the container was created from it.
+func getEnterBannerImageLines(details podman.ContainerDetails) []string {
+	info, err := podman.InspectImage(details.ImageName)
+	if err != nil {
+		logrus.Debugf("Inspecting image %s failed: %s", details.ImageName, err)
+		return nil
+	}
+
+	var lines []string
+
+	line := "Image:   " + details.ImageName
+	if createdString, ok := info["Created"].(string); ok {
+		if created, err := time.Parse(time.RFC3339Nano, createdString); err == nil {
+			line += ", built " + utils.HumanDuration(created.Unix())
+		}
+	}
+
+	lines = append(lines, line)
+
+	if id, ok := info["Id"].(string); ok && id != details.Image {
+		lines = append(lines, "Update:  a newer image was pulled, recreate the container to use it")
+	}
+
+	return lines
+}
+
+// writeEnterBanner is used by 'toolbox init-container' to describe the
+// container from the inside, each time it starts.
+func writeEnterBanner() error {
+	var banner strings.Builder
+
+	if osRelease, err := osrelease.Read(); err != nil {
+		logrus.Debugf("Reading os-release failed: %s", err)
+	} else if prettyName := osRelease["PRETTY_NAME"]; prettyName != "" {
+		fmt.Fprintf(&banner, "Distro:  %s\n", prettyName)
+	}
+
+	if mountInfo, err := os.ReadFile("/proc/self/mountinfo"); err != nil {
+		logrus.Debugf("Reading /proc/self/mountinfo failed: %s", err)
+	} else if mounts := getEnterBannerMounts(mountInfo); len(mounts) != 0 {
+		fmt.Fprintf(&banner, "Mounts:  %s\n", strings.Join(mounts, ", "))
+	}
+
+	if err := os.MkdirAll("/run/toolbox", 0755); err != nil {
+		return fmt.Errorf("failed to create /run/toolbox: %w", err)
+	}
+
+	if err := os.WriteFile(enterBannerFile, []byte(banner.String()), 0644); err != nil {
+		return fmt.Errorf("failed to create %s: %w", enterBannerFile, err)
+	}
+
+	return nil
+}
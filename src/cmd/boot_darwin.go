@@ -0,0 +1,308 @@
+//go:build darwin
+
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/containers/toolbox/pkg/i18n"
+	"github.com/containers/toolbox/pkg/podman"
+	"github.com/containers/toolbox/pkg/shell"
+	"github.com/containers/toolbox/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+const (
+	bootLabel = "com.github.containers.toolbox.boot"
+
+	// bootShell starts a login shell for root, preferring Bash because
+	// bootc base images are not guaranteed to configure root's shell.
+	bootShell = `command -v bash >/dev/null 2>&1 && exec bash --login
+exec sh -l`
+
+	bootStopTimeout = 30
+)
+
+var (
+	bootFlags struct {
+		authFile string
+		list     bool
+		name     string
+	}
+)
+
+var bootCmd = &cobra.Command{
+	Use:               "boot",
+	Short:             "Boot a bootable container image for testing (macOS version)",
+	RunE:              boot,
+	ValidArgsFunction: completionBootImageNames,
+}
+
+func init() {
+	flags := bootCmd.Flags()
+
+	flags.StringVar(&bootFlags.authFile,
+		"authfile",
+		"",
+		"Path to a file with credentials for authenticating to the registry for private images")
+
+	flags.BoolVarP(&bootFlags.list,
+		"list",
+		"l",
+		false,
+		"List the containers created by 'toolbox boot'")
+
+	flags.StringVarP(&bootFlags.name,
+		"name",
+		"n",
+		"",
+		"Assign a different name to the container")
+
+	bootCmd.SetHelpFunc(bootHelp)
+	rootCmd.AddCommand(bootCmd)
+}
+
+func boot(cmd *cobra.Command, args []string) error {
+	if utils.IsInsideContainer() {
+		return errors.New("boot is not supported inside a container")
+	}
+
+	if bootFlags.list {
+		if len(args) != 0 {
+			return errors.New("'--list' does not take an image")
+		}
+
+		containers, err := getBootContainers()
+		if err != nil {
+			return err
+		}
+
+		listOutput(nil, containers, nil)
+		return nil
+	}
+
+	if len(args) != 1 {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "missing argument for \"boot\"\n")
+		i18n.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	image := args[0]
+
+	container := bootFlags.name
+	if container == "" {
+		basename := utils.ImageReferenceGetBasename(image)
+		if basename == "" {
+			return fmt.Errorf("failed to get the basename of image %s", image)
+		}
+
+		container = basename + "-boot"
+	}
+
+	if !utils.IsContainerNameValid(container) {
+		return createErrorInvalidContainer(container)
+	}
+
+	if err := ensureBootContainer(container, image); err != nil {
+		return err
+	}
+
+	s := showSpinner(fmt.Sprintf("Booting %s", container))
+	err := startBootContainer(container)
+	stopSpinner(s)
+
+	if err != nil {
+		return err
+	}
+
+	logLevelString := podman.LogLevel.String()
+	execArgs := []string{
+		"--log-level", logLevelString,
+		"exec",
+		"--interactive",
+		"--tty",
+		container,
+		"sh", "-c", bootShell,
+	}
+
+	exitCode, err := shell.RunWithExitCode("podman", os.Stdin, os.Stdout, os.Stderr, execArgs...)
+
+	s = showSpinner(fmt.Sprintf("Shutting down %s", container))
+	stopErr := podman.Stop(container, bootStopTimeout)
+	stopSpinner(s)
+
+	if stopErr != nil {
+		logrus.Debugf("Stopping container %s failed: %s", container, stopErr)
+		showWarning("failed to shut down container %s", container)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if exitCode != 0 {
+		return &exitError{exitCode, nil}
+	}
+
+	return nil
+}
+
+func bootHelp(cmd *cobra.Command, args []string) {
+	if utils.IsInsideContainer() {
+		if !utils.IsInsideToolboxContainer() {
+			fmt.Fprintf(os.Stderr, "Error: this is not a Toolbx container\n")
+			return
+		}
+
+		if _, err := utils.ForwardToHost(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return
+		}
+
+		return
+	}
+
+	if err := showManual("toolbox-boot"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return
+	}
+}
+
+// completionBootImageNames offers the local images that declare themselves
+// bootable with the containers.bootc label, since those are what 'toolbox
+// boot' is meant for.
+func completionBootImageNames(cmd *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+	if bootFlags.list || len(args) >= 1 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	images, err := podman.GetImages("--filter", "label=containers.bootc=1")
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var imageNames []string
+	for _, image := range images {
+		imageNames = append(imageNames, image.Names...)
+	}
+
+	return imageNames, cobra.ShellCompDirectiveNoFileComp
+}
+
+// ensureBootContainer creates a container for a bootable image, unless one
+// already exists.  It is labelled differently from Toolbx containers, so that
+// it isn't picked up by 'toolbox enter' and the like, and runs systemd as its
+// entry point instead of 'toolbox init-container'.
+func ensureBootContainer(container, image string) error {
+	exists, err := podman.ContainerExists(container)
+	if err == nil && exists {
+		containerObj, err := podman.InspectContainer(container)
+		if err != nil {
+			return fmt.Errorf("failed to inspect container %s", container)
+		}
+
+		if containerObj.Labels()[bootLabel] != "true" {
+			return fmt.Errorf("container %s was not created by 'toolbox boot'", container)
+		}
+
+		return nil
+	}
+
+	if exists, _ := podman.ImageExists(image); !exists {
+		if err := pullImage(image, bootFlags.authFile); err != nil {
+			return err
+		}
+	}
+
+	logLevelString := podman.LogLevel.String()
+	createArgs := []string{
+		"--log-level", logLevelString,
+		"create",
+		"--hostname", container,
+		"--interactive",
+		"--label", bootLabel + "=true",
+		"--name", container,
+		"--systemd", "always",
+		"--tty",
+		image,
+		"/sbin/init",
+	}
+
+	logrus.Debugf("Full podman create command: podman %s", strings.Join(utils.RedactEnvOptions(createArgs), " "))
+
+	s := showSpinner(fmt.Sprintf("Creating container %s", container))
+	err = shell.Run("podman", nil, nil, nil, createArgs...)
+	stopSpinner(s)
+
+	if err != nil {
+		return fmt.Errorf("failed to create container %s: %w", container, err)
+	}
+
+	return nil
+}
+
+func getBootContainers() ([]podman.Container, error) {
+	logrus.Debug("Fetching containers created by 'toolbox boot'")
+
+	args := []string{"--all", "--filter", "label=" + bootLabel + "=true", "--sort", "names"}
+	containers, err := podman.GetContainers(args...)
+	if err != nil {
+		logrus.Debugf("Fetching containers created by 'toolbox boot' failed: %s", err)
+		return nil, errors.New("failed to get containers")
+	}
+
+	var bootContainers []podman.Container
+	for containers.Next() {
+		bootContainers = append(bootContainers, containers.Get())
+	}
+
+	return bootContainers, nil
+}
+
+// startBootContainer starts the container and waits for systemd to finish
+// booting.  A degraded system is not an error, because the point is to test
+// the image.
+func startBootContainer(container string) error {
+	var stderr strings.Builder
+	if err := podman.Start(container, &stderr); err != nil {
+		logrus.Debugf("Starting container %s failed: %s", container, stderr.String())
+		return fmt.Errorf("failed to start container %s", container)
+	}
+
+	logLevelString := podman.LogLevel.String()
+	args := []string{
+		"--log-level", logLevelString,
+		"exec",
+		container,
+		"systemctl", "is-system-running", "--wait",
+	}
+
+	if err := shell.Run("podman", nil, nil, nil, args...); err != nil {
+		logrus.Debugf("Waiting for container %s to boot: %s", container, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,233 @@
+//go:build darwin
+
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/containers/toolbox/pkg/i18n"
+	"github.com/containers/toolbox/pkg/podman"
+	"github.com/containers/toolbox/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	buildFlags struct {
+		authFile  string
+		farm      string
+		file      string
+		platforms string
+		tag       string
+	}
+)
+
+var buildCmd = &cobra.Command{
+	Use:   "build",
+	Short: "Build a Toolbx image, optionally on a Podman farm (macOS version)",
+	RunE:  build,
+}
+
+func init() {
+	flags := buildCmd.Flags()
+
+	flags.StringVar(&buildFlags.authFile,
+		"authfile",
+		"",
+		"Path to a file with credentials for authenticating to the registry")
+
+	flags.StringVar(&buildFlags.farm,
+		"farm",
+		"",
+		"Build on this Podman farm instead of the default one")
+
+	flags.StringVarP(&buildFlags.file,
+		"file",
+		"f",
+		"",
+		"Path to the Containerfile")
+
+	flags.StringVar(&buildFlags.platforms,
+		"platforms",
+		"",
+		"Comma separated list of platforms to build for, eg., linux/amd64,linux/arm64")
+
+	flags.StringVarP(&buildFlags.tag,
+		"tag",
+		"t",
+		"",
+		"Name of the image to build")
+
+	buildCmd.SetHelpFunc(buildHelp)
+
+	if err := buildCmd.RegisterFlagCompletionFunc("farm", completionFarmNames); err != nil {
+		panicMsg := fmt.Sprintf("failed to register flag completion function: %v", err)
+		panic(panicMsg)
+	}
+
+	rootCmd.AddCommand(buildCmd)
+}
+
+// build builds an image labelled as a Toolbx image.  Builds for other
+// platforms than the Podman machine's are offloaded to the remote Linux
+// builders of a Podman farm, which push a manifest list to the registry in
+// the tag, and the image for the local platform is pulled back afterwards so
+// that it can be used with 'toolbox create --image'.
+func build(cmd *cobra.Command, args []string) error {
+	if utils.IsInsideContainer() {
+		return errors.New("build is not supported inside a container")
+	}
+
+	if len(args) > 1 {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "too many arguments for \"build\"\n")
+		i18n.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	if buildFlags.tag == "" {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "missing option '--tag'\n")
+		i18n.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	contextDirectory := "."
+	if len(args) == 1 {
+		contextDirectory = args[0]
+	}
+
+	farm, err := resolveFarm(buildFlags.farm, buildFlags.platforms != "")
+	if err != nil {
+		return err
+	}
+
+	buildArgs := []string{"--label", "com.github.containers.toolbox=true", "--tag", buildFlags.tag}
+
+	if buildFlags.authFile != "" {
+		buildArgs = append(buildArgs, "--authfile", buildFlags.authFile)
+	}
+
+	if buildFlags.file != "" {
+		buildArgs = append(buildArgs, "--file", buildFlags.file)
+	}
+
+	if buildFlags.platforms != "" {
+		buildArgs = append(buildArgs, "--platforms", buildFlags.platforms)
+	}
+
+	buildArgs = append(buildArgs, contextDirectory)
+
+	if farm == "" {
+		warnIfOnBatteryPower()
+		showStatus("Building %s", buildFlags.tag)
+	} else {
+		showStatus("Building %s on Podman farm %s", buildFlags.tag, farm)
+	}
+
+	if err := podman.Build(farm, os.Stdout, os.Stderr, buildArgs...); err != nil {
+		return fmt.Errorf("failed to build image %s", buildFlags.tag)
+	}
+
+	if farm == "" {
+		return nil
+	}
+
+	if err := pullImage(buildFlags.tag, buildFlags.authFile); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func buildHelp(cmd *cobra.Command, args []string) {
+	if utils.IsInsideContainer() {
+		if !utils.IsInsideToolboxContainer() {
+			fmt.Fprintf(os.Stderr, "Error: this is not a Toolbx container\n")
+			return
+		}
+
+		if _, err := utils.ForwardToHost(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return
+		}
+
+		return
+	}
+
+	if err := showManual("toolbox-build"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return
+	}
+}
+
+func completionFarmNames(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+	var farmNames []string
+	if farms, err := podman.GetFarms(); err == nil {
+		for _, farm := range farms {
+			farmNames = append(farmNames, farm.Name)
+		}
+	}
+
+	return farmNames, cobra.ShellCompDirectiveNoFileComp
+}
+
+// resolveFarm returns the Podman farm to build on.  It's the one that was
+// asked for, or the default farm if one is needed for a multi-platform
+// build, or none for a plain local build.
+func resolveFarm(farm string, platformsRequested bool) (string, error) {
+	if farm == "" && !platformsRequested {
+		return "", nil
+	}
+
+	farms, err := podman.GetFarms()
+	if err != nil {
+		logrus.Debugf("Listing Podman farms failed: %s", err)
+		return "", errors.New("failed to list Podman farms")
+	}
+
+	for _, farmObj := range farms {
+		if farm == "" && farmObj.Default || farm != "" && farmObj.Name == farm {
+			logrus.Debugf("Using Podman farm %s with connections %s",
+				farmObj.Name,
+				strings.Join(farmObj.Connections, ", "))
+
+			return farmObj.Name, nil
+		}
+	}
+
+	var builder strings.Builder
+	if farm == "" {
+		fmt.Fprintf(&builder, "no default Podman farm for building for other platforms\n")
+	} else {
+		fmt.Fprintf(&builder, "Podman farm %s not found\n", farm)
+	}
+
+	fmt.Fprintf(&builder, "Use 'podman system connection add' and 'podman farm create' to set one up.")
+
+	errMsg := builder.String()
+	return "", errors.New(errMsg)
+}
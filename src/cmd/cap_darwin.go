@@ -0,0 +1,545 @@
+//go:build darwin
+
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/containers/toolbox/pkg/i18n"
+	"github.com/containers/toolbox/pkg/podman"
+	"github.com/containers/toolbox/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// containerPrivileges are the capabilities and security options that a
+// Toolbx container is created with.  They are recorded in labels, because
+// 'podman inspect' mixes them with what Podman adds on its own.
+type containerPrivileges struct {
+	capAdd      []string
+	capDrop     []string
+	securityOpt []string
+}
+
+const (
+	capAddLabel      = "com.github.containers.toolbox.cap-add"
+	capDropLabel     = "com.github.containers.toolbox.cap-drop"
+	securityOptLabel = "com.github.containers.toolbox.security-opt"
+)
+
+var (
+	capSetFlags struct {
+		add               []string
+		drop              []string
+		removeSecurityOpt []string
+		reset             bool
+		securityOpt       []string
+	}
+)
+
+var capCmd = &cobra.Command{
+	Use:   "cap",
+	Short: "Show or change the privileges of a Toolbx container (macOS version)",
+	RunE:  capRun,
+}
+
+var capSetCmd = &cobra.Command{
+	Use:               "set",
+	Short:             "Recreate a Toolbx container with different privileges",
+	RunE:              capSet,
+	ValidArgsFunction: completionContainerNamesFiltered,
+}
+
+var capShowCmd = &cobra.Command{
+	Use:               "show",
+	Short:             "Show the privileges of a Toolbx container",
+	RunE:              capShow,
+	ValidArgsFunction: completionContainerNamesFiltered,
+}
+
+func init() {
+	flags := capSetCmd.Flags()
+
+	flags.StringSliceVar(&capSetFlags.add,
+		"add",
+		nil,
+		"Give the container this capability")
+
+	flags.StringSliceVar(&capSetFlags.drop,
+		"drop",
+		nil,
+		"Take this capability away from the container")
+
+	flags.StringSliceVar(&capSetFlags.removeSecurityOpt,
+		"remove-security-opt",
+		nil,
+		"Remove this security option from the container")
+
+	flags.BoolVar(&capSetFlags.reset,
+		"reset",
+		false,
+		"Start from the privileges that 'toolbox create' gives by default")
+
+	flags.StringSliceVar(&capSetFlags.securityOpt,
+		"security-opt",
+		nil,
+		"Set this security option of the container")
+
+	capCmd.AddCommand(capSetCmd)
+	capCmd.AddCommand(capShowCmd)
+
+	capCmd.SetHelpFunc(capHelp)
+	rootCmd.AddCommand(capCmd)
+}
+
+func capRun(cmd *cobra.Command, args []string) error {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "missing command for \"cap\", eg., show or set\n")
+	i18n.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+	errMsg := builder.String()
+	return errors.New(errMsg)
+}
+
+// capSet can't change the privileges of an existing container, because
+// Podman can't.  Instead, the container is committed into an image, and a new
+// one with the same name is created from it, so that what was installed in it
+// is kept.  The home directory and the workspace volume are mounted again as
+// they were.
+func capSet(cmd *cobra.Command, args []string) error {
+	if utils.IsInsideContainer() {
+		return errors.New("cap is not supported inside a container")
+	}
+
+	if len(args) != 1 {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "cap set needs a container\n")
+		i18n.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	container := args[0]
+
+	details, err := getCapContainerDetails(container)
+	if err != nil {
+		return err
+	}
+
+	oldPrivileges := getContainerPrivileges(details)
+
+	privileges := oldPrivileges
+	if capSetFlags.reset {
+		privileges = defaultContainerPrivileges()
+	}
+
+	privileges.update(capSetFlags.add, capSetFlags.drop, capSetFlags.securityOpt, capSetFlags.removeSecurityOpt)
+
+	if privileges.equal(oldPrivileges) {
+		showMessage("Container %s already has these privileges", container)
+		return nil
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	showContainerPrivileges(writer, privileges)
+	writer.Flush()
+
+	if !rootFlags.assumeYes {
+		prompt := i18n.Sprintf("Recreate container %s with these privileges? [y/N]: ", container)
+		if !askForConfirmation(prompt) {
+			return nil
+		}
+	}
+
+	if details.State.Status == "running" {
+		s := showSpinner(fmt.Sprintf("Stopping container %s", container))
+		err := podman.Stop(container, 10)
+		stopSpinner(s)
+
+		if err != nil {
+			return fmt.Errorf("failed to stop container %s: %w", container, err)
+		}
+	}
+
+	timestamp := time.Now().Format("20060102150405")
+	image := fmt.Sprintf("localhost/%s:cap-%s", container, timestamp)
+
+	s := showSpinner(fmt.Sprintf("Committing container %s", container))
+	err = podman.Commit(container, image)
+	stopSpinner(s)
+
+	if err != nil {
+		return fmt.Errorf("failed to commit container %s: %w", container, err)
+	}
+
+	if err := recreateContainer(container, details, image, "cap-"+timestamp, privileges); err != nil {
+		return err
+	}
+
+	// An image committed by an earlier 'toolbox cap set' is only used by
+	// the container that was just removed.
+	if strings.HasPrefix(details.ImageName, "localhost/"+container+":cap-") {
+		if err := podman.RemoveImage(details.ImageName, false); err != nil {
+			logrus.Debugf("Removing image %s failed: %s", details.ImageName, err)
+		}
+	}
+
+	showMessage("Recreated container %s from image %s", container, image)
+	return nil
+}
+
+func capShow(cmd *cobra.Command, args []string) error {
+	if utils.IsInsideContainer() {
+		return errors.New("cap is not supported inside a container")
+	}
+
+	if len(args) != 1 {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "cap show needs a container\n")
+		i18n.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	container := args[0]
+
+	details, err := getCapContainerDetails(container)
+	if err != nil {
+		return err
+	}
+
+	privileges := getContainerPrivileges(details)
+
+	privileged := "no"
+	if details.HostConfig.Privileged {
+		privileged = "yes"
+	}
+
+	effectiveCaps := "unknown, the container is not running"
+	if details.State.Status == "running" {
+		effectiveCaps = formatPrivilegesList(details.EffectiveCaps)
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	showContainerPrivileges(writer, privileges)
+	fmt.Fprintf(writer, "Privileged:\t%s\n", privileged)
+	fmt.Fprintf(writer, "Effective capabilities:\t%s\n", effectiveCaps)
+	writer.Flush()
+
+	return nil
+}
+
+func capHelp(cmd *cobra.Command, args []string) {
+	if utils.IsInsideContainer() {
+		if !utils.IsInsideToolboxContainer() {
+			fmt.Fprintf(os.Stderr, "Error: this is not a Toolbx container\n")
+			return
+		}
+
+		if _, err := utils.ForwardToHost(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return
+		}
+
+		return
+	}
+
+	if err := showManual("toolbox-cap"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return
+	}
+}
+
+func defaultContainerPrivileges() containerPrivileges {
+	privileges := containerPrivileges{
+		capAdd:      []string{"SYS_PTRACE"},
+		securityOpt: []string{"label=disable"},
+	}
+
+	return privileges
+}
+
+func formatPrivilegesList(list []string) string {
+	if len(list) == 0 {
+		return "none"
+	}
+
+	return strings.Join(list, ", ")
+}
+
+func getCapContainerDetails(container string) (podman.ContainerDetails, error) {
+	containerObj, err := podman.InspectContainer(container)
+	if err != nil {
+		return podman.ContainerDetails{}, createErrorContainerNotFound(container)
+	}
+
+	if !containerObj.IsToolbx() {
+		return podman.ContainerDetails{}, fmt.Errorf("%s is not a Toolbx container", container)
+	}
+
+	if err := checkContainerOwner(containerObj); err != nil {
+		return podman.ContainerDetails{}, err
+	}
+
+	details, err := podman.InspectContainerDetails(container)
+	if err != nil {
+		logrus.Debugf("Inspecting container %s failed: %s", container, err)
+		return podman.ContainerDetails{}, fmt.Errorf("failed to inspect container %s", container)
+	}
+
+	return details, nil
+}
+
+// getContainerPrivileges reads the privileges of a container from its labels.
+// Containers created before they were recorded fall back to what Podman
+// reports.
+func getContainerPrivileges(details podman.ContainerDetails) containerPrivileges {
+	labels := details.Config.Labels
+
+	if _, ok := labels[capAddLabel]; !ok {
+		var privileges containerPrivileges
+		privileges.update(details.HostConfig.CapAdd, details.HostConfig.CapDrop, details.HostConfig.SecurityOpt, nil)
+		return privileges
+	}
+
+	splitLabel := func(label string) []string {
+		value := labels[label]
+		if value == "" {
+			return nil
+		}
+
+		return strings.Split(value, ",")
+	}
+
+	privileges := containerPrivileges{
+		capAdd:      splitLabel(capAddLabel),
+		capDrop:     splitLabel(capDropLabel),
+		securityOpt: splitLabel(securityOptLabel),
+	}
+
+	return privileges
+}
+
+// getCreateCommandOptions returns the values of an option in the 'podman
+// create' command that created a container, eg., all the '--env' values.
+func getCreateCommandOptions(createCommand []string, option string) []string {
+	var values []string
+
+	for i := 0; i < len(createCommand); i++ {
+		arg := createCommand[i]
+
+		if arg == option && i+1 < len(createCommand) {
+			values = append(values, createCommand[i+1])
+			i++
+			continue
+		}
+
+		if value, found := strings.CutPrefix(arg, option+"="); found {
+			values = append(values, value)
+		}
+	}
+
+	return values
+}
+
+func normalizeCapability(capability string) string {
+	capability = strings.ToUpper(strings.TrimSpace(capability))
+	capability = strings.TrimPrefix(capability, "CAP_")
+	return capability
+}
+
+// recreateContainer replaces a stopped container with a new one with the
+// same name created from image, and the same settings otherwise.  The old
+// container is renamed with suffix out of the way until the new one is in
+// place, so that it can be put back if that fails.
+func recreateContainer(container string, details podman.ContainerDetails, image, suffix string, privileges containerPrivileges) error {
+	oldContainer := container + "-" + suffix
+	if err := podman.Rename(container, oldContainer); err != nil {
+		return fmt.Errorf("failed to rename container %s: %w", container, err)
+	}
+
+	setCreateFlagsFromContainer(details)
+
+	if err := createContainerWithMacOSOptions(container, image, "", privileges); err != nil {
+		if err := podman.Rename(oldContainer, container); err != nil {
+			logrus.Debugf("Renaming container %s back to %s failed: %s", oldContainer, container, err)
+		}
+
+		return err
+	}
+
+	transferContainerLastUsed(details.ID, container)
+	transferContainerProtected(details.ID, container)
+	transferContainerSettings(details.ID, container)
+
+	if err := podman.RemoveContainer(oldContainer, true); err != nil {
+		logrus.Debugf("Removing container %s failed: %s", oldContainer, err)
+		showWarning("failed to remove the old container %s", oldContainer)
+	}
+
+	return nil
+}
+
+// setCreateFlagsFromContainer sets up createFlags for
+// createContainerWithMacOSOptions to create a container like an existing one,
+// and returns the name of its workspace volume, if any, which the new
+// container uses too.
+func setCreateFlagsFromContainer(details podman.ContainerDetails) string {
+	extraMounts, workspaceVolume := getContainerExtraMounts(details)
+
+	createCommand := details.Config.CreateCommand
+
+	// The '--env' and '--dns' options of 'podman create' come from the
+	// options of 'toolbox create' with the same names, so there's nothing
+	// else to tell them apart from the image's environment.
+	createFlags.env = getCreateCommandOptions(createCommand, "--env")
+	createFlags.envFile = nil
+	createFlags.dns = getCreateCommandOptions(createCommand, "--dns")
+	createFlags.dnsSearch = getCreateCommandOptions(createCommand, "--dns-search")
+	createFlags.publish = getContainerPublish(details)
+	createFlags.publishFromContainer = true
+
+	// A network shared with another container is recorded as
+	// 'container:ID', which 'podman create' takes as it is.
+	createFlags.network = details.HostConfig.NetworkMode
+	createFlags.networkFrom = ""
+
+	createFlags.credentials = nil
+	if credentials := details.Config.Labels[credentialsLabel]; credentials != "" {
+		createFlags.credentials = strings.Split(credentials, ",")
+	}
+
+	createFlags.entitlement = details.Config.Labels[entitlementLabel]
+	createFlags.gpu = details.Config.Labels[gpuLabel] == "true"
+	createFlags.nested = details.Config.Labels[nestedLabel] == "true"
+
+	createFlags.secrets = nil
+	if secrets := details.Config.Labels[secretsLabel]; secrets != "" {
+		createFlags.secrets = strings.Split(secrets, ",")
+	}
+
+	createFlags.shell = details.Config.Labels[shellLabel]
+	createFlags.loginShell = details.Config.Labels[loginShellLabel] != "false"
+
+	createFlags.volumes = nil
+	for _, mount := range extraMounts {
+		volume := mount.Source + ":" + mount.Destination
+		if mount.ReadOnly {
+			volume += ":ro"
+		}
+
+		createFlags.volumes = append(createFlags.volumes, volume)
+	}
+
+	createFlags.workspaceVolume = workspaceVolume != ""
+	createFlags.workspaceVolumeName = workspaceVolume
+	return workspaceVolume
+}
+
+func showContainerPrivileges(writer io.Writer, privileges containerPrivileges) {
+	fmt.Fprintf(writer, "Added capabilities:\t%s\n", formatPrivilegesList(privileges.capAdd))
+	fmt.Fprintf(writer, "Dropped capabilities:\t%s\n", formatPrivilegesList(privileges.capDrop))
+	fmt.Fprintf(writer, "Security options:\t%s\n", formatPrivilegesList(privileges.securityOpt))
+}
+
+func (privileges containerPrivileges) equal(other containerPrivileges) bool {
+	return slices.Equal(privileges.capAdd, other.capAdd) &&
+		slices.Equal(privileges.capDrop, other.capDrop) &&
+		slices.Equal(privileges.securityOpt, other.securityOpt)
+}
+
+// getCreateArgs returns the options for 'podman create', including the labels
+// that record the privileges.
+func (privileges containerPrivileges) getCreateArgs() []string {
+	var args []string
+
+	for _, capability := range privileges.capAdd {
+		args = append(args, "--cap-add", capability)
+	}
+
+	for _, capability := range privileges.capDrop {
+		args = append(args, "--cap-drop", capability)
+	}
+
+	for _, securityOpt := range privileges.securityOpt {
+		args = append(args, "--security-opt", securityOpt)
+	}
+
+	args = append(args,
+		"--label", capAddLabel+"="+strings.Join(privileges.capAdd, ","),
+		"--label", capDropLabel+"="+strings.Join(privileges.capDrop, ","),
+		"--label", securityOptLabel+"="+strings.Join(privileges.securityOpt, ","))
+
+	return args
+}
+
+// update adds and drops capabilities and security options.  Dropping a
+// capability that was added only undoes the addition, and the other way
+// round, so that the defaults of Podman are restored.
+func (privileges *containerPrivileges) update(add, drop, securityOpt, removeSecurityOpt []string) {
+	capAdd := slices.Clone(privileges.capAdd)
+	capDrop := slices.Clone(privileges.capDrop)
+
+	for _, capability := range add {
+		capability = normalizeCapability(capability)
+
+		if i := slices.Index(capDrop, capability); i != -1 {
+			capDrop = slices.Delete(capDrop, i, i+1)
+		} else if !slices.Contains(capAdd, capability) {
+			capAdd = append(capAdd, capability)
+		}
+	}
+
+	for _, capability := range drop {
+		capability = normalizeCapability(capability)
+
+		if i := slices.Index(capAdd, capability); i != -1 {
+			capAdd = slices.Delete(capAdd, i, i+1)
+		} else if !slices.Contains(capDrop, capability) {
+			capDrop = append(capDrop, capability)
+		}
+	}
+
+	newSecurityOpt := slices.Clone(privileges.securityOpt)
+
+	for _, opt := range securityOpt {
+		if !slices.Contains(newSecurityOpt, opt) {
+			newSecurityOpt = append(newSecurityOpt, opt)
+		}
+	}
+
+	for _, opt := range removeSecurityOpt {
+		if i := slices.Index(newSecurityOpt, opt); i != -1 {
+			newSecurityOpt = slices.Delete(newSecurityOpt, i, i+1)
+		}
+	}
+
+	privileges.capAdd = capAdd
+	privileges.capDrop = capDrop
+	privileges.securityOpt = newSecurityOpt
+}
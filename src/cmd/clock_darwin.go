@@ -0,0 +1,133 @@
+//go:build darwin
+
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/containers/toolbox/pkg/podman"
+	"github.com/containers/toolbox/pkg/utils"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// machineClockCheckInterval throttles the checks, because each one
+	// needs a round trip through 'podman machine ssh'.
+	machineClockCheckInterval = time.Minute
+
+	machineClockDriftMax = 5 * time.Second
+)
+
+// syncMachineClock corrects the clock of the Podman machine if it drifted
+// away from the host.  This typically happens after the Mac wakes up from
+// sleep, and leads to TLS certificates that are not yet valid, and to make(1)
+// complaining about modification times in the future.
+func syncMachineClock() error {
+	toolboxRuntimeDirectory, err := utils.GetRuntimeDirectory(currentUser)
+	if err != nil {
+		return err
+	}
+
+	stamp := filepath.Join(toolboxRuntimeDirectory, "machine-clock-checked")
+	if fileInfo, err := os.Stat(stamp); err == nil {
+		if time.Since(fileInfo.ModTime()) < machineClockCheckInterval {
+			logrus.Debug("Checking the Podman machine clock: skipping, checked recently")
+			return nil
+		}
+	}
+
+	if err := os.WriteFile(stamp, nil, 0644); err != nil {
+		logrus.Debugf("Checking the Podman machine clock: failed to update %s: %s", stamp, err)
+	}
+
+	drift, err := getMachineClockDrift()
+	if err != nil {
+		logrus.Debugf("Checking the Podman machine clock failed: %s", err)
+		return nil
+	}
+
+	logrus.Debugf("Podman machine clock is off by %s", drift)
+
+	if isMachineClockDriftTolerable(drift) {
+		return nil
+	}
+
+	logrus.Debug("Synchronizing the Podman machine clock with chronyc(1)")
+
+	if err := podman.MachineSSH(nil, "sudo", "chronyc", "-a", "makestep"); err != nil {
+		logrus.Debugf("Synchronizing the Podman machine clock with chronyc(1) failed: %s", err)
+	} else if drift, err = getMachineClockDrift(); err == nil && isMachineClockDriftTolerable(drift) {
+		return nil
+	}
+
+	logrus.Debug("Setting the Podman machine clock to the host's time")
+
+	hostTime := fmt.Sprintf("@%d", time.Now().Unix())
+	if err := podman.MachineSSH(nil, "sudo", "date", "--utc", "--set", hostTime); err != nil {
+		logrus.Debugf("Setting the Podman machine clock failed: %s", err)
+
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "the Podman machine clock is off by %s\n", drift.Round(time.Second))
+		fmt.Fprintf(&builder, "Restart it with: podman machine stop; podman machine start")
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	return nil
+}
+
+// getMachineClockDrift returns how far the clock of the Podman machine is
+// ahead of the host, or behind if negative.  The round trip through 'podman
+// machine ssh' is compensated by comparing against the midpoint.
+func getMachineClockDrift() (time.Duration, error) {
+	var stdout bytes.Buffer
+
+	before := time.Now()
+	if err := podman.MachineSSH(&stdout, "date", "+%s.%N"); err != nil {
+		return 0, err
+	}
+
+	after := time.Now()
+
+	output := strings.TrimSpace(stdout.String())
+	seconds, err := strconv.ParseFloat(output, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse time %s: %w", output, err)
+	}
+
+	secondsInt, secondsFrac := math.Modf(seconds)
+	machineTime := time.Unix(int64(secondsInt), int64(secondsFrac*float64(time.Second)))
+
+	hostTime := before.Add(after.Sub(before) / 2)
+	drift := machineTime.Sub(hostTime)
+	return drift, nil
+}
+
+func isMachineClockDriftTolerable(drift time.Duration) bool {
+	return drift.Abs() <= machineClockDriftMax
+}
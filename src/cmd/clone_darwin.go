@@ -0,0 +1,184 @@
+//go:build darwin
+
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/containers/toolbox/pkg/i18n"
+	"github.com/containers/toolbox/pkg/podman"
+	"github.com/containers/toolbox/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var cloneCmd = &cobra.Command{
+	Use:               "clone",
+	Short:             "Duplicate a Toolbx container with what was installed in it (macOS version)",
+	RunE:              clone,
+	ValidArgsFunction: completionContainerNameFirst,
+}
+
+func init() {
+	cloneCmd.SetHelpFunc(cloneHelp)
+	rootCmd.AddCommand(cloneCmd)
+}
+
+// clone commits the container into an image, and creates a new one from it,
+// like 'toolbox cap set', but with a new name and host name, and without
+// touching the original.  The extra mounts, environment variables, DNS
+// servers, privileges, shell and network are carried over, and the contents
+// of the workspace volume are copied into a new one.  Published ports are
+// not, because they can only be used by one container at a time.
+func clone(cmd *cobra.Command, args []string) error {
+	if utils.IsInsideContainer() {
+		return errors.New("clone is not supported inside a container")
+	}
+
+	if len(args) != 2 {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "clone needs a container and a name for the new one\n")
+		i18n.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	container := args[0]
+	newContainer := args[1]
+
+	if !utils.IsContainerNameValid(newContainer) {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "invalid argument for the new name: %s\n", newContainer)
+		fmt.Fprintf(&builder, "Container names must match '%s'.\n", utils.ContainerNameRegexp)
+		i18n.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	details, err := getCapContainerDetails(container)
+	if err != nil {
+		return err
+	}
+
+	if exists, _ := podman.ContainerExists(newContainer); exists {
+		return fmt.Errorf("container %s already exists", newContainer)
+	}
+
+	timestamp := time.Now().Format("20060102150405")
+	image := fmt.Sprintf("localhost/%s:clone-%s", newContainer, timestamp)
+
+	s := showSpinner(fmt.Sprintf("Committing container %s", container))
+	err = podman.Commit(container, image)
+	stopSpinner(s)
+
+	if err != nil {
+		return fmt.Errorf("failed to commit container %s: %w", container, err)
+	}
+
+	// The clone gets a new workspace volume, and not the original's.
+	workspaceVolume := setCreateFlagsFromContainer(details)
+	createFlags.workspaceVolumeName = ""
+
+	// Nor does it share the storage of Podman and Buildah inside the
+	// original, which can't be used by two containers at once.
+	createFlags.volumes = slices.DeleteFunc(createFlags.volumes, isNestedStorageVolume)
+
+	privileges := getContainerPrivileges(details)
+
+	if err := createContainerWithMacOSOptions(newContainer, image, "", privileges); err != nil {
+		if err := podman.RemoveImage(image, false); err != nil {
+			logrus.Debugf("Removing image %s failed: %s", image, err)
+		}
+
+		return err
+	}
+
+	if workspaceVolume != "" {
+		newWorkspaceVolume := getWorkspaceVolumeName(newContainer)
+
+		s := showSpinner(fmt.Sprintf("Copying workspace volume %s", workspaceVolume))
+		err := copyVolume(workspaceVolume, newWorkspaceVolume)
+		stopSpinner(s)
+
+		if err != nil {
+			logrus.Debugf("Copying volume %s to %s failed: %s", workspaceVolume, newWorkspaceVolume, err)
+			showWarning("the workspace volume of container %s was not copied, and is empty", newContainer)
+		}
+	}
+
+	copyContainerSettings(details.ID, newContainer)
+
+	showMessage("Cloned container %s to %s", container, newContainer)
+	showMessage("Enter it with 'toolbox enter %s'", newContainer)
+	return nil
+}
+
+func cloneHelp(cmd *cobra.Command, args []string) {
+	if utils.IsInsideContainer() {
+		if !utils.IsInsideToolboxContainer() {
+			fmt.Fprintf(os.Stderr, "Error: this is not a Toolbx container\n")
+			return
+		}
+
+		if _, err := utils.ForwardToHost(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return
+		}
+
+		return
+	}
+
+	if err := showManual("toolbox-clone"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return
+	}
+}
+
+func copyVolume(volume, newVolume string) error {
+	reader, writer := io.Pipe()
+
+	exportErrCh := make(chan error, 1)
+
+	go func() {
+		err := podman.VolumeExport(volume, writer)
+		writer.CloseWithError(err)
+		exportErrCh <- err
+	}()
+
+	importErr := podman.VolumeImport(newVolume, reader)
+	reader.Close()
+
+	if err := <-exportErrCh; err != nil {
+		return fmt.Errorf("failed to export volume %s: %w", volume, err)
+	}
+
+	if importErr != nil {
+		return fmt.Errorf("failed to import volume %s: %w", newVolume, importErr)
+	}
+
+	return nil
+}
@@ -0,0 +1,276 @@
+//go:build darwin
+
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/containers/toolbox/pkg/i18n"
+	"github.com/containers/toolbox/pkg/shell"
+	"github.com/containers/toolbox/pkg/utils"
+	"github.com/google/renameio/v2"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// codeApplication is where the 'code' command is, if it wasn't
+	// installed in PATH from inside VS Code.
+	codeApplication = "/Applications/Visual Studio Code.app/Contents/Resources/app/bin/code"
+
+	// codeUserDirectory has the settings of VS Code, and the
+	// configurations of attached containers that the Dev Containers
+	// extension keeps, relative to the home directory.
+	codeUserDirectory = "Library/Application Support/Code/User"
+)
+
+var codeCmd = &cobra.Command{
+	Use:               "code",
+	Short:             "Open VS Code attached to a Toolbx container (macOS version)",
+	RunE:              code,
+	ValidArgsFunction: completionContainerNameFirst,
+}
+
+func init() {
+	codeCmd.SetHelpFunc(codeHelp)
+	rootCmd.AddCommand(codeCmd)
+}
+
+// code opens VS Code with the Dev Containers extension attached to the
+// container, which needs to be running for that.  The extension doesn't know
+// about Toolbx, so it's told through the configuration that it keeps for each
+// attached container, which user to be and where to start.
+func code(cmd *cobra.Command, args []string) error {
+	if utils.IsInsideContainer() {
+		return errors.New("code is not supported inside a container")
+	}
+
+	if len(args) != 1 && len(args) != 2 {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "code needs a container, and optionally a directory\n")
+		i18n.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	container := args[0]
+
+	codeCommand, err := getCodeCommand()
+	if err != nil {
+		return err
+	}
+
+	if _, err := getCapContainerDetails(container); err != nil {
+		return err
+	}
+
+	dir := workingDirectory
+	if len(args) == 2 {
+		dir = args[1]
+	}
+
+	workspaceFolder, err := getCodeWorkspaceFolder(container, dir, len(args) == 2)
+	if err != nil {
+		return err
+	}
+
+	s := showSpinner(fmt.Sprintf("Starting container %s", container))
+	err = startContainerAndWait(container)
+	stopSpinner(s)
+
+	if err != nil {
+		return err
+	}
+
+	if err := updateCodeNameConfig(container, workspaceFolder); err != nil {
+		return err
+	}
+
+	if !isCodeDockerPathSet() {
+		showWarning("the Dev Containers extension of VS Code might not find Podman")
+		fmt.Fprintf(os.Stderr, "Set 'dev.containers.dockerPath' to 'podman' in the settings of VS Code.\n")
+	}
+
+	// The Dev Containers extension attaches to containers by their name,
+	// which is hex-encoded into the URI.
+	attachedContainer, err := json.Marshal(map[string]string{"containerName": "/" + container})
+	if err != nil {
+		return fmt.Errorf("failed to encode the name of container %s: %w", container, err)
+	}
+
+	folderURI := fmt.Sprintf("vscode-remote://attached-container+%s%s",
+		hex.EncodeToString(attachedContainer),
+		workspaceFolder)
+
+	logrus.Debugf("Opening %s with %s", folderURI, codeCommand)
+
+	if err := shell.Run(codeCommand, nil, nil, os.Stderr, "--folder-uri", folderURI); err != nil {
+		return fmt.Errorf("failed to open VS Code: %w", err)
+	}
+
+	return nil
+}
+
+func codeHelp(cmd *cobra.Command, args []string) {
+	if utils.IsInsideContainer() {
+		if !utils.IsInsideToolboxContainer() {
+			fmt.Fprintf(os.Stderr, "Error: this is not a Toolbx container\n")
+			return
+		}
+
+		if _, err := utils.ForwardToHost(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return
+		}
+
+		return
+	}
+
+	if err := showManual("toolbox-code"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return
+	}
+}
+
+func getCodeCommand() (string, error) {
+	if path, err := exec.LookPath("code"); err == nil {
+		return path, nil
+	}
+
+	if utils.PathExists(codeApplication) {
+		return codeApplication, nil
+	}
+
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "VS Code not found\n")
+	fmt.Fprintf(&builder, "Install it, or run 'Shell Command: Install 'code' command in PATH' in it.")
+
+	errMsg := builder.String()
+	return "", errors.New(errMsg)
+}
+
+// getCodeWorkspaceFolder returns where dir is inside the container.  A
+// directory that was given, and isn't on the host, is taken to be a path
+// inside the container, while the current directory falls back to the home
+// directory if it's not shared with the container.
+func getCodeWorkspaceFolder(container, dir string, given bool) (string, error) {
+	if given {
+		absDir, err := filepath.Abs(dir)
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve %s: %w", dir, err)
+		}
+
+		if !utils.PathExists(absDir) {
+			if !filepath.IsAbs(dir) {
+				return "", fmt.Errorf("directory %s not found", dir)
+			}
+
+			return dir, nil
+		}
+
+		dir = absDir
+	}
+
+	workspaceFolder := getContainerWorkingDirectory(container, dir)
+	if workspaceFolder != "" {
+		return workspaceFolder, nil
+	}
+
+	if given {
+		return "", fmt.Errorf("directory %s not shared with container %s", dir, container)
+	}
+
+	workspaceFolder = getCurrentUserHomeDir()
+	return workspaceFolder, nil
+}
+
+// isCodeDockerPathSet checks if VS Code was told to use Podman instead of
+// Docker.  The settings allow comments, so they are only searched.
+func isCodeDockerPathSet() bool {
+	settingsFile := filepath.Join(getCurrentUserHomeDir(), codeUserDirectory, "settings.json")
+
+	data, err := os.ReadFile(settingsFile)
+	if err != nil {
+		logrus.Debugf("Reading %s failed: %s", settingsFile, err)
+		return false
+	}
+
+	return bytes.Contains(data, []byte(`"dev.containers.dockerPath"`))
+}
+
+// updateCodeNameConfig updates the configuration of the attached container
+// with the user that Toolbx runs commands as, and the workspace folder.  The
+// rest of it, eg., extensions added from VS Code, is kept.
+func updateCodeNameConfig(container, workspaceFolder string) error {
+	nameConfigsDirectory := filepath.Join(getCurrentUserHomeDir(),
+		codeUserDirectory,
+		"globalStorage",
+		"ms-vscode-remote.remote-containers",
+		"nameConfigs")
+	nameConfigFile := filepath.Join(nameConfigsDirectory, container+".json")
+
+	nameConfig := make(map[string]interface{})
+
+	if data, err := os.ReadFile(nameConfigFile); err == nil {
+		if err := json.Unmarshal(data, &nameConfig); err != nil {
+			logrus.Debugf("Parsing %s failed: %s", nameConfigFile, err)
+			nameConfig = make(map[string]interface{})
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		logrus.Debugf("Reading %s failed: %s", nameConfigFile, err)
+	}
+
+	remoteEnv, _ := nameConfig["remoteEnv"].(map[string]interface{})
+	if remoteEnv == nil {
+		remoteEnv = make(map[string]interface{})
+	}
+
+	remoteEnv["TOOLBOX_NAME"] = container
+
+	nameConfig["remoteEnv"] = remoteEnv
+	nameConfig["remoteUser"] = currentUser.Username
+	nameConfig["workspaceFolder"] = workspaceFolder
+
+	data, err := json.MarshalIndent(nameConfig, "", "\t")
+	if err != nil {
+		return fmt.Errorf("failed to encode the VS Code configuration of container %s: %w", container, err)
+	}
+
+	data = append(data, '\n')
+
+	if err := os.MkdirAll(nameConfigsDirectory, 0755); err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", nameConfigsDirectory, err)
+	}
+
+	if err := renameio.WriteFile(nameConfigFile, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", nameConfigFile, err)
+	}
+
+	logrus.Debugf("Updated %s", nameConfigFile)
+	return nil
+}
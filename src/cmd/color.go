@@ -0,0 +1,81 @@
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/containers/toolbox/pkg/term"
+)
+
+var (
+	colorModes = []string{"auto", "always", "never"}
+)
+
+func setUpColor() error {
+	for _, mode := range colorModes {
+		if rootFlags.color == mode {
+			return nil
+		}
+	}
+
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "invalid argument for '--color': %s\n", rootFlags.color)
+	fmt.Fprintf(&builder, "Supported values are: %s\n", strings.Join(colorModes, ", "))
+	fmt.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+	errMsg := builder.String()
+	return errors.New(errMsg)
+}
+
+// shouldUseColor decides whether colors and spinners can be written to file.
+// With '--color auto', the NO_COLOR, CLICOLOR_FORCE and CLICOLOR conventions
+// are followed, in that order, before falling back to whether file is a
+// terminal.  The '--accessible' option always turns colors off.
+func shouldUseColor(file *os.File) bool {
+	if rootFlags.accessible {
+		return false
+	}
+
+	switch rootFlags.color {
+	case "always":
+		return true
+	case "never":
+		return false
+	}
+
+	if value := os.Getenv("NO_COLOR"); value != "" {
+		return false
+	}
+
+	if value := os.Getenv("CLICOLOR_FORCE"); value != "" && value != "0" {
+		return true
+	}
+
+	if value := os.Getenv("CLICOLOR"); value == "0" {
+		return false
+	}
+
+	if value := os.Getenv("TERM"); value == "dumb" {
+		return false
+	}
+
+	return term.IsTerminal(file)
+}
@@ -17,6 +17,7 @@
 package cmd
 
 import (
+	"io"
 	"os"
 	"strings"
 
@@ -39,15 +40,20 @@ func init() {
 }
 
 func completion(cmd *cobra.Command, args []string) error {
-	switch args[0] {
+	err := generateCompletion(cmd.Root(), args[0], os.Stdout)
+	return err
+}
+
+func generateCompletion(root *cobra.Command, shell string, w io.Writer) error {
+	switch shell {
 	case "bash":
-		err := cmd.Root().GenBashCompletionV2(os.Stdout, true)
+		err := root.GenBashCompletionV2(w, true)
 		return err
 	case "fish":
-		err := cmd.Root().GenFishCompletion(os.Stdout, true)
+		err := root.GenFishCompletion(w, true)
 		return err
 	case "zsh":
-		err := cmd.Root().GenZshCompletion(os.Stdout)
+		err := root.GenZshCompletion(w)
 		return err
 	}
 
@@ -83,11 +89,28 @@ func completionContainerNames(_ *cobra.Command, _ []string, _ string) ([]string,
 	return containerNames, cobra.ShellCompDirectiveNoFileComp
 }
 
-func completionContainerNamesFiltered(cmd *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
-	if cmd.Name() == "enter" && len(args) >= 1 {
+// completionContainerNameFirst completes only the first argument with the
+// names of containers, for commands whose second argument is a new name.
+func completionContainerNameFirst(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) != 0 {
 		return nil, cobra.ShellCompDirectiveNoFileComp
 	}
 
+	return completionContainerNames(cmd, args, toComplete)
+}
+
+func completionContainerNamesFiltered(cmd *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+	switch cmd.Name() {
+	case "enter", "handoff", "logs", "netdump":
+		if len(args) >= 1 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+	case "link":
+		if len(args) >= 2 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+	}
+
 	var containerNames []string
 	if containers, err := getContainers(); err == nil {
 		for _, container := range containers {
@@ -171,6 +194,57 @@ func completionImageNamesFiltered(_ *cobra.Command, args []string, _ string) ([]
 	return imageNames, cobra.ShellCompDirectiveNoFileComp
 }
 
+func completionColorModes(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+	return colorModes, cobra.ShellCompDirectiveNoFileComp
+}
+
+func completionListFilters(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+	var filters []string
+	for _, key := range listFilterKeys {
+		filters = append(filters, key+"=")
+	}
+
+	return filters, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+}
+
+func completionListSortKeys(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+	return listSortKeys, cobra.ShellCompDirectiveNoFileComp
+}
+
+func completionLogFormats(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+	return logFormats, cobra.ShellCompDirectiveNoFileComp
+}
+
 func completionLogLevels(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
 	return []string{"trace", "debug", "info", "warn", "error", "fatal", "panic"}, cobra.ShellCompDirectiveNoFileComp
 }
+
+func completionReleases(cmd *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+	imageFlag := cmd.Flag("image")
+	if imageFlag != nil && imageFlag.Changed {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var distro string
+	if distroFlag := cmd.Flag("distro"); distroFlag != nil {
+		distro = distroFlag.Value.String()
+	}
+
+	var imageNames []string
+	if images, err := getImages(true); err == nil {
+		for _, image := range images {
+			if len(image.Names) != 1 {
+				panic("cannot complete unflattened Image")
+			}
+
+			imageNames = append(imageNames, image.Names[0])
+		}
+	}
+
+	releases, err := utils.GetReleasesForDistro(distro, imageNames)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return releases, cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,188 @@
+//go:build darwin
+
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/containers/toolbox/pkg/shell"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"golang.org/x/sys/unix"
+)
+
+var (
+	completionInstallFlags struct {
+		user bool
+	}
+)
+
+var completionInstallCmd = &cobra.Command{
+	Use:                   "install",
+	Short:                 "Install completion scripts (macOS version)",
+	DisableFlagsInUseLine: true,
+	ValidArgs:             []string{"bash", "fish", "zsh"},
+	Args:                  cobra.OnlyValidArgs,
+	RunE:                  completionInstall,
+}
+
+func init() {
+	flags := completionInstallCmd.Flags()
+
+	flags.BoolVar(&completionInstallFlags.user,
+		"user",
+		false,
+		"Install into the XDG directories in the home directory even if Homebrew is present")
+
+	completionCmd.AddCommand(completionInstallCmd)
+}
+
+// completionInstall writes the completion scripts where the shells on a Mac
+// look for them.  The scripts are the same ones that 'toolbox completion'
+// prints, and they call back into toolbox(1) to complete container and image
+// names, so they don't need to be reinstalled when those change.
+func completionInstall(cmd *cobra.Command, args []string) error {
+	shells := args
+	if len(shells) == 0 {
+		shells = []string{"bash", "fish", "zsh"}
+	}
+
+	var brewPrefix string
+	if !completionInstallFlags.user {
+		brewPrefix = getHomebrewPrefix()
+	}
+
+	for _, shellName := range shells {
+		path, err := getCompletionInstallPath(shellName, brewPrefix)
+		if err != nil {
+			return err
+		}
+
+		if err := writeCompletion(cmd.Root(), shellName, path); err != nil {
+			return err
+		}
+
+		fmt.Printf("Installed %s completion to %s\n", shellName, path)
+
+		if shellName == "zsh" && brewPrefix == "" {
+			dir := filepath.Dir(path)
+			fmt.Printf("Add 'fpath=(%s $fpath)' before 'compinit' in ~/.zshrc if it isn't there already\n", dir)
+		}
+	}
+
+	return nil
+}
+
+// getCompletionInstallPath returns the path for the completion script of
+// shellName.  If brewPrefix is empty, then the XDG Base Directory locations in
+// the home directory are used.
+func getCompletionInstallPath(shellName, brewPrefix string) (string, error) {
+	if brewPrefix != "" {
+		switch shellName {
+		case "bash":
+			return filepath.Join(brewPrefix, "etc", "bash_completion.d", "toolbox"), nil
+		case "fish":
+			return filepath.Join(brewPrefix, "share", "fish", "vendor_completions.d", "toolbox.fish"), nil
+		case "zsh":
+			return filepath.Join(brewPrefix, "share", "zsh", "site-functions", "_toolbox"), nil
+		}
+
+		panic("code should not be reached")
+	}
+
+	homeDir := getCurrentUserHomeDir()
+	if homeDir == "" {
+		return "", errors.New("failed to get the home directory")
+	}
+
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		dataHome = filepath.Join(homeDir, ".local", "share")
+	}
+
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" {
+		configHome = filepath.Join(homeDir, ".config")
+	}
+
+	switch shellName {
+	case "bash":
+		return filepath.Join(dataHome, "bash-completion", "completions", "toolbox"), nil
+	case "fish":
+		return filepath.Join(configHome, "fish", "completions", "toolbox.fish"), nil
+	case "zsh":
+		return filepath.Join(dataHome, "zsh", "site-functions", "_toolbox"), nil
+	}
+
+	panic("code should not be reached")
+}
+
+// getHomebrewPrefix returns the Homebrew prefix, or an empty string if
+// Homebrew isn't installed or its prefix isn't writable by the current user.
+func getHomebrewPrefix() string {
+	prefix := os.Getenv("HOMEBREW_PREFIX")
+	if prefix == "" {
+		var stdout strings.Builder
+		if err := shell.Run("brew", nil, &stdout, nil, "--prefix"); err != nil {
+			logrus.Debugf("Getting the Homebrew prefix failed: %s", err)
+			return ""
+		}
+
+		prefix = strings.TrimSpace(stdout.String())
+	}
+
+	if prefix == "" {
+		return ""
+	}
+
+	if err := unix.Access(prefix, unix.W_OK); err != nil {
+		logrus.Debugf("Homebrew prefix %s is not writable: %s", prefix, err)
+		return ""
+	}
+
+	logrus.Debugf("Homebrew prefix is %s", prefix)
+	return prefix
+}
+
+func writeCompletion(root *cobra.Command, shellName, path string) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		logrus.Debugf("Creating directory %s failed: %s", dir, err)
+		return fmt.Errorf("failed to create directory %s", dir)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		logrus.Debugf("Creating %s failed: %s", path, err)
+		return fmt.Errorf("failed to install %s completion", shellName)
+	}
+
+	defer file.Close()
+
+	if err := generateCompletion(root, shellName, file); err != nil {
+		logrus.Debugf("Generating %s completion failed: %s", shellName, err)
+		return fmt.Errorf("failed to install %s completion", shellName)
+	}
+
+	return nil
+}
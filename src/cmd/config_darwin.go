@@ -0,0 +1,426 @@
+//go:build darwin
+
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/containers/toolbox/pkg/i18n"
+	"github.com/containers/toolbox/pkg/podman"
+	"github.com/containers/toolbox/pkg/utils"
+	"github.com/google/renameio/v2"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// containerSettings are the settings of a container that 'toolbox config'
+// changed after it was created.  Podman can't change the labels of an
+// existing container, so they are kept in a file on the host, which takes
+// precedence over the labels.
+type containerSettings struct {
+	LoginShell *bool  `json:"login-shell,omitempty"`
+	Shell      string `json:"shell,omitempty"`
+}
+
+const (
+	loginShellLabel = "com.github.containers.toolbox.login-shell"
+	shellLabel      = "com.github.containers.toolbox.shell"
+)
+
+var (
+	configFlags struct {
+		unset bool
+	}
+
+	configKeys = []string{"login-shell", "shell"}
+)
+
+var configCmd = &cobra.Command{
+	Use:               "config",
+	Short:             "Show or change the settings of a Toolbx container (macOS version)",
+	RunE:              config,
+	ValidArgsFunction: completionContainerNamesFiltered,
+}
+
+func init() {
+	flags := configCmd.Flags()
+
+	flags.BoolVar(&configFlags.unset,
+		"unset",
+		false,
+		"Go back to the value that the container was created with")
+
+	configCmd.SetHelpFunc(configHelp)
+	rootCmd.AddCommand(configCmd)
+}
+
+func config(cmd *cobra.Command, args []string) error {
+	if utils.IsInsideContainer() {
+		if !utils.IsInsideToolboxContainer() {
+			return errors.New("this is not a Toolbx container")
+		}
+
+		exitCode, err := utils.ForwardToHost()
+		return &exitError{exitCode, err}
+	}
+
+	if len(args) == 0 || len(args) > 3 || (configFlags.unset && len(args) != 2) {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "config needs a container, and optionally a key and a value\n")
+		i18n.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	container := args[0]
+
+	containerObj, err := podman.InspectContainer(container)
+	if err != nil {
+		return createErrorContainerNotFound(container)
+	}
+
+	if !containerObj.IsToolbx() {
+		return fmt.Errorf("%s is not a Toolbx container", container)
+	}
+
+	if err := checkContainerOwner(containerObj); err != nil {
+		return err
+	}
+
+	if len(args) == 1 {
+		for _, key := range configKeys {
+			value := getContainerSetting(containerObj, key)
+			fmt.Printf("%s = %s\n", key, value)
+		}
+
+		return nil
+	}
+
+	key := args[1]
+	if !isConfigKeyValid(key) {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "invalid key %s\n", key)
+		fmt.Fprintf(&builder, "Valid keys are: %s\n", strings.Join(configKeys, ", "))
+		i18n.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	if len(args) == 2 && !configFlags.unset {
+		value := getContainerSetting(containerObj, key)
+		fmt.Println(value)
+		return nil
+	}
+
+	settings := readContainerSettings(containerObj.ID())
+
+	switch key {
+	case "login-shell":
+		settings.LoginShell = nil
+		if !configFlags.unset {
+			loginShell, err := strconv.ParseBool(args[2])
+			if err != nil {
+				return fmt.Errorf("invalid value %s for login-shell: needs to be true or false", args[2])
+			}
+
+			settings.LoginShell = &loginShell
+		}
+	case "shell":
+		settings.Shell = ""
+		if !configFlags.unset {
+			if !filepath.IsAbs(args[2]) {
+				return fmt.Errorf("invalid value %s for shell: needs to be an absolute path", args[2])
+			}
+
+			settings.Shell = args[2]
+		}
+	}
+
+	if err := writeContainerSettings(containerObj.ID(), settings); err != nil {
+		return fmt.Errorf("failed to change the settings of container %s: %w", container, err)
+	}
+
+	return nil
+}
+
+func configHelp(cmd *cobra.Command, args []string) {
+	if utils.IsInsideContainer() {
+		if !utils.IsInsideToolboxContainer() {
+			fmt.Fprintf(os.Stderr, "Error: this is not a Toolbx container\n")
+			return
+		}
+
+		if _, err := utils.ForwardToHost(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return
+		}
+
+		return
+	}
+
+	if err := showManual("toolbox-config"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return
+	}
+}
+
+// forgetContainerSettings removes the settings changed by 'toolbox config'
+// for a container that is gone.
+func forgetContainerSettings(containerObj podman.Container) {
+	settingsFile, err := getContainerSettingsFile(containerObj.ID())
+	if err != nil {
+		return
+	}
+
+	if err := os.Remove(settingsFile); err != nil && !errors.Is(err, os.ErrNotExist) {
+		logrus.Debugf("Removing %s failed: %s", settingsFile, err)
+	}
+}
+
+// getContainerSetting returns the effective value of the setting key of a
+// container: the one set by 'toolbox config', or else the one it was created
+// with, or else the default.
+func getContainerSetting(containerObj podman.Container, key string) string {
+	settings := readContainerSettings(containerObj.ID())
+	labels := containerObj.Labels()
+
+	switch key {
+	case "login-shell":
+		if settings.LoginShell != nil {
+			return strconv.FormatBool(*settings.LoginShell)
+		}
+
+		if loginShell, err := strconv.ParseBool(labels[loginShellLabel]); err == nil {
+			return strconv.FormatBool(loginShell)
+		}
+
+		return "true"
+	case "shell":
+		if settings.Shell != "" {
+			return settings.Shell
+		}
+
+		if shell := labels[shellLabel]; shell != "" {
+			return shell
+		}
+
+		return os.Getenv("SHELL")
+	}
+
+	panicMsg := fmt.Sprintf("unknown setting %s", key)
+	panic(panicMsg)
+}
+
+func getContainerSettingsFile(id string) (string, error) {
+	homeDir := getCurrentUserHomeDir()
+	if homeDir == "" {
+		return "", errors.New("failed to find the home directory")
+	}
+
+	settingsFile := filepath.Join(homeDir, ".local", "share", "toolbox", "config", id+".json")
+	return settingsFile, nil
+}
+
+// getShellArgs returns the '--label' arguments for 'podman create' that
+// record the shell for 'toolbox enter', if one was asked for.
+func getShellArgs() []string {
+	var shellArgs []string
+
+	if createFlags.shell != "" {
+		shellArgs = append(shellArgs, "--label", shellLabel+"="+createFlags.shell)
+	}
+
+	if !createFlags.loginShell {
+		shellArgs = append(shellArgs, "--label", loginShellLabel+"=false")
+	}
+
+	return shellArgs
+}
+
+// getShellCommand returns the command that 'toolbox enter' runs inside the
+// container: the shell from its settings, or the user's shell, as a login
+// shell unless the settings say otherwise.  A container that doesn't exist
+// yet gets the defaults.
+func getShellCommand(container, userShell string) []string {
+	containerObj, err := podman.InspectContainer(container)
+	if err != nil {
+		logrus.Debugf("Inspecting container %s failed: %s", container, err)
+		return []string{userShell, "-l"}
+	}
+
+	shell := getContainerSetting(containerObj, "shell")
+	if shell == "" {
+		shell = userShell
+	}
+
+	if getContainerSetting(containerObj, "login-shell") == "false" {
+		return []string{shell}
+	}
+
+	return []string{shell, "-l"}
+}
+
+func isConfigKeyValid(key string) bool {
+	for _, configKey := range configKeys {
+		if key == configKey {
+			return true
+		}
+	}
+
+	return false
+}
+
+func readContainerSettings(id string) containerSettings {
+	var settings containerSettings
+
+	settingsFile, err := getContainerSettingsFile(id)
+	if err != nil {
+		logrus.Debugf("Reading the settings of container %s: %s", id, err)
+		return settings
+	}
+
+	data, err := os.ReadFile(settingsFile)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			logrus.Debugf("Reading %s failed: %s", settingsFile, err)
+		}
+
+		return settings
+	}
+
+	if err := json.Unmarshal(data, &settings); err != nil {
+		logrus.Debugf("Parsing %s failed: %s", settingsFile, err)
+	}
+
+	return settings
+}
+
+// transferContainerSettings keeps the settings changed by 'toolbox config'
+// after a container was recreated with the same name, eg., by 'toolbox cap
+// set', because they go by the ID of the container.
+func transferContainerSettings(oldID, container string) {
+	oldSettingsFile, err := getContainerSettingsFile(oldID)
+	if err != nil {
+		logrus.Debugf("Transferring the settings of container %s: %s", container, err)
+		return
+	}
+
+	if _, err := os.Stat(oldSettingsFile); err != nil {
+		return
+	}
+
+	containerObj, err := podman.InspectContainer(container)
+	if err != nil {
+		logrus.Debugf("Inspecting container %s failed: %s", container, err)
+		return
+	}
+
+	settingsFile, err := getContainerSettingsFile(containerObj.ID())
+	if err != nil {
+		logrus.Debugf("Transferring the settings of container %s: %s", container, err)
+		return
+	}
+
+	if err := os.Rename(oldSettingsFile, settingsFile); err != nil {
+		logrus.Debugf("Renaming %s to %s failed: %s", oldSettingsFile, settingsFile, err)
+		showWarning("the settings of container %s were reset", container)
+	}
+}
+
+// copyContainerSettings gives the new container the settings from 'toolbox
+// config' of the original, which go by the ID of the container.
+func copyContainerSettings(id, newContainer string) {
+	settingsFile, err := getContainerSettingsFile(id)
+	if err != nil {
+		logrus.Debugf("Copying the settings to container %s: %s", newContainer, err)
+		return
+	}
+
+	data, err := os.ReadFile(settingsFile)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			logrus.Debugf("Reading %s failed: %s", settingsFile, err)
+		}
+
+		return
+	}
+
+	containerObj, err := podman.InspectContainer(newContainer)
+	if err != nil {
+		logrus.Debugf("Inspecting container %s failed: %s", newContainer, err)
+		return
+	}
+
+	newSettingsFile, err := getContainerSettingsFile(containerObj.ID())
+	if err != nil {
+		logrus.Debugf("Copying the settings to container %s: %s", newContainer, err)
+		return
+	}
+
+	if err := os.MkdirAll(filepath.Dir(newSettingsFile), 0755); err != nil {
+		logrus.Debugf("Creating directory %s failed: %s", filepath.Dir(newSettingsFile), err)
+		return
+	}
+
+	if err := renameio.WriteFile(newSettingsFile, data, 0644); err != nil {
+		logrus.Debugf("Writing %s failed: %s", newSettingsFile, err)
+		showWarning("the settings of container %s were not copied", newContainer)
+	}
+}
+
+// writeContainerSettings removes the file if nothing is left in it, so that
+// the container goes back to the settings it was created with.
+func writeContainerSettings(id string, settings containerSettings) error {
+	settingsFile, err := getContainerSettingsFile(id)
+	if err != nil {
+		return err
+	}
+
+	if settings == (containerSettings{}) {
+		if err := os.Remove(settingsFile); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+
+		return nil
+	}
+
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return err
+	}
+
+	settingsDir := filepath.Dir(settingsFile)
+	if err := os.MkdirAll(settingsDir, 0755); err != nil {
+		return err
+	}
+
+	if err := renameio.WriteFile(settingsFile, data, 0644); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -54,6 +54,8 @@ var (
 		authFile  string
 		container string
 		distro    string
+		env       []string
+		envFile   []string
 		image     string
 		release   string
 	}
@@ -94,6 +96,17 @@ func init() {
 		"",
 		"Create a Toolbx container for a different operating system distribution than the host")
 
+	flags.StringArrayVarP(&createFlags.env,
+		"env",
+		"e",
+		nil,
+		"Set this environment variable in the Toolbx container, as KEY=VALUE or KEY to use the value from the host")
+
+	flags.StringArrayVar(&createFlags.envFile,
+		"env-file",
+		nil,
+		"Set the environment variables in this file in the Toolbx container")
+
 	flags.StringVarP(&createFlags.image,
 		"image",
 		"i",
@@ -118,6 +131,11 @@ func init() {
 		panic(panicMsg)
 	}
 
+	if err := createCmd.RegisterFlagCompletionFunc("release", completionReleases); err != nil {
+		panicMsg := fmt.Sprintf("failed to register flag completion function: %v", err)
+		panic(panicMsg)
+	}
+
 	rootCmd.AddCommand(createCmd)
 }
 
@@ -243,6 +261,16 @@ func createContainer(container, image, release, authFile string, showCommandToEn
 		}
 	}
 
+	environ, err := utils.GetEnvironmentFromOptions(createFlags.env, createFlags.envFile)
+	if err != nil {
+		return err
+	}
+
+	var envOptions []string
+	for _, env := range environ {
+		envOptions = append(envOptions, "--env", env)
+	}
+
 	var toolbxDelayEntryPointEnv []string
 
 	if toolbxDelayEntryPoint, ok := os.LookupEnv("TOOLBX_DELAY_ENTRY_POINT"); ok {
@@ -439,6 +467,8 @@ func createContainer(container, image, release, authFile string, showCommandToEn
 		"--env", toolboxPathEnvArg,
 	}...)
 
+	createArgs = append(createArgs, envOptions...)
+
 	createArgs = append(createArgs, xdgRuntimeDirEnv...)
 
 	createArgs = append(createArgs, []string{
@@ -488,7 +518,7 @@ func createContainer(container, image, release, authFile string, showCommandToEn
 	}
 
 	s := spinner.New(spinner.CharSets[9], 500*time.Millisecond, spinner.WithWriterFile(os.Stdout))
-	if logLevel := logrus.GetLevel(); logLevel < logrus.DebugLevel {
+	if terminalLogLevel < logrus.DebugLevel && shouldUseColor(os.Stdout) && !rootFlags.quiet {
 		s.Prefix = fmt.Sprintf("Creating container %s: ", container)
 		s.Start()
 		defer s.Stop()
@@ -501,7 +531,7 @@ func createContainer(container, image, release, authFile string, showCommandToEn
 	// The spinner must be stopped before showing the 'enter' hint below.
 	s.Stop()
 
-	if showCommandToEnter {
+	if showCommandToEnter && !rootFlags.quiet {
 		fmt.Printf("Created container: %s\n", container)
 		fmt.Printf("Enter with: %s\n", enterCommand)
 	}
@@ -736,7 +766,7 @@ func pullImage(image, release, authFile string) (bool, error) {
 
 	logrus.Debugf("Pulling image %s", imageFull)
 
-	if logLevel := logrus.GetLevel(); logLevel < logrus.DebugLevel {
+	if terminalLogLevel < logrus.DebugLevel && shouldUseColor(os.Stdout) && !rootFlags.quiet {
 		s := spinner.New(spinner.CharSets[9], 500*time.Millisecond, spinner.WithWriterFile(os.Stdout))
 		s.Prefix = fmt.Sprintf("Pulling %s: ", imageFull)
 		s.Start()
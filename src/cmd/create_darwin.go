@@ -19,15 +19,22 @@
 package cmd
 
 import (
+	"bufio"
 	"context"
 	"errors"
 	"fmt"
 	"os"
+	"os/user"
 	"path/filepath"
+	"runtime"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/briandowns/spinner"
+	"github.com/containers/toolbox/pkg/i18n"
 	"github.com/containers/toolbox/pkg/podman"
 	"github.com/containers/toolbox/pkg/shell"
 	"github.com/containers/toolbox/pkg/skopeo"
@@ -36,6 +43,7 @@ import (
 	"github.com/docker/go-units"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 type promptForDownloadError struct {
@@ -48,13 +56,66 @@ const (
 	alphanum = alpha + num
 )
 
+const (
+	// nativeImageLookupTimeout limits how long the registry is asked for
+	// an arm64 version of an image that would run under emulation.
+	nativeImageLookupTimeout = 15 * time.Second
+
+	// podmanSocketPath is where '--podman-socket' puts the API socket of
+	// Podman inside the container, as in a rootful Podman.
+	podmanSocketPath = "/run/podman/podman.sock"
+
+	workspaceDirectory = "/workspace"
+)
+
 var (
 	createFlags struct {
-		authFile  string
-		container string
-		distro    string
-		image     string
-		release   string
+		authFile         string
+		capAdd           []string
+		capDrop          []string
+		container        string
+		credentials      []string
+		distro           string
+		dns              []string
+		dotfiles         bool
+		dnsSearch        []string
+		entitlement      string
+		env              []string
+		envFile          []string
+		fromDevcontainer string
+		gpu              bool
+		image            string
+		loginShell       bool
+		nativeArch       bool
+		nested           bool
+		network          string
+		offline          bool
+		networkFrom      string
+		owner            string
+		podmanSocket     bool
+		publish          []string
+		release          string
+		securityOpt      []string
+		secrets          []string
+		shell            string
+		ssh              bool
+		workspaceVolume  bool
+
+		// publishFromContainer makes getPublishArgs use publish even
+		// if it's empty, instead of 'publish' in the configuration, so
+		// that 'toolbox cap set' keeps the ports of a container as
+		// they were.  It's not an option of 'toolbox create'.
+		publishFromContainer bool
+
+		// volumes are extra bind mounts, as HOST:CONTAINER[:ro], that
+		// 'toolbox migrate-from' brings over from Linux.  They are not
+		// an option of 'toolbox create'.
+		volumes []string
+
+		// workspaceVolumeName overrides the name of the workspace
+		// volume, so that 'toolbox cap set' keeps using the volume of
+		// a container that was renamed with 'toolbox rename'.
+		workspaceVolumeName string
 	}
 
 	createToolboxShMounts = []struct {
@@ -75,7 +136,7 @@ var createCmd = &cobra.Command{
 
 func init() {
 	rootCmd.AddCommand(createCmd)
-	
+
 	flags := createCmd.Flags()
 
 	flags.StringVar(&createFlags.authFile,
@@ -83,29 +144,188 @@ func init() {
 		"",
 		"Path to a file with credentials for authenticating to the registry for private images")
 
+	flags.StringSliceVar(&createFlags.capAdd,
+		"cap-add",
+		nil,
+		"Give the Toolbx container this capability, besides SYS_PTRACE")
+
+	flags.StringSliceVar(&createFlags.capDrop,
+		"cap-drop",
+		nil,
+		"Take this capability away from the Toolbx container")
+
 	flags.StringVarP(&createFlags.container,
 		"container",
 		"c",
 		"",
 		"Assign a different name to the Toolbx container")
 
+	flags.StringSliceVar(&createFlags.credentials,
+		"credentials",
+		nil,
+		"Share the configuration of this cloud SDK (aws, azure, gcloud) read-only with the Toolbx container")
+
 	flags.StringVarP(&createFlags.distro,
 		"distro",
 		"d",
 		"",
 		"Create a Toolbx container for a different operating system distribution than the host")
 
+	flags.StringSliceVar(&createFlags.dns,
+		"dns",
+		nil,
+		"Use this name server instead of the Podman machine's and the host's")
+
+	flags.StringSliceVar(&createFlags.dnsSearch,
+		"dns-search",
+		nil,
+		"Use this search domain instead of the Podman machine's and the host's")
+
+	flags.BoolVar(&createFlags.dotfiles,
+		"dotfiles",
+		true,
+		"Set up the dotfiles in the Toolbx container, as configured in toolbox.conf")
+
+	flags.StringVar(&createFlags.entitlement,
+		"entitlement",
+		"",
+		"Share the RHEL entitlement certificates in this directory with the Toolbx container")
+
+	flags.StringArrayVarP(&createFlags.env,
+		"env",
+		"e",
+		nil,
+		"Set this environment variable in the Toolbx container, as KEY=VALUE or KEY to use the value from the host")
+
+	flags.StringArrayVar(&createFlags.envFile,
+		"env-file",
+		nil,
+		"Set the environment variables in this file in the Toolbx container")
+
+	flags.StringVar(&createFlags.fromDevcontainer,
+		"from-devcontainer",
+		"",
+		"Create the Toolbx container described by this devcontainer.json file")
+
+	flags.BoolVar(&createFlags.gpu,
+		"gpu",
+		false,
+		"Give the Toolbx container the GPU of the Podman machine (experimental)")
+
 	flags.StringVarP(&createFlags.image,
 		"image",
 		"i",
 		"",
 		"Change the name of the base image used to create the Toolbx container")
 
+	flags.BoolVar(&createFlags.loginShell,
+		"login-shell",
+		true,
+		"Make 'toolbox enter' start the shell as a login shell")
+
+	flags.BoolVar(&createFlags.nativeArch,
+		"native-arch",
+		false,
+		"Refuse images that would run under emulation on an Apple silicon Mac")
+
+	flags.BoolVar(&createFlags.nested,
+		"nested",
+		false,
+		"Set up the Toolbx container to run podman and buildah inside it")
+
+	flags.StringVar(&createFlags.network,
+		"network",
+		"slirp4netns",
+		"Connect the Toolbx container to this Podman network, eg., bridge for 'toolbox link'")
+
+	flags.StringVar(&createFlags.networkFrom,
+		"network-from",
+		"",
+		"Share the network of this Toolbx container, so that both can talk over localhost")
+
+	flags.BoolVar(&createFlags.offline,
+		"offline",
+		false,
+		"Never pull images, and only use those that were pulled or loaded before")
+
+	flags.StringVar(&createFlags.owner,
+		"owner",
+		"",
+		"Provision the Toolbx container for another user of the shared Podman machine")
+
+	flags.BoolVar(&createFlags.podmanSocket,
+		"podman-socket",
+		false,
+		"Share the Podman machine's API socket, so that podman and docker work inside the Toolbx container")
+
+	flags.StringArrayVarP(&createFlags.publish,
+		"publish",
+		"p",
+		nil,
+		"Make this port of the Toolbx container reachable at localhost on macOS, eg., 8080 or 8000:80")
+
 	flags.StringVarP(&createFlags.release,
 		"release",
 		"r",
 		"",
 		"Create a Toolbx container for a different operating system release than the host")
+
+	flags.StringArrayVar(&createFlags.secrets,
+		"secret",
+		nil,
+		"Inject this secret from the keychain, as NAME[=VARIABLE|=/PATH], whenever the Toolbx container is used")
+
+	flags.StringSliceVar(&createFlags.securityOpt,
+		"security-opt",
+		nil,
+		"Set this security option of the Toolbx container, besides label=disable")
+
+	flags.StringVar(&createFlags.shell,
+		"shell",
+		"",
+		"Make 'toolbox enter' start this shell, instead of the user's shell on the host")
+
+	flags.BoolVar(&createFlags.ssh,
+		"ssh",
+		false,
+		"Set up the OpenSSH server inside the Toolbx container for 'toolbox ssh-config'")
+
+	flags.BoolVar(&createFlags.workspaceVolume,
+		"workspace-volume",
+		false,
+		"Mount a case-sensitive named volume at /workspace inside the Toolbx container")
+
+	createCmd.SetHelpFunc(createHelp)
+
+	if err := createCmd.RegisterFlagCompletionFunc("credentials", completionCredentialsProfiles); err != nil {
+		panicMsg := fmt.Sprintf("failed to register flag completion function: %v", err)
+		panic(panicMsg)
+	}
+
+	if err := createCmd.RegisterFlagCompletionFunc("distro", completionDistroNames); err != nil {
+		panicMsg := fmt.Sprintf("failed to register flag completion function: %v", err)
+		panic(panicMsg)
+	}
+
+	if err := createCmd.RegisterFlagCompletionFunc("image", completionImageNames); err != nil {
+		panicMsg := fmt.Sprintf("failed to register flag completion function: %v", err)
+		panic(panicMsg)
+	}
+
+	if err := createCmd.RegisterFlagCompletionFunc("network-from", completionContainerNames); err != nil {
+		panicMsg := fmt.Sprintf("failed to register flag completion function: %v", err)
+		panic(panicMsg)
+	}
+
+	if err := createCmd.RegisterFlagCompletionFunc("release", completionReleases); err != nil {
+		panicMsg := fmt.Sprintf("failed to register flag completion function: %v", err)
+		panic(panicMsg)
+	}
+
+	if err := createCmd.RegisterFlagCompletionFunc("secret", completionSecretNames); err != nil {
+		panicMsg := fmt.Sprintf("failed to register flag completion function: %v", err)
+		panic(panicMsg)
+	}
 }
 
 func (err promptForDownloadError) Error() string {
@@ -117,10 +337,68 @@ func create(cmd *cobra.Command, args []string) error {
 		return errors.New("create is not supported inside a container")
 	}
 
+	if createFlags.networkFrom != "" {
+		conflicts := []string{"dns", "dns-search", "network", "publish"}
+		for _, conflict := range conflicts {
+			if !cmd.Flag(conflict).Changed {
+				continue
+			}
+
+			var builder strings.Builder
+			fmt.Fprintf(&builder, "options --network-from and --%s cannot be used together\n", conflict)
+			i18n.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+			errMsg := builder.String()
+			return errors.New(errMsg)
+		}
+	}
+
+	if createFlags.shell != "" && !filepath.IsAbs(createFlags.shell) {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "invalid argument for '--shell': %s\n", createFlags.shell)
+		fmt.Fprintf(&builder, "The shell must be an absolute path.\n")
+		i18n.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	if err := checkCredentialsFlag(); err != nil {
+		return err
+	}
+
+	if err := checkSecretOptions(createFlags.secrets); err != nil {
+		return err
+	}
+
+	if createFlags.fromDevcontainer != "" {
+		return createFromDevcontainer(cmd, createFlags.fromDevcontainer)
+	}
+
+	distro := createFlags.distro
+	release := createFlags.release
+
+	if shouldChooseDistroAndRelease() {
+		var err error
+		distro, release, err = chooseDistroAndRelease()
+		if err != nil {
+			return err
+		}
+	}
+
+	image := createFlags.image
+	if path, ok := getImageArchivePath(image); ok {
+		var err error
+		image, err = loadImageForCreate(path)
+		if err != nil {
+			return err
+		}
+	}
+
 	container, image, release, err := utils.ResolveContainerAndImageNames(createFlags.container,
-		createFlags.distro,
-		createFlags.image,
-		createFlags.release)
+		distro,
+		image,
+		release)
 	if err != nil {
 		return err
 	}
@@ -132,6 +410,124 @@ func create(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// chooseDistroAndRelease asks which distro and release to create the
+// container for, because unlike a Linux host, a Mac gives no hint.  The
+// default release and the ones that were downloaded before are offered, with
+// the size of the image that would be downloaded.
+func chooseDistroAndRelease() (string, string, error) {
+	_, defaultImage, _, err := utils.ResolveContainerAndImageNames("", "", "", "")
+	if err != nil {
+		return "", "", err
+	}
+
+	defaultDistro, _ := utils.GetDistroAndReleaseForImage(defaultImage)
+
+	policy, err := getPolicy()
+	if err != nil {
+		return "", "", err
+	}
+
+	distros := policy.filterDistros(utils.GetSupportedDistros())
+	if len(distros) == 0 {
+		return "", "", fmt.Errorf("no distros are allowed by the policy in %s", policy.source)
+	}
+
+	sort.Strings(distros)
+
+	defaultDistroIndex := 0
+	var distroOptions []string
+
+	for i, distro := range distros {
+		option := distro
+		if distro == defaultDistro {
+			defaultDistroIndex = i
+			option += " (default)"
+		}
+
+		distroOptions = append(distroOptions, option)
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+
+	fmt.Printf("Which distro should the Toolbx container be for?\n")
+	distroIndex, err := readChoice(reader, distroOptions, defaultDistroIndex)
+	if err != nil {
+		return "", "", err
+	}
+
+	distro := distros[distroIndex]
+
+	var imageNames []string
+	if images, err := getImages(true); err == nil {
+		for _, image := range images {
+			imageNames = append(imageNames, image.Names...)
+		}
+	}
+
+	releases, err := utils.GetReleasesForDistro(distro, imageNames)
+	if err != nil {
+		return "", "", err
+	}
+
+	var releaseOptions []string
+
+	for i, release := range releases {
+		option := release + " (" + getReleaseImageSize(distro, release) + ")"
+		if i == 0 {
+			option += ", default"
+		}
+
+		releaseOptions = append(releaseOptions, option)
+	}
+
+	releaseOptions = append(releaseOptions, "another release")
+
+	fmt.Printf("Which release of %s?\n", distro)
+	releaseIndex, err := readChoice(reader, releaseOptions, 0)
+	if err != nil {
+		return "", "", err
+	}
+
+	if releaseIndex < len(releases) {
+		release := releases[releaseIndex]
+		return distro, release, nil
+	}
+
+	for {
+		fmt.Printf("Release: ")
+
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return "", "", errors.New("no release chosen")
+		}
+
+		if release := strings.TrimSpace(line); release != "" {
+			return distro, release, nil
+		}
+	}
+}
+
+func createHelp(cmd *cobra.Command, args []string) {
+	if utils.IsInsideContainer() {
+		if !utils.IsInsideToolboxContainer() {
+			fmt.Fprintf(os.Stderr, "Error: this is not a Toolbx container\n")
+			return
+		}
+
+		if _, err := utils.ForwardToHost(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return
+		}
+
+		return
+	}
+
+	if err := showManual("toolbox-create"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return
+	}
+}
+
 func createContainer(container, image, release, authFile string, showCommandToEnter bool) error {
 	if container == "" {
 		panic("container not specified")
@@ -147,6 +543,20 @@ func createContainer(container, image, release, authFile string, showCommandToEn
 		return fmt.Errorf("container %s already exists", container)
 	}
 
+	image, err := resolveImageAlias(image, release)
+	if err != nil {
+		return err
+	}
+
+	policy, err := getPolicy()
+	if err != nil {
+		return err
+	}
+
+	if err := policy.checkImage(image, release); err != nil {
+		return err
+	}
+
 	// Check if image exists locally, pull if not
 	if imageExists, _ := podman.ImageExists(image); !imageExists {
 		if err := pullImage(image, authFile); err != nil {
@@ -156,41 +566,242 @@ func createContainer(container, image, release, authFile string, showCommandToEn
 
 	// Validate it's a toolbox image
 	if _, err := podman.IsToolboxImage(image); err != nil {
-		fmt.Fprintf(os.Stderr, "WARNING: %s\n", err)
+		if err := warnOrFail(err); err != nil {
+			return err
+		}
 	}
 
+	if err := checkImageArchitecture(image); err != nil {
+		return err
+	}
+
+	privileges := defaultContainerPrivileges()
+	privileges.update(createFlags.capAdd, createFlags.capDrop, createFlags.securityOpt, nil)
+
 	// Create the container with macOS-specific options
-	if err := createContainerWithMacOSOptions(container, image, release); err != nil {
+	if err := createContainerWithMacOSOptions(container, image, release, privileges); err != nil {
+		return err
+	}
+
+	if err := copyToolboxSh(container); err != nil {
+		return err
+	}
+
+	if err := bootstrapDotfiles(container); err != nil {
+		return err
+	}
+
+	if err := provisionSSH(container); err != nil {
+		return err
+	}
+
+	if err := setUpSubscription(container, image, showCommandToEnter); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func createContainerWithMacOSOptions(container, image, release string) error {
+// checkImageArchitecture points out images for amd64 on an Apple silicon Mac,
+// because they run 5 to 10 times slower under emulation, and nothing else
+// tells users why.  With '--native-arch', or 'native-arch = true' in the
+// general section of the configuration, they are refused.
+func checkImageArchitecture(image string) error {
+	if runtime.GOARCH != "arm64" {
+		return nil
+	}
+
+	info, err := podman.InspectImage(image)
+	if err != nil {
+		logrus.Debugf("Inspecting image %s failed: %s", image, err)
+		return nil
+	}
+
+	architecture, _ := info["Architecture"].(string)
+	if architecture == "" || architecture == "arm64" {
+		return nil
+	}
+
+	logrus.Debugf("Image %s is for %s, and would run under emulation", image, architecture)
+
+	var nativeImage string
+	if !isOffline() {
+		s := showSpinner(fmt.Sprintf("Looking for an arm64 version of %s", image))
+		nativeImage = getNativeImage(image)
+		stopSpinner(s)
+	}
+
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "image %s is for %s, and runs 5 to 10 times slower under emulation on this Mac\n",
+		image,
+		architecture)
+
+	switch nativeImage {
+	case "":
+		fmt.Fprintf(&builder, "Look for an arm64 or multi-architecture version of it.")
+	case image:
+		fmt.Fprintf(&builder, "Pull it for arm64 with: podman pull --arch arm64 %s", image)
+	default:
+		fmt.Fprintf(&builder, "Use %s instead.", nativeImage)
+	}
+
+	errMsg := builder.String()
+	err = errors.New(errMsg)
+
+	if createFlags.nativeArch || viper.GetBool("general.native-arch") {
+		return err
+	}
+
+	return warnOrFail(err)
+}
+
+// copyToolboxSh copies toolbox.sh into the container, because it's usually
+// installed outside the directories that the Podman machine shares with the
+// host, which rules out a bind mount.  Without it the container works, but
+// without the prompt and the welcome message, so it's only an error with
+// --strict.
+func copyToolboxSh(container string) error {
+	logrus.Debug("Looking up toolbox.sh")
+
+	sources := []string{
+		filepath.Join(filepath.Dir(executable), "..", "share", "profile.d", "toolbox.sh"),
+	}
+
+	for _, mount := range createToolboxShMounts {
+		sources = append(sources, mount.source)
+	}
+
+	for _, source := range sources {
+		if !utils.PathExists(source) {
+			continue
+		}
+
+		logrus.Debugf("Found %s", source)
+
+		if err := podman.CopyToContainer(source, container, "/etc/profile.d/toolbox.sh"); err != nil {
+			logrus.Debugf("Copying %s into container %s failed: %s", source, container, err)
+			err := fmt.Errorf("failed to copy toolbox.sh into container %s", container)
+			return warnOrFail(err)
+		}
+
+		return nil
+	}
+
+	logrus.Debug("toolbox.sh not found")
+	if rootFlags.strict {
+		return errors.New("failed to find toolbox.sh")
+	}
+
+	return nil
+}
+
+func createContainerWithMacOSOptions(container, image, release string, privileges containerPrivileges) error {
 	logrus.Debugf("Creating container %s with macOS-specific options", container)
 
+	owner, err := getContainerOwner()
+	if err != nil {
+		return err
+	}
+
+	usernsArgs, err := getUsernsArgs(owner)
+	if err != nil {
+		return err
+	}
+
+	dnsArgs, err := getDNSArgs()
+	if err != nil {
+		return err
+	}
+
+	environ, err := utils.GetEnvironmentFromOptions(createFlags.env, createFlags.envFile)
+	if err != nil {
+		return err
+	}
+
+	networkArgs, err := getNetworkArgs(container)
+	if err != nil {
+		return err
+	}
+
+	publishArgs, err := getPublishArgs()
+	if err != nil {
+		return err
+	}
+
+	podmanSocketArgs, err := getPodmanSocketArgs()
+	if err != nil {
+		return err
+	}
+
+	credentialsArgs, err := getCredentialsArgs()
+	if err != nil {
+		return err
+	}
+
+	sshAgentArgs, err := getSSHAgentArgs()
+	if err != nil {
+		return err
+	}
+
+	secretArgs, err := getSecretArgs()
+	if err != nil {
+		return err
+	}
+
+	entitlementArgs, err := getEntitlementArgs(image)
+	if err != nil {
+		return err
+	}
+
+	gpuArgs, err := getGPUArgs()
+	if err != nil {
+		return err
+	}
+
 	logLevelString := podman.LogLevel.String()
 
 	// Basic container creation arguments for macOS
 	createArgs := []string{
 		"--log-level", logLevelString,
 		"create",
-		"--dns", "none",
-		"--hostname", container,
 		"--interactive",
+		"--label", "com.github.containers.toolbox=true",
 		"--name", container,
-		"--network", "slirp4netns",
 		"--tty",
 		"--user", "root:root",
 	}
 
+	createArgs = append(createArgs, dnsArgs...)
+
+	for _, env := range environ {
+		createArgs = append(createArgs, "--env", env)
+	}
+
+	createArgs = append(createArgs, networkArgs...)
+	createArgs = append(createArgs, usernsArgs...)
+	createArgs = append(createArgs, publishArgs...)
+	createArgs = append(createArgs, podmanSocketArgs...)
+	createArgs = append(createArgs, getNestedArgs(container)...)
+	createArgs = append(createArgs, gpuArgs...)
+	createArgs = append(createArgs, credentialsArgs...)
+	createArgs = append(createArgs, sshAgentArgs...)
+	createArgs = append(createArgs, secretArgs...)
+	createArgs = append(createArgs, entitlementArgs...)
+	createArgs = append(createArgs, getOwnerLabelArgs(owner)...)
+	createArgs = append(createArgs, getShellArgs()...)
+
 	// macOS-specific volume mounts (simplified for compatibility)
 	// Note: On macOS, containers run in VMs so mount options are limited
 	homeDir := os.Getenv("HOME")
+	if owner != currentUser {
+		homeDir = owner.HomeDir
+	}
+
 	if homeDir != "" {
 		homeDirMountArg := fmt.Sprintf("%s:%s", homeDir, homeDir)
 		createArgs = append(createArgs, "--volume", homeDirMountArg)
+	} else if rootFlags.strict {
+		return errors.New("failed to find the home directory to share with the container")
 	}
 
 	// Mount some common macOS directories if they exist (simplified mounts)
@@ -211,11 +822,28 @@ func createContainerWithMacOSOptions(container, image, release string) error {
 		}
 	}
 
-	// Simplified security options for macOS compatibility
-	createArgs = append(createArgs,
-		"--cap-add", "SYS_PTRACE",
-		"--security-opt", "label=disable",
-	)
+	for _, volume := range createFlags.volumes {
+		createArgs = append(createArgs, "--volume", volume)
+	}
+
+	var workspaceVolumeArg []string
+
+	if createFlags.workspaceVolume {
+		workspaceVolume := createFlags.workspaceVolumeName
+		if workspaceVolume == "" {
+			workspaceVolume = getWorkspaceVolumeName(container)
+		}
+
+		logrus.Debugf("Mounting named volume %s at %s", workspaceVolume, workspaceDirectory)
+
+		workspaceVolumeMountArg := workspaceVolume + ":" + workspaceDirectory
+		createArgs = append(createArgs, "--volume", workspaceVolumeMountArg)
+		workspaceVolumeArg = []string{"--workspace-volume"}
+	} else if homeDir != "" {
+		warnIfCaseInsensitive(homeDir)
+	}
+
+	createArgs = append(createArgs, privileges.getCreateArgs()...)
 
 	// Mount the toolbox binary into the container so init-container can run
 	// Dynamically determine where the current toolbox binary is located
@@ -224,35 +852,358 @@ func createContainerWithMacOSOptions(container, image, release string) error {
 		if resolvedExecutable, err := filepath.EvalSymlinks(executable); err == nil {
 			executable = resolvedExecutable
 		}
-		
+
 		logrus.Debugf("Mounting toolbox binary from %s to /usr/bin/toolbox in container", executable)
 		toolboxMountArg := fmt.Sprintf("%s:/usr/bin/toolbox:ro", executable)
 		createArgs = append(createArgs, "--volume", toolboxMountArg)
 	} else {
-		logrus.Warnf("Could not determine toolbox binary location: %v", err)
+		logrus.Debugf("Resolving the path to the toolbox binary failed: %s", err)
+		if err := warnOrFail(errors.New("failed to find the toolbox binary to share with the container")); err != nil {
+			return err
+		}
 	}
 
 	// Add the image
 	createArgs = append(createArgs, image)
 
+	userShell := createFlags.shell
+	if userShell == "" {
+		userShell = getOwnerShell(owner)
+	}
+
 	// Add initialization command
-	createArgs = append(createArgs, "toolbox", "init-container",
-		"--user", os.Getenv("USER"),
-		"--uid", fmt.Sprintf("%d", os.Getuid()),
-		"--gid", fmt.Sprintf("%d", os.Getgid()),
+	createArgs = append(createArgs, "toolbox", "--log-level", "debug", "init-container",
+		"--user", owner.Username,
+		"--uid", owner.Uid,
+		"--gid", owner.Gid,
 		"--home", homeDir,
-		"--shell", os.Getenv("SHELL"))
+		"--monitor-host",
+		"--shell", userShell)
+
+	createArgs = append(createArgs, getGroupArgs(owner)...)
+	createArgs = append(createArgs, workspaceVolumeArg...)
+
+	if createFlags.nested {
+		createArgs = append(createArgs, "--nested")
+	}
+
+	for _, env := range utils.GetHostLocaleEnvironment() {
+		if lang, found := strings.CutPrefix(env, "LANG="); found {
+			createArgs = append(createArgs, "--locale", lang)
+			break
+		}
+	}
+
+	if timeZone, err := utils.GetHostTimeZone(); err != nil {
+		logrus.Debugf("Getting the host's time zone failed: %s", err)
+		if rootFlags.strict {
+			return errors.New("failed to get the host's time zone")
+		}
+	} else {
+		createArgs = append(createArgs, "--timezone", timeZone)
+	}
 
 	logrus.Debug("Creating container:")
-	logrus.Debugf("Full podman create command: podman %s", strings.Join(createArgs, " "))
+	logrus.Debugf("Full podman create command: podman %s", strings.Join(utils.RedactEnvOptions(createArgs), " "))
 
-	if err := shell.Run("podman", nil, nil, nil, createArgs...); err != nil {
+	s := showSpinner(fmt.Sprintf("Creating container %s", container))
+	err = shell.Run("podman", nil, nil, nil, createArgs...)
+	stopSpinner(s)
+
+	if err != nil {
 		return fmt.Errorf("failed to create container %s: %w", container, err)
 	}
 
 	return nil
 }
 
+// getGroupArgs returns the '--group' arguments for 'toolbox init-container',
+// so that the user is a member of the same groups of macOS inside the
+// container, with the same GIDs, and files owned by them in the shared
+// directories stay accessible.  Only the groups listed in 'groups' in the
+// general section of the configuration are mapped, because macOS has many
+// groups, like everyone and com.apple.access_ssh, that mean nothing inside a
+// container.  The primary group is already taken care of by '--gid'.
+func getGroupArgs(owner *user.User) []string {
+	groupNames := []string{"admin", "staff"}
+	if viper.IsSet("general.groups") {
+		groupNames = viper.GetStringSlice("general.groups")
+	}
+
+	if len(groupNames) == 0 {
+		return nil
+	}
+
+	hostGroups, err := utils.GetHostGroups(owner.Username)
+	if err != nil {
+		logrus.Debugf("Getting the groups of user %s failed: %s", owner.Username, err)
+		return nil
+	}
+
+	var groupArgs []string
+
+	for _, hostGroup := range hostGroups {
+		if fmt.Sprint(hostGroup.GID) == owner.Gid {
+			continue
+		}
+
+		if !slices.Contains(groupNames, hostGroup.Name) {
+			continue
+		}
+
+		logrus.Debugf("Mapping group %s with GID %d", hostGroup.Name, hostGroup.GID)
+
+		groupArg := fmt.Sprintf("%s:%d", hostGroup.Name, hostGroup.GID)
+		groupArgs = append(groupArgs, "--group", groupArg)
+	}
+
+	return groupArgs
+}
+
+// getNetworkArgs returns the network arguments for 'podman create'.  With
+// '--network-from', the container joins the network namespace of another
+// Toolbx container, including its host name, which Podman doesn't allow to
+// be set separately.  If that container shares the network of a third one,
+// the third one is joined instead, so that a group of containers doesn't
+// depend on the order in which they were created.
+func getNetworkArgs(container string) ([]string, error) {
+	networkFrom := createFlags.networkFrom
+	if networkFrom == "" {
+		args := []string{"--hostname", container, "--network", createFlags.network}
+		return args, nil
+	}
+
+	if networkFrom == container {
+		return nil, errors.New("cannot share the network of a container with itself")
+	}
+
+	containerObj, err := podman.InspectContainer(networkFrom)
+	if err != nil {
+		return nil, createErrorContainerNotFound(networkFrom)
+	}
+
+	if !containerObj.IsToolbx() {
+		return nil, fmt.Errorf("%s is not a Toolbx container", networkFrom)
+	}
+
+	if err := checkContainerOwner(containerObj); err != nil {
+		return nil, err
+	}
+
+	networkMode, err := podman.GetContainerNetworkMode(networkFrom)
+	if err != nil {
+		logrus.Debugf("Getting the network mode of container %s failed: %s", networkFrom, err)
+		return nil, fmt.Errorf("failed to inspect container %s", networkFrom)
+	}
+
+	network := "container:" + containerObj.ID()
+	if strings.HasPrefix(networkMode, "container:") {
+		network = networkMode
+	}
+
+	logrus.Debugf("Sharing the network of container %s with %s", networkFrom, network)
+
+	args := []string{"--network", network}
+	return args, nil
+}
+
+// getNativeImage looks for an arm64 version of an image in its registry.  The
+// same tag is tried first, in case it's a manifest list that was pulled for
+// the wrong architecture, and then the tag with the suffixes and prefixes
+// that are commonly used for arm64.  It returns an empty string if there's
+// none.
+func getNativeImage(image string) string {
+	if strings.Contains(image, "@") {
+		return ""
+	}
+
+	repository, tag := image, "latest"
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		repository, tag = image[:i], image[i+1:]
+	}
+
+	candidates := []string{
+		image,
+		repository + ":" + tag + "-arm64",
+		repository + ":" + tag + "-aarch64",
+		repository + ":arm64-" + tag,
+		repository + ":aarch64-" + tag,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), nativeImageLookupTimeout)
+	defer cancel()
+
+	for _, candidate := range candidates {
+		info, err := skopeo.InspectForArchitecture(ctx, candidate, "arm64")
+		if err != nil {
+			logrus.Debugf("Inspecting image %s for arm64 failed: %s", candidate, err)
+
+			if ctx.Err() != nil {
+				break
+			}
+
+			continue
+		}
+
+		if info.Architecture == "arm64" {
+			return candidate
+		}
+	}
+
+	return ""
+}
+
+// getReleaseImageSize describes how much would be downloaded for a release of
+// a distro.
+func getReleaseImageSize(distro, release string) string {
+	_, image, release, err := utils.ResolveContainerAndImageNames("", distro, "", release)
+	if err != nil {
+		logrus.Debugf("Resolving the image for %s %s failed: %s", distro, release, err)
+		return "size unknown"
+	}
+
+	if imageExists, _ := podman.ImageExists(image); imageExists {
+		return "downloaded"
+	}
+
+	if isOffline() {
+		return "not downloaded"
+	}
+
+	imageFull, err := utils.GetFullyQualifiedImageFromDistros(image, release)
+	if err != nil {
+		logrus.Debugf("Resolving the fully qualified name of image %s failed: %s", image, err)
+		return "size unknown"
+	}
+
+	imageSize, err := getImageSize(imageFull)
+	if err != nil {
+		logrus.Debugf("Getting the size of image %s failed: %s", imageFull, err)
+		return "size unknown"
+	}
+
+	return imageSize
+}
+
+// getPodmanSocketArgs shares the API socket of Podman inside the Podman
+// machine with the container for '--podman-socket', and points the podman
+// and docker commands at it, so that containers can be built and run from
+// inside the Toolbx container.  They are siblings of it, rather than nested,
+// and paths in bind mounts are those of the Podman machine.  The mount and
+// the variables are carried over when the container is recreated, like any
+// other.
+func getPodmanSocketArgs() ([]string, error) {
+	if !createFlags.podmanSocket {
+		return nil, nil
+	}
+
+	info, err := podman.GetInfo()
+	if err != nil {
+		logrus.Debugf("Getting the API socket of Podman failed: %s", err)
+		return nil, errors.New("failed to get the API socket of Podman")
+	}
+
+	socket := strings.TrimPrefix(info.Host.RemoteSocket.Path, "unix://")
+	if socket == "" || !info.Host.RemoteSocket.Exists {
+		return nil, errors.New("the API socket of Podman is not available in the Podman machine")
+	}
+
+	logrus.Debugf("Sharing the API socket of Podman at %s as %s", socket, podmanSocketPath)
+
+	containerHost := "unix://" + podmanSocketPath
+	args := []string{
+		"--volume", socket + ":" + podmanSocketPath,
+		"--env", "CONTAINER_HOST=" + containerHost,
+		"--env", "DOCKER_HOST=" + containerHost,
+	}
+
+	return args, nil
+}
+
+// getUsernsArgs maps the macOS user that owns the container, usually UID 501
+// and GID 20 (staff), to the same IDs inside the container, so that files
+// created in the shared home directory have the same owner on both sides of
+// the Podman machine.
+func getUsernsArgs(owner *user.User) ([]string, error) {
+	rootless, err := podman.IsRootless()
+	if err != nil {
+		logrus.Debugf("Checking if the Podman machine is rootless failed: %s", err)
+		return nil, errors.New("failed to check if the Podman machine is rootless")
+	}
+
+	if !rootless {
+		logrus.Debug("Podman machine is rootful: ownership is left to the file system share")
+		return nil, nil
+	}
+
+	uid := owner.Uid
+	gid := owner.Gid
+
+	logrus.Debug("Checking if 'podman create' supports '--userns keep-id:uid=UID,gid=GID'")
+
+	if !podman.CheckVersion("4.3.0") {
+		// A plain keep-id maps the user running Podman, which is the
+		// administrator, not the owner, in system mode.
+		if owner != currentUser {
+			return nil, errors.New("'--owner' needs Podman 4.3.0 or newer in a rootless machine")
+		}
+
+		logrus.Debugf("Mapping UID %s and GID %s with '--userns keep-id'", uid, gid)
+		return []string{"--userns", "keep-id"}, nil
+	}
+
+	usernsArg := fmt.Sprintf("keep-id:uid=%s,gid=%s", uid, gid)
+	logrus.Debugf("Mapping UID %s and GID %s with '--userns %s'", uid, gid, usernsArg)
+	return []string{"--userns", usernsArg}, nil
+}
+
+// getOwnerShell returns the login shell of the user that owns the container.
+// In system mode, $SHELL is the administrator's, so the owner's is looked up
+// in Open Directory instead.  If that fails, init-container falls back to
+// /bin/sh.
+func getOwnerShell(owner *user.User) string {
+	if owner == currentUser {
+		userShell := os.Getenv("SHELL")
+		return userShell
+	}
+
+	userShell, err := utils.GetHostUserShell(owner.Username)
+	if err != nil {
+		logrus.Debugf("Getting the login shell of user %s failed: %s", owner.Username, err)
+		return ""
+	}
+
+	return userShell
+}
+
+func getWorkspaceVolumeName(container string) string {
+	return "toolbox-" + container + "-workspace"
+}
+
+// warnIfCaseInsensitive points out that APFS, the default on macOS, ignores
+// case, which breaks some Linux builds that have file names differing only in
+// case.  Named volumes live inside the Podman machine and are case-sensitive.
+func warnIfCaseInsensitive(path string) {
+	caseSensitive, err := utils.IsCaseSensitive(path)
+	if err != nil {
+		logrus.Debugf("Checking if %s is case-sensitive failed: %s", path, err)
+		return
+	}
+
+	if caseSensitive {
+		logrus.Debugf("%s is on a case-sensitive file system", path)
+		return
+	}
+
+	if rootFlags.quiet {
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "Warning: %s is on a case-insensitive file system\n", path)
+	fmt.Fprintf(os.Stderr, "Builds that rely on file names differing only in case may fail there.\n")
+	fmt.Fprintf(os.Stderr, "Use '--workspace-volume' to get a case-sensitive %s.\n", workspaceDirectory)
+}
+
 func pullImage(image, authFile string) error {
 	if image == "" {
 		panic("image not specified")
@@ -260,6 +1211,15 @@ func pullImage(image, authFile string) error {
 
 	logrus.Debugf("Pulling image %s", image)
 
+	if isOffline() {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "image %s not found, and images aren't pulled offline\n", image)
+		fmt.Fprintf(&builder, "Use 'toolbox images load' to load it from an archive.")
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
 	// Check if we need to prompt for download
 	if shouldPromptForDownload(image) {
 		if err := promptForDownload(image); err != nil {
@@ -267,15 +1227,99 @@ func pullImage(image, authFile string) error {
 		}
 	}
 
+	mirrors, err := getRegistryMirrors()
+	if err != nil {
+		return err
+	}
+
+	for _, mirrorImage := range getMirrorImages(image, mirrors) {
+		s := showSpinner(fmt.Sprintf("Pulling %s from %s", image, utils.ImageReferenceGetDomain(mirrorImage)))
+		err := pullImageFromMirror(image, mirrorImage, authFile)
+		stopSpinner(s)
+
+		if err != nil {
+			logrus.Debugf("Pulling image %s from mirror %s failed: %s", image, mirrorImage, err)
+			showWarning("failed to pull %s from mirror %s, falling back",
+				image,
+				utils.ImageReferenceGetDomain(mirrorImage))
+			continue
+		}
+
+		return nil
+	}
+
 	// Pull the image
-	if err := podman.Pull(image, authFile); err != nil {
+	s := showSpinner(fmt.Sprintf("Pulling %s", image))
+	err = podman.Pull(image, authFile)
+	stopSpinner(s)
+
+	if err != nil {
 		return fmt.Errorf("failed to pull image %s: %w", image, err)
 	}
 
 	return nil
 }
 
+// isOffline checks if images must not be pulled, because of '--offline', or
+// 'offline = true' in the general section of the configuration.
+func isOffline() bool {
+	return createFlags.offline || viper.GetBool("general.offline")
+}
+
+// readChoice shows numbered options and reads the number of one of them.  An
+// empty answer chooses the default.
+func readChoice(reader *bufio.Reader, options []string, defaultIndex int) (int, error) {
+	for i, option := range options {
+		fmt.Printf("  %d) %s\n", i+1, option)
+	}
+
+	for {
+		fmt.Printf("Choose [%d]: ", defaultIndex+1)
+
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return 0, errors.New("no choice made")
+		}
+
+		answer := strings.TrimSpace(line)
+		if answer == "" {
+			return defaultIndex, nil
+		}
+
+		if choice, err := strconv.Atoi(answer); err == nil && choice >= 1 && choice <= len(options) {
+			return choice - 1, nil
+		}
+
+		fmt.Printf("Please enter a number between 1 and %d\n", len(options))
+	}
+}
+
+// shouldChooseDistroAndRelease checks if 'toolbox create' was used without
+// anything that picks an image, in which case the user is asked, if there's
+// somebody to ask.
+func shouldChooseDistroAndRelease() bool {
+	if createFlags.distro != "" || createFlags.image != "" || createFlags.release != "" {
+		return false
+	}
+
+	for _, key := range []string{"general.distro", "general.image", "general.release"} {
+		if viper.IsSet(key) {
+			return false
+		}
+	}
+
+	if rootFlags.assumeYes {
+		return false
+	}
+
+	return term.IsTerminal(os.Stdin) && term.IsTerminal(os.Stdout)
+}
+
 func shouldPromptForDownload(image string) bool {
+	if rootFlags.assumeYes {
+		return false
+	}
+
 	// For macOS, always check image size before pulling
 	// This is especially important since macOS containers run in VMs
 	// and may have limited bandwidth/storage
@@ -286,19 +1330,16 @@ func promptForDownload(image string) error {
 	imageSize, err := getImageSize(image)
 	if err != nil {
 		logrus.Debugf("Failed to get image size: %v", err)
-		// Continue anyway if we can't get size
-		return nil
+		// Continue anyway if we can't get size, unless --strict was used
+		return warnOrFail(fmt.Errorf("failed to get the size of image %s", image))
 	}
 
-	fmt.Printf("Image required to create container: %s (%s)\n", image, imageSize)
-	fmt.Print("Continue? [y/N]: ")
+	i18n.Printf("Image required to create container: %s (%s)\n", image, imageSize)
 
-	var response string
-	fmt.Scanln(&response)
-	
-	response = strings.ToLower(strings.TrimSpace(response))
-	if response != "y" && response != "yes" {
-		return errors.New("download cancelled by user")
+	prompt := i18n.Sprintf("Continue? [y/N]: ")
+	if !askForConfirmation(prompt) {
+		errMsg := i18n.Sprintf("download cancelled by user")
+		return errors.New(errMsg)
 	}
 
 	return nil
@@ -329,8 +1370,12 @@ func getImageSize(image string) (string, error) {
 }
 
 func showSpinner(message string) *spinner.Spinner {
-	if !term.IsTerminal(os.Stderr) {
-		fmt.Fprintf(os.Stderr, "%s\n", message)
+	if terminalLogLevel >= logrus.DebugLevel || rootFlags.quiet {
+		return nil
+	}
+
+	if !term.IsTerminal(os.Stderr) || !shouldUseColor(os.Stderr) {
+		showStatus("%s", message)
 		return nil
 	}
 
@@ -344,4 +1389,4 @@ func stopSpinner(s *spinner.Spinner) {
 	if s != nil {
 		s.Stop()
 	}
-}
\ No newline at end of file
+}
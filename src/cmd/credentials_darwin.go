@@ -0,0 +1,283 @@
+//go:build darwin
+
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/containers/toolbox/pkg/i18n"
+	"github.com/containers/toolbox/pkg/podman"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+type credentialsProfile struct {
+	name string
+
+	// directory is the configuration directory of the cloud SDK, relative
+	// to the home directory, unless it's moved elsewhere by directoryEnv.
+	directory    string
+	directoryEnv string
+
+	// environ are the variables that choose the account, project or
+	// region, and are forwarded as they are.
+	environ []string
+
+	// endpoints are the variables that point at a helper that hands out
+	// and refreshes tokens, like 'aws-vault exec --ecs-server' or a
+	// metadata server emulator.  Those listening on localhost of the Mac
+	// are reached through host.containers.internal.
+	endpoints []string
+}
+
+const credentialsLabel = "com.github.containers.toolbox.credentials"
+
+var (
+	credentialsProfiles = []credentialsProfile{
+		{
+			name:      "aws",
+			directory: ".aws",
+			environ: []string{
+				"AWS_CONTAINER_AUTHORIZATION_TOKEN",
+				"AWS_DEFAULT_REGION",
+				"AWS_PROFILE",
+				"AWS_REGION",
+			},
+			endpoints: []string{
+				"AWS_CONTAINER_CREDENTIALS_FULL_URI",
+			},
+		},
+		{
+			name:         "azure",
+			directory:    ".azure",
+			directoryEnv: "AZURE_CONFIG_DIR",
+			environ: []string{
+				"AZURE_SUBSCRIPTION_ID",
+				"AZURE_TENANT_ID",
+				"IDENTITY_HEADER",
+			},
+			endpoints: []string{
+				"IDENTITY_ENDPOINT",
+				"MSI_ENDPOINT",
+			},
+		},
+		{
+			name:         "gcloud",
+			directory:    ".config/gcloud",
+			directoryEnv: "CLOUDSDK_CONFIG",
+			environ: []string{
+				"CLOUDSDK_ACTIVE_CONFIG_NAME",
+				"CLOUDSDK_CORE_PROJECT",
+				"GOOGLE_CLOUD_PROJECT",
+			},
+			endpoints: []string{
+				"GCE_METADATA_HOST",
+			},
+		},
+	}
+)
+
+// checkCredentialsFlag rejects the values of '--credentials' that aren't the
+// name of a supported cloud SDK.
+func checkCredentialsFlag() error {
+	for _, name := range createFlags.credentials {
+		if _, err := getCredentialsProfiles([]string{name}); err == nil {
+			continue
+		}
+
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "invalid argument for '--credentials': %s\n", name)
+		fmt.Fprintf(&builder, "Supported cloud SDKs are: %s\n", strings.Join(getCredentialsProfileNames(), ", "))
+		i18n.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	return nil
+}
+
+func completionCredentialsProfiles(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+	return getCredentialsProfileNames(), cobra.ShellCompDirectiveNoFileComp
+}
+
+// getCredentialsArgs returns the options for 'podman create' that
+// '--credentials' needs.  The configuration directories of the cloud SDKs
+// are mounted read-only over the shared home directory, so that the CLIs
+// inside the container can use the tokens that are refreshed on the host,
+// but can't overwrite the host's files with ones for other versions.  The
+// mounts are carried over from the container that is recreated, if any, like
+// other mounts.
+func getCredentialsArgs() ([]string, error) {
+	if len(createFlags.credentials) == 0 {
+		return nil, nil
+	}
+
+	profiles, err := getCredentialsProfiles(createFlags.credentials)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, profile := range profiles {
+		names = append(names, profile.name)
+	}
+
+	args := []string{"--label", credentialsLabel + "=" + strings.Join(names, ",")}
+	homeDir := getCurrentUserHomeDir()
+
+	for _, profile := range profiles {
+		directory := profile.getDirectory(homeDir, os.Getenv)
+		if directory == "" {
+			continue
+		}
+
+		if _, err := os.Stat(directory); err != nil {
+			logrus.Debugf("Not sharing the %s credentials: %s", profile.name, err)
+			showWarning("%s has no %s credentials to share with the container", directory, profile.name)
+			continue
+		}
+
+		isMounted := func(volume string) bool {
+			source, _, _ := strings.Cut(volume, ":")
+			return source == directory
+		}
+
+		if slices.ContainsFunc(createFlags.volumes, isMounted) {
+			continue
+		}
+
+		logrus.Debugf("Sharing %s read-only for the %s credentials", directory, profile.name)
+		args = append(args, "--volume", directory+":"+directory+":ro")
+	}
+
+	return args, nil
+}
+
+// getCredentialsEnviron returns the host's environment variables for the
+// cloud SDKs that the container was created with '--credentials' for.
+func getCredentialsEnviron(containerObj podman.Container) []string {
+	names := containerObj.Labels()[credentialsLabel]
+	if names == "" {
+		return nil
+	}
+
+	profiles, err := getCredentialsProfiles(strings.Split(names, ","))
+	if err != nil {
+		logrus.Debugf("Reading the credentials of container %s failed: %s", containerObj.Name(), err)
+		return nil
+	}
+
+	var environ []string
+	for _, profile := range profiles {
+		environ = append(environ, profile.getEnviron(os.Getenv)...)
+	}
+
+	return environ
+}
+
+func getCredentialsProfileNames() []string {
+	var names []string
+	for _, profile := range credentialsProfiles {
+		names = append(names, profile.name)
+	}
+
+	return names
+}
+
+// getCredentialsProfiles looks up profiles by name, in the order of
+// credentialsProfiles, and without duplicates.
+func getCredentialsProfiles(names []string) ([]credentialsProfile, error) {
+	wanted := make(map[string]struct{})
+
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		isProfile := func(profile credentialsProfile) bool {
+			return profile.name == name
+		}
+
+		if !slices.ContainsFunc(credentialsProfiles, isProfile) {
+			return nil, fmt.Errorf("unsupported cloud SDK %s", name)
+		}
+
+		wanted[name] = struct{}{}
+	}
+
+	var profiles []credentialsProfile
+
+	for _, profile := range credentialsProfiles {
+		if _, ok := wanted[profile.name]; ok {
+			profiles = append(profiles, profile)
+		}
+	}
+
+	return profiles, nil
+}
+
+func (profile credentialsProfile) getDirectory(homeDir string, getenv func(string) string) string {
+	if profile.directoryEnv != "" {
+		if directory := getenv(profile.directoryEnv); filepath.IsAbs(directory) {
+			return filepath.Clean(directory)
+		}
+	}
+
+	if homeDir == "" {
+		return ""
+	}
+
+	directory := filepath.Join(homeDir, profile.directory)
+	return directory
+}
+
+func (profile credentialsProfile) getEnviron(getenv func(string) string) []string {
+	var environ []string
+
+	if profile.directoryEnv != "" {
+		if value := getenv(profile.directoryEnv); value != "" {
+			environ = append(environ, profile.directoryEnv+"="+value)
+		}
+	}
+
+	for _, name := range profile.environ {
+		if value := getenv(name); value != "" {
+			environ = append(environ, name+"="+value)
+		}
+	}
+
+	for _, name := range profile.endpoints {
+		value := getenv(name)
+		if value == "" {
+			continue
+		}
+
+		if address, hostname := getContainerHostAddress(value); hostname != "" {
+			logrus.Debugf("Reaching %s of the host at %s", name, address)
+			value = address
+		}
+
+		environ = append(environ, name+"="+value)
+	}
+
+	return environ
+}
@@ -0,0 +1,114 @@
+//go:build darwin
+
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetCredentialsProfiles(t *testing.T) {
+	profiles, err := getCredentialsProfiles([]string{"gcloud", " aws", "gcloud"})
+	require.NoError(t, err)
+	require.Len(t, profiles, 2)
+	assert.Equal(t, "aws", profiles[0].name)
+	assert.Equal(t, "gcloud", profiles[1].name)
+
+	_, err = getCredentialsProfiles([]string{"aws", "gcp"})
+	assert.EqualError(t, err, "unsupported cloud SDK gcp")
+}
+
+func TestGetCredentialsDirectory(t *testing.T) {
+	profiles, err := getCredentialsProfiles([]string{"aws", "gcloud"})
+	require.NoError(t, err)
+
+	getenv := func(name string) string {
+		if name == "CLOUDSDK_CONFIG" {
+			return "/Volumes/Work/gcloud/"
+		}
+
+		return ""
+	}
+
+	assert.Equal(t, "/Users/alice/.aws", profiles[0].getDirectory("/Users/alice", getenv))
+	assert.Equal(t, "/Volumes/Work/gcloud", profiles[1].getDirectory("/Users/alice", getenv))
+	assert.Equal(t, "/Users/alice/.config/gcloud", profiles[1].getDirectory("/Users/alice", emptyGetenv))
+	assert.Empty(t, profiles[0].getDirectory("", emptyGetenv))
+}
+
+func TestGetCredentialsEnviron(t *testing.T) {
+	hostEnviron := map[string]string{
+		"AWS_CONTAINER_CREDENTIALS_FULL_URI": "http://127.0.0.1:9099/credentials",
+		"AWS_PROFILE":                        "dev",
+		"AWS_SECRET_ACCESS_KEY":              "secret",
+		"GCE_METADATA_HOST":                  "localhost:8080",
+		"GOOGLE_CLOUD_PROJECT":               "example",
+		"MSI_ENDPOINT":                       "http://metadata.example.com/token",
+	}
+
+	getenv := func(name string) string {
+		return hostEnviron[name]
+	}
+
+	profiles, err := getCredentialsProfiles([]string{"aws", "azure", "gcloud"})
+	require.NoError(t, err)
+
+	var environ []string
+	for _, profile := range profiles {
+		environ = append(environ, profile.getEnviron(getenv)...)
+	}
+
+	assert.Equal(t, []string{
+		"AWS_PROFILE=dev",
+		"AWS_CONTAINER_CREDENTIALS_FULL_URI=http://host.containers.internal:9099/credentials",
+		"MSI_ENDPOINT=http://metadata.example.com/token",
+		"GOOGLE_CLOUD_PROJECT=example",
+		"GCE_METADATA_HOST=host.containers.internal:8080",
+	}, environ)
+}
+
+func TestGetContainerHostAddress(t *testing.T) {
+	testCases := []struct {
+		address  string
+		expected string
+		hostname string
+	}{
+		{"https://127.0.0.1:6443", "https://host.containers.internal:6443", "127.0.0.1"},
+		{"https://[::1]:6443/path", "https://host.containers.internal:6443/path", "::1"},
+		{"http://localhost/token", "http://host.containers.internal/token", "localhost"},
+		{"0.0.0.0:8080", "host.containers.internal:8080", "0.0.0.0"},
+		{"localhost", "host.containers.internal", "localhost"},
+		{"https://k8s.example.com", "https://k8s.example.com", ""},
+		{"169.254.169.254", "169.254.169.254", ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.address, func(t *testing.T) {
+			address, hostname := getContainerHostAddress(tc.address)
+			assert.Equal(t, tc.expected, address)
+			assert.Equal(t, tc.hostname, hostname)
+		})
+	}
+}
+
+func emptyGetenv(string) string {
+	return ""
+}
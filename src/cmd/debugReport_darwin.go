@@ -0,0 +1,372 @@
+//go:build darwin
+
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/containers/toolbox/pkg/i18n"
+	"github.com/containers/toolbox/pkg/shell"
+	"github.com/containers/toolbox/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// debugReportFile is one file inside the tarball of 'toolbox debug-report'.
+type debugReportFile struct {
+	data []byte
+	name string
+}
+
+const (
+	bugReportURL = "https://github.com/nickmerrett/toolbox-mac/issues"
+
+	debugReportContainerLogLines = 200
+	debugReportRedacted          = "<redacted>"
+)
+
+var (
+	debugReportFlags struct {
+		output string
+	}
+
+	// debugReportSecretWords are parts of the names of variables and
+	// settings whose values are left out of the tarball.
+	debugReportSecretWords = "credential|passphrase|passwd|password|private|secret|token|api_?key"
+
+	debugReportEnvRegexp = regexp.MustCompile(`(?i)\b([a-z0-9_]*(?:` +
+		debugReportSecretWords +
+		`)[a-z0-9_]*)=[^"\s]*`)
+
+	debugReportJSONRegexp = regexp.MustCompile(`(?i)("[a-z0-9_.-]*(?:` +
+		debugReportSecretWords +
+		`)[a-z0-9_.-]*"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+
+	debugReportTOMLRegexp = regexp.MustCompile(`(?im)^(\s*[a-z0-9_.-]*(?:` +
+		debugReportSecretWords +
+		`)[a-z0-9_.-]*\s*=\s*).*$`)
+)
+
+var debugReportCmd = &cobra.Command{
+	Use:               "debug-report",
+	Short:             "Gather diagnostic information into a tarball for bug reports (macOS version)",
+	RunE:              debugReport,
+	ValidArgsFunction: completionEmpty,
+}
+
+func init() {
+	flags := debugReportCmd.Flags()
+
+	flags.StringVarP(&debugReportFlags.output,
+		"output",
+		"o",
+		"",
+		"Write the tarball to this path instead of the current directory")
+
+	debugReportCmd.SetHelpFunc(debugReportHelp)
+	rootCmd.AddCommand(debugReportCmd)
+}
+
+// debugReport collects what is usually asked for in the first reply to a bug
+// report.  Whatever can't be collected, for example because the Podman
+// machine is stopped, is listed in errors.txt inside the tarball, instead of
+// failing, because a broken installation is when the report is needed most.
+func debugReport(cmd *cobra.Command, args []string) error {
+	if utils.IsInsideContainer() {
+		return errors.New("debug-report is not supported inside a container")
+	}
+
+	if len(args) != 0 {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "unknown argument %s for \"debug-report\"\n", args[0])
+		i18n.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	output := debugReportFlags.output
+	if output == "" {
+		timestamp := time.Now().Format("20060102-150405")
+		output = fmt.Sprintf("toolbox-debug-report-%s.tar.gz", timestamp)
+	}
+
+	showStatus("Gathering information for the debug report")
+
+	files, failures := getDebugReportFiles()
+	if len(failures) != 0 {
+		data := strings.Join(failures, "\n") + "\n"
+		files = append(files, debugReportFile{data: []byte(data), name: "errors.txt"})
+	}
+
+	replacer := getDebugReportReplacer()
+	for i := range files {
+		files[i].data = redactDebugReport(replacer, files[i].data)
+	}
+
+	if err := writeDebugReport(output, files); err != nil {
+		if !errors.Is(err, os.ErrExist) {
+			os.Remove(output)
+		}
+
+		return err
+	}
+
+	i18n.Printf("Debug report saved to %s\n", output)
+	i18n.Printf("Please look through it before attaching it to a bug report.\n")
+	return nil
+}
+
+func debugReportHelp(cmd *cobra.Command, args []string) {
+	if utils.IsInsideContainer() {
+		if !utils.IsInsideToolboxContainer() {
+			fmt.Fprintf(os.Stderr, "Error: this is not a Toolbx container\n")
+			return
+		}
+
+		if _, err := utils.ForwardToHost(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return
+		}
+
+		return
+	}
+
+	if err := showManual("toolbox-debug-report"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return
+	}
+}
+
+// getBugReportHint points to 'toolbox debug-report', because a bug report
+// with its tarball attached rarely needs a round of questions.
+func getBugReportHint() string {
+	hint := i18n.Sprintf("Run '%s debug-report', and attach the tarball to a bug report at %s",
+		executableBase,
+		bugReportURL)
+
+	return hint
+}
+
+func getDebugReportCommandOutput(name string, arg ...string) ([]byte, error) {
+	var stdout bytes.Buffer
+	if err := shell.Run(name, nil, &stdout, &stdout, arg...); err != nil {
+		logrus.Debugf("Running %s %s failed: %s", name, strings.Join(arg, " "), err)
+		return nil, fmt.Errorf("failed to run %s %s", name, strings.Join(arg, " "))
+	}
+
+	return stdout.Bytes(), nil
+}
+
+// getDebugReportFiles returns the files for the tarball, and a description
+// of everything that couldn't be collected.
+func getDebugReportFiles() ([]debugReportFile, []string) {
+	var failures []string
+	var files []debugReportFile
+
+	addCommandOutput := func(name string, command ...string) bool {
+		data, err := getDebugReportCommandOutput(command[0], command[1:]...)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %s", name, err))
+			return false
+		}
+
+		files = append(files, debugReportFile{data: data, name: name})
+		return true
+	}
+
+	info, err := json.MarshalIndent(getInfoReport(), "", "    ")
+	if err != nil {
+		failures = append(failures, fmt.Sprintf("info.json: failed to encode JSON: %s", err))
+	} else {
+		files = append(files, debugReportFile{data: append(info, '\n'), name: "info.json"})
+	}
+
+	addCommandOutput("sw_vers.txt", "sw_vers")
+	podmanWorks := addCommandOutput("podman/version.txt", "podman", "version")
+
+	if podmanWorks {
+		addCommandOutput("podman/machine-info.json", "podman", "machine", "info", "--format", "json")
+		addCommandOutput("podman/machine-inspect.json", "podman", "machine", "inspect")
+		addCommandOutput("podman/machine-list.json", "podman", "machine", "list", "--format", "json")
+		addCommandOutput("podman/info.json", "podman", "info", "--format", "json")
+	}
+
+	if configFiles, err := utils.GetConfigurationFiles(); err != nil {
+		failures = append(failures, fmt.Sprintf("config: %s", err))
+	} else {
+		for i, configFile := range configFiles {
+			data, err := os.ReadFile(configFile)
+			if err != nil {
+				if !errors.Is(err, os.ErrNotExist) {
+					failures = append(failures, fmt.Sprintf("config: failed to read %s", configFile))
+				}
+
+				continue
+			}
+
+			name := fmt.Sprintf("config/%d-%s", i, filepath.Base(configFile))
+			files = append(files, debugReportFile{data: data, name: name})
+		}
+	}
+
+	if logFile, err := getLogFile(); err != nil {
+		failures = append(failures, fmt.Sprintf("logs: %s", err))
+	} else {
+		for _, path := range []string{logFile, logFile + ".1"} {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				if !errors.Is(err, os.ErrNotExist) {
+					failures = append(failures, fmt.Sprintf("logs: failed to read %s", path))
+				}
+
+				continue
+			}
+
+			name := filepath.Join("logs", filepath.Base(path))
+			files = append(files, debugReportFile{data: data, name: name})
+		}
+	}
+
+	if !podmanWorks {
+		return files, failures
+	}
+
+	containers, err := getContainers()
+	if err != nil {
+		failures = append(failures, fmt.Sprintf("containers: %s", err))
+		return files, failures
+	}
+
+	for _, container := range containers {
+		containerName := container.Name()
+		containerID := container.ID()
+
+		addCommandOutput(fmt.Sprintf("containers/%s/inspect.json", containerName),
+			"podman", "inspect", "--type", "container", containerID)
+
+		addCommandOutput(fmt.Sprintf("containers/%s/logs.txt", containerName),
+			"podman",
+			"logs",
+			"--tail", fmt.Sprint(debugReportContainerLogLines),
+			containerID)
+	}
+
+	return files, failures
+}
+
+// getDebugReportReplacer hides the home directory and the host name, which
+// identify the user, but say nothing about the problem.
+func getDebugReportReplacer() *strings.Replacer {
+	var oldnew []string
+
+	if homeDir := getCurrentUserHomeDir(); homeDir != "" && homeDir != "/" {
+		oldnew = append(oldnew, homeDir, "~")
+	}
+
+	if hostname, err := os.Hostname(); err == nil && hostname != "" {
+		oldnew = append(oldnew, hostname, "<hostname>")
+
+		if shortHostname, _, found := strings.Cut(hostname, "."); found && shortHostname != "" {
+			oldnew = append(oldnew, shortHostname, "<hostname>")
+		}
+	}
+
+	replacer := strings.NewReplacer(oldnew...)
+	return replacer
+}
+
+// redactDebugReport leaves out the values of environment variables, JSON keys
+// and configuration settings that look like they hold secrets, along with
+// the personal details hidden by replacer.
+func redactDebugReport(replacer *strings.Replacer, data []byte) []byte {
+	data = debugReportJSONRegexp.ReplaceAll(data, []byte(`$1"`+debugReportRedacted+`"`))
+	data = debugReportEnvRegexp.ReplaceAll(data, []byte("$1="+debugReportRedacted))
+	data = debugReportTOMLRegexp.ReplaceAll(data, []byte(`$1"`+debugReportRedacted+`"`))
+
+	redacted := replacer.Replace(string(data))
+	return []byte(redacted)
+}
+
+func writeDebugReport(output string, files []debugReportFile) error {
+	file, err := os.OpenFile(output, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		logrus.Debugf("Writing the debug report: failed to create %s: %s", output, err)
+		if errors.Is(err, os.ErrExist) {
+			return fmt.Errorf("failed to create %s: %w", output, os.ErrExist)
+		}
+
+		return fmt.Errorf("failed to create %s", output)
+	}
+
+	defer file.Close()
+
+	gzipWriter := gzip.NewWriter(file)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	directory := strings.TrimSuffix(filepath.Base(output), ".tar.gz")
+	modTime := time.Now()
+
+	for _, debugReportFile := range files {
+		header := &tar.Header{
+			ModTime:  modTime,
+			Mode:     0600,
+			Name:     filepath.Join(directory, debugReportFile.name),
+			Size:     int64(len(debugReportFile.data)),
+			Typeflag: tar.TypeReg,
+		}
+
+		if err := tarWriter.WriteHeader(header); err != nil {
+			logrus.Debugf("Writing the debug report: failed to write header of %s: %s", header.Name, err)
+			return fmt.Errorf("failed to write %s", output)
+		}
+
+		if _, err := tarWriter.Write(debugReportFile.data); err != nil {
+			logrus.Debugf("Writing the debug report: failed to write %s: %s", header.Name, err)
+			return fmt.Errorf("failed to write %s", output)
+		}
+	}
+
+	if err := tarWriter.Close(); err != nil {
+		logrus.Debugf("Writing the debug report: failed to close the tarball: %s", err)
+		return fmt.Errorf("failed to write %s", output)
+	}
+
+	if err := gzipWriter.Close(); err != nil {
+		logrus.Debugf("Writing the debug report: failed to close the compressed stream: %s", err)
+		return fmt.Errorf("failed to write %s", output)
+	}
+
+	if err := file.Close(); err != nil {
+		logrus.Debugf("Writing the debug report: failed to close %s: %s", output, err)
+		return fmt.Errorf("failed to write %s", output)
+	}
+
+	return nil
+}
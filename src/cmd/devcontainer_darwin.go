@@ -0,0 +1,658 @@
+//go:build darwin
+
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/containers/toolbox/pkg/i18n"
+	"github.com/containers/toolbox/pkg/podman"
+	"github.com/containers/toolbox/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// devcontainer is what 'toolbox create --from-devcontainer' understands of a
+// devcontainer.json file.  See https://containers.dev/implementors/json_reference/
+type devcontainer struct {
+	Build                json.RawMessage            `json:"build"`
+	CapAdd               []string                   `json:"capAdd"`
+	ContainerEnv         map[string]string          `json:"containerEnv"`
+	DockerComposeFile    json.RawMessage            `json:"dockerComposeFile"`
+	DockerFile           string                     `json:"dockerFile"`
+	Features             map[string]json.RawMessage `json:"features"`
+	ForwardPorts         []json.RawMessage          `json:"forwardPorts"`
+	Image                string                     `json:"image"`
+	Mounts               []json.RawMessage          `json:"mounts"`
+	Name                 string                     `json:"name"`
+	OnCreateCommand      json.RawMessage            `json:"onCreateCommand"`
+	PostCreateCommand    json.RawMessage            `json:"postCreateCommand"`
+	Privileged           bool                       `json:"privileged"`
+	RemoteEnv            map[string]string          `json:"remoteEnv"`
+	RunArgs              []string                   `json:"runArgs"`
+	SecurityOpt          []string                   `json:"securityOpt"`
+	UpdateContentCommand json.RawMessage            `json:"updateContentCommand"`
+	WorkspaceFolder      string                     `json:"workspaceFolder"`
+	WorkspaceMount       string                     `json:"workspaceMount"`
+}
+
+// devcontainerPlan is how a devcontainer.json file translates into a Toolbx
+// container.
+type devcontainerPlan struct {
+	capAdd          []string
+	commands        [][]string
+	container       string
+	env             []string
+	image           string
+	packages        []string
+	publish         []string
+	securityOpt     []string
+	volumes         []string
+	warnings        []string
+	workspaceFolder string
+}
+
+var (
+	// devcontainerFeaturePackages are the packages that stand in for the
+	// features from https://github.com/devcontainers/features that have
+	// one in the distributions, because features need the devcontainer
+	// CLI to be installed.  The Toolbx images have what common-utils
+	// installs already.
+	devcontainerFeaturePackages = map[string][]string{
+		"common-utils": nil,
+		"git":          {"git"},
+		"github-cli":   {"gh"},
+		"go":           {"golang"},
+		"node":         {"nodejs", "npm"},
+		"python":       {"python3", "python3-pip"},
+		"rust":         {"cargo"},
+	}
+
+	devcontainerVariableRegexp = regexp.MustCompile(`\$\{([^}]+)\}`)
+)
+
+// createDevcontainerContainer creates the container for a devcontainer.json
+// file, installs the packages for its features and runs its commands, like
+// the Dev Containers extension of VS Code would when creating it.
+func createDevcontainerContainer(plan devcontainerPlan, authFile string) error {
+	for _, warning := range plan.warnings {
+		showWarning("%s", warning)
+	}
+
+	createFlags.capAdd = append(createFlags.capAdd, plan.capAdd...)
+	createFlags.env = append(createFlags.env, plan.env...)
+	createFlags.publish = append(createFlags.publish, plan.publish...)
+	createFlags.securityOpt = append(createFlags.securityOpt, plan.securityOpt...)
+	createFlags.volumes = append(createFlags.volumes, plan.volumes...)
+
+	if err := createContainer(plan.container, plan.image, "", authFile, false); err != nil {
+		return err
+	}
+
+	if len(plan.packages) != 0 || len(plan.commands) != 0 {
+		s := showSpinner(fmt.Sprintf("Starting container %s", plan.container))
+		err := startContainerAndWait(plan.container)
+		stopSpinner(s)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, packageName := range plan.packages {
+		s := showSpinner(fmt.Sprintf("Installing %s in container %s", packageName, plan.container))
+		err := podman.ExecAsRoot(plan.container, nil, "sh", "-c", installPackageScript, "sh", packageName)
+		stopSpinner(s)
+
+		if err != nil {
+			logrus.Debugf("Installing %s in container %s failed: %s", packageName, plan.container, err)
+			showWarning("failed to install %s in container %s", packageName, plan.container)
+		}
+	}
+
+	workDir := getContainerWorkingDirectory(plan.container, plan.workspaceFolder)
+
+	for _, command := range plan.commands {
+		showStatus("Running %s", strings.Join(command, " "))
+
+		if err := runCommand(plan.container,
+			false,
+			plan.image,
+			"",
+			0,
+			"",
+			workDir,
+			command,
+			nil,
+			false,
+			false,
+			true); err != nil {
+			return fmt.Errorf("failed to run %s in container %s: %w", command[0], plan.container, err)
+		}
+	}
+
+	showMessage("Created container %s from %s", plan.container, plan.workspaceFolder)
+	showMessage("Enter with: toolbox enter %s", plan.container)
+	return nil
+}
+
+func createFromDevcontainer(cmd *cobra.Command, path string) error {
+	for _, conflict := range []string{"distro", "image", "release"} {
+		if !cmd.Flag(conflict).Changed {
+			continue
+		}
+
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "options --from-devcontainer and --%s cannot be used together\n", conflict)
+		i18n.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	config, workspaceFolder, err := readDevcontainer(path)
+	if err != nil {
+		return err
+	}
+
+	plan, err := getDevcontainerPlan(config, workspaceFolder, os.LookupEnv)
+	if err != nil {
+		return err
+	}
+
+	if createFlags.container != "" {
+		plan.container = createFlags.container
+	}
+
+	if !utils.IsContainerNameValid(plan.container) {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "invalid argument for '--container'\n")
+		fmt.Fprintf(&builder, "Container names must match '%s'.\n", utils.ContainerNameRegexp)
+		i18n.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	return createDevcontainerContainer(plan, createFlags.authFile)
+}
+
+// expandDevcontainerVariables replaces the variables that devcontainer.json
+// files use, eg., ${localWorkspaceFolder}, and leaves the ones that only make
+// sense inside the container, eg., ${containerEnv:PATH}, alone.
+func expandDevcontainerVariables(value, workspaceFolder, containerWorkspaceFolder string,
+	getenv func(string) (string, bool)) string {
+
+	expanded := devcontainerVariableRegexp.ReplaceAllStringFunc(value, func(match string) string {
+		variable := match[2 : len(match)-1]
+
+		switch variable {
+		case "localWorkspaceFolder":
+			return workspaceFolder
+		case "localWorkspaceFolderBasename":
+			return filepath.Base(workspaceFolder)
+		case "containerWorkspaceFolder":
+			return containerWorkspaceFolder
+		case "containerWorkspaceFolderBasename":
+			return filepath.Base(containerWorkspaceFolder)
+		}
+
+		name, found := strings.CutPrefix(variable, "localEnv:")
+		if !found {
+			name, found = strings.CutPrefix(variable, "env:")
+		}
+
+		if !found {
+			return match
+		}
+
+		name, defaultValue, _ := strings.Cut(name, ":")
+		if value, ok := getenv(name); ok {
+			return value
+		}
+
+		return defaultValue
+	})
+
+	return expanded
+}
+
+// getDevcontainerCommands returns the commands of a lifecycle script, which
+// is a string for a shell, an array for a command with arguments, or an object
+// with more than one of either, which are run in the order of their names,
+// instead of in parallel.
+func getDevcontainerCommands(script json.RawMessage) ([][]string, error) {
+	if len(script) == 0 || string(script) == "null" {
+		return nil, nil
+	}
+
+	var shellCommand string
+	if err := json.Unmarshal(script, &shellCommand); err == nil {
+		if shellCommand == "" {
+			return nil, nil
+		}
+
+		return [][]string{{"/bin/sh", "-c", shellCommand}}, nil
+	}
+
+	var command []string
+	if err := json.Unmarshal(script, &command); err == nil {
+		if len(command) == 0 {
+			return nil, nil
+		}
+
+		return [][]string{command}, nil
+	}
+
+	var scripts map[string]json.RawMessage
+	if err := json.Unmarshal(script, &scripts); err != nil {
+		return nil, errors.New("commands need to be a string, an array or an object")
+	}
+
+	names := make([]string, 0, len(scripts))
+	for name := range scripts {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	var commands [][]string
+
+	for _, name := range names {
+		script := scripts[name]
+
+		var shellCommand string
+		if err := json.Unmarshal(script, &shellCommand); err == nil {
+			commands = append(commands, []string{"/bin/sh", "-c", shellCommand})
+			continue
+		}
+
+		var command []string
+		if err := json.Unmarshal(script, &command); err == nil && len(command) != 0 {
+			commands = append(commands, command)
+			continue
+		}
+
+		return nil, fmt.Errorf("command %s needs to be a string or an array", name)
+	}
+
+	return commands, nil
+}
+
+// getDevcontainerContainerName makes a valid container name out of the name
+// in a devcontainer.json file, or else the name of the workspace folder.
+func getDevcontainerContainerName(name, workspaceFolder string) string {
+	if name == "" {
+		name = filepath.Base(workspaceFolder)
+	}
+
+	var builder strings.Builder
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '_', r == '.':
+			builder.WriteRune(r)
+		default:
+			builder.WriteRune('-')
+		}
+	}
+
+	container := strings.Trim(builder.String(), "-_.")
+	if container == "" {
+		container = "devcontainer"
+	}
+
+	return container
+}
+
+// getDevcontainerMount translates a mount, which is either a string in the
+// format of 'docker run --mount', or an object with the same keys, into an
+// argument for 'podman create --volume'.
+func getDevcontainerMount(mount json.RawMessage) (string, error) {
+	options := make(map[string]string)
+
+	var mountString string
+	if err := json.Unmarshal(mount, &mountString); err == nil {
+		for _, option := range strings.Split(mountString, ",") {
+			key, value, _ := strings.Cut(option, "=")
+			options[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	} else {
+		var mountObject map[string]interface{}
+		if err := json.Unmarshal(mount, &mountObject); err != nil {
+			return "", errors.New("mounts need to be strings or objects")
+		}
+
+		for key, value := range mountObject {
+			options[key] = fmt.Sprint(value)
+		}
+	}
+
+	source := options["source"]
+	if source == "" {
+		source = options["src"]
+	}
+
+	target := options["target"]
+	for _, key := range []string{"destination", "dst"} {
+		if target == "" {
+			target = options[key]
+		}
+	}
+
+	if target == "" {
+		return "", errors.New("mount without a target")
+	}
+
+	mountType := options["type"]
+	if mountType == "" {
+		mountType = "volume"
+	}
+
+	switch mountType {
+	case "bind", "volume":
+	default:
+		return "", fmt.Errorf("mount of type %s at %s is not supported", mountType, target)
+	}
+
+	if source == "" {
+		return "", fmt.Errorf("mount at %s without a source is not supported", target)
+	}
+
+	volume := source + ":" + target
+
+	if _, ok := options["readonly"]; ok {
+		volume += ":ro"
+	} else if _, ok := options["ro"]; ok {
+		volume += ":ro"
+	}
+
+	return volume, nil
+}
+
+// getDevcontainerPlan translates a devcontainer.json file for a workspace
+// folder on the host.  The home directory is shared with the container at the
+// same path, so the workspace folder is inside the container where it is on
+// the host, unless the file says otherwise.
+func getDevcontainerPlan(config devcontainer, workspaceFolder string,
+	getenv func(string) (string, bool)) (devcontainerPlan, error) {
+
+	if config.Image == "" {
+		if len(config.DockerComposeFile) != 0 {
+			return devcontainerPlan{}, errors.New("devcontainer.json files with Docker Compose are not supported")
+		}
+
+		if len(config.Build) != 0 || config.DockerFile != "" {
+			var builder strings.Builder
+			fmt.Fprintf(&builder, "devcontainer.json files that build an image are not supported\n")
+			fmt.Fprintf(&builder, "Build it with 'toolbox build', and set it as \"image\".")
+
+			errMsg := builder.String()
+			return devcontainerPlan{}, errors.New(errMsg)
+		}
+
+		return devcontainerPlan{}, errors.New("devcontainer.json file without an image")
+	}
+
+	containerWorkspaceFolder := workspaceFolder
+	if config.WorkspaceFolder != "" {
+		containerWorkspaceFolder = config.WorkspaceFolder
+	}
+
+	expand := func(value string) string {
+		return expandDevcontainerVariables(value, workspaceFolder, containerWorkspaceFolder, getenv)
+	}
+
+	plan := devcontainerPlan{
+		capAdd:          config.CapAdd,
+		container:       getDevcontainerContainerName(config.Name, workspaceFolder),
+		image:           expand(config.Image),
+		securityOpt:     config.SecurityOpt,
+		workspaceFolder: workspaceFolder,
+	}
+
+	for _, environment := range []map[string]string{config.ContainerEnv, config.RemoteEnv} {
+		keys := make([]string, 0, len(environment))
+		for key := range environment {
+			keys = append(keys, key)
+		}
+
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			plan.env = append(plan.env, key+"="+expand(environment[key]))
+		}
+	}
+
+	features := make([]string, 0, len(config.Features))
+	for feature := range config.Features {
+		features = append(features, feature)
+	}
+
+	sort.Strings(features)
+
+	for _, feature := range features {
+		name := feature[strings.LastIndex(feature, "/")+1:]
+		name, _, _ = strings.Cut(name, ":")
+		name, _, _ = strings.Cut(name, "@")
+
+		packages, ok := devcontainerFeaturePackages[name]
+		if !ok {
+			plan.warnings = append(plan.warnings, fmt.Sprintf("feature %s is not supported, and was skipped", feature))
+			continue
+		}
+
+		plan.packages = append(plan.packages, packages...)
+	}
+
+	for _, port := range config.ForwardPorts {
+		var number int
+		if err := json.Unmarshal(port, &number); err == nil {
+			plan.publish = append(plan.publish, strconv.Itoa(number))
+			continue
+		}
+
+		plan.warnings = append(plan.warnings, fmt.Sprintf("forwarded port %s is not supported, and was skipped", port))
+	}
+
+	for _, mount := range config.Mounts {
+		var expandedMount json.RawMessage = mount
+
+		var mountString string
+		if err := json.Unmarshal(mount, &mountString); err == nil {
+			expandedMount, _ = json.Marshal(expand(mountString))
+		}
+
+		volume, err := getDevcontainerMount(expandedMount)
+		if err != nil {
+			plan.warnings = append(plan.warnings, fmt.Sprintf("%s, and was skipped", err))
+			continue
+		}
+
+		plan.volumes = append(plan.volumes, volume)
+	}
+
+	if config.WorkspaceMount != "" {
+		plan.warnings = append(plan.warnings, "\"workspaceMount\" is not supported, and the home directory is shared instead")
+	}
+
+	if config.Privileged {
+		plan.warnings = append(plan.warnings, "\"privileged\" is not supported, and was skipped")
+	}
+
+	if len(config.RunArgs) != 0 {
+		plan.warnings = append(plan.warnings, "\"runArgs\" is not supported, and was skipped")
+	}
+
+	for _, script := range []json.RawMessage{config.OnCreateCommand,
+		config.UpdateContentCommand,
+		config.PostCreateCommand} {
+
+		commands, err := getDevcontainerCommands(script)
+		if err != nil {
+			return devcontainerPlan{}, err
+		}
+
+		for _, command := range commands {
+			for i := range command {
+				command[i] = expand(command[i])
+			}
+
+			plan.commands = append(plan.commands, command)
+		}
+	}
+
+	return plan, nil
+}
+
+// getDevcontainerWorkspaceFolder returns the folder that a devcontainer.json
+// file is for, which is the parent of the .devcontainer directory, or where a
+// .devcontainer.json file is.
+func getDevcontainerWorkspaceFolder(file string) string {
+	dir := filepath.Dir(file)
+
+	if filepath.Base(dir) == ".devcontainer" {
+		return filepath.Dir(dir)
+	}
+
+	// Eg., .devcontainer/python/devcontainer.json
+	if filepath.Base(filepath.Dir(dir)) == ".devcontainer" {
+		return filepath.Dir(filepath.Dir(dir))
+	}
+
+	return dir
+}
+
+// readDevcontainer reads a devcontainer.json file, which can be a directory
+// with one in .devcontainer.  The format is JSON with comments and trailing
+// commas.
+func readDevcontainer(path string) (devcontainer, string, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return devcontainer{}, "", fmt.Errorf("failed to resolve %s: %w", path, err)
+	}
+
+	if fileInfo, err := os.Stat(absPath); err == nil && fileInfo.IsDir() {
+		candidates := []string{
+			filepath.Join(absPath, ".devcontainer", "devcontainer.json"),
+			filepath.Join(absPath, ".devcontainer.json"),
+		}
+
+		absPath = candidates[0]
+		for _, candidate := range candidates {
+			if utils.PathExists(candidate) {
+				absPath = candidate
+				break
+			}
+		}
+	}
+
+	data, err := os.ReadFile(absPath)
+	if err != nil {
+		return devcontainer{}, "", fmt.Errorf("failed to read %s: %w", absPath, err)
+	}
+
+	var config devcontainer
+	if err := json.Unmarshal(stripJSONComments(data), &config); err != nil {
+		return devcontainer{}, "", fmt.Errorf("failed to parse %s: %w", absPath, err)
+	}
+
+	workspaceFolder := getDevcontainerWorkspaceFolder(absPath)
+	return config, workspaceFolder, nil
+}
+
+// stripJSONComments turns JSON with comments and trailing commas, as used by
+// VS Code, into plain JSON.
+func stripJSONComments(data []byte) []byte {
+	var withoutComments bytes.Buffer
+
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+
+		switch {
+		case c == '"':
+			end := getJSONStringEnd(data, i)
+			withoutComments.Write(data[i:end])
+			i = end - 1
+		case c == '/' && i+1 < len(data) && data[i+1] == '/':
+			for i+1 < len(data) && data[i+1] != '\n' {
+				i++
+			}
+		case c == '/' && i+1 < len(data) && data[i+1] == '*':
+			end := bytes.Index(data[i+2:], []byte("*/"))
+			if end == -1 {
+				i = len(data)
+			} else {
+				i += 2 + end + 1
+			}
+
+			withoutComments.WriteByte(' ')
+		default:
+			withoutComments.WriteByte(c)
+		}
+	}
+
+	data = withoutComments.Bytes()
+
+	var stripped bytes.Buffer
+
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+
+		switch c {
+		case '"':
+			end := getJSONStringEnd(data, i)
+			stripped.Write(data[i:end])
+			i = end - 1
+		case ',':
+			next := bytes.TrimLeft(data[i+1:], " \t\r\n")
+			if len(next) != 0 && (next[0] == '}' || next[0] == ']') {
+				continue
+			}
+
+			stripped.WriteByte(c)
+		default:
+			stripped.WriteByte(c)
+		}
+	}
+
+	return stripped.Bytes()
+}
+
+// getJSONStringEnd returns the index after the string that starts at start.
+func getJSONStringEnd(data []byte, start int) int {
+	for i := start + 1; i < len(data); i++ {
+		switch data[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1
+		}
+	}
+
+	return len(data)
+}
@@ -0,0 +1,87 @@
+//go:build darwin
+
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const devcontainerJSON = `{
+  // The image needs to be a Toolbx image.
+  "name": "My Project",
+  "image": "quay.io/toolbx/ubuntu-toolbox:24.04",
+  "features": {
+    "ghcr.io/devcontainers/features/node:1": { "version": "lts" },
+    "ghcr.io/devcontainers/features/docker-in-docker:2": {},
+  },
+  "forwardPorts": [3000, "db:5432"],
+  "mounts": [
+    "source=${localWorkspaceFolder}/.cache,target=/cache,type=bind",
+    { "source": "node_modules", "target": "/node_modules", "type": "volume" },
+    "target=/tmp/scratch,type=tmpfs"
+  ],
+  "containerEnv": { "EDITOR": "${localEnv:EDITOR:vi}" },
+  "remoteEnv": { "PROJECT": "${localWorkspaceFolderBasename}" },
+  /* Runs once, in the workspace folder. */
+  "postCreateCommand": "npm install // not a comment",
+}`
+
+func TestDevcontainerPlan(t *testing.T) {
+	var config devcontainer
+	err := json.Unmarshal(stripJSONComments([]byte(devcontainerJSON)), &config)
+	require.NoError(t, err)
+
+	getenv := func(string) (string, bool) { return "", false }
+	plan, err := getDevcontainerPlan(config, "/Users/me/src/project", getenv)
+	require.NoError(t, err)
+
+	assert.Equal(t, "my-project", plan.container)
+	assert.Equal(t, "quay.io/toolbx/ubuntu-toolbox:24.04", plan.image)
+	assert.Equal(t, []string{"nodejs", "npm"}, plan.packages)
+	assert.Equal(t, []string{"3000"}, plan.publish)
+	assert.Equal(t, []string{"EDITOR=vi", "PROJECT=project"}, plan.env)
+	assert.Equal(t, []string{"/Users/me/src/project/.cache:/cache", "node_modules:/node_modules"}, plan.volumes)
+	assert.Equal(t, [][]string{{"/bin/sh", "-c", "npm install // not a comment"}}, plan.commands)
+	assert.Len(t, plan.warnings, 3)
+}
+
+func TestDevcontainerPlanWithoutImage(t *testing.T) {
+	config := devcontainer{Build: json.RawMessage(`{"dockerfile": "Dockerfile"}`)}
+	_, err := getDevcontainerPlan(config, "/Users/me/src/project", nil)
+	assert.ErrorContains(t, err, "toolbox build")
+}
+
+func TestDevcontainerCommands(t *testing.T) {
+	commands, err := getDevcontainerCommands(json.RawMessage(`{"b": ["make", "all"], "a": "echo a"}`))
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"/bin/sh", "-c", "echo a"}, {"make", "all"}}, commands)
+
+	_, err = getDevcontainerCommands(json.RawMessage(`42`))
+	assert.Error(t, err)
+}
+
+func TestDevcontainerWorkspaceFolder(t *testing.T) {
+	assert.Equal(t, "/src/project", getDevcontainerWorkspaceFolder("/src/project/.devcontainer/devcontainer.json"))
+	assert.Equal(t, "/src/project", getDevcontainerWorkspaceFolder("/src/project/.devcontainer/go/devcontainer.json"))
+	assert.Equal(t, "/src/project", getDevcontainerWorkspaceFolder("/src/project/.devcontainer.json"))
+}
@@ -0,0 +1,101 @@
+//go:build darwin
+
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/containers/toolbox/pkg/i18n"
+	"github.com/containers/toolbox/pkg/podman"
+)
+
+const (
+	// dnsLabel marks containers created with '--dns' or '--dns-search',
+	// whose /etc/resolv.conf is left alone by 'toolbox monitor-host'.
+	dnsLabel = "com.github.containers.toolbox.dns"
+
+	// machineResolvConf is a copy of the /etc/resolv.conf that Podman
+	// generated from the Podman machine's resolver, which 'toolbox
+	// init-container' saves each time the container starts.
+	machineResolvConf = "/run/toolbox/resolv.conf.machine"
+)
+
+// getDNSArgs returns the DNS arguments for 'podman create'.  Without
+// '--dns' and '--dns-search', Podman points the container at the resolver of
+// the Podman machine, which forwards to macOS, and 'toolbox monitor-host'
+// keeps it in sync with the host's name servers.
+func getDNSArgs() ([]string, error) {
+	if len(createFlags.dns) == 0 && len(createFlags.dnsSearch) == 0 {
+		return nil, nil
+	}
+
+	args := []string{"--label", dnsLabel + "=custom"}
+
+	for _, server := range createFlags.dns {
+		if server != "none" && net.ParseIP(server) == nil {
+			var builder strings.Builder
+			fmt.Fprintf(&builder, "invalid argument for '--dns': %s\n", server)
+			i18n.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+			errMsg := builder.String()
+			return nil, errors.New(errMsg)
+		}
+
+		args = append(args, "--dns", server)
+	}
+
+	for _, domain := range createFlags.dnsSearch {
+		args = append(args, "--dns-search", domain)
+	}
+
+	return args, nil
+}
+
+// hasCustomDNS returns whether the container was created with '--dns' or
+// '--dns-search'.
+func hasCustomDNS(containerObj podman.Container) bool {
+	_, ok := containerObj.Labels()[dnsLabel]
+	return ok
+}
+
+// pushResolvConf replaces /etc/resolv.conf of a running container with the
+// host's, or with the Podman machine's if the host has no usable name
+// servers, like when a VPN client runs its own resolver on the loopback
+// interface, or if propagating them was turned off.
+func pushResolvConf(container string, resolvConf []byte) error {
+	if resolvConf != nil {
+		if err := writeFileInContainer(container, "/etc/resolv.conf", resolvConf); err != nil {
+			return err
+		}
+
+		return nil
+	}
+
+	const script = `test -s "$1" || exit 0
+cat "$1" >/etc/resolv.conf`
+
+	if err := podman.ExecAsRoot(container, nil, "sh", "-c", script, "sh", machineResolvConf); err != nil {
+		return err
+	}
+
+	return nil
+}
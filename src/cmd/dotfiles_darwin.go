@@ -0,0 +1,194 @@
+//go:build darwin
+
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/containers/toolbox/pkg/podman"
+	"github.com/containers/toolbox/pkg/shell"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+// installChezmoiScript puts chezmoi(1) in /usr/local/bin, if the image
+// doesn't have it, because distributions rarely package it.  Installing it
+// in the home directory would put a Linux binary next to the one for macOS.
+const installChezmoiScript = `command -v chezmoi >/dev/null 2>&1 && exit 0
+command -v curl >/dev/null 2>&1 || exit 127
+sh -c "$(curl -fsLS https://get.chezmoi.io)" -- -b /usr/local/bin`
+
+// bootstrapDotfiles runs the user's dotfile managers inside a new container,
+// as set up in the dotfiles section of the configuration, so that the tools
+// and settings that they install are there from the start.  The home
+// directory is shared with the host, so the dotfiles themselves are usually
+// already in place, and it's the scripts of the managers that matter.
+//
+// A failure only leads to a warning, unless '--strict' was given, because
+// the container itself is fine.
+func bootstrapDotfiles(container string) error {
+	chezmoiRepository := viper.GetString("dotfiles.chezmoi")
+	stowPackages := viper.GetStringSlice("dotfiles.stow")
+	script := viper.GetString("dotfiles.script")
+
+	if chezmoiRepository == "" && len(stowPackages) == 0 && script == "" {
+		return nil
+	}
+
+	if !createFlags.dotfiles || createFlags.owner != "" {
+		logrus.Debugf("Not setting up dotfiles in container %s", container)
+		return nil
+	}
+
+	if getCurrentUserHomeDir() == "" {
+		return warnOrFail(errors.New("failed to find the home directory to set up the dotfiles in"))
+	}
+
+	if err := startContainerAndWait(container); err != nil {
+		return warnOrFail(err)
+	}
+
+	if chezmoiRepository != "" {
+		if err := runChezmoi(container, chezmoiRepository); err != nil {
+			return warnOrFail(err)
+		}
+	}
+
+	if len(stowPackages) != 0 {
+		if err := runStow(container, stowPackages); err != nil {
+			return warnOrFail(err)
+		}
+	}
+
+	if script != "" {
+		if err := runDotfilesScript(container, script); err != nil {
+			return warnOrFail(err)
+		}
+	}
+
+	return nil
+}
+
+// execDotfilesCommand runs a command as the user inside the container, in
+// the home directory, and shows what it prints, because dotfile managers
+// can take a while and might need attention.
+func execDotfilesCommand(container string, command ...string) error {
+	logLevelString := podman.LogLevel.String()
+	args := []string{
+		"--log-level", logLevelString,
+		"exec",
+		"--user", currentUser.Username,
+		"--workdir", getCurrentUserHomeDir(),
+		container,
+	}
+
+	args = append(args, command...)
+
+	if err := shell.Run("podman", os.Stdin, os.Stdout, os.Stderr, args...); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func expandHomeDir(path string) string {
+	if path == "~" {
+		return getCurrentUserHomeDir()
+	}
+
+	if relativePath, found := strings.CutPrefix(path, "~/"); found {
+		return filepath.Join(getCurrentUserHomeDir(), relativePath)
+	}
+
+	return path
+}
+
+func runChezmoi(container, repository string) error {
+	s := showSpinner(fmt.Sprintf("Installing chezmoi in container %s", container))
+	err := podman.ExecAsRoot(container, nil, "sh", "-c", installChezmoiScript)
+	stopSpinner(s)
+
+	if err != nil {
+		logrus.Debugf("Installing chezmoi in container %s failed: %s", container, err)
+		return fmt.Errorf("failed to install chezmoi in container %s", container)
+	}
+
+	showStatus("Applying dotfiles from %s with chezmoi", repository)
+
+	if err := execDotfilesCommand(container, "chezmoi", "init", "--apply", repository); err != nil {
+		logrus.Debugf("Running chezmoi in container %s failed: %s", container, err)
+		return fmt.Errorf("failed to apply dotfiles with chezmoi in container %s", container)
+	}
+
+	return nil
+}
+
+// runDotfilesScript runs a script of the user's, which is visible inside the
+// container at the same path if it's in the home directory.
+func runDotfilesScript(container, script string) error {
+	script = expandHomeDir(script)
+	if !filepath.IsAbs(script) {
+		return fmt.Errorf("invalid dotfiles script %s: needs to be an absolute path", script)
+	}
+
+	showStatus("Running %s", script)
+
+	if err := execDotfilesCommand(container, script); err != nil {
+		logrus.Debugf("Running %s in container %s failed: %s", script, container, err)
+		return fmt.Errorf("failed to run %s in container %s", script, container)
+	}
+
+	return nil
+}
+
+func runStow(container string, packages []string) error {
+	stowDir := "~/.dotfiles"
+	if viper.IsSet("dotfiles.stow-directory") {
+		stowDir = viper.GetString("dotfiles.stow-directory")
+	}
+
+	stowDir = expandHomeDir(stowDir)
+
+	if _, err := isCommandPresent(container, "stow"); err != nil {
+		s := showSpinner(fmt.Sprintf("Installing stow in container %s", container))
+		err := podman.ExecAsRoot(container, nil, "sh", "-c", installPackageScript, "sh", "stow")
+		stopSpinner(s)
+
+		if err != nil {
+			logrus.Debugf("Installing stow in container %s failed: %s", container, err)
+			return fmt.Errorf("failed to install stow in container %s", container)
+		}
+	}
+
+	showStatus("Linking dotfiles from %s with stow", stowDir)
+
+	stowArgs := []string{"stow", "--dir", stowDir, "--target", getCurrentUserHomeDir()}
+	stowArgs = append(stowArgs, packages...)
+
+	if err := execDotfilesCommand(container, stowArgs...); err != nil {
+		logrus.Debugf("Running stow in container %s failed: %s", container, err)
+		return fmt.Errorf("failed to link dotfiles with stow in container %s", container)
+	}
+
+	return nil
+}
@@ -20,16 +20,43 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/containers/toolbox/pkg/utils"
 	"github.com/spf13/cobra"
 )
 
+const (
+	// enterSessionScript starts the shell in a tmux(1) session, or else a
+	// dtach(1) one, or attaches to it if it's already there, so that the
+	// shell outlives the terminal and 'podman exec'.
+	enterSessionScript = `session="$1"
+shift
+command -v "$1" >/dev/null 2>&1 || set -- /bin/bash -l
+if command -v tmux >/dev/null 2>&1; then
+	exec tmux new-session -A -s "$session" "$@"
+elif command -v dtach >/dev/null 2>&1; then
+	exec dtach -A "${XDG_RUNTIME_DIR:-/tmp}/toolbox-session-$session" -r winch "$@"
+fi
+echo "Error: tmux or dtach is needed for sessions, but neither was found" >&2
+echo "Install one of them in the container." >&2
+exit 127
+`
+)
+
 var (
 	enterFlags struct {
 		container string
 		distro    string
+		env       []string
+		envFile   []string
+		ephemeral bool
+		image     string
 		release   string
+		root      bool
+		session   string
+		workDir   string
 	}
 )
 
@@ -55,12 +82,51 @@ func init() {
 		"",
 		"Enter a Toolbx container for a different operating system distribution than the host")
 
+	flags.StringArrayVarP(&enterFlags.env,
+		"env",
+		"e",
+		nil,
+		"Set this environment variable, as KEY=VALUE or KEY to use the value from the host")
+
+	flags.StringArrayVar(&enterFlags.envFile,
+		"env-file",
+		nil,
+		"Set the environment variables in this file, which has a KEY=VALUE or KEY on each line")
+
+	flags.BoolVar(&enterFlags.ephemeral,
+		"ephemeral",
+		false,
+		"Enter a new Toolbx container, which is removed when leaving it")
+
+	flags.StringVarP(&enterFlags.image,
+		"image",
+		"i",
+		"",
+		"Create the ephemeral Toolbx container from this image")
+
 	flags.StringVarP(&enterFlags.release,
 		"release",
 		"r",
 		"",
 		"Enter a Toolbx container for a different operating system release than the host")
 
+	flags.BoolVar(&enterFlags.root,
+		"root",
+		false,
+		"Enter the container as root, instead of the current user")
+
+	flags.StringVar(&enterFlags.session,
+		"session",
+		"",
+		"Start the shell in a tmux or dtach session with this name, or attach to it")
+	flags.Lookup("session").NoOptDefVal = "main"
+
+	flags.StringVarP(&enterFlags.workDir,
+		"workdir",
+		"w",
+		"",
+		"Start in this directory inside the container, instead of the current one")
+
 	if err := enterCmd.RegisterFlagCompletionFunc("container", completionContainerNames); err != nil {
 		panicMsg := fmt.Sprintf("failed to register flag completion function: %v", err)
 		panic(panicMsg)
@@ -69,6 +135,14 @@ func init() {
 		panicMsg := fmt.Sprintf("failed to register flag completion function: %v", err)
 		panic(panicMsg)
 	}
+	if err := enterCmd.RegisterFlagCompletionFunc("image", completionImageNames); err != nil {
+		panicMsg := fmt.Sprintf("failed to register flag completion function: %v", err)
+		panic(panicMsg)
+	}
+	if err := enterCmd.RegisterFlagCompletionFunc("release", completionReleases); err != nil {
+		panicMsg := fmt.Sprintf("failed to register flag completion function: %v", err)
+		panic(panicMsg)
+	}
 
 	enterCmd.SetHelpFunc(enterHelp)
 	rootCmd.AddCommand(enterCmd)
@@ -100,6 +174,10 @@ func enter(cmd *cobra.Command, args []string) error {
 		defaultContainer = false
 	}
 
+	if err := checkEphemeralOptions(cmd, enterFlags.ephemeral, containerArg); err != nil {
+		return err
+	}
+
 	if enterFlags.release != "" {
 		defaultContainer = false
 	}
@@ -107,21 +185,86 @@ func enter(cmd *cobra.Command, args []string) error {
 	container, image, release, err := resolveContainerAndImageNames(container,
 		containerArg,
 		enterFlags.distro,
-		"",
+		enterFlags.image,
 		enterFlags.release)
 
 	if err != nil {
 		return err
 	}
 
+	if enterFlags.workDir != "" && !filepath.IsAbs(enterFlags.workDir) {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "invalid argument for '--workdir': %s\n", enterFlags.workDir)
+		fmt.Fprintf(&builder, "The directory must be an absolute path.\n")
+		fmt.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	environ, err := utils.GetEnvironmentFromOptions(enterFlags.env, enterFlags.envFile)
+	if err != nil {
+		return err
+	}
+
 	userShell := os.Getenv("SHELL")
 	if userShell == "" {
 		return errors.New("failed to get the current user's default shell")
 	}
 
+	// An ephemeral container is new, and has no settings from 'toolbox
+	// config' yet.
 	command := []string{userShell, "-l"}
+	if !enterFlags.ephemeral {
+		command = getShellCommand(container, userShell)
+	}
+
+	if enterFlags.session != "" {
+		if !utils.IsContainerNameValid(enterFlags.session) {
+			var builder strings.Builder
+			fmt.Fprintf(&builder, "invalid argument for '--session': %s\n", enterFlags.session)
+			fmt.Fprintf(&builder, "Session names must match '%s'.\n", utils.ContainerNameRegexp)
+			fmt.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+			errMsg := builder.String()
+			return errors.New(errMsg)
+		}
+
+		command = append([]string{"/bin/sh", "-c", enterSessionScript, "sh", enterFlags.session}, command...)
+	}
+
+	var user string
+	if enterFlags.root {
+		user = "root"
+	}
+
+	if enterFlags.ephemeral {
+		err := runEphemeral(container,
+			image,
+			release,
+			0,
+			user,
+			enterFlags.workDir,
+			command,
+			environ,
+			true,
+			true)
+
+		return err
+	}
 
-	if err := runCommand(container, defaultContainer, image, release, 0, command, true, true, false); err != nil {
+	if err := runCommand(container,
+		defaultContainer,
+		image,
+		release,
+		0,
+		user,
+		enterFlags.workDir,
+		command,
+		environ,
+		true,
+		true,
+		false); err != nil {
 		return err
 	}
 
@@ -0,0 +1,131 @@
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/containers/toolbox/pkg/podman"
+	"github.com/containers/toolbox/pkg/toolbox"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// checkEphemeralOptions checks the '--ephemeral' and '--image' options of
+// 'toolbox enter' and 'toolbox run' against the others.  The container is
+// given by containerArg, if at all, eg., '--container'.
+func checkEphemeralOptions(cmd *cobra.Command, ephemeral bool, containerArg string) error {
+	var errMsg string
+
+	if cmd.Flag("image").Changed && !ephemeral {
+		errMsg = "option --image needs --ephemeral"
+	} else if ephemeral && containerArg == "CONTAINER" {
+		errMsg = "option --ephemeral can't be used with a container"
+	} else if ephemeral && containerArg != "" {
+		errMsg = fmt.Sprintf("options %s and --ephemeral cannot be used together", containerArg)
+	} else if cmd.Flag("distro").Changed && cmd.Flag("image").Changed {
+		errMsg = "options --distro and --image cannot be used together"
+	} else if cmd.Flag("image").Changed && cmd.Flag("release").Changed {
+		errMsg = "options --image and --release cannot be used together"
+	}
+
+	if errMsg == "" {
+		return nil
+	}
+
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "%s\n", errMsg)
+	fmt.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+	errMsg = builder.String()
+	return errors.New(errMsg)
+}
+
+// runEphemeral creates a throwaway container from image, runs command in it
+// like runCommand, and removes the container afterwards, whatever the command
+// did.  The container is named after the one that would normally be used,
+// with a random suffix, so that more than one can be used at the same time.
+func runEphemeral(container, image, release string,
+	preserveFDs uint,
+	user, workDir string,
+	command, extraEnviron []string,
+	emitEscapeSequence, fallbackToBash bool) error {
+
+	suffix := make([]byte, 3)
+	if _, err := rand.Read(suffix); err != nil {
+		return fmt.Errorf("failed to generate a name for the ephemeral container: %w", err)
+	}
+
+	container = container + "-ephemeral-" + hex.EncodeToString(suffix)
+
+	if err := createContainer(container, image, release, "", false); err != nil {
+		return err
+	}
+
+	// Interrupting the session is left to the command running in the
+	// container, so that the container is still removed afterwards.
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGHUP, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	defer removeEphemeral(container)
+
+	if err := runCommand(container,
+		false,
+		image,
+		release,
+		preserveFDs,
+		user,
+		workDir,
+		command,
+		extraEnviron,
+		emitEscapeSequence,
+		fallbackToBash,
+		true); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func removeEphemeral(container string) {
+	logrus.Debugf("Removing ephemeral container %s", container)
+
+	containerObj, err := podman.InspectContainer(container)
+	if err != nil {
+		logrus.Debugf("Inspecting container %s failed: %s", container, err)
+		showWarning("failed to remove ephemeral container %s", container)
+		return
+	}
+
+	options := toolbox.RemoveOptions{
+		Force:          true,
+		ForceProtected: true,
+	}
+
+	if err := toolbox.Remove(containerObj, options); err != nil {
+		logrus.Debugf("Removing container %s failed: %s", container, err)
+		showWarning("failed to remove ephemeral container %s", container)
+	}
+}
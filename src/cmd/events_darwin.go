@@ -0,0 +1,206 @@
+//go:build darwin
+
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/containers/toolbox/pkg/i18n"
+	"github.com/containers/toolbox/pkg/podman"
+	"github.com/containers/toolbox/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// toolboxEvent is what 'toolbox events --json' prints for every event, one
+// object per line.
+type toolboxEvent struct {
+	Action    string
+	Container string
+	ID        string
+	Image     string
+	Time      time.Time
+}
+
+var (
+	eventsFlags struct {
+		json  bool
+		since string
+	}
+
+	// eventActions maps the events of Podman that matter for Toolbx
+	// containers to what is shown.
+	eventActions = map[string]string{
+		"create": "created",
+		"died":   "exited",
+		"remove": "removed",
+		"start":  "started",
+		"stop":   "stopped",
+	}
+)
+
+var eventsCmd = &cobra.Command{
+	Use:   "events",
+	Short: "Show Toolbx containers being created, started, stopped and removed (macOS version)",
+	RunE:  events,
+}
+
+func init() {
+	flags := eventsCmd.Flags()
+
+	flags.BoolVar(&eventsFlags.json,
+		"json",
+		false,
+		"Print every event as a JSON object on its own line")
+
+	flags.StringVar(&eventsFlags.since,
+		"since",
+		"",
+		"Show past events since this time, eg., 10m or 2026-01-01T00:00:00")
+
+	eventsCmd.SetHelpFunc(eventsHelp)
+	rootCmd.AddCommand(eventsCmd)
+}
+
+// events follows 'podman events', limited to Toolbx containers, until
+// interrupted, so that scripts and menu bar apps can react to containers
+// changing state without polling 'toolbox list'.
+func events(cmd *cobra.Command, args []string) error {
+	if utils.IsInsideContainer() {
+		return errors.New("events is not supported inside a container")
+	}
+
+	if len(args) != 0 {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "too many arguments for \"events\"\n")
+		i18n.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	eventsArgs := []string{
+		"--filter", "type=container",
+		"--filter", "label=com.github.containers.toolbox=true",
+	}
+
+	var eventNames []string
+	for eventName := range eventActions {
+		eventNames = append(eventNames, eventName)
+	}
+
+	sort.Strings(eventNames)
+
+	for _, eventName := range eventNames {
+		eventsArgs = append(eventsArgs, "--filter", "event="+eventName)
+	}
+
+	if systemMode {
+		eventsArgs = append(eventsArgs, "--filter", "label="+ownerLabel+"="+currentUser.Username)
+	}
+
+	if eventsFlags.since != "" {
+		eventsArgs = append(eventsArgs, "--since", eventsFlags.since)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt)
+	defer signal.Stop(signals)
+
+	go func() {
+		select {
+		case <-signals:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	if err := podman.EventsContext(ctx, showEvent, eventsArgs...); err != nil {
+		if errors.Is(err, context.Canceled) {
+			return nil
+		}
+
+		logrus.Debugf("Following Podman events failed: %s", err)
+		return errors.New("failed to get events from Podman")
+	}
+
+	return nil
+}
+
+func eventsHelp(cmd *cobra.Command, args []string) {
+	if utils.IsInsideContainer() {
+		if !utils.IsInsideToolboxContainer() {
+			fmt.Fprintf(os.Stderr, "Error: this is not a Toolbx container\n")
+			return
+		}
+
+		if _, err := utils.ForwardToHost(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return
+		}
+
+		return
+	}
+
+	if err := showManual("toolbox-events"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return
+	}
+}
+
+func showEvent(event podman.Event) {
+	action, ok := eventActions[event.Status]
+	if !ok {
+		logrus.Debugf("Ignoring event %s of container %s", event.Status, event.Name)
+		return
+	}
+
+	if eventsFlags.json {
+		toolboxEvent := toolboxEvent{
+			Action:    action,
+			Container: event.Name,
+			ID:        event.ID,
+			Image:     event.Image,
+			Time:      event.Time,
+		}
+
+		data, err := json.Marshal(toolboxEvent)
+		if err != nil {
+			logrus.Debugf("Encoding event %s of container %s failed: %s", event.Status, event.Name, err)
+			return
+		}
+
+		fmt.Printf("%s\n", data)
+		return
+	}
+
+	timestamp := event.Time.Local().Format("2006-01-02 15:04:05")
+	fmt.Printf("%s  %-8s %s\n", timestamp, action, event.Name)
+}
@@ -0,0 +1,196 @@
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/containers/toolbox/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+var featuresCmd = &cobra.Command{
+	Use:               "features",
+	Short:             "List, enable and disable experimental features",
+	RunE:              featuresList,
+	ValidArgsFunction: completionEmpty,
+}
+
+var featuresDisableCmd = &cobra.Command{
+	Use:               "disable",
+	Short:             "Disable an experimental feature",
+	RunE:              featuresDisable,
+	ValidArgsFunction: completionFeatureNames,
+}
+
+var featuresEnableCmd = &cobra.Command{
+	Use:               "enable",
+	Short:             "Enable an experimental feature",
+	RunE:              featuresEnable,
+	ValidArgsFunction: completionFeatureNames,
+}
+
+var featuresListCmd = &cobra.Command{
+	Use:               "list",
+	Short:             "List experimental features",
+	RunE:              featuresList,
+	ValidArgsFunction: completionEmpty,
+}
+
+func init() {
+	featuresCmd.AddCommand(featuresDisableCmd)
+	featuresCmd.AddCommand(featuresEnableCmd)
+	featuresCmd.AddCommand(featuresListCmd)
+
+	featuresCmd.SetHelpFunc(featuresHelp)
+	rootCmd.AddCommand(featuresCmd)
+}
+
+func completionFeatureNames(_ *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+	if len(args) != 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var names []string
+	for _, feature := range utils.GetFeatures() {
+		names = append(names, feature.Name)
+	}
+
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+// createErrorFeatureDisabled is meant to be returned by the code paths that
+// are gated behind an experimental feature.
+func createErrorFeatureDisabled(name string) error {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "experimental feature %s is not enabled\n", name)
+	fmt.Fprintf(&builder, "Enable it with '%s features enable %s' or TOOLBOX_EXPERIMENTAL=%s.",
+		executableBase,
+		name,
+		name)
+
+	errMsg := builder.String()
+	return errors.New(errMsg)
+}
+
+func featuresDisable(cmd *cobra.Command, args []string) error {
+	return featuresSetEnabled(cmd, args, false)
+}
+
+func featuresEnable(cmd *cobra.Command, args []string) error {
+	return featuresSetEnabled(cmd, args, true)
+}
+
+func featuresHelp(cmd *cobra.Command, args []string) {
+	if utils.IsInsideContainer() {
+		if !utils.IsInsideToolboxContainer() {
+			fmt.Fprintf(os.Stderr, "Error: this is not a Toolbx container\n")
+			return
+		}
+
+		if _, err := utils.ForwardToHost(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return
+		}
+
+		return
+	}
+
+	if err := showManual("toolbox-features"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return
+	}
+}
+
+func featuresList(cmd *cobra.Command, args []string) error {
+	if utils.IsInsideContainer() {
+		if !utils.IsInsideToolboxContainer() {
+			return errors.New("this is not a Toolbx container")
+		}
+
+		exitCode, err := utils.ForwardToHost()
+		return &exitError{exitCode, err}
+	}
+
+	if len(args) != 0 {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "unrecognized argument \"%s\" for \"%s\"\n", args[0], cmd.CommandPath())
+		fmt.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(writer, "%s\t%s\t%s\n", "FEATURE", "STATUS", "DESCRIPTION")
+
+	for _, feature := range utils.GetFeatures() {
+		status := "disabled"
+		if utils.IsFeatureEnabled(feature.Name) {
+			status = "enabled"
+		}
+
+		fmt.Fprintf(writer, "%s\t%s\t%s\n", feature.Name, status, feature.Description)
+	}
+
+	writer.Flush()
+	return nil
+}
+
+func featuresSetEnabled(cmd *cobra.Command, args []string, enabled bool) error {
+	if utils.IsInsideContainer() {
+		if !utils.IsInsideToolboxContainer() {
+			return errors.New("this is not a Toolbx container")
+		}
+
+		exitCode, err := utils.ForwardToHost()
+		return &exitError{exitCode, err}
+	}
+
+	if len(args) != 1 {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "%s needs exactly one feature\n", cmd.CommandPath())
+		fmt.Fprintf(&builder, "Run '%s features' to list them.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	name := args[0]
+	if err := utils.SetFeatureEnabled(name, enabled); err != nil {
+		if errors.Is(err, utils.ErrFeatureUnknown) {
+			var builder strings.Builder
+			fmt.Fprintf(&builder, "%s\n", err)
+			fmt.Fprintf(&builder, "Run '%s features' to list them.", executableBase)
+
+			errMsg := builder.String()
+			return errors.New(errMsg)
+		}
+
+		return err
+	}
+
+	if enabled {
+		showWarning("experimental feature %s may change or go away", name)
+	}
+
+	return nil
+}
@@ -0,0 +1,381 @@
+//go:build darwin
+
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/containers/toolbox/pkg/i18n"
+	"github.com/containers/toolbox/pkg/podman"
+	"github.com/containers/toolbox/pkg/shell"
+	"github.com/containers/toolbox/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// forwardPortScript connects its standard input and output to port $2 at
+// address $1 inside the container, with whichever tool the image has.
+const forwardPortScript = `case "$1" in
+    *:*) socat_address="[$1]" ;;
+    *) socat_address="$1" ;;
+esac
+if command -v socat >/dev/null 2>&1; then
+    exec socat - "TCP:$socat_address:$2"
+elif command -v nc >/dev/null 2>&1; then
+    exec nc "$1" "$2"
+elif command -v bash >/dev/null 2>&1; then
+    exec bash -c 'exec 3<>"/dev/tcp/$1/$2" && { cat <&3 & cat >&3; wait; }' bash "$1" "$2"
+fi
+exit 127`
+
+// listeningPort is a TCP port that something listens on inside a container,
+// at an address that can be connected to from inside it.
+type listeningPort struct {
+	address string
+	port    int
+}
+
+// forwardedPort is a port that is forwarded from localhost on macOS.
+type forwardedPort struct {
+	listener net.Listener
+	target   listeningPort
+}
+
+var (
+	forwardPortsFlags struct {
+		ignore   []int
+		interval time.Duration
+		notify   bool
+	}
+)
+
+var forwardPortsCmd = &cobra.Command{
+	Use:               "forward-ports",
+	Short:             "Forward ports that start listening in a Toolbx container to localhost (macOS version)",
+	RunE:              forwardPorts,
+	ValidArgsFunction: completionContainerNames,
+}
+
+func init() {
+	flags := forwardPortsCmd.Flags()
+
+	flags.IntSliceVar(&forwardPortsFlags.ignore,
+		"ignore",
+		nil,
+		"Don't forward this port")
+
+	flags.DurationVar(&forwardPortsFlags.interval,
+		"interval",
+		2*time.Second,
+		"Look for new ports this often")
+
+	flags.BoolVar(&forwardPortsFlags.notify,
+		"notify",
+		true,
+		"Show a notification when a port is forwarded")
+
+	forwardPortsCmd.SetHelpFunc(forwardPortsHelp)
+	rootCmd.AddCommand(forwardPortsCmd)
+}
+
+// forwardPorts watches the sockets that listen for TCP connections inside the
+// container, and forwards each one from the same port at localhost on macOS
+// until it goes away, like VS Code does.  Published ports need to be set when
+// the container is created, so every connection goes through 'podman exec'
+// instead, which also reaches servers that only listen at localhost inside
+// the container.
+func forwardPorts(cmd *cobra.Command, args []string) error {
+	if utils.IsInsideContainer() {
+		return errors.New("forward-ports is not supported inside a container")
+	}
+
+	if len(args) != 1 {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "forward-ports needs a container\n")
+		i18n.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	if forwardPortsFlags.interval < 100*time.Millisecond {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "invalid argument for '--interval': %s\n", forwardPortsFlags.interval)
+		fmt.Fprintf(&builder, "The interval must be at least 100ms.\n")
+		i18n.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	container := args[0]
+
+	if _, err := getCapContainerDetails(container); err != nil {
+		return err
+	}
+
+	s := showSpinner(fmt.Sprintf("Starting container %s", container))
+	err := startContainerAndWait(container)
+	stopSpinner(s)
+
+	if err != nil {
+		return err
+	}
+
+	ignored := make(map[int]bool)
+	for _, port := range forwardPortsFlags.ignore {
+		ignored[port] = true
+	}
+
+	// The ports that are in use on macOS are skipped until they go away
+	// inside the container, so that they are only warned about once.
+	inUse := make(map[int]bool)
+
+	forwarded := make(map[int]forwardedPort)
+	defer func() {
+		for _, forwardedPort := range forwarded {
+			forwardedPort.listener.Close()
+		}
+	}()
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt)
+	defer signal.Stop(signals)
+
+	ticker := time.NewTicker(forwardPortsFlags.interval)
+	defer ticker.Stop()
+
+	showMessage("Forwarding ports from container %s to localhost until interrupted", container)
+
+	for {
+		ports, err := getListeningPorts(container)
+		if err != nil {
+			logrus.Debugf("Looking for listening ports in container %s failed: %s", container, err)
+
+			if containerObj, err := podman.InspectContainer(container); err != nil || containerObj.EntryPointPID() <= 0 {
+				showMessage("Container %s stopped", container)
+				return nil
+			}
+		} else {
+			updateForwardedPorts(container, ports, forwarded, ignored, inUse)
+		}
+
+		select {
+		case <-signals:
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func forwardPortsHelp(cmd *cobra.Command, args []string) {
+	if utils.IsInsideContainer() {
+		if !utils.IsInsideToolboxContainer() {
+			fmt.Fprintf(os.Stderr, "Error: this is not a Toolbx container\n")
+			return
+		}
+
+		if _, err := utils.ForwardToHost(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return
+		}
+
+		return
+	}
+
+	if err := showManual("toolbox-forward-ports"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return
+	}
+}
+
+// forwardPortConnection hands a connection over to the port inside the
+// container.
+func forwardPortConnection(container string, target listeningPort, conn net.Conn) {
+	defer conn.Close()
+
+	logrus.Debugf("Forwarding connection from %s to port %d in container %s",
+		conn.RemoteAddr(),
+		target.port,
+		container)
+
+	args := []string{
+		"--log-level", podman.LogLevel.String(),
+		"exec",
+		"--interactive",
+		container,
+		"sh", "-c", forwardPortScript, "sh", target.address, strconv.Itoa(target.port),
+	}
+
+	if err := shell.Run("podman", conn, conn, nil, args...); err != nil {
+		logrus.Debugf("Forwarding connection to port %d in container %s failed: %s",
+			target.port,
+			container,
+			err)
+	}
+}
+
+// getListeningPorts returns the TCP ports that are listened on inside the
+// container, from /proc/net/tcp and /proc/net/tcp6, which unlike ss(8) are
+// there in every image.
+func getListeningPorts(container string) ([]listeningPort, error) {
+	var stdout strings.Builder
+
+	logLevelString := podman.LogLevel.String()
+	args := []string{
+		"--log-level", logLevelString,
+		"exec",
+		container,
+		"sh", "-c", "cat /proc/net/tcp /proc/net/tcp6 2>/dev/null",
+	}
+
+	if err := shell.Run("podman", nil, &stdout, nil, args...); err != nil {
+		return nil, err
+	}
+
+	ports := parseListeningPorts(stdout.String())
+	return ports, nil
+}
+
+// parseListeningPorts parses the sockets in the LISTEN state in the format
+// of /proc/net/tcp and /proc/net/tcp6, where the addresses are in hex, and
+// returns them sorted by port.  Sockets at an address other than localhost or
+// every address can't be reached through localhost inside the container, and
+// are skipped.
+func parseListeningPorts(procNetTCP string) []listeningPort {
+	const tcpListen = "0A"
+
+	ports := make(map[int]listeningPort)
+
+	scanner := bufio.NewScanner(strings.NewReader(procNetTCP))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 4 || fields[3] != tcpListen {
+			continue
+		}
+
+		addressHex, portHex, found := strings.Cut(fields[1], ":")
+		if !found {
+			continue
+		}
+
+		port, err := strconv.ParseUint(portHex, 16, 16)
+		if err != nil || port == 0 {
+			continue
+		}
+
+		var address string
+
+		switch addressHex {
+		case "00000000", "0100007F", "00000000000000000000000000000000":
+			address = "127.0.0.1"
+		case "00000000000000000000000001000000":
+			address = "::1"
+		default:
+			continue
+		}
+
+		// A socket at every address is preferred over one at localhost
+		// for the same port, because it takes IPv4.
+		if existing, ok := ports[int(port)]; ok && existing.address == "127.0.0.1" {
+			continue
+		}
+
+		ports[int(port)] = listeningPort{address: address, port: int(port)}
+	}
+
+	sortedPorts := make([]listeningPort, 0, len(ports))
+	for _, port := range ports {
+		sortedPorts = append(sortedPorts, port)
+	}
+
+	sort.Slice(sortedPorts, func(i, j int) bool { return sortedPorts[i].port < sortedPorts[j].port })
+	return sortedPorts
+}
+
+// updateForwardedPorts starts forwarding the ports that are new, and stops
+// forwarding the ones that went away.
+func updateForwardedPorts(container string,
+	ports []listeningPort,
+	forwarded map[int]forwardedPort,
+	ignored, inUse map[int]bool) {
+
+	listening := make(map[int]bool)
+
+	for _, target := range ports {
+		listening[target.port] = true
+
+		if _, ok := forwarded[target.port]; ok || ignored[target.port] || inUse[target.port] {
+			continue
+		}
+
+		address := net.JoinHostPort(publishHostIP, strconv.Itoa(target.port))
+		listener, err := net.Listen("tcp", address)
+		if err != nil {
+			logrus.Debugf("Listening on %s failed: %s", address, err)
+			showWarning("port %d of container %s is in use on macOS, and is not forwarded", target.port, container)
+			inUse[target.port] = true
+			continue
+		}
+
+		forwarded[target.port] = forwardedPort{listener: listener, target: target}
+
+		go func(listener net.Listener, target listeningPort) {
+			for {
+				conn, err := listener.Accept()
+				if err != nil {
+					return
+				}
+
+				go forwardPortConnection(container, target, conn)
+			}
+		}(listener, target)
+
+		showMessage("Forwarding port %d of container %s to localhost:%d", target.port, container, target.port)
+
+		if forwardPortsFlags.notify {
+			showNotification(fmt.Sprintf("Port %d of container %s is at localhost:%d", target.port, container, target.port))
+		}
+	}
+
+	for port, forwardedPort := range forwarded {
+		if listening[port] {
+			continue
+		}
+
+		forwardedPort.listener.Close()
+		delete(forwarded, port)
+		showMessage("Stopped forwarding port %d, which container %s no longer listens on", port, container)
+	}
+
+	for port := range inUse {
+		if !listening[port] {
+			delete(inUse, port)
+		}
+	}
+}
@@ -0,0 +1,281 @@
+//go:build darwin
+
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/containers/toolbox/pkg/i18n"
+	"github.com/containers/toolbox/pkg/shell"
+	"github.com/containers/toolbox/pkg/utils"
+	"github.com/google/renameio/v2"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	gatewayFlags struct {
+		port int
+	}
+)
+
+var gatewayCmd = &cobra.Command{
+	Use:               "gateway",
+	Short:             "Let JetBrains Gateway connect to a Toolbx container over SSH (macOS version)",
+	RunE:              gateway,
+	ValidArgsFunction: completionContainerNames,
+}
+
+func init() {
+	flags := gatewayCmd.Flags()
+
+	flags.IntVarP(&gatewayFlags.port,
+		"port",
+		"p",
+		2222,
+		"Forward this port at localhost on macOS to SSH inside the Toolbx container")
+
+	gatewayCmd.SetHelpFunc(gatewayHelp)
+	rootCmd.AddCommand(gatewayCmd)
+}
+
+// gateway sets up SSH inside the container for JetBrains Gateway, which only
+// knows how to connect to remote machines over SSH, adds a host for it to
+// ~/.ssh/config, and forwards the port at localhost to the container until
+// interrupted.  Every connection gets its own sshd(8) in inetd mode through
+// 'podman exec', so the container needn't publish any port, and its network
+// can't be reached from the Podman machine.
+func gateway(cmd *cobra.Command, args []string) error {
+	if utils.IsInsideContainer() {
+		return errors.New("gateway is not supported inside a container")
+	}
+
+	if len(args) != 1 {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "gateway needs a container\n")
+		i18n.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	if gatewayFlags.port < 1 || gatewayFlags.port > 65535 {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "invalid argument for '--port': %d\n", gatewayFlags.port)
+		i18n.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	container := args[0]
+
+	if _, err := getCapContainerDetails(container); err != nil {
+		return err
+	}
+
+	address := net.JoinHostPort(publishHostIP, strconv.Itoa(gatewayFlags.port))
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		logrus.Debugf("Listening on %s failed: %s", address, err)
+
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "failed to listen on port %d\n", gatewayFlags.port)
+		fmt.Fprintf(&builder, "Use a different one with '--port'.")
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	defer listener.Close()
+
+	keyFile, err := ensureSSHKey()
+	if err != nil {
+		return err
+	}
+
+	s := showSpinner(fmt.Sprintf("Starting container %s", container))
+	err = startContainerAndWait(container)
+	stopSpinner(s)
+
+	if err != nil {
+		return err
+	}
+
+	if err := setUpSSHD(container, keyFile); err != nil {
+		return err
+	}
+
+	host := "toolbox-" + container
+	if err := updateGatewaySSHConfig(host, gatewayFlags.port, keyFile); err != nil {
+		return err
+	}
+
+	showMessage("In JetBrains Gateway, connect over SSH to host %s, which was added to ~/.ssh/config,", host)
+	showMessage("or to %s@localhost at port %d with the key %s", currentUser.Username, gatewayFlags.port, keyFile)
+	showMessage("Forwarding port %d to container %s until interrupted", gatewayFlags.port, container)
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt)
+	defer signal.Stop(signals)
+
+	var closing bool
+	var mutex sync.Mutex
+
+	go func() {
+		<-signals
+
+		mutex.Lock()
+		closing = true
+		mutex.Unlock()
+
+		listener.Close()
+	}()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			if closing {
+				return nil
+			}
+
+			return fmt.Errorf("failed to accept a connection on port %d: %w", gatewayFlags.port, err)
+		}
+
+		go forwardGatewayConnection(container, conn)
+	}
+}
+
+func gatewayHelp(cmd *cobra.Command, args []string) {
+	if utils.IsInsideContainer() {
+		if !utils.IsInsideToolboxContainer() {
+			fmt.Fprintf(os.Stderr, "Error: this is not a Toolbx container\n")
+			return
+		}
+
+		if _, err := utils.ForwardToHost(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return
+		}
+
+		return
+	}
+
+	if err := showManual("toolbox-gateway"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return
+	}
+}
+
+// forwardGatewayConnection hands a connection over to sshd(8) in inetd mode
+// inside the container.
+func forwardGatewayConnection(container string, conn net.Conn) {
+	defer conn.Close()
+
+	logrus.Debugf("Forwarding connection from %s to container %s", conn.RemoteAddr(), container)
+
+	endContainerSession := startSSHDSession(container)
+	defer endContainerSession()
+
+	args := getSSHDExecArgs(container)
+
+	if err := shell.Run("podman", conn, conn, nil, args...); err != nil {
+		logrus.Debugf("Forwarding connection from %s to container %s failed: %s",
+			conn.RemoteAddr(),
+			container,
+			err)
+	}
+}
+
+// getGatewaySSHConfig returns ~/.ssh/config with the host for the container
+// added, or replacing an older one between the same markers.
+func getGatewaySSHConfig(config []byte, host string, port int, keyFile string) []byte {
+	begin := fmt.Sprintf("# BEGIN %s, added by toolbox gateway", host)
+	end := fmt.Sprintf("# END %s", host)
+
+	var entry bytes.Buffer
+	fmt.Fprintf(&entry, "%s\n", begin)
+	entry.WriteString(getSSHHostConfig(host, keyFile, "", port))
+	fmt.Fprintf(&entry, "%s\n", end)
+
+	if i := bytes.Index(config, []byte(begin+"\n")); i != -1 {
+		if j := bytes.Index(config[i:], []byte(end+"\n")); j != -1 {
+			var updated bytes.Buffer
+			updated.Write(config[:i])
+			updated.Write(entry.Bytes())
+			updated.Write(config[i+j+len(end)+1:])
+			return updated.Bytes()
+		}
+	}
+
+	var updated bytes.Buffer
+	updated.Write(config)
+
+	if len(config) != 0 {
+		if !bytes.HasSuffix(config, []byte("\n")) {
+			updated.WriteString("\n")
+		}
+
+		updated.WriteString("\n")
+	}
+
+	updated.Write(entry.Bytes())
+	return updated.Bytes()
+}
+
+// updateGatewaySSHConfig adds a host for the container to ~/.ssh/config,
+// because JetBrains Gateway offers the hosts in it.
+func updateGatewaySSHConfig(host string, port int, keyFile string) error {
+	homeDir := getCurrentUserHomeDir()
+	if homeDir == "" {
+		return errors.New("failed to find the home directory")
+	}
+
+	configFile := filepath.Join(homeDir, ".ssh", "config")
+
+	config, err := os.ReadFile(configFile)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to read %s: %w", configFile, err)
+	}
+
+	updated := getGatewaySSHConfig(config, host, port, keyFile)
+	if bytes.Equal(updated, config) {
+		return nil
+	}
+
+	logrus.Debugf("Adding host %s to %s", host, configFile)
+
+	if err := renameio.WriteFile(configFile, updated, 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", configFile, err)
+	}
+
+	return nil
+}
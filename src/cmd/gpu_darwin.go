@@ -0,0 +1,54 @@
+//go:build darwin
+
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"github.com/containers/toolbox/pkg/utils"
+)
+
+const (
+	// gpuDevice has the render node of the virtio-gpu of a Podman machine
+	// with the libkrun provider, which Mesa's Venus driver turns into
+	// Vulkan on top of Metal on the Mac.
+	gpuDevice = "/dev/dri"
+
+	gpuFeature = "gpu"
+
+	gpuLabel = "com.github.containers.toolbox.gpu"
+)
+
+// getGPUArgs returns the options for 'podman create' that '--gpu' needs.
+// It's an experimental feature, because only some Podman machines have a
+// GPU, and only Vulkan works through it.
+func getGPUArgs() ([]string, error) {
+	if !createFlags.gpu {
+		return nil, nil
+	}
+
+	if !utils.IsFeatureEnabled(gpuFeature) {
+		return nil, createErrorFeatureDisabled(gpuFeature)
+	}
+
+	args := []string{
+		"--device", gpuDevice,
+		"--label", gpuLabel + "=true",
+	}
+
+	return args, nil
+}
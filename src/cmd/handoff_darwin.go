@@ -0,0 +1,204 @@
+//go:build darwin
+
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/containers/toolbox/pkg/i18n"
+	"github.com/containers/toolbox/pkg/podman"
+	"github.com/containers/toolbox/pkg/shell"
+	"github.com/containers/toolbox/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var handoffCmd = &cobra.Command{
+	Use:               "handoff",
+	Short:             "Recreate a Toolbx container on a remote Linux machine (macOS version)",
+	RunE:              handoff,
+	ValidArgsFunction: completionContainerNamesFiltered,
+}
+
+func init() {
+	handoffCmd.SetHelpFunc(handoffHelp)
+	rootCmd.AddCommand(handoffCmd)
+}
+
+// handoff commits the container into an image, streams it to the remote
+// machine over ssh(1) with 'podman save' and 'podman load', and runs 'toolbox
+// create' there.  The remote machine needs Podman and Toolbx, and the same CPU
+// architecture, because the image is copied as it is.
+func handoff(cmd *cobra.Command, args []string) error {
+	if utils.IsInsideContainer() {
+		return errors.New("handoff is not supported inside a container")
+	}
+
+	if len(args) != 2 {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "handoff needs a container and a remote machine, eg., user@host\n")
+		i18n.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	container := args[0]
+	destination := args[1]
+
+	containerObj, err := podman.InspectContainer(container)
+	if err != nil {
+		return createErrorContainerNotFound(container)
+	}
+
+	if !containerObj.IsToolbx() {
+		return fmt.Errorf("%s is not a Toolbx container", container)
+	}
+
+	if err := checkHandoffArchitecture(containerObj.Image(), destination); err != nil {
+		return err
+	}
+
+	image := "localhost/" + container + ":handoff"
+
+	s := showSpinner(fmt.Sprintf("Committing container %s", container))
+	err = podman.Commit(container, image)
+	stopSpinner(s)
+
+	if err != nil {
+		return fmt.Errorf("failed to commit container %s: %w", container, err)
+	}
+
+	defer func() {
+		if err := podman.RemoveImage(image, false); err != nil {
+			logrus.Debugf("Removing image %s failed: %s", image, err)
+		}
+	}()
+
+	s = showSpinner(fmt.Sprintf("Copying image %s to %s", image, destination))
+	err = copyImageOverSSH(image, destination)
+	stopSpinner(s)
+
+	if err != nil {
+		return err
+	}
+
+	showStatus("Creating container %s on %s", container, destination)
+
+	createArgs := []string{"-t", destination, "toolbox", "create", "--image", image, container}
+	if err := shell.Run("ssh", os.Stdin, os.Stdout, os.Stderr, createArgs...); err != nil {
+		return fmt.Errorf("failed to create container %s on %s: %w", container, destination, err)
+	}
+
+	showMessage("Enter it on %s with 'toolbox enter %s'", destination, container)
+	return nil
+}
+
+func handoffHelp(cmd *cobra.Command, args []string) {
+	if utils.IsInsideContainer() {
+		if !utils.IsInsideToolboxContainer() {
+			fmt.Fprintf(os.Stderr, "Error: this is not a Toolbx container\n")
+			return
+		}
+
+		if _, err := utils.ForwardToHost(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return
+		}
+
+		return
+	}
+
+	if err := showManual("toolbox-handoff"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return
+	}
+}
+
+// checkHandoffArchitecture refuses to copy an image to a machine that can't
+// run it, which is common when moving from an Apple silicon Mac to an x86_64
+// workstation.  Such images need to be rebuilt for the other architecture.
+func checkHandoffArchitecture(image, destination string) error {
+	info, err := podman.InspectImage(image)
+	if err != nil {
+		logrus.Debugf("Inspecting image %s failed: %s", image, err)
+		return nil
+	}
+
+	architecture, _ := info["Architecture"].(string)
+
+	var stdout bytes.Buffer
+	if err := shell.Run("ssh", nil, &stdout, nil, destination, "uname", "-m"); err != nil {
+		logrus.Debugf("Reading the architecture of %s failed: %s", destination, err)
+		return fmt.Errorf("failed to connect to %s", destination)
+	}
+
+	remoteArchitecture := strings.TrimSpace(stdout.String())
+	logrus.Debugf("Image %s is for %s, and %s runs %s", image, architecture, destination, remoteArchitecture)
+
+	goArchitectures := map[string]string{
+		"aarch64": "arm64",
+		"x86_64":  "amd64",
+	}
+
+	if goArchitecture, ok := goArchitectures[remoteArchitecture]; ok {
+		remoteArchitecture = goArchitecture
+	}
+
+	if architecture != "" && architecture != remoteArchitecture {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "image %s is for %s, but %s is %s\n", image, architecture, destination, remoteArchitecture)
+		fmt.Fprintf(&builder, "Rebuild it for both with 'toolbox build --platforms' instead.")
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	return nil
+}
+
+func copyImageOverSSH(image, destination string) error {
+	reader, writer := io.Pipe()
+
+	saveErrCh := make(chan error, 1)
+
+	go func() {
+		err := podman.Save(image, writer)
+		writer.CloseWithError(err)
+		saveErrCh <- err
+	}()
+
+	loadErr := shell.Run("ssh", reader, nil, nil, destination, "podman", "load")
+	reader.Close()
+
+	if err := <-saveErrCh; err != nil {
+		return fmt.Errorf("failed to save image %s: %w", image, err)
+	}
+
+	if loadErr != nil {
+		return fmt.Errorf("failed to load image %s on %s: %w", image, destination, loadErr)
+	}
+
+	return nil
+}
@@ -0,0 +1,608 @@
+//go:build darwin
+
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"archive/tar"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/containers/toolbox/pkg/i18n"
+	"github.com/containers/toolbox/pkg/podman"
+	"github.com/containers/toolbox/pkg/term"
+	"github.com/containers/toolbox/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// prefetchResult is how pulling one of the images of 'toolbox images
+// prefetch' went.
+type prefetchResult struct {
+	err   error
+	image string
+}
+
+var (
+	imagesPrefetchFlags struct {
+		authFile string
+		jobs     int
+	}
+
+	imagesSaveFlags struct {
+		output string
+	}
+
+	// imageArchiveTransports are the prefixes of '--image' for images in
+	// a local OCI layout or archive, as in containers-transports(5).
+	imageArchiveTransports = []string{"docker-archive:", "oci:", "oci-archive:"}
+)
+
+var imagesCmd = &cobra.Command{
+	Use:   "images",
+	Short: "Prefetch, save and load images for Macs away from a registry (macOS version)",
+	RunE:  imagesRun,
+}
+
+var imagesLoadCmd = &cobra.Command{
+	Use:   "load",
+	Short: "Load images from archives or OCI layouts",
+	RunE:  imagesLoad,
+}
+
+var imagesPrefetchCmd = &cobra.Command{
+	Use:               "prefetch",
+	Short:             "Pull the images of the configured distros in one go",
+	RunE:              imagesPrefetch,
+	ValidArgsFunction: completionDistroNames,
+}
+
+var imagesSaveCmd = &cobra.Command{
+	Use:               "save",
+	Short:             "Save images into an archive",
+	RunE:              imagesSave,
+	ValidArgsFunction: completionImageNames,
+}
+
+func init() {
+	flags := imagesPrefetchCmd.Flags()
+
+	flags.StringVar(&imagesPrefetchFlags.authFile,
+		"authfile",
+		"",
+		"Path to a file with credentials for authenticating to the registries for private images")
+
+	flags.IntVarP(&imagesPrefetchFlags.jobs,
+		"jobs",
+		"j",
+		3,
+		"Pull this many images at the same time")
+
+	flags = imagesSaveCmd.Flags()
+
+	flags.StringVarP(&imagesSaveFlags.output,
+		"output",
+		"o",
+		"",
+		"Write the archive to this file, instead of the standard output")
+
+	imagesCmd.AddCommand(imagesLoadCmd)
+	imagesCmd.AddCommand(imagesPrefetchCmd)
+	imagesCmd.AddCommand(imagesSaveCmd)
+
+	imagesCmd.SetHelpFunc(imagesHelp)
+	rootCmd.AddCommand(imagesCmd)
+}
+
+func imagesRun(cmd *cobra.Command, args []string) error {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "missing command for \"images\", eg., load, prefetch or save\n")
+	i18n.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+	errMsg := builder.String()
+	return errors.New(errMsg)
+}
+
+func imagesLoad(cmd *cobra.Command, args []string) error {
+	if utils.IsInsideContainer() {
+		return errors.New("images is not supported inside a container")
+	}
+
+	if len(args) == 0 {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "images load needs at least one archive or OCI layout\n")
+		i18n.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	for _, arg := range args {
+		path := arg
+		if archivePath, ok := getImageArchivePath(arg); ok {
+			path = archivePath
+		}
+
+		images, err := loadImageArchive(path)
+		if err != nil {
+			return err
+		}
+
+		for _, image := range images {
+			showMessage("Loaded image %s", image)
+		}
+	}
+
+	return nil
+}
+
+// imagesPrefetch pulls the images of DISTRO[:RELEASE] or IMAGE arguments, or
+// else the default image and those in 'prefetch' in the general section of
+// the configuration, several at a time, and shows how far along they are
+// together.  The images are pulled even if they are already there, to bring
+// them up to date.
+func imagesPrefetch(cmd *cobra.Command, args []string) error {
+	if utils.IsInsideContainer() {
+		return errors.New("images is not supported inside a container")
+	}
+
+	if imagesPrefetchFlags.jobs < 1 {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "invalid argument for '--jobs': %d\n", imagesPrefetchFlags.jobs)
+		fmt.Fprintf(&builder, "At least one image has to be pulled at a time.\n")
+		i18n.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	if isOffline() {
+		return errors.New("images aren't pulled offline")
+	}
+
+	specs := args
+	if len(specs) == 0 {
+		specs = append([]string{""}, viper.GetStringSlice("general.prefetch")...)
+	}
+
+	var images []string
+
+	for _, spec := range specs {
+		image, err := resolvePrefetchImage(spec)
+		if err != nil {
+			return err
+		}
+
+		if !slices.Contains(images, image) {
+			images = append(images, image)
+		}
+	}
+
+	mirrors, err := getRegistryMirrors()
+	if err != nil {
+		return err
+	}
+
+	results := prefetchImages(images, mirrors, imagesPrefetchFlags.jobs)
+
+	var failed int
+
+	for _, result := range results {
+		if result.err != nil {
+			logrus.Debugf("Pulling image %s failed: %s", result.image, result.err)
+			showWarning("failed to pull image %s", result.image)
+			failed++
+			continue
+		}
+
+		showMessage("Pulled image %s", result.image)
+	}
+
+	if failed != 0 {
+		return fmt.Errorf("failed to pull %d of %d images", failed, len(images))
+	}
+
+	return nil
+}
+
+// imagesSave saves the images into a Docker archive, because it can hold more
+// than one image, and keeps their names, unlike an OCI layout.
+func imagesSave(cmd *cobra.Command, args []string) error {
+	if utils.IsInsideContainer() {
+		return errors.New("images is not supported inside a container")
+	}
+
+	if len(args) == 0 {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "images save needs at least one image\n")
+		i18n.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	for _, image := range args {
+		if exists, _ := podman.ImageExists(image); !exists {
+			return fmt.Errorf("image %s not found", image)
+		}
+	}
+
+	output := imagesSaveFlags.output
+	if output == "" {
+		if term.IsTerminal(os.Stdout) {
+			var builder strings.Builder
+			fmt.Fprintf(&builder, "images save doesn't write an archive to a terminal\n")
+			fmt.Fprintf(&builder, "Use '--output FILE', or redirect the standard output.")
+
+			errMsg := builder.String()
+			return errors.New(errMsg)
+		}
+
+		if err := podman.SaveImages(args, os.Stdout); err != nil {
+			return fmt.Errorf("failed to save images: %w", err)
+		}
+
+		return nil
+	}
+
+	file, err := os.Create(output)
+	if err != nil {
+		logrus.Debugf("Creating %s failed: %s", output, err)
+		return fmt.Errorf("failed to create %s", output)
+	}
+
+	s := showSpinner(fmt.Sprintf("Saving images into %s", output))
+	err = podman.SaveImages(args, file)
+	stopSpinner(s)
+
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		os.Remove(output)
+		return fmt.Errorf("failed to save images into %s: %w", output, err)
+	}
+
+	showMessage("Saved %s into %s", strings.Join(args, ", "), output)
+	return nil
+}
+
+// getImageArchiveName returns the name that an image without one gets when
+// it's loaded from the archive or OCI layout at path, eg., localhost/dev:latest
+// for dev.tar.
+func getImageArchiveName(path string) string {
+	name := filepath.Base(path)
+	for _, extension := range []string{".tar", ".tar.gz", ".tgz"} {
+		if strings.HasSuffix(name, extension) && name != extension {
+			name = strings.TrimSuffix(name, extension)
+			break
+		}
+	}
+
+	name = strings.ToLower(name)
+	name = regexp.MustCompile(`[^a-z0-9._-]+`).ReplaceAllString(name, "-")
+	name = strings.Trim(name, "._-")
+	if name == "" {
+		name = "image"
+	}
+
+	return "localhost/" + name + ":latest"
+}
+
+// getImageArchivePath returns the path of an image that '--image' refers to
+// with the docker-archive, oci or oci-archive transport.
+func getImageArchivePath(image string) (string, bool) {
+	for _, transport := range imageArchiveTransports {
+		if path, ok := strings.CutPrefix(image, transport); ok {
+			return path, true
+		}
+	}
+
+	return "", false
+}
+
+// loadImageArchive loads the images in a Docker or OCI archive, or an OCI
+// layout, which is turned into an OCI archive on the fly, into the Podman
+// machine.  Images without a name are named after the path.
+func loadImageArchive(path string) ([]string, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find %s: %w", path, err)
+	}
+
+	fileInfo, err := os.Stat(path)
+	if err != nil {
+		logrus.Debugf("Loading images from %s failed: %s", path, err)
+		return nil, fmt.Errorf("%s not found", path)
+	}
+
+	var reader io.ReadCloser
+	errCh := make(chan error, 1)
+
+	if fileInfo.IsDir() {
+		if !utils.PathExists(filepath.Join(path, "index.json")) {
+			return nil, fmt.Errorf("%s is not an OCI layout", path)
+		}
+
+		pipeReader, pipeWriter := io.Pipe()
+
+		go func() {
+			err := writeOCILayout(path, pipeWriter)
+			pipeWriter.CloseWithError(err)
+			errCh <- err
+		}()
+
+		reader = pipeReader
+	} else {
+		file, err := os.Open(path)
+		if err != nil {
+			logrus.Debugf("Loading images from %s failed: %s", path, err)
+			return nil, fmt.Errorf("failed to open %s", path)
+		}
+
+		reader = file
+		errCh <- nil
+	}
+
+	s := showSpinner(fmt.Sprintf("Loading images from %s", path))
+	images, loadErr := podman.LoadImages(reader)
+	reader.Close()
+	stopSpinner(s)
+
+	if err := <-errCh; err != nil {
+		return nil, fmt.Errorf("failed to read OCI layout %s: %w", path, err)
+	}
+
+	if loadErr != nil {
+		return nil, fmt.Errorf("failed to load images from %s: %w", path, loadErr)
+	}
+
+	for i, image := range images {
+		if !strings.HasPrefix(image, "sha256:") {
+			continue
+		}
+
+		name := getImageArchiveName(path)
+		if err := podman.Tag(image, name); err != nil {
+			return nil, fmt.Errorf("failed to tag image %s as %s: %w", image, name, err)
+		}
+
+		images[i] = name
+	}
+
+	return images, nil
+}
+
+// loadImageForCreate loads the image that '--image' refers to with a
+// transport, and returns its name.
+func loadImageForCreate(path string) (string, error) {
+	images, err := loadImageArchive(path)
+	if err != nil {
+		return "", err
+	}
+
+	switch len(images) {
+	case 0:
+		return "", fmt.Errorf("no images found in %s", path)
+	case 1:
+		return images[0], nil
+	}
+
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "%s has more than one image: %s\n", path, strings.Join(images, ", "))
+	fmt.Fprintf(&builder, "They were loaded, so choose one with '--image NAME'.")
+
+	errMsg := builder.String()
+	return "", errors.New(errMsg)
+}
+
+func imagesHelp(cmd *cobra.Command, args []string) {
+	if utils.IsInsideContainer() {
+		if !utils.IsInsideToolboxContainer() {
+			fmt.Fprintf(os.Stderr, "Error: this is not a Toolbx container\n")
+			return
+		}
+
+		if _, err := utils.ForwardToHost(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return
+		}
+
+		return
+	}
+
+	if err := showManual("toolbox-images"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return
+	}
+}
+
+// prefetchImage pulls an image from its mirrors, and then from its registry,
+// without showing anything.
+func prefetchImage(image string, mirrors []registryMirror) error {
+	for _, mirrorImage := range getMirrorImages(image, mirrors) {
+		if err := pullImageFromMirror(image, mirrorImage, imagesPrefetchFlags.authFile); err != nil {
+			logrus.Debugf("Pulling image %s from mirror %s failed: %s", image, mirrorImage, err)
+			continue
+		}
+
+		return nil
+	}
+
+	logrus.Debugf("Pulling image %s", image)
+	err := podman.Pull(image, imagesPrefetchFlags.authFile)
+	return err
+}
+
+// prefetchImages pulls the images with as many workers as jobs, and returns
+// the results in the order of the images.
+func prefetchImages(images []string, mirrors []registryMirror, jobs int) []prefetchResult {
+	results := make([]prefetchResult, len(images))
+	indexes := make(chan int)
+
+	var done int
+	var mutex sync.Mutex
+	var waitGroup sync.WaitGroup
+
+	progress := func() string {
+		return fmt.Sprintf("Pulling %d images (%d done)", len(images), done)
+	}
+
+	s := showSpinner(progress())
+
+	for i := 0; i < min(jobs, len(images)); i++ {
+		waitGroup.Add(1)
+
+		go func() {
+			defer waitGroup.Done()
+
+			for index := range indexes {
+				image := images[index]
+				err := prefetchImage(image, mirrors)
+				results[index] = prefetchResult{err: err, image: image}
+
+				mutex.Lock()
+				done++
+				message := progress()
+				mutex.Unlock()
+
+				if s == nil {
+					showStatus("%s", message)
+					continue
+				}
+
+				s.Lock()
+				s.Prefix = message + " "
+				s.Unlock()
+			}
+		}()
+	}
+
+	for index := range images {
+		indexes <- index
+	}
+
+	close(indexes)
+	waitGroup.Wait()
+	stopSpinner(s)
+
+	return results
+}
+
+// resolvePrefetchImage resolves DISTRO[:RELEASE] like 'toolbox create
+// --distro DISTRO --release RELEASE', and an empty spec to the default image.
+// Anything else is an image.  Images without a registry are resolved through
+// the aliases, or like the images of the supported distros.
+func resolvePrefetchImage(spec string) (string, error) {
+	distro, release, _ := strings.Cut(spec, ":")
+
+	image := spec
+	if spec == "" || slices.Contains(utils.GetSupportedDistros(), distro) {
+		var err error
+		_, image, release, err = utils.ResolveContainerAndImageNames("", distro, "", release)
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve %s: %w", spec, err)
+		}
+	} else {
+		release = utils.ImageReferenceGetTag(image)
+	}
+
+	image, err := resolveImageAlias(image, release)
+	if err != nil {
+		return "", err
+	}
+
+	return image, nil
+}
+
+// writeOCILayout writes the OCI layout in directory as an OCI archive, which
+// is a tarball of it.
+func writeOCILayout(directory string, writer io.Writer) error {
+	tarWriter := tar.NewWriter(writer)
+
+	err := filepath.WalkDir(directory, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		name, err := filepath.Rel(directory, path)
+		if err != nil {
+			return err
+		}
+
+		if name == "." {
+			return nil
+		}
+
+		if !entry.IsDir() && !entry.Type().IsRegular() {
+			return fmt.Errorf("%s is not a regular file", path)
+		}
+
+		fileInfo, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		header, err := tar.FileInfoHeader(fileInfo, "")
+		if err != nil {
+			return err
+		}
+
+		header.Name = filepath.ToSlash(name)
+		if entry.IsDir() {
+			header.Name += "/"
+		}
+
+		if err := tarWriter.WriteHeader(header); err != nil {
+			return err
+		}
+
+		if entry.IsDir() {
+			return nil
+		}
+
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+
+		defer file.Close()
+
+		if _, err := io.Copy(tarWriter, file); err != nil {
+			return err
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return tarWriter.Close()
+}
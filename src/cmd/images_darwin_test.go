@@ -0,0 +1,150 @@
+//go:build darwin
+
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetImageArchiveName(t *testing.T) {
+	testCases := []struct {
+		path     string
+		expected string
+	}{
+		{"/Users/alice/dev.tar", "localhost/dev:latest"},
+		{"/Users/alice/Fedora Toolbox.tar.gz", "localhost/fedora-toolbox:latest"},
+		{"/Users/alice/images/toolbox-oci", "localhost/toolbox-oci:latest"},
+		{"/Users/alice/.tar", "localhost/tar:latest"},
+		{"/Users/alice/___", "localhost/image:latest"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.path, func(t *testing.T) {
+			name := getImageArchiveName(tc.path)
+			assert.Equal(t, tc.expected, name)
+		})
+	}
+}
+
+func TestGetImageArchivePath(t *testing.T) {
+	testCases := []struct {
+		image    string
+		path     string
+		archived bool
+	}{
+		{"oci:/Users/alice/layout", "/Users/alice/layout", true},
+		{"oci-archive:/Users/alice/dev.tar", "/Users/alice/dev.tar", true},
+		{"docker-archive:dev.tar", "dev.tar", true},
+		{"registry.fedoraproject.org/fedora-toolbox:42", "", false},
+		{"fedora-toolbox:42", "", false},
+		{"docker://quay.io/toolbx/ubuntu-toolbox:24.04", "", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.image, func(t *testing.T) {
+			path, archived := getImageArchivePath(tc.image)
+			assert.Equal(t, tc.archived, archived)
+			assert.Equal(t, tc.path, path)
+		})
+	}
+}
+
+func TestResolvePrefetchImage(t *testing.T) {
+	testCases := []struct {
+		spec     string
+		expected string
+	}{
+		{"fedora:41", "registry.fedoraproject.org/fedora-toolbox:41"},
+		{"ubuntu:24.04", "quay.io/toolbx/ubuntu-toolbox:24.04"},
+		{"fedora-toolbox:41", "registry.fedoraproject.org/fedora-toolbox:41"},
+		{"registry.example.com/toolbx/dev:1.0", "registry.example.com/toolbx/dev:1.0"},
+		{"dev", "dev"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.spec, func(t *testing.T) {
+			image, err := resolvePrefetchImage(tc.spec)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, image)
+		})
+	}
+
+	_, err := resolvePrefetchImage("ubuntu")
+	assert.Error(t, err)
+}
+
+func TestWriteOCILayout(t *testing.T) {
+	directory := t.TempDir()
+
+	files := map[string]string{
+		"oci-layout":            `{"imageLayoutVersion": "1.0.0"}`,
+		"index.json":            `{"schemaVersion": 2, "manifests": []}`,
+		"blobs/sha256/0123abcd": "blob",
+	}
+
+	for name, data := range files {
+		path := filepath.Join(directory, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(data), 0644))
+	}
+
+	var archive bytes.Buffer
+	require.NoError(t, writeOCILayout(directory, &archive))
+
+	var directories []string
+	written := make(map[string]string)
+
+	tarReader := tar.NewReader(&archive)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+
+		require.NoError(t, err)
+
+		if header.Typeflag == tar.TypeDir {
+			directories = append(directories, header.Name)
+			continue
+		}
+
+		data, err := io.ReadAll(tarReader)
+		require.NoError(t, err)
+		written[header.Name] = string(data)
+	}
+
+	assert.Equal(t, []string{"blobs/", "blobs/sha256/"}, directories)
+	assert.Equal(t, files, written)
+}
+
+func TestWriteOCILayoutSymlink(t *testing.T) {
+	directory := t.TempDir()
+	require.NoError(t, os.Symlink("/etc/passwd", filepath.Join(directory, "index.json")))
+
+	err := writeOCILayout(directory, io.Discard)
+	assert.Error(t, err)
+}
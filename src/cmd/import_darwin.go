@@ -0,0 +1,387 @@
+//go:build darwin
+
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/containers/toolbox/pkg/docker"
+	"github.com/containers/toolbox/pkg/i18n"
+	"github.com/containers/toolbox/pkg/podman"
+	"github.com/containers/toolbox/pkg/utils"
+	"github.com/docker/go-units"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	importDockerFlags struct {
+		all bool
+	}
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Import images from other container engines (macOS version)",
+	RunE:  importRun,
+}
+
+var importDockerCmd = &cobra.Command{
+	Use:               "docker",
+	Short:             "Import images and containers from Docker Desktop",
+	RunE:              importDocker,
+	ValidArgsFunction: completionDockerNames,
+}
+
+func init() {
+	flags := importDockerCmd.Flags()
+
+	flags.BoolVarP(&importDockerFlags.all,
+		"all",
+		"a",
+		false,
+		"Import all tagged images from Docker Desktop")
+
+	importCmd.AddCommand(importDockerCmd)
+
+	importCmd.SetHelpFunc(importHelp)
+	rootCmd.AddCommand(importCmd)
+}
+
+func importRun(cmd *cobra.Command, args []string) error {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "missing command for \"import\", eg., docker\n")
+	i18n.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+	errMsg := builder.String()
+	return errors.New(errMsg)
+}
+
+// importDocker streams images out of Docker Desktop with 'docker save' and
+// into the Podman machine with 'podman load', so that nothing is downloaded
+// again.  Containers are committed into images first, because only images
+// can be moved between the two.  Without arguments, it lists what can be
+// imported.
+func importDocker(cmd *cobra.Command, args []string) error {
+	if utils.IsInsideContainer() {
+		return errors.New("import is not supported inside a container")
+	}
+
+	if importDockerFlags.all && len(args) != 0 {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "'--all' can't be used with images or containers\n")
+		i18n.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	images, err := docker.GetImages()
+	if err != nil {
+		return createErrorDocker(err)
+	}
+
+	containers, err := docker.GetContainers()
+	if err != nil {
+		return createErrorDocker(err)
+	}
+
+	if !importDockerFlags.all && len(args) == 0 {
+		listDockerOutput(images, containers)
+		return nil
+	}
+
+	if importDockerFlags.all {
+		for _, image := range images {
+			for _, tag := range image.RepoTags {
+				if tag == "<none>:<none>" {
+					continue
+				}
+
+				args = append(args, tag)
+			}
+		}
+	}
+
+	for _, arg := range args {
+		if container, ok := findDockerContainer(containers, arg); ok {
+			if err := importDockerContainer(container); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		image, ok := findDockerImage(images, arg)
+		if !ok {
+			return fmt.Errorf("no Docker image or container %s", arg)
+		}
+
+		if err := importDockerImage(image); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func importHelp(cmd *cobra.Command, args []string) {
+	if utils.IsInsideContainer() {
+		if !utils.IsInsideToolboxContainer() {
+			fmt.Fprintf(os.Stderr, "Error: this is not a Toolbx container\n")
+			return
+		}
+
+		if _, err := utils.ForwardToHost(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return
+		}
+
+		return
+	}
+
+	if err := showManual("toolbox-import"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return
+	}
+}
+
+func completionDockerNames(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+	var names []string
+
+	if images, err := docker.GetImages(); err == nil {
+		for _, image := range images {
+			for _, tag := range image.RepoTags {
+				if tag != "<none>:<none>" {
+					names = append(names, tag)
+				}
+			}
+		}
+	}
+
+	if containers, err := docker.GetContainers(); err == nil {
+		for _, container := range containers {
+			names = append(names, container.Names...)
+		}
+	}
+
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+func createErrorDocker(err error) error {
+	if !errors.Is(err, docker.ErrNotRunning) {
+		return fmt.Errorf("failed to talk to Docker: %w", err)
+	}
+
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "Docker is not running\n")
+	fmt.Fprintf(&builder, "Start Docker Desktop, or set DOCKER_HOST to the socket of the Docker Engine.")
+
+	errMsg := builder.String()
+	return errors.New(errMsg)
+}
+
+// findDockerContainer matches a name or a prefix of an ID, like the docker(1)
+// command line.
+func findDockerContainer(containers []docker.Container, nameOrID string) (docker.Container, bool) {
+	for _, container := range containers {
+		for _, name := range container.Names {
+			if name == nameOrID {
+				return container, true
+			}
+		}
+	}
+
+	for _, container := range containers {
+		if strings.HasPrefix(container.ID, nameOrID) {
+			return container, true
+		}
+	}
+
+	return docker.Container{}, false
+}
+
+// findDockerImage matches a tag, with ':latest' if none was given, or a prefix
+// of an ID, and returns what to import.  That's the tag, if there was one,
+// because an image loaded by ID has no name in Podman.
+func findDockerImage(images []docker.Image, nameOrID string) (string, bool) {
+	tagged := nameOrID
+	if utils.ImageReferenceGetTag(nameOrID) == "" {
+		tagged = nameOrID + ":latest"
+	}
+
+	for _, image := range images {
+		for _, tag := range image.RepoTags {
+			if tag == tagged {
+				return tag, true
+			}
+		}
+	}
+
+	id := strings.TrimPrefix(nameOrID, "sha256:")
+
+	for _, image := range images {
+		if strings.HasPrefix(strings.TrimPrefix(image.ID, "sha256:"), id) {
+			if len(image.RepoTags) != 0 && image.RepoTags[0] != "<none>:<none>" {
+				return image.RepoTags[0], true
+			}
+
+			return image.ID, true
+		}
+	}
+
+	return "", false
+}
+
+// importDockerContainer commits the container into a temporary image in
+// Docker, imports it, and removes it from Docker again.
+func importDockerContainer(container docker.Container) error {
+	name := container.ID
+	if len(container.Names) != 0 {
+		name = container.Names[0]
+	}
+
+	repository := "localhost/" + strings.ToLower(name)
+	const tag = "imported"
+	image := repository + ":" + tag
+
+	s := showSpinner(fmt.Sprintf("Committing Docker container %s", name))
+	id, err := docker.Commit(container.ID, repository, tag)
+	stopSpinner(s)
+
+	if err != nil {
+		return fmt.Errorf("failed to commit Docker container %s: %w", name, err)
+	}
+
+	defer func() {
+		if err := docker.RemoveImage(id); err != nil {
+			logrus.Debugf("Removing Docker image %s failed: %s", id, err)
+		}
+	}()
+
+	if err := importDockerImage(image); err != nil {
+		return err
+	}
+
+	showMessage("Create a Toolbx container from it with 'toolbox create --image %s'", image)
+	return nil
+}
+
+func importDockerImage(image string) error {
+	if exists, _ := podman.ImageExists(image); exists {
+		showMessage("Image %s is already in Podman", image)
+		return nil
+	}
+
+	s := showSpinner(fmt.Sprintf("Importing image %s", image))
+	err := copyImageFromDocker(image)
+	stopSpinner(s)
+
+	if err != nil {
+		return err
+	}
+
+	showMessage("Imported image %s", image)
+	return nil
+}
+
+func copyImageFromDocker(image string) error {
+	reader, writer := io.Pipe()
+
+	saveErrCh := make(chan error, 1)
+
+	go func() {
+		err := docker.Save(image, writer)
+		writer.CloseWithError(err)
+		saveErrCh <- err
+	}()
+
+	loadErr := podman.Load(reader)
+	reader.Close()
+
+	if err := <-saveErrCh; err != nil {
+		return fmt.Errorf("failed to save Docker image %s: %w", image, err)
+	}
+
+	if loadErr != nil {
+		return fmt.Errorf("failed to load image %s: %w", image, loadErr)
+	}
+
+	return nil
+}
+
+func listDockerOutput(images []docker.Image, containers []docker.Container) {
+	if len(images) == 0 && len(containers) == 0 {
+		showMessage("Docker has no images or containers to import")
+		return
+	}
+
+	if len(images) != 0 {
+		writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n", "IMAGE ID", "IMAGE NAME", "CREATED", "SIZE")
+
+		for _, image := range images {
+			id := utils.ShortID(strings.TrimPrefix(image.ID, "sha256:"))
+			created := utils.HumanDuration(image.Created)
+			size := units.HumanSize(float64(image.Size))
+
+			names := image.RepoTags
+			if len(names) == 0 {
+				names = []string{"<none>:<none>"}
+			}
+
+			for _, name := range names {
+				fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n", id, name, created, size)
+			}
+		}
+
+		writer.Flush()
+	}
+
+	if len(images) != 0 && len(containers) != 0 {
+		fmt.Println()
+	}
+
+	if len(containers) != 0 {
+		writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n", "CONTAINER ID", "CONTAINER NAME", "STATUS", "IMAGE NAME")
+
+		for _, container := range containers {
+			name := strings.Join(container.Names, ",")
+			fmt.Fprintf(writer,
+				"%s\t%s\t%s\t%s\n",
+				utils.ShortID(container.ID),
+				name,
+				container.State,
+				container.Image)
+		}
+
+		writer.Flush()
+	}
+
+	if !rootFlags.quiet {
+		fmt.Println()
+		showMessage("Import them with 'toolbox import docker IMAGE|CONTAINER...'")
+	}
+}
@@ -0,0 +1,413 @@
+//go:build darwin
+
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/containers/toolbox/pkg/i18n"
+	"github.com/containers/toolbox/pkg/podman"
+	"github.com/containers/toolbox/pkg/shell"
+	"github.com/containers/toolbox/pkg/utils"
+	"github.com/containers/toolbox/pkg/version"
+	"github.com/docker/go-units"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// infoFormatVersion is increased when fields are renamed or removed from the
+// output of 'toolbox info --json', but not when new ones are added.
+const infoFormatVersion = 1
+
+// infoReport is what 'toolbox info' shows.  The names of the fields are
+// used as they are as JSON keys, which scripts depend on.  Sizes are in bytes.
+type infoReport struct {
+	FormatVersion int
+	Config        map[string]interface{}
+	Engine        infoEngine
+	Host          infoHost
+	Machine       infoMachine
+	Toolbox       infoToolbox
+}
+
+type infoEngine struct {
+	ClientVersion string
+	Connection    string
+	Containers    int
+	Error         string `json:",omitempty"`
+	Images        int
+	ServerVersion string
+	StorageDriver string
+}
+
+type infoHost struct {
+	Arch           string
+	CPUs           int
+	Error          string `json:",omitempty"`
+	Memory         uint64
+	ProductBuild   string
+	ProductVersion string
+	Translated     bool
+}
+
+type infoMachine struct {
+	Arch          string
+	CPUs          int
+	DiskSize      uint64
+	Distro        string
+	DistroVersion string
+	Error         string `json:",omitempty"`
+	Kernel        string
+	Memory        uint64
+	Name          string
+	Rootful       bool
+	State         string
+}
+
+type infoToolbox struct {
+	ConfigFiles []string
+	Containers  int
+	Error       string `json:",omitempty"`
+	Executable  string
+	Features    []string
+	SystemMode  bool
+	Version     string
+}
+
+var (
+	infoFlags struct {
+		json bool
+	}
+)
+
+var infoCmd = &cobra.Command{
+	Use:               "info",
+	Short:             "Show facts about the host, Podman and Toolbx (macOS version)",
+	RunE:              info,
+	ValidArgsFunction: completionEmpty,
+}
+
+func init() {
+	flags := infoCmd.Flags()
+
+	flags.BoolVar(&infoFlags.json,
+		"json",
+		false,
+		"Show the facts as JSON, for scripts")
+
+	infoCmd.SetHelpFunc(infoHelp)
+	rootCmd.AddCommand(infoCmd)
+}
+
+// info gathers what support scripts, IDE plugins and inventory tools ask
+// about, without changing anything.  Whatever can't be found out is reported
+// in the Error field of its section, so that a stopped Podman machine or a
+// missing Podman still leaves the rest of the facts usable.
+func info(cmd *cobra.Command, args []string) error {
+	if utils.IsInsideContainer() {
+		return errors.New("info is not supported inside a container")
+	}
+
+	if len(args) != 0 {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "unknown argument %s for \"info\"\n", args[0])
+		i18n.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	report := getInfoReport()
+
+	if infoFlags.json {
+		data, err := json.MarshalIndent(report, "", "    ")
+		if err != nil {
+			return fmt.Errorf("failed to encode JSON: %w", err)
+		}
+
+		fmt.Printf("%s\n", data)
+		return nil
+	}
+
+	showInfo(os.Stdout, report)
+	return nil
+}
+
+// getInfoEngine asks the Podman service, which also knows the operating system
+// of the Podman machine that it runs in.
+func getInfoEngine(machine infoMachine) (infoEngine, infoMachine) {
+	var engine infoEngine
+
+	engine.Connection = os.Getenv("CONTAINER_CONNECTION")
+
+	clientVersion, err := podman.GetVersion()
+	if err != nil {
+		logrus.Debugf("Getting the Podman version failed: %s", err)
+		engine.Error = "failed to get the Podman version"
+		return engine, machine
+	}
+
+	engine.ClientVersion = clientVersion
+
+	podmanInfo, err := podman.GetInfo()
+	if err != nil {
+		logrus.Debugf("Getting information from Podman failed: %s", err)
+		engine.Error = "failed to get information from the Podman service"
+		return engine, machine
+	}
+
+	engine.Containers = podmanInfo.Store.ContainerStore.Number
+	engine.Images = podmanInfo.Store.ImageStore.Number
+	engine.ServerVersion = podmanInfo.Version.Version
+	engine.StorageDriver = podmanInfo.Store.GraphDriverName
+
+	machine.Arch = podmanInfo.Host.Arch
+	machine.Distro = podmanInfo.Host.Distribution.Distribution
+	machine.DistroVersion = podmanInfo.Host.Distribution.Version
+	machine.Kernel = podmanInfo.Host.Kernel
+
+	return engine, machine
+}
+
+func getInfoHost() infoHost {
+	host := infoHost{
+		Arch: runtime.GOARCH,
+		CPUs: runtime.NumCPU(),
+	}
+
+	var err error
+	if host.ProductVersion, err = getHostValue("sw_vers", "-productVersion"); err != nil {
+		host.Error = "failed to get the macOS version"
+	}
+
+	if host.ProductBuild, err = getHostValue("sw_vers", "-buildVersion"); err != nil {
+		host.Error = "failed to get the macOS version"
+	}
+
+	if memory, err := getHostValue("sysctl", "-n", "hw.memsize"); err == nil {
+		host.Memory, _ = strconv.ParseUint(memory, 10, 64)
+	}
+
+	// The key doesn't exist on Intel Macs, so failures are not errors.
+	if translated, err := getHostValue("sysctl", "-n", "sysctl.proc_translated"); err == nil {
+		host.Translated = translated == "1"
+	}
+
+	return host
+}
+
+// getInfoMachine asks 'podman machine', which works while the Podman machine
+// is stopped.
+func getInfoMachine() infoMachine {
+	var machine infoMachine
+
+	podmanMachine, err := podman.MachineInspect()
+	if err != nil {
+		logrus.Debugf("Inspecting the Podman machine failed: %s", err)
+		machine.Error = "failed to inspect the Podman machine"
+		return machine
+	}
+
+	machine.CPUs = podmanMachine.Resources.CPUs
+	machine.DiskSize = podmanMachine.Resources.DiskSize * units.GiB
+	machine.Memory = podmanMachine.Resources.Memory * units.MiB
+	machine.Name = podmanMachine.Name
+	machine.Rootful = podmanMachine.Rootful
+	machine.State = podmanMachine.State
+
+	return machine
+}
+
+func getInfoToolbox(engineWorks bool) infoToolbox {
+	toolbox := infoToolbox{
+		ConfigFiles: []string{},
+		Executable:  executable,
+		Features:    []string{},
+		SystemMode:  systemMode,
+		Version:     version.GetVersion(),
+	}
+
+	if configFiles, err := utils.GetConfigurationFiles(); err == nil {
+		for _, configFile := range configFiles {
+			if utils.PathExists(configFile) {
+				toolbox.ConfigFiles = append(toolbox.ConfigFiles, configFile)
+			}
+		}
+	}
+
+	for _, feature := range utils.GetFeatures() {
+		if utils.IsFeatureEnabled(feature.Name) {
+			toolbox.Features = append(toolbox.Features, feature.Name)
+		}
+	}
+
+	if !engineWorks {
+		return toolbox
+	}
+
+	containers, err := getContainers()
+	if err != nil {
+		toolbox.Error = err.Error()
+		return toolbox
+	}
+
+	toolbox.Containers = len(containers)
+	return toolbox
+}
+
+func getInfoReport() infoReport {
+	config := viper.AllSettings()
+	if config == nil {
+		config = make(map[string]interface{})
+	}
+
+	report := infoReport{
+		FormatVersion: infoFormatVersion,
+		Config:        config,
+		Host:          getInfoHost(),
+		Machine:       getInfoMachine(),
+	}
+
+	report.Engine, report.Machine = getInfoEngine(report.Machine)
+	report.Toolbox = getInfoToolbox(report.Engine.Error == "")
+	return report
+}
+
+func getHostValue(name string, arg ...string) (string, error) {
+	var stdout bytes.Buffer
+	if err := shell.Run(name, nil, &stdout, nil, arg...); err != nil {
+		logrus.Debugf("Running %s %s failed: %s", name, strings.Join(arg, " "), err)
+		return "", err
+	}
+
+	value := strings.TrimSpace(stdout.String())
+	return value, nil
+}
+
+func infoHelp(cmd *cobra.Command, args []string) {
+	if utils.IsInsideContainer() {
+		if !utils.IsInsideToolboxContainer() {
+			fmt.Fprintf(os.Stderr, "Error: this is not a Toolbx container\n")
+			return
+		}
+
+		if _, err := utils.ForwardToHost(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return
+		}
+
+		return
+	}
+
+	if err := showManual("toolbox-info"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return
+	}
+}
+
+func showInfo(w io.Writer, report infoReport) {
+	writer := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	host := report.Host
+	fmt.Fprintf(writer, "Host:\n")
+	showInfoError(writer, host.Error)
+	fmt.Fprintf(writer, "  macOS:\t%s (%s)\n", host.ProductVersion, host.ProductBuild)
+
+	arch := host.Arch
+	if host.Translated {
+		arch += " (Rosetta)"
+	}
+
+	fmt.Fprintf(writer, "  Architecture:\t%s\n", arch)
+	fmt.Fprintf(writer, "  CPUs:\t%d\n", host.CPUs)
+	fmt.Fprintf(writer, "  Memory:\t%s\n", units.BytesSize(float64(host.Memory)))
+
+	engine := report.Engine
+	fmt.Fprintf(writer, "Podman:\n")
+	showInfoError(writer, engine.Error)
+	fmt.Fprintf(writer, "  Client:\t%s\n", engine.ClientVersion)
+	fmt.Fprintf(writer, "  Server:\t%s\n", engine.ServerVersion)
+
+	if engine.Connection != "" {
+		fmt.Fprintf(writer, "  Connection:\t%s\n", engine.Connection)
+	}
+
+	fmt.Fprintf(writer, "  Containers:\t%d\n", engine.Containers)
+	fmt.Fprintf(writer, "  Images:\t%d\n", engine.Images)
+	fmt.Fprintf(writer, "  Storage driver:\t%s\n", engine.StorageDriver)
+
+	machine := report.Machine
+	fmt.Fprintf(writer, "Podman machine:\n")
+	showInfoError(writer, machine.Error)
+	fmt.Fprintf(writer, "  Name:\t%s\n", machine.Name)
+	fmt.Fprintf(writer, "  State:\t%s\n", machine.State)
+	fmt.Fprintf(writer, "  Operating system:\t%s %s\n", machine.Distro, machine.DistroVersion)
+	fmt.Fprintf(writer, "  Kernel:\t%s\n", machine.Kernel)
+	fmt.Fprintf(writer, "  CPUs:\t%d\n", machine.CPUs)
+	fmt.Fprintf(writer, "  Memory:\t%s\n", units.BytesSize(float64(machine.Memory)))
+	fmt.Fprintf(writer, "  Disk size:\t%s\n", units.BytesSize(float64(machine.DiskSize)))
+
+	toolbox := report.Toolbox
+	fmt.Fprintf(writer, "Toolbx:\n")
+	showInfoError(writer, toolbox.Error)
+	fmt.Fprintf(writer, "  Version:\t%s\n", toolbox.Version)
+	fmt.Fprintf(writer, "  Executable:\t%s\n", toolbox.Executable)
+
+	mode := "user"
+	if toolbox.SystemMode {
+		mode = "system"
+	}
+
+	fmt.Fprintf(writer, "  Mode:\t%s\n", mode)
+	fmt.Fprintf(writer, "  Containers:\t%d\n", toolbox.Containers)
+
+	configFiles := "none"
+	if len(toolbox.ConfigFiles) != 0 {
+		configFiles = strings.Join(toolbox.ConfigFiles, ", ")
+	}
+
+	fmt.Fprintf(writer, "  Configuration:\t%s\n", configFiles)
+
+	features := "none"
+	if len(toolbox.Features) != 0 {
+		features = strings.Join(toolbox.Features, ", ")
+	}
+
+	fmt.Fprintf(writer, "  Experimental features:\t%s\n", features)
+
+	writer.Flush()
+}
+
+func showInfoError(writer io.Writer, errMsg string) {
+	if errMsg == "" {
+		return
+	}
+
+	fmt.Fprintf(writer, "  Error:\t%s\n", errMsg)
+}
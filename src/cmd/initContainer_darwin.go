@@ -19,27 +19,46 @@
 package cmd
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
+	"io"
 	"os"
+	"os/exec"
 	"os/user"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/containers/toolbox/pkg/shell"
 	"github.com/containers/toolbox/pkg/utils"
+	"github.com/google/renameio/v2"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
 
+const (
+	initContainerLog = "/var/log/toolbox/init-container.log"
+)
+
 var (
 	initContainerFlags struct {
-		gid         int
-		home        string
-		homeLink    bool
-		mediaLink   bool
-		mntLink     bool
-		monitorHost bool
-		shell       string
-		uid         int
-		user        string
+		gid             int
+		groups          []string
+		home            string
+		homeLink        bool
+		locale          string
+		mediaLink       bool
+		mntLink         bool
+		monitorHost     bool
+		nested          bool
+		shell           string
+		timeZone        string
+		uid             int
+		user            string
+		workspaceVolume bool
 	}
 
 	// macOS-specific container initialization mounts
@@ -73,6 +92,11 @@ func init() {
 		0,
 		"GID to configure inside the Toolbx container")
 
+	flags.StringArrayVar(&initContainerFlags.groups,
+		"group",
+		nil,
+		"Supplementary group as NAME:GID to add the user to inside the Toolbx container")
+
 	flags.StringVar(&initContainerFlags.home,
 		"home",
 		"",
@@ -83,6 +107,11 @@ func init() {
 		false,
 		"Make /home a symbolic link to /var/home")
 
+	flags.StringVar(&initContainerFlags.locale,
+		"locale",
+		"",
+		"Locale to make available inside the Toolbx container")
+
 	flags.BoolVar(&initContainerFlags.mediaLink,
 		"media-link",
 		false,
@@ -98,11 +127,21 @@ func init() {
 		false,
 		"Monitor host configuration changes")
 
+	flags.BoolVar(&initContainerFlags.nested,
+		"nested",
+		false,
+		"Set up Podman and Buildah inside the Toolbx container")
+
 	flags.StringVar(&initContainerFlags.shell,
 		"shell",
 		"",
 		"Path to the user's default shell inside the Toolbx container")
 
+	flags.StringVar(&initContainerFlags.timeZone,
+		"timezone",
+		"",
+		"Time zone to configure inside the Toolbx container")
+
 	flags.IntVar(&initContainerFlags.uid,
 		"uid",
 		0,
@@ -113,15 +152,25 @@ func init() {
 		"",
 		"Username to configure inside the Toolbx container")
 
+	flags.BoolVar(&initContainerFlags.workspaceVolume,
+		"workspace-volume",
+		false,
+		"Hand the named volume at /workspace over to the user")
+
 	initContainerCmd.Flags().MarkHidden("gid")
+	initContainerCmd.Flags().MarkHidden("group")
 	initContainerCmd.Flags().MarkHidden("home")
 	initContainerCmd.Flags().MarkHidden("home-link")
+	initContainerCmd.Flags().MarkHidden("locale")
 	initContainerCmd.Flags().MarkHidden("media-link")
 	initContainerCmd.Flags().MarkHidden("mnt-link")
 	initContainerCmd.Flags().MarkHidden("monitor-host")
+	initContainerCmd.Flags().MarkHidden("nested")
 	initContainerCmd.Flags().MarkHidden("shell")
+	initContainerCmd.Flags().MarkHidden("timezone")
 	initContainerCmd.Flags().MarkHidden("uid")
 	initContainerCmd.Flags().MarkHidden("user")
+	initContainerCmd.Flags().MarkHidden("workspace-volume")
 }
 
 func initContainer(cmd *cobra.Command, args []string) error {
@@ -131,6 +180,26 @@ func initContainer(cmd *cobra.Command, args []string) error {
 		return errors.New("init-container is only intended to be run inside a container")
 	}
 
+	logFile := openInitContainerLog()
+	if logFile != nil {
+		defer logFile.Close()
+		logrus.SetOutput(io.MultiWriter(os.Stderr, logFile))
+	}
+
+	if err := initContainerSetUp(); err != nil {
+		if logFile != nil {
+			fmt.Fprintf(logFile, "Error: %s\n", err)
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// initContainerSetUp does the actual work of init-container, so that the
+// error it fails with can be written to the log file too.
+func initContainerSetUp() error {
 	// Create toolbox environment marker for macOS
 	if err := createToolboxEnvironmentFile(); err != nil {
 		return err
@@ -141,20 +210,87 @@ func initContainer(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	if err := setupSupplementaryGroups(); err != nil {
+		return err
+	}
+
+	if err := setupSudo(); err != nil {
+		return err
+	}
+
+	if isOSTreeImage() {
+		logrus.Debug("Image is based on OSTree or bootc")
+
+		if err := setupOSTreeWritablePaths(); err != nil {
+			return err
+		}
+	}
+
 	// Create necessary directory structure
 	if err := setupDirectories(); err != nil {
 		return err
 	}
 
+	if initContainerFlags.monitorHost {
+		if err := setupHostConfigurationFiles(); err != nil {
+			return err
+		}
+	}
+
+	if initContainerFlags.nested {
+		if err := setupNested(); err != nil {
+			return err
+		}
+	}
+
+	if err := setupEntitlement(); err != nil {
+		return err
+	}
+
+	if initContainerFlags.locale != "" {
+		setupLocale(initContainerFlags.locale)
+	}
+
+	if initContainerFlags.timeZone != "" {
+		if err := setupTimeZone(initContainerFlags.timeZone); err != nil {
+			return err
+		}
+	}
+
 	// Configure hostname if needed
 	if err := setupHostname(); err != nil {
 		return err
 	}
 
+	if err := writeEnterBanner(); err != nil {
+		logrus.Debugf("Writing the banner for 'toolbox enter' failed: %s", err)
+	}
+
 	logrus.Debug("macOS container initialization completed")
 	return nil
 }
 
+// openInitContainerLog opens the log file that 'toolbox logs --file' reads.
+// It only covers the latest start of the container, while 'podman logs' has
+// all of them.  Not being able to write it isn't a reason to fail.
+func openInitContainerLog() *os.File {
+	logDir := filepath.Dir(initContainerLog)
+	if err := os.MkdirAll(logDir, 0755); err != nil {
+		logrus.Debugf("Creating directory %s failed: %s", logDir, err)
+		return nil
+	}
+
+	logFile, err := os.Create(initContainerLog)
+	if err != nil {
+		logrus.Debugf("Creating %s failed: %s", initContainerLog, err)
+		return nil
+	}
+
+	timestamp := time.Now().Format(time.RFC3339)
+	fmt.Fprintf(logFile, "Starting init-container at %s\n", timestamp)
+	return logFile
+}
+
 func createToolboxEnvironmentFile() error {
 	logrus.Debug("Creating toolbox environment marker")
 
@@ -179,6 +315,13 @@ func createToolboxEnvironmentFile() error {
 	return errors.New("failed to create toolbox environment marker")
 }
 
+// setupUser makes sure that the user exists inside the container with the
+// same UID, GID, home directory and shell as on the host, so that the files in
+// the home directory have the right owner and whoami(1) works.  Podman's
+// '--userns keep-id' might have already added an entry for the UID, but with
+// the name and home directory of the user inside the Podman machine.  Minimal
+// images without useradd(8) get their /etc/passwd and /etc/group edited
+// directly.
 func setupUser() error {
 	if initContainerFlags.user == "" {
 		return nil
@@ -189,11 +332,387 @@ func setupUser() error {
 		initContainerFlags.uid,
 		initContainerFlags.gid)
 
-	// On macOS containers, user setup is typically handled by the container runtime
-	// We just need to ensure the user exists and has proper permissions
+	if err := setupGroup(); err != nil {
+		return err
+	}
+
+	userShell := initContainerFlags.shell
+	if userShell == "" || !utils.PathExists(userShell) {
+		logrus.Debugf("Shell %s not found, using /bin/sh", userShell)
+		userShell = "/bin/sh"
+	}
+
+	uid := fmt.Sprint(initContainerFlags.uid)
+
+	existingUser, err := user.LookupId(uid)
+	if err != nil {
+		existingUser, err = user.Lookup(initContainerFlags.user)
+	}
+
+	if err != nil {
+		return addUser(userShell)
+	}
+
+	return modifyUser(existingUser.Username, userShell)
+}
+
+func setupGroup() error {
+	if _, err := ensureGroup(initContainerFlags.user, initContainerFlags.gid); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// setupSupplementaryGroups adds the user to the groups of macOS given with
+// '--group'.  A group with the same GID inside the container is used as it
+// is, whatever its name, because it's the GID that decides who can access
+// the files.
+func setupSupplementaryGroups() error {
+	if initContainerFlags.user == "" {
+		return nil
+	}
+
+	for _, groupArg := range initContainerFlags.groups {
+		name, gidString, found := strings.Cut(groupArg, ":")
+		gid, err := strconv.Atoi(gidString)
+		if !found || name == "" || err != nil {
+			return fmt.Errorf("invalid group %s: needs to be NAME:GID", groupArg)
+		}
+
+		group, err := ensureGroup(name, gid)
+		if err != nil {
+			return err
+		}
+
+		if err := addUserToGroup(group); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ensureGroup returns the name of the group with the GID inside the
+// container, after adding it as name if there's none.  If name is already
+// taken by a group with a different GID, like a distribution's own admin
+// group, then it's added as host-name instead.
+func ensureGroup(name string, gid int) (string, error) {
+	gidString := fmt.Sprint(gid)
+	if group, err := user.LookupGroupId(gidString); err == nil {
+		logrus.Debugf("Group with GID %d is %s", gid, group.Name)
+		return group.Name, nil
+	}
+
+	if _, err := user.LookupGroup(name); err == nil {
+		logrus.Debugf("Group %s already exists with a different GID", name)
+		name = "host-" + name
+	}
+
+	logrus.Debugf("Adding group %s with GID %d", name, gid)
+
+	if _, err := exec.LookPath("groupadd"); err != nil {
+		entry := fmt.Sprintf("%s:x:%d:", name, gid)
+		if err := writeUserDatabaseEntry("/etc/group", "", entry); err != nil {
+			return "", fmt.Errorf("failed to add group %s with GID %d: %w", name, gid, err)
+		}
+
+		return name, nil
+	}
+
+	if err := shell.Run("groupadd", nil, nil, nil, "--gid", gidString, name); err != nil {
+		return "", fmt.Errorf("failed to add group %s with GID %d: %w", name, gid, err)
+	}
+
+	return name, nil
+}
+
+func addUserToGroup(group string) error {
+	logrus.Debugf("Adding user %s to group %s", initContainerFlags.user, group)
+
+	if _, err := exec.LookPath("usermod"); err == nil {
+		if err := shell.Run("usermod", nil, nil, nil, "--append", "--groups", group, initContainerFlags.user); err != nil {
+			return fmt.Errorf("failed to add user %s to group %s: %w", initContainerFlags.user, group, err)
+		}
+
+		return nil
+	}
+
+	data, err := os.ReadFile("/etc/group")
+	if err != nil {
+		return fmt.Errorf("failed to add user %s to group %s: %w", initContainerFlags.user, group, err)
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Split(line, ":")
+		if len(fields) != 4 || fields[0] != group {
+			continue
+		}
+
+		var members []string
+		if fields[3] != "" {
+			members = strings.Split(fields[3], ",")
+		}
+
+		if slices.Contains(members, initContainerFlags.user) {
+			return nil
+		}
+
+		members = append(members, initContainerFlags.user)
+		fields[3] = strings.Join(members, ",")
+
+		entry := strings.Join(fields, ":")
+		if err := writeUserDatabaseEntry("/etc/group", group, entry); err != nil {
+			return fmt.Errorf("failed to add user %s to group %s: %w", initContainerFlags.user, group, err)
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("failed to add user %s to group %s: group not found", initContainerFlags.user, group)
+}
+
+func addUser(userShell string) error {
+	logrus.Debugf("Adding user %s with UID %d", initContainerFlags.user, initContainerFlags.uid)
+
+	if _, err := exec.LookPath("useradd"); err != nil {
+		if err := writePasswdEntry("", userShell); err != nil {
+			return fmt.Errorf("failed to add user %s with UID %d: %w",
+				initContainerFlags.user,
+				initContainerFlags.uid,
+				err)
+		}
+
+		return nil
+	}
+
+	useraddArgs := []string{
+		"--gid", fmt.Sprint(initContainerFlags.gid),
+		"--home-dir", initContainerFlags.home,
+		"--no-create-home",
+		"--password", "",
+		"--shell", userShell,
+		"--uid", fmt.Sprint(initContainerFlags.uid),
+	}
+
+	if sudoGroup, err := utils.GetGroupForSudo(); err == nil {
+		useraddArgs = append(useraddArgs, "--groups", sudoGroup)
+	}
+
+	useraddArgs = append(useraddArgs, initContainerFlags.user)
+
+	logrus.Debug("useradd")
+	for _, arg := range useraddArgs {
+		logrus.Debugf("%s", arg)
+	}
+
+	if err := shell.Run("useradd", nil, nil, nil, useraddArgs...); err != nil {
+		return fmt.Errorf("failed to add user %s with UID %d: %w",
+			initContainerFlags.user,
+			initContainerFlags.uid,
+			err)
+	}
+
+	return nil
+}
+
+// modifyUser renames existingUser, if needed, and gives it the UID, GID, home
+// directory and shell of the user.
+func modifyUser(existingUser, userShell string) error {
+	logrus.Debugf("Modifying user %s to be %s with UID %d",
+		existingUser,
+		initContainerFlags.user,
+		initContainerFlags.uid)
+
+	if _, err := exec.LookPath("usermod"); err != nil {
+		if err := writePasswdEntry(existingUser, userShell); err != nil {
+			return fmt.Errorf("failed to modify user %s with UID %d: %w",
+				initContainerFlags.user,
+				initContainerFlags.uid,
+				err)
+		}
+
+		return nil
+	}
+
+	usermodArgs := []string{
+		"--gid", fmt.Sprint(initContainerFlags.gid),
+		"--home", initContainerFlags.home,
+		"--shell", userShell,
+		"--uid", fmt.Sprint(initContainerFlags.uid),
+	}
+
+	if existingUser != initContainerFlags.user {
+		usermodArgs = append(usermodArgs, "--login", initContainerFlags.user)
+	}
+
+	if sudoGroup, err := utils.GetGroupForSudo(); err == nil {
+		usermodArgs = append(usermodArgs, "--append", "--groups", sudoGroup)
+	}
+
+	usermodArgs = append(usermodArgs, existingUser)
+
+	logrus.Debug("usermod")
+	for _, arg := range usermodArgs {
+		logrus.Debugf("%s", arg)
+	}
+
+	if err := shell.Run("usermod", nil, nil, nil, usermodArgs...); err != nil {
+		return fmt.Errorf("failed to modify user %s with UID %d: %w",
+			initContainerFlags.user,
+			initContainerFlags.uid,
+			err)
+	}
+
+	return nil
+}
+
+// writePasswdEntry replaces the entry for existingUser in /etc/passwd with
+// one for the user, or adds it if existingUser is empty.  The password is
+// left locked, because sudo(8) is set up to not ask for one.  Images like
+// Alpine, which use BusyBox instead of the shadow utilities, still have an
+// /etc/shadow, and passwd(1) and su(1) fail for users missing from it.
+func writePasswdEntry(existingUser, userShell string) error {
+	entry := fmt.Sprintf("%s:x:%d:%d::%s:%s",
+		initContainerFlags.user,
+		initContainerFlags.uid,
+		initContainerFlags.gid,
+		initContainerFlags.home,
+		userShell)
+
+	if err := writeUserDatabaseEntry("/etc/passwd", existingUser, entry); err != nil {
+		return err
+	}
+
+	if !utils.PathExists("/etc/shadow") {
+		return nil
+	}
+
+	shadowEntry := fmt.Sprintf("%s:!::0:::::", initContainerFlags.user)
+	if err := writeUserDatabaseEntry("/etc/shadow", existingUser, shadowEntry); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// writeUserDatabaseEntry replaces the line for name in a file like
+// /etc/passwd or /etc/group with entry, or adds entry to the end of the file
+// if name is empty or not found.  The permissions of the file are kept, so
+// that /etc/shadow stays unreadable by the user.
+func writeUserDatabaseEntry(path, name, entry string) error {
+	fileInfo, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	content := strings.TrimSuffix(string(data), "\n")
+
+	var lines []string
+	if content != "" {
+		lines = strings.Split(content, "\n")
+	}
+
+	var replaced bool
+
+	if name != "" {
+		for i, line := range lines {
+			if fields := strings.SplitN(line, ":", 2); fields[0] == name {
+				lines[i] = entry
+				replaced = true
+				break
+			}
+		}
+	}
+
+	if !replaced {
+		lines = append(lines, entry)
+	}
+
+	newContent := strings.Join(lines, "\n") + "\n"
+	if err := renameio.WriteFile(path, []byte(newContent), fileInfo.Mode().Perm()); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// setupSudo lets the user run sudo(8) without a password, like on Linux,
+// where the user is added to the group for sudo and has no password.  Here,
+// it's done with a drop-in file instead, because not every image has a group
+// for sudo, and the user might have been added without useradd(8).  Images
+// without sudo(8) are left alone.
+func setupSudo() error {
+	if initContainerFlags.user == "" {
+		return nil
+	}
+
+	const logPrefix = "Configuring sudo(8) for the user"
+	logrus.Debugf("%s", logPrefix)
+
+	if !utils.PathExists("/etc/sudoers") {
+		logrus.Debugf("%s: sudo(8) is not installed", logPrefix)
+		return nil
+	}
+
+	const sudoersD = "/etc/sudoers.d"
+	fileInfo, err := os.Stat(sudoersD)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			err := createErrorSudoersDNotFound()
+			return err
+		}
+
+		logrus.Debugf("%s: failed to stat %s: %s", logPrefix, sudoersD, err)
+		return fmt.Errorf("failed to stat %s", sudoersD)
+	}
+
+	fileMode := fileInfo.Mode()
+	if !fileMode.IsDir() {
+		err := createErrorSudoersDNotFound()
+		return err
+	}
+
+	sudoers := filepath.Join(sudoersD, "toolbox")
+
+	var builder strings.Builder
+	builder.WriteString("# Written by Toolbx\n")
+	builder.WriteString("# https://containertoolbx.org/\n")
+	builder.WriteString("\n")
+	fmt.Fprintf(&builder, "%s ALL=(ALL:ALL) NOPASSWD: ALL\n", initContainerFlags.user)
+
+	sudoersString := builder.String()
+	sudoersBytes := []byte(sudoersString)
+
+	pendingFile, err := renameio.NewPendingFile(sudoers, renameio.WithPermissions(0440))
+	if err != nil {
+		return fmt.Errorf("failed to configure sudo(8) for user %s: %w", initContainerFlags.user, err)
+	}
+
+	defer pendingFile.Cleanup()
+
+	if _, err := pendingFile.Write(sudoersBytes); err != nil {
+		return fmt.Errorf("failed to configure sudo(8) for user %s: %w", initContainerFlags.user, err)
+	}
 
-	if _, err := user.Lookup(initContainerFlags.user); err != nil {
-		logrus.Debugf("User %s not found, this may be expected in macOS containers", initContainerFlags.user)
+	// A broken drop-in file breaks sudo(8) entirely, so it's checked
+	// before it's put in place, if visudo(8) is around to do that.
+	if _, err := exec.LookPath("visudo"); err == nil {
+		var stderr strings.Builder
+		if err := shell.Run("visudo", nil, nil, &stderr, "--check", "--file", pendingFile.Name()); err != nil {
+			errString := stderr.String()
+			logrus.Debugf("%s: checking %s failed: %s", logPrefix, pendingFile.Name(), errString)
+			return fmt.Errorf("failed to configure sudo(8) for user %s: invalid sudoers(5) file", initContainerFlags.user)
+		}
+	}
+
+	if err := pendingFile.CloseAtomicallyReplace(); err != nil {
+		return fmt.Errorf("failed to configure sudo(8) for user %s: %w", initContainerFlags.user, err)
 	}
 
 	return nil
@@ -214,6 +733,17 @@ func setupDirectories() error {
 		}
 	}
 
+	if initContainerFlags.workspaceVolume {
+		logrus.Debugf("Changing ownership of %s to UID %d and GID %d",
+			workspaceDirectory,
+			initContainerFlags.uid,
+			initContainerFlags.gid)
+
+		if err := os.Chown(workspaceDirectory, initContainerFlags.uid, initContainerFlags.gid); err != nil {
+			return fmt.Errorf("failed to change ownership of %s: %w", workspaceDirectory, err)
+		}
+	}
+
 	// Handle symbolic links if requested
 	if initContainerFlags.homeLink {
 		if err := createSymlinkIfNeeded("/home", "/var/home"); err != nil {
@@ -237,6 +767,135 @@ func setupDirectories() error {
 	return nil
 }
 
+// isOSTreeImage checks if the image is an OSTree or bootc based one, like
+// Fedora Silverblue or a bootc base image.  These images don't have SELinux
+// policies loaded inside the container, keep their mutable state under /var,
+// and have paths like /home, /opt and /usr/local as symbolic links into it.
+func isOSTreeImage() bool {
+	paths := []string{
+		"/ostree",
+		"/sysroot/ostree",
+		"/usr/lib/bootc",
+	}
+
+	for _, path := range paths {
+		if _, err := os.Lstat(path); err == nil {
+			logrus.Debugf("Found %s", path)
+			return true
+		}
+	}
+
+	return false
+}
+
+// setupOSTreeWritablePaths creates the targets of the symbolic links that
+// OSTree based images have at the top level, because the image only ships an
+// empty /var that is populated by systemd-tmpfiles when booted on a host.
+func setupOSTreeWritablePaths() error {
+	logrus.Debug("Setting up writable paths for OSTree")
+
+	paths := []string{
+		"/home",
+		"/media",
+		"/mnt",
+		"/opt",
+		"/root",
+		"/srv",
+		"/usr/local",
+	}
+
+	for _, path := range paths {
+		target, err := os.Readlink(path)
+		if err != nil {
+			continue
+		}
+
+		if !filepath.IsAbs(target) {
+			target = filepath.Join(filepath.Dir(path), target)
+		}
+
+		logrus.Debugf("Creating %s for %s", target, path)
+
+		if err := os.MkdirAll(target, 0755); err != nil {
+			return fmt.Errorf("failed to create %s for %s: %w", target, path, err)
+		}
+	}
+
+	return nil
+}
+
+// setupHostConfigurationFiles prepares the files that 'toolbox monitor-host'
+// updates from the macOS host.  Images using systemd-resolved have
+// /etc/resolv.conf as a symbolic link into /run, which isn't set up without
+// systemd, so it's replaced with a regular file.  The /etc/resolv.conf that
+// Podman generated for the Podman machine's resolver is kept, for when the
+// host's name servers can't be used.
+func setupHostConfigurationFiles() error {
+	logrus.Debug("Setting up files for monitoring the host")
+
+	const resolvConf = "/etc/resolv.conf"
+
+	if fileInfo, err := os.Lstat(resolvConf); err == nil && fileInfo.Mode()&os.ModeSymlink != 0 {
+		data, err := os.ReadFile(resolvConf)
+		if err != nil {
+			logrus.Debugf("Reading %s failed: %s", resolvConf, err)
+			data = nil
+		}
+
+		logrus.Debugf("Replacing symbolic link %s with a regular file", resolvConf)
+
+		if err := os.Remove(resolvConf); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", resolvConf, err)
+		}
+
+		if err := os.WriteFile(resolvConf, data, 0644); err != nil {
+			return fmt.Errorf("failed to create %s: %w", resolvConf, err)
+		}
+	}
+
+	if data, err := os.ReadFile(resolvConf); err != nil {
+		logrus.Debugf("Reading %s failed: %s", resolvConf, err)
+	} else {
+		if err := os.MkdirAll("/run/toolbox", 0755); err != nil {
+			return fmt.Errorf("failed to create /run/toolbox: %w", err)
+		}
+
+		if err := os.WriteFile(machineResolvConf, data, 0644); err != nil {
+			return fmt.Errorf("failed to create %s: %w", machineResolvConf, err)
+		}
+	}
+
+	if err := os.MkdirAll("/etc/profile.d", 0755); err != nil {
+		return fmt.Errorf("failed to create /etc/profile.d: %w", err)
+	}
+
+	return nil
+}
+
+// setupEntitlement lets dnf(8) use the entitlement certificates from
+// 'toolbox create --entitlement'.  UBI images only look for them if
+// /run/secrets/rhsm has the configuration of subscription-manager(8) too,
+// which Podman shares from a RHEL host.  A Mac has none, so the image's own
+// is used.
+func setupEntitlement() error {
+	if !utils.PathExists(entitlementDirectory) {
+		return nil
+	}
+
+	const rhsmHost = "/run/secrets/rhsm"
+	if utils.PathExists(rhsmHost) || !utils.PathExists("/etc/rhsm") {
+		return nil
+	}
+
+	logrus.Debugf("Linking %s to /etc/rhsm", rhsmHost)
+
+	if err := os.Symlink("/etc/rhsm", rhsmHost); err != nil {
+		return fmt.Errorf("failed to set up the entitlement certificates: %w", err)
+	}
+
+	return nil
+}
+
 func setupHostname() error {
 	// On macOS containers, hostname is typically managed by the container runtime
 	// Just log that we're skipping this
@@ -244,9 +903,100 @@ func setupHostname() error {
 	return nil
 }
 
+// setupLocale generates the locale with localedef(1) if the image doesn't
+// have it yet.  Failures are not fatal, because minimal images often lack the
+// locale sources, and LANG still falls back to C.
+func setupLocale(locale string) {
+	if locale == "C" || locale == "POSIX" || strings.HasPrefix(locale, "C.") {
+		return
+	}
+
+	if isMuslImage() {
+		logrus.Debugf("Not generating locale %s: musl has UTF-8 built in", locale)
+		return
+	}
+
+	var stdout bytes.Buffer
+	if err := shell.Run("locale", nil, &stdout, nil, "--all-locales"); err != nil {
+		logrus.Debugf("Listing the available locales failed: %s", err)
+		return
+	}
+
+	wanted := normalizeCodeset(locale)
+	for _, available := range strings.Fields(stdout.String()) {
+		if normalizeCodeset(available) == wanted {
+			logrus.Debugf("Locale %s is available", locale)
+			return
+		}
+	}
+
+	name, codeset, found := strings.Cut(locale, ".")
+	if !found {
+		codeset = "UTF-8"
+	}
+
+	logrus.Debugf("Generating locale %s", locale)
+
+	if err := shell.Run("localedef", nil, nil, nil, "--inputfile", name, "--charmap", codeset, locale); err != nil {
+		logrus.Debugf("Generating locale %s failed: %s", locale, err)
+	}
+}
+
+// isMuslImage checks if the image uses the musl C library, like Alpine,
+// instead of glibc.  musl has no compiled locales to generate, and always
+// uses UTF-8 for LC_CTYPE whatever the locale is.
+func isMuslImage() bool {
+	matches, err := filepath.Glob("/lib/ld-musl-*.so.1")
+	if err != nil || len(matches) == 0 {
+		return false
+	}
+
+	return true
+}
+
+// normalizeCodeset makes en_US.UTF-8 and en_US.utf8 compare equal, because
+// 'locale --all-locales' lists the latter.
+func normalizeCodeset(locale string) string {
+	locale = strings.ToLower(locale)
+	locale = strings.ReplaceAll(locale, "-", "")
+	return locale
+}
+
+// setupTimeZone points /etc/localtime at the host's time zone, because the
+// containers can't bind mount it from macOS.  'toolbox monitor-host' keeps it
+// up to date afterwards.
+func setupTimeZone(timeZone string) error {
+	logrus.Debugf("Setting up time zone %s", timeZone)
+
+	zoneInfo := filepath.Join("/usr/share/zoneinfo", timeZone)
+	if _, err := os.Stat(zoneInfo); err != nil {
+		logrus.Debugf("Time zone %s not found in the image: %s", timeZone, err)
+		return nil
+	}
+
+	if err := os.Remove("/etc/localtime"); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove /etc/localtime: %w", err)
+	}
+
+	if err := os.Symlink(zoneInfo, "/etc/localtime"); err != nil {
+		return fmt.Errorf("failed to create /etc/localtime: %w", err)
+	}
+
+	if err := os.WriteFile("/etc/timezone", []byte(timeZone+"\n"), 0644); err != nil {
+		return fmt.Errorf("failed to create /etc/timezone: %w", err)
+	}
+
+	return nil
+}
+
 func createSymlinkIfNeeded(linkPath, targetPath string) error {
 	// Check if link already exists and points to the right place
 	if target, err := os.Readlink(linkPath); err == nil {
+		// OSTree based images use relative targets like var/home
+		if !filepath.IsAbs(target) {
+			target = filepath.Join(filepath.Dir(linkPath), target)
+		}
+
 		if target == targetPath {
 			return nil // Already correct
 		}
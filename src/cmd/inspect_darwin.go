@@ -0,0 +1,392 @@
+//go:build darwin
+
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+	"time"
+
+	"github.com/containers/toolbox/pkg/i18n"
+	"github.com/containers/toolbox/pkg/podman"
+	"github.com/containers/toolbox/pkg/toolbox"
+	"github.com/containers/toolbox/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// inspectInfo is what 'toolbox inspect' shows about a container.  The names
+// of the fields are used as they are by '--format', both as JSON keys and in
+// templates.
+type inspectInfo struct {
+	CreateCommand   []string
+	Created         time.Time
+	Distro          string
+	ExtraMounts     []inspectMount
+	ID              string
+	Image           string
+	ImageID         string
+	LastUsed        *time.Time `json:",omitempty"`
+	Name            string
+	Network         string
+	Owner           string `json:",omitempty"`
+	Protected       bool
+	Release         string
+	StartedAt       *time.Time `json:",omitempty"`
+	Status          string
+	WorkspaceVolume string `json:",omitempty"`
+}
+
+type inspectMount struct {
+	Destination string
+	ReadOnly    bool
+	Source      string
+}
+
+var (
+	inspectFlags struct {
+		format string
+	}
+)
+
+var inspectCmd = &cobra.Command{
+	Use:               "inspect",
+	Short:             "Show details about Toolbx containers (macOS version)",
+	RunE:              inspect,
+	ValidArgsFunction: completionContainerNamesFiltered,
+}
+
+func init() {
+	flags := inspectCmd.Flags()
+
+	flags.StringVarP(&inspectFlags.format,
+		"format",
+		"f",
+		"",
+		"Show the details as JSON with 'json', or through a Go template")
+
+	if err := inspectCmd.RegisterFlagCompletionFunc("format", completionInspectFormats); err != nil {
+		panicMsg := fmt.Sprintf("failed to register flag completion function: %v", err)
+		panic(panicMsg)
+	}
+
+	inspectCmd.SetHelpFunc(inspectHelp)
+	rootCmd.AddCommand(inspectCmd)
+}
+
+// inspect boils 'podman inspect' down to what matters for a Toolbx container,
+// and adds what only Toolbx knows, like when it was last entered.
+func inspect(cmd *cobra.Command, args []string) error {
+	if utils.IsInsideContainer() {
+		return errors.New("inspect is not supported inside a container")
+	}
+
+	if len(args) == 0 {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "missing argument for \"inspect\"\n")
+		i18n.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	var tmpl *template.Template
+
+	if format := inspectFlags.format; format != "" && format != "json" {
+		var err error
+		tmpl, err = template.New("format").Parse(format + "\n")
+		if err != nil {
+			return fmt.Errorf("invalid argument for '--format': %w", err)
+		}
+	}
+
+	var infos []inspectInfo
+
+	for _, container := range args {
+		info, err := getInspectInfo(container)
+		if err != nil {
+			return err
+		}
+
+		infos = append(infos, info)
+	}
+
+	switch {
+	case tmpl != nil:
+		for _, info := range infos {
+			if err := tmpl.Execute(os.Stdout, info); err != nil {
+				return fmt.Errorf("failed to format container %s: %w", info.Name, err)
+			}
+		}
+	case inspectFlags.format == "json":
+		data, err := json.MarshalIndent(infos, "", "    ")
+		if err != nil {
+			return fmt.Errorf("failed to encode JSON: %w", err)
+		}
+
+		fmt.Printf("%s\n", data)
+	default:
+		for i, info := range infos {
+			if i > 0 {
+				fmt.Printf("\n")
+			}
+
+			showInspectInfo(os.Stdout, info)
+		}
+	}
+
+	return nil
+}
+
+func completionInspectFormats(cmd *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+	formats := []string{"json"}
+	return formats, cobra.ShellCompDirectiveNoFileComp
+}
+
+func formatInspectTime(timestamp *time.Time) string {
+	if timestamp == nil {
+		return "never"
+	}
+
+	humanDuration := utils.HumanDuration(timestamp.Unix())
+	timestampString := timestamp.Local().Format("2006-01-02 15:04:05 MST")
+	return fmt.Sprintf("%s (%s)", timestampString, humanDuration)
+}
+
+func getInspectInfo(container string) (inspectInfo, error) {
+	containerObj, err := podman.InspectContainer(container)
+	if err != nil {
+		return inspectInfo{}, createErrorContainerNotFound(container)
+	}
+
+	if !containerObj.IsToolbx() {
+		return inspectInfo{}, fmt.Errorf("%s is not a Toolbx container", container)
+	}
+
+	if err := checkContainerOwner(containerObj); err != nil {
+		return inspectInfo{}, err
+	}
+
+	details, err := podman.InspectContainerDetails(container)
+	if err != nil {
+		logrus.Debugf("Inspecting container %s failed: %s", container, err)
+		return inspectInfo{}, fmt.Errorf("failed to inspect container %s", container)
+	}
+
+	distro, release := utils.GetDistroAndReleaseForImage(details.ImageName)
+
+	info := inspectInfo{
+		CreateCommand: details.Config.CreateCommand,
+		Created:       details.Created,
+		Distro:        distro,
+		ID:            details.ID,
+		Image:         details.ImageName,
+		ImageID:       details.Image,
+		Name:          details.Name,
+		Network:       details.HostConfig.NetworkMode,
+		Owner:         details.Config.Labels[ownerLabel],
+		Release:       release,
+		Status:        details.State.Status,
+	}
+
+	if startedAt := details.State.StartedAt; !startedAt.IsZero() {
+		info.StartedAt = &startedAt
+	}
+
+	if lastUsed, ok := toolbox.GetLastUsed(details.ID); ok {
+		info.LastUsed = &lastUsed
+	}
+
+	if stamp, err := toolbox.GetProtectedStamp(details.ID); err == nil {
+		if _, err := os.Stat(stamp); err == nil {
+			info.Protected = true
+		}
+	}
+
+	info.ExtraMounts, info.WorkspaceVolume = getContainerExtraMounts(details)
+	return info, nil
+}
+
+// getContainerExtraMounts returns the mounts of a container that 'toolbox
+// create' didn't add by itself, and the name of its workspace volume, if any.
+func getContainerExtraMounts(details podman.ContainerDetails) ([]inspectMount, string) {
+	var extraMounts []inspectMount
+	var workspaceVolume string
+
+	homeDir := getCurrentUserHomeDir()
+
+	for _, mount := range details.Mounts {
+		// These are added to every container by 'toolbox create'.
+		switch {
+		case mount.Destination == homeDir && mount.Source == homeDir:
+			continue
+		case mount.Destination == "/usr/bin/toolbox":
+			continue
+		case mount.Destination == entitlementDirectory:
+			continue
+		case mount.Destination == secretsDirectory:
+			continue
+		case mount.Destination == sshAgentDirectory:
+			continue
+		case strings.HasPrefix(mount.Destination, "/host/"):
+			continue
+		case mount.Destination == workspaceDirectory && mount.Type == "volume":
+			workspaceVolume = mount.Name
+			continue
+		}
+
+		source := mount.Source
+		if mount.Type == "volume" {
+			source = mount.Name
+		}
+
+		extraMount := inspectMount{
+			Destination: mount.Destination,
+			ReadOnly:    !mount.RW,
+			Source:      source,
+		}
+
+		extraMounts = append(extraMounts, extraMount)
+	}
+
+	return extraMounts, workspaceVolume
+}
+
+func inspectHelp(cmd *cobra.Command, args []string) {
+	if utils.IsInsideContainer() {
+		if !utils.IsInsideToolboxContainer() {
+			fmt.Fprintf(os.Stderr, "Error: this is not a Toolbx container\n")
+			return
+		}
+
+		if _, err := utils.ForwardToHost(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return
+		}
+
+		return
+	}
+
+	if err := showManual("toolbox-inspect"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return
+	}
+}
+
+// transferContainerLastUsed keeps the last-used time of a container after it
+// was recreated with the same name, eg., by 'toolbox cap set', so that it
+// doesn't look unused to 'toolbox report'.
+func transferContainerLastUsed(oldID, container string) {
+	oldStamp, err := toolbox.GetLastUsedStamp(oldID)
+	if err != nil {
+		logrus.Debugf("Transferring the last use of container %s: %s", container, err)
+		return
+	}
+
+	if _, err := os.Stat(oldStamp); err != nil {
+		return
+	}
+
+	containerObj, err := podman.InspectContainer(container)
+	if err != nil {
+		logrus.Debugf("Inspecting container %s failed: %s", container, err)
+		return
+	}
+
+	stamp, err := toolbox.GetLastUsedStamp(containerObj.ID())
+	if err != nil {
+		logrus.Debugf("Transferring the last use of container %s: %s", container, err)
+		return
+	}
+
+	if err := os.Rename(oldStamp, stamp); err != nil {
+		logrus.Debugf("Renaming %s to %s failed: %s", oldStamp, stamp, err)
+	}
+}
+
+func showInspectInfo(w io.Writer, info inspectInfo) {
+	distro := info.Distro
+	if distro == "" {
+		distro = "unknown"
+	}
+
+	release := info.Release
+	if release == "" {
+		release = "unknown"
+	}
+
+	workspaceVolume := info.WorkspaceVolume
+	if workspaceVolume == "" {
+		workspaceVolume = "none"
+	}
+
+	writer := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(writer, "Name:\t%s\n", info.Name)
+	fmt.Fprintf(writer, "ID:\t%s\n", utils.ShortID(info.ID))
+	fmt.Fprintf(writer, "Status:\t%s\n", info.Status)
+	fmt.Fprintf(writer, "Distro:\t%s\n", distro)
+	fmt.Fprintf(writer, "Release:\t%s\n", release)
+	fmt.Fprintf(writer, "Image:\t%s (%s)\n", info.Image, utils.ShortID(info.ImageID))
+
+	if info.Owner != "" {
+		fmt.Fprintf(writer, "Owner:\t%s\n", info.Owner)
+	}
+
+	fmt.Fprintf(writer, "Created:\t%s\n", formatInspectTime(&info.Created))
+	fmt.Fprintf(writer, "Started:\t%s\n", formatInspectTime(info.StartedAt))
+	fmt.Fprintf(writer, "Last used:\t%s\n", formatInspectTime(info.LastUsed))
+	fmt.Fprintf(writer, "Network:\t%s\n", info.Network)
+
+	protected := "no"
+	if info.Protected {
+		protected = "yes"
+	}
+
+	fmt.Fprintf(writer, "Protected:\t%s\n", protected)
+	fmt.Fprintf(writer, "Workspace volume:\t%s\n", workspaceVolume)
+
+	if len(info.ExtraMounts) == 0 {
+		fmt.Fprintf(writer, "Extra mounts:\tnone\n")
+	}
+
+	for i, mount := range info.ExtraMounts {
+		label := ""
+		if i == 0 {
+			label = "Extra mounts:"
+		}
+
+		mode := "rw"
+		if mount.ReadOnly {
+			mode = "ro"
+		}
+
+		fmt.Fprintf(writer, "%s\t%s -> %s (%s)\n", label, mount.Source, mount.Destination, mode)
+	}
+
+	createCommand := strings.Join(info.CreateCommand, " ")
+	fmt.Fprintf(writer, "Create command:\t%s\n", createCommand)
+
+	writer.Flush()
+}
@@ -0,0 +1,103 @@
+//go:build darwin
+
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/containers/toolbox/pkg/shell"
+	"github.com/containers/toolbox/pkg/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// getKeyboardEnviron returns the host's keyboard layout and input method,
+// and whether the Option key of Terminal acts as Meta, as environment
+// variables.  Editors and shells inside the container can't find these out
+// by themselves, because they don't see the window server of macOS.
+func getKeyboardEnviron() []string {
+	environ := utils.GetHostKeyboardEnvironment()
+
+	if optionAsMeta, ok := isTerminalOptionAsMeta(); ok {
+		value := "0"
+		if optionAsMeta {
+			value = "1"
+		}
+
+		environ = append(environ, "TOOLBOX_OPTION_AS_META="+value)
+	}
+
+	return environ
+}
+
+// isTerminalOptionAsMeta tells whether the default profile of Terminal sends
+// the Option key as an Escape prefix, like the Meta key of a PC keyboard.
+// Otherwise, Option types characters like å and ∫.  The second return value
+// is false if Toolbx isn't running in Terminal, or the setting couldn't be
+// read.
+func isTerminalOptionAsMeta() (bool, bool) {
+	if os.Getenv("TERM_PROGRAM") != "Apple_Terminal" {
+		return false, false
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		logrus.Debugf("Reading the Terminal preferences failed: %s", err)
+		return false, false
+	}
+
+	var stdout bytes.Buffer
+	if err := shell.Run("defaults",
+		nil,
+		&stdout,
+		nil,
+		"read",
+		"com.apple.Terminal",
+		"Default Window Settings"); err != nil {
+		logrus.Debugf("Reading the default Terminal profile failed: %s", err)
+		return false, false
+	}
+
+	profile := strings.TrimSpace(stdout.String())
+	if profile == "" {
+		return false, false
+	}
+
+	preferences := filepath.Join(homeDir, "Library", "Preferences", "com.apple.Terminal.plist")
+	entry := ":Window Settings:" + profile + ":useOptionAsMetaKey"
+
+	stdout.Reset()
+	if err := shell.Run("/usr/libexec/PlistBuddy",
+		nil,
+		&stdout,
+		nil,
+		"-c",
+		"Print \""+entry+"\"",
+		preferences); err != nil {
+		// The key is absent unless the setting was changed from the
+		// default, which is off.
+		logrus.Debugf("Reading %s from the Terminal preferences failed: %s", entry, err)
+		return false, true
+	}
+
+	optionAsMeta := strings.TrimSpace(stdout.String()) == "true"
+	return optionAsMeta, true
+}
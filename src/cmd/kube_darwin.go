@@ -0,0 +1,244 @@
+//go:build darwin
+
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/containers/toolbox/pkg/podman"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	kubeconfigInContainer = "/etc/toolbox/kubeconfig"
+)
+
+var (
+	// kubeconfigFileKeys are the keys of a kubeconfig file that point at
+	// files, and the keys for their contents instead.
+	kubeconfigFileKeys = map[string]string{
+		"certificate-authority": "certificate-authority-data",
+		"client-certificate":    "client-certificate-data",
+		"client-key":            "client-key-data",
+	}
+
+	// kubeconfigSharedPaths are the directories of the Mac that 'toolbox
+	// create' shares with every container, and where they are inside it.
+	// The home directory is at the same path.
+	kubeconfigSharedPaths = []struct {
+		host      string
+		container string
+	}{
+		{"/Users", "/host/Users"},
+		{"/opt", "/host/opt"},
+		{"/private/tmp", "/host/tmp"},
+		{"/tmp", "/host/tmp"},
+		{"/usr/local", "/host/usr/local"},
+	}
+)
+
+// getHostKubeconfig returns ~/.kube/config of the host, rewritten for the
+// inside of a Toolbx container.
+func getHostKubeconfig() ([]byte, error) {
+	homeDir := getCurrentUserHomeDir()
+	if homeDir == "" {
+		return nil, errors.New("failed to find the home directory")
+	}
+
+	kubeconfigFile := filepath.Join(homeDir, ".kube", "config")
+	data, err := os.ReadFile(kubeconfigFile)
+	if err != nil {
+		return nil, err
+	}
+
+	kubeconfig, err := rewriteKubeconfig(data, filepath.Dir(kubeconfigFile), homeDir, os.ReadFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to rewrite %s: %w", kubeconfigFile, err)
+	}
+
+	return kubeconfig, nil
+}
+
+// getKubeconfigContainerPath returns where a file of the Mac is inside a
+// Toolbx container, or an empty string if it's not shared.
+func getKubeconfigContainerPath(path, homeDir string) string {
+	if homeDir != "" && (path == homeDir || strings.HasPrefix(path, homeDir+"/")) {
+		return path
+	}
+
+	for _, sharedPath := range kubeconfigSharedPaths {
+		if rest, found := strings.CutPrefix(path, sharedPath.host); found && (rest == "" || rest[0] == '/') {
+			return sharedPath.container + rest
+		}
+	}
+
+	return ""
+}
+
+// rewriteKubeconfig changes a kubeconfig file of the Mac so that it works
+// inside a Toolbx container.  Clusters at localhost, like those of kind and
+// minikube, are reached through host.containers.internal, while their
+// certificates are still checked for the original name.  The files that it
+// points at are made absolute, and moved to where they are inside the
+// container, or embedded if they aren't shared with it.
+func rewriteKubeconfig(data []byte, dir, homeDir string, readFile func(string) ([]byte, error)) ([]byte, error) {
+	var kubeconfig map[string]interface{}
+	if err := yaml.Unmarshal(data, &kubeconfig); err != nil {
+		return nil, err
+	}
+
+	for _, section := range []struct {
+		key  string
+		item string
+	}{
+		{"clusters", "cluster"},
+		{"users", "user"},
+	} {
+		entries, _ := kubeconfig[section.key].([]interface{})
+
+		for _, entry := range entries {
+			entryMap, _ := entry.(map[string]interface{})
+			item, _ := entryMap[section.item].(map[string]interface{})
+			if item == nil {
+				continue
+			}
+
+			if section.item == "cluster" {
+				rewriteKubeconfigServer(item)
+			}
+
+			if err := rewriteKubeconfigFiles(item, dir, homeDir, readFile); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	rewritten, err := yaml.Marshal(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return rewritten, nil
+}
+
+func rewriteKubeconfigFiles(item map[string]interface{},
+	dir, homeDir string,
+	readFile func(string) ([]byte, error)) error {
+
+	for key, dataKey := range kubeconfigFileKeys {
+		path, _ := item[key].(string)
+		if path == "" {
+			continue
+		}
+
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+
+		if containerPath := getKubeconfigContainerPath(path, homeDir); containerPath != "" {
+			item[key] = containerPath
+			continue
+		}
+
+		data, err := readFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+
+		delete(item, key)
+		item[dataKey] = base64.StdEncoding.EncodeToString(data)
+	}
+
+	return nil
+}
+
+func rewriteKubeconfigServer(cluster map[string]interface{}) {
+	server, _ := cluster["server"].(string)
+	if server == "" {
+		return
+	}
+
+	server, hostname := getContainerHostAddress(server)
+	if hostname == "" {
+		return
+	}
+
+	cluster["server"] = server
+
+	if _, ok := cluster["tls-server-name"]; ok || cluster["insecure-skip-tls-verify"] == true {
+		return
+	}
+
+	if ip := net.ParseIP(hostname); ip != nil && ip.IsUnspecified() {
+		hostname = "127.0.0.1"
+	}
+
+	cluster["tls-server-name"] = hostname
+}
+
+// pushKubeconfig makes the rewritten kubeconfig of the host the default one
+// of the user inside a running container, or removes it once it's not
+// wanted anymore.
+func pushKubeconfig(container string, enabled bool, kubeconfig []byte) error {
+	const kubeSh = "/etc/profile.d/toolbox-kube.sh"
+
+	if !enabled || kubeconfig == nil {
+		if err := podman.ExecAsRoot(container, nil, "rm", "--force", kubeconfigInContainer); err != nil {
+			return err
+		}
+
+		if err := writeFileInContainer(container, kubeSh, nil); err != nil {
+			return err
+		}
+
+		return nil
+	}
+
+	const script = `umask 077
+mkdir --parents "$(dirname "$2")"
+cat >"$2.toolbox"
+chown "$1" "$2.toolbox"
+mv --force "$2.toolbox" "$2"`
+
+	stdin := bytes.NewReader(kubeconfig)
+	if err := podman.ExecAsRoot(container,
+		stdin,
+		"sh",
+		"-c",
+		script,
+		"sh",
+		currentUser.Uid,
+		kubeconfigInContainer); err != nil {
+		return err
+	}
+
+	kubeShData := "export KUBECONFIG=" + kubeconfigInContainer + "\n"
+	if err := writeFileInContainer(container, kubeSh, []byte(kubeShData)); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,135 @@
+//go:build darwin
+
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+const testKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: kind-dev
+  cluster:
+    server: https://127.0.0.1:6443
+    certificate-authority: ca.crt
+- name: minikube
+  cluster:
+    server: https://localhost:8443
+    tls-server-name: minikube
+    certificate-authority: /opt/minikube/ca.crt
+- name: remote
+  cluster:
+    server: https://k8s.example.com
+users:
+- name: kind-dev
+  user:
+    client-certificate: /var/folders/kind/client.crt
+    client-key: /Users/alice/.minikube/client.key
+`
+
+func TestRewriteKubeconfig(t *testing.T) {
+	readFile := func(path string) ([]byte, error) {
+		if path == "/var/folders/kind/client.crt" {
+			return []byte("certificate"), nil
+		}
+
+		return nil, os.ErrNotExist
+	}
+
+	data, err := rewriteKubeconfig([]byte(testKubeconfig), "/Users/alice/.kube", "/Users/alice", readFile)
+	require.NoError(t, err)
+
+	var kubeconfig struct {
+		Clusters []struct {
+			Cluster map[string]string `yaml:"cluster"`
+		} `yaml:"clusters"`
+		Users []struct {
+			User map[string]string `yaml:"user"`
+		} `yaml:"users"`
+	}
+
+	err = yaml.Unmarshal(data, &kubeconfig)
+	require.NoError(t, err)
+	require.Len(t, kubeconfig.Clusters, 3)
+	require.Len(t, kubeconfig.Users, 1)
+
+	assert.Equal(t, map[string]string{
+		"server":                "https://host.containers.internal:6443",
+		"tls-server-name":       "127.0.0.1",
+		"certificate-authority": "/Users/alice/.kube/ca.crt",
+	}, kubeconfig.Clusters[0].Cluster)
+
+	assert.Equal(t, map[string]string{
+		"server":                "https://host.containers.internal:8443",
+		"tls-server-name":       "minikube",
+		"certificate-authority": "/host/opt/minikube/ca.crt",
+	}, kubeconfig.Clusters[1].Cluster)
+
+	assert.Equal(t, map[string]string{
+		"server": "https://k8s.example.com",
+	}, kubeconfig.Clusters[2].Cluster)
+
+	assert.Equal(t, map[string]string{
+		"client-certificate-data": "Y2VydGlmaWNhdGU=",
+		"client-key":              "/Users/alice/.minikube/client.key",
+	}, kubeconfig.Users[0].User)
+}
+
+func TestRewriteKubeconfigUnreadable(t *testing.T) {
+	readFile := func(path string) ([]byte, error) {
+		return nil, errors.New("permission denied")
+	}
+
+	const kubeconfig = `users:
+- name: test
+  user:
+    client-key: /etc/kubernetes/client.key
+`
+
+	_, err := rewriteKubeconfig([]byte(kubeconfig), "/Users/alice/.kube", "/Users/alice", readFile)
+	assert.Error(t, err)
+}
+
+func TestGetKubeconfigContainerPath(t *testing.T) {
+	testCases := []struct {
+		path     string
+		expected string
+	}{
+		{"/Users/alice/certs/ca.crt", "/Users/alice/certs/ca.crt"},
+		{"/Users/bob/ca.crt", "/host/Users/bob/ca.crt"},
+		{"/private/tmp/ca.crt", "/host/tmp/ca.crt"},
+		{"/tmp/ca.crt", "/host/tmp/ca.crt"},
+		{"/usr/local/etc/ca.crt", "/host/usr/local/etc/ca.crt"},
+		{"/optional/ca.crt", ""},
+		{"/etc/ca.crt", ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.path, func(t *testing.T) {
+			assert.Equal(t, tc.expected, getKubeconfigContainerPath(tc.path, "/Users/alice"))
+		})
+	}
+}
@@ -0,0 +1,254 @@
+//go:build darwin
+
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/containers/toolbox/pkg/shell"
+	"github.com/sirupsen/logrus"
+)
+
+// launchAgent is a launchd.plist(5) job that runs in the user's session, ie.,
+// in ~/Library/LaunchAgents.
+type launchAgent struct {
+	arguments   []string
+	environment map[string]string
+	label       string
+	runAtLoad   bool
+
+	// If logFile is not empty, then the standard output and error of the
+	// job are appended to it.
+	logFile string
+
+	// If weekday is not nil, then the job runs once a week on that day,
+	// where 0 is Sunday, at hour.
+	hour    int
+	weekday *int
+}
+
+// getLaunchAgentPath returns the property list file of the launchd job with
+// the given label.
+func getLaunchAgentPath(label string) (string, error) {
+	homeDir := getCurrentUserHomeDir()
+	if homeDir == "" {
+		return "", errors.New("failed to find the home directory")
+	}
+
+	path := filepath.Join(homeDir, "Library", "LaunchAgents", label+".plist")
+	return path, nil
+}
+
+func getLaunchdDomain() string {
+	domain := "gui/" + currentUser.Uid
+	return domain
+}
+
+// installLaunchAgent writes the property list of the job and loads it into
+// launchd(8), replacing an older version of it, if any.
+func installLaunchAgent(agent launchAgent) error {
+	path, err := getLaunchAgentPath(agent.label)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", dir, err)
+	}
+
+	if err := os.WriteFile(path, agent.plist(), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	if err := reloadLaunchAgent(agent.label, path); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// isLaunchAgentInstalled checks if the property list of the launchd job
+// with the given label exists.
+func isLaunchAgentInstalled(label string) bool {
+	path, err := getLaunchAgentPath(label)
+	if err != nil {
+		logrus.Debugf("Checking launchd job %s: %s", label, err)
+		return false
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		return false
+	}
+
+	return true
+}
+
+// reloadLaunchAgent loads the property list of the job into launchd(8),
+// replacing the older version of it that was loaded, if any.
+func reloadLaunchAgent(label, path string) error {
+	domain := getLaunchdDomain()
+
+	if err := shell.Run("launchctl", nil, nil, nil, "bootout", domain, path); err != nil {
+		logrus.Debugf("Unloading launchd job %s failed: %s", label, err)
+	}
+
+	if err := shell.Run("launchctl", nil, nil, nil, "bootstrap", domain, path); err != nil {
+		logrus.Debugf("Loading launchd job %s failed: %s", label, err)
+		return fmt.Errorf("failed to load launchd job %s", label)
+	}
+
+	return nil
+}
+
+// uninstallLaunchAgent unloads the launchd job with the given label and
+// removes its property list.  It's not an error if it wasn't installed.
+func uninstallLaunchAgent(label string) error {
+	path, err := getLaunchAgentPath(label)
+	if err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+
+		return fmt.Errorf("failed to access %s: %w", path, err)
+	}
+
+	domain := getLaunchdDomain()
+
+	if err := shell.Run("launchctl", nil, nil, nil, "bootout", domain, path); err != nil {
+		logrus.Debugf("Unloading launchd job %s failed: %s", label, err)
+	}
+
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("failed to remove %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// updateLaunchAgentExecutable points the installed launchd job with the given
+// label at a new path to the executable, eg., after Homebrew upgraded Toolbx
+// into a different directory.  It's not an error if the job isn't installed.
+func updateLaunchAgentExecutable(label, oldExecutable, newExecutable string) error {
+	path, err := getLaunchAgentPath(label)
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var oldString, newString bytes.Buffer
+	xml.EscapeText(&oldString, []byte(oldExecutable))
+	xml.EscapeText(&newString, []byte(newExecutable))
+
+	oldArgument := "<string>" + oldString.String() + "</string>"
+	newArgument := "<string>" + newString.String() + "</string>"
+
+	if !bytes.Contains(data, []byte(oldArgument)) {
+		return nil
+	}
+
+	data = bytes.ReplaceAll(data, []byte(oldArgument), []byte(newArgument))
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	if err := reloadLaunchAgent(label, path); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (agent launchAgent) plist() []byte {
+	var buffer bytes.Buffer
+
+	writeString := func(indent, key, value string) {
+		fmt.Fprintf(&buffer, "%s<key>%s</key>\n%s<string>", indent, key, indent)
+		xml.EscapeText(&buffer, []byte(value))
+		fmt.Fprintf(&buffer, "</string>\n")
+	}
+
+	fmt.Fprintf(&buffer, "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n")
+	fmt.Fprintf(&buffer, "<!DOCTYPE plist PUBLIC \"-//Apple//DTD PLIST 1.0//EN\" ")
+	fmt.Fprintf(&buffer, "\"http://www.apple.com/DTDs/PropertyList-1.0.dtd\">\n")
+	fmt.Fprintf(&buffer, "<plist version=\"1.0\">\n<dict>\n")
+
+	writeString("\t", "Label", agent.label)
+
+	fmt.Fprintf(&buffer, "\t<key>ProgramArguments</key>\n\t<array>\n")
+	for _, argument := range agent.arguments {
+		fmt.Fprintf(&buffer, "\t\t<string>")
+		xml.EscapeText(&buffer, []byte(argument))
+		fmt.Fprintf(&buffer, "</string>\n")
+	}
+	fmt.Fprintf(&buffer, "\t</array>\n")
+
+	if len(agent.environment) != 0 {
+		keys := make([]string, 0, len(agent.environment))
+		for key := range agent.environment {
+			keys = append(keys, key)
+		}
+
+		sort.Strings(keys)
+
+		fmt.Fprintf(&buffer, "\t<key>EnvironmentVariables</key>\n\t<dict>\n")
+		for _, key := range keys {
+			writeString("\t\t", key, agent.environment[key])
+		}
+		fmt.Fprintf(&buffer, "\t</dict>\n")
+	}
+
+	if agent.logFile != "" {
+		writeString("\t", "StandardErrorPath", agent.logFile)
+		writeString("\t", "StandardOutPath", agent.logFile)
+	}
+
+	if agent.runAtLoad {
+		fmt.Fprintf(&buffer, "\t<key>RunAtLoad</key>\n\t<true/>\n")
+	}
+
+	if agent.weekday != nil {
+		fmt.Fprintf(&buffer, "\t<key>StartCalendarInterval</key>\n\t<dict>\n")
+		fmt.Fprintf(&buffer, "\t\t<key>Hour</key>\n\t\t<integer>%d</integer>\n", agent.hour)
+		fmt.Fprintf(&buffer, "\t\t<key>Minute</key>\n\t\t<integer>0</integer>\n")
+		fmt.Fprintf(&buffer, "\t\t<key>Weekday</key>\n\t\t<integer>%d</integer>\n", *agent.weekday)
+		fmt.Fprintf(&buffer, "\t</dict>\n")
+	}
+
+	fmt.Fprintf(&buffer, "</dict>\n</plist>\n")
+	return buffer.Bytes()
+}
@@ -0,0 +1,260 @@
+//go:build darwin
+
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/containers/toolbox/pkg/i18n"
+	"github.com/containers/toolbox/pkg/podman"
+	"github.com/containers/toolbox/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+const (
+	linkHostsMarker = "# toolbox link"
+	linkNetwork     = "toolbox"
+)
+
+var (
+	linkFlags struct {
+		port int
+	}
+)
+
+var linkCmd = &cobra.Command{
+	Use:               "link",
+	Short:             "Let two Toolbx containers reach each other by name (macOS version)",
+	RunE:              link,
+	ValidArgsFunction: completionContainerNamesFiltered,
+}
+
+func init() {
+	flags := linkCmd.Flags()
+
+	flags.IntVarP(&linkFlags.port,
+		"port",
+		"p",
+		0,
+		"Check that the second container can reach this port of the first one")
+
+	linkCmd.SetHelpFunc(linkHelp)
+	rootCmd.AddCommand(linkCmd)
+}
+
+// link connects two containers to a shared Podman network.  Containers on
+// macOS use slirp4netns by default, which can't be connected to a network
+// after they were created, so this needs containers created with
+// '--network bridge'.
+func link(cmd *cobra.Command, args []string) error {
+	if utils.IsInsideContainer() {
+		return errors.New("link is not supported inside a container")
+	}
+
+	if len(args) != 2 {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "link needs two containers\n")
+		i18n.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	if args[0] == args[1] {
+		return errors.New("cannot link a container to itself")
+	}
+
+	if port := linkFlags.port; port < 0 || port > 65535 {
+		return fmt.Errorf("invalid port %d", port)
+	}
+
+	running := true
+	for _, container := range args {
+		containerObj, err := getLinkableContainer(container)
+		if err != nil {
+			return err
+		}
+
+		if containerObj.Status() != "running" {
+			running = false
+		}
+	}
+
+	if exists, _ := podman.NetworkExists(linkNetwork); !exists {
+		logrus.Debugf("Creating network %s", linkNetwork)
+
+		if err := podman.NetworkCreate(linkNetwork); err != nil {
+			logrus.Debugf("Creating network %s failed: %s", linkNetwork, err)
+			return fmt.Errorf("failed to create network %s", linkNetwork)
+		}
+	}
+
+	for _, container := range args {
+		logrus.Debugf("Connecting container %s to network %s", container, linkNetwork)
+
+		if err := podman.NetworkConnect(linkNetwork, container, container); err != nil {
+			logrus.Debugf("Connecting container %s to network %s failed: %s", container, linkNetwork, err)
+			return fmt.Errorf("failed to connect container %s to network %s", container, linkNetwork)
+		}
+	}
+
+	fmt.Printf("Linked containers %s and %s\n", args[0], args[1])
+
+	if !running {
+		return nil
+	}
+
+	updateLinkedHosts(args[0])
+
+	if linkFlags.port != 0 {
+		checkLinkedPort(args[1], args[0], linkFlags.port)
+	}
+
+	return nil
+}
+
+func linkHelp(cmd *cobra.Command, args []string) {
+	if utils.IsInsideContainer() {
+		if !utils.IsInsideToolboxContainer() {
+			fmt.Fprintf(os.Stderr, "Error: this is not a Toolbx container\n")
+			return
+		}
+
+		if _, err := utils.ForwardToHost(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return
+		}
+
+		return
+	}
+
+	if err := showManual("toolbox-link"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return
+	}
+}
+
+// checkLinkedPort warns if nothing in container to is listening on port yet,
+// as seen from container from.  It's only a hint, because the service might
+// not have been started yet.
+func checkLinkedPort(from, to string, port int) {
+	portString := strconv.Itoa(port)
+	script := "exec 3<>/dev/tcp/" + to + "/" + portString
+
+	if err := podman.ExecAsRoot(from, nil, "timeout", "5", "bash", "-c", script); err != nil {
+		logrus.Debugf("Connecting from container %s to %s:%d failed: %s", from, to, port, err)
+		showWarning("container %s can't reach port %d of container %s yet", from, port, to)
+		return
+	}
+
+	fmt.Printf("Container %s can reach port %d of container %s at %s:%d\n", from, port, to, to, port)
+}
+
+func getLinkableContainer(container string) (podman.Container, error) {
+	containerObj, err := podman.InspectContainer(container)
+	if err != nil {
+		return nil, createErrorContainerNotFound(container)
+	}
+
+	if !containerObj.IsToolbx() {
+		return nil, fmt.Errorf("%s is not a Toolbx container", container)
+	}
+
+	if err := checkContainerOwner(containerObj); err != nil {
+		return nil, err
+	}
+
+	networkMode, err := podman.GetContainerNetworkMode(container)
+	if err != nil {
+		logrus.Debugf("Getting the network mode of container %s failed: %s", container, err)
+		return nil, fmt.Errorf("failed to inspect container %s", container)
+	}
+
+	if networkMode != "bridge" {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "container %s uses %s networking, which can't be linked\n", container, networkMode)
+		fmt.Fprintf(&builder, "Recreate it with 'toolbox create --network bridge'.")
+
+		errMsg := builder.String()
+		return nil, errors.New(errMsg)
+	}
+
+	return containerObj, nil
+}
+
+// updateLinkedHosts adds the running containers linked to container to each
+// other's /etc/hosts.  'toolbox monitor-host' replaces /etc/resolv.conf with
+// the host's name servers, which rules out Podman's DNS server for the names on
+// the link network, and Podman rewrites /etc/hosts when a container starts, so
+// 'toolbox enter' and 'toolbox run' call this again.
+func updateLinkedHosts(container string) {
+	containers, err := podman.GetContainers("--filter", "network="+linkNetwork)
+	if err != nil {
+		logrus.Debugf("Getting the containers on network %s failed: %s", linkNetwork, err)
+		return
+	}
+
+	var names []string
+	ipAddresses := make(map[string]string)
+
+	for containers.Next() {
+		name := containers.Get().Name()
+
+		ipAddress, err := podman.GetContainerIPAddress(name, linkNetwork)
+		if err != nil {
+			logrus.Debugf("Getting the IP address of container %s failed: %s", name, err)
+			continue
+		}
+
+		names = append(names, name)
+		ipAddresses[name] = ipAddress
+	}
+
+	if _, linked := ipAddresses[container]; !linked {
+		logrus.Debugf("Container %s is not linked to other containers", container)
+		return
+	}
+
+	logrus.Debugf("Updating /etc/hosts of the containers linked to %s", container)
+
+	for _, name := range names {
+		var hosts strings.Builder
+		for _, peer := range names {
+			if peer == name {
+				continue
+			}
+
+			fmt.Fprintf(&hosts, "%s %s %s\n", ipAddresses[peer], peer, linkHostsMarker)
+		}
+
+		script := "{ grep -v ' " + linkHostsMarker + "$' /etc/hosts; cat; } >/etc/hosts.toolbox" +
+			" && cat /etc/hosts.toolbox >/etc/hosts" +
+			" && rm -f /etc/hosts.toolbox"
+
+		hostsReader := strings.NewReader(hosts.String())
+		if err := podman.ExecAsRoot(name, hostsReader, "sh", "-c", script); err != nil {
+			logrus.Debugf("Updating /etc/hosts of container %s failed: %s", name, err)
+		}
+	}
+}
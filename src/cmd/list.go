@@ -20,27 +20,39 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path"
+	"slices"
 	"sort"
+	"strings"
 	"text/tabwriter"
 
 	"github.com/containers/toolbox/pkg/podman"
-	"github.com/containers/toolbox/pkg/term"
+	"github.com/containers/toolbox/pkg/toolbox"
 	"github.com/containers/toolbox/pkg/utils"
-	"github.com/sirupsen/logrus"
+	"github.com/docker/go-units"
 	"github.com/spf13/cobra"
 )
 
+type listFilter struct {
+	key   string
+	value string
+}
+
 var (
 	listFlags struct {
+		filters        []string
 		onlyContainers bool
 		onlyImages     bool
+		size           bool
+		sort           string
 	}
 
-	// toolboxLabels holds labels used by containers/images that mark them as compatible with Toolbx
-	toolboxLabels = map[string]string{
-		"com.github.debarshiray.toolbox": "true",
-		"com.github.containers.toolbox":  "true",
-	}
+	// listFilterKeys are the keys understood by '--filter'.  Those that
+	// can't be answered for an image, like 'name' and 'status', hide the
+	// images altogether.
+	listFilterKeys = []string{"distro", "image", "name", "release", "status"}
+
+	listSortKeys = []string{"created", "last-used", "name", "size"}
 )
 
 var listCmd = &cobra.Command{
@@ -65,6 +77,33 @@ func init() {
 		false,
 		"List only Toolbx images, not containers")
 
+	flags.StringArrayVarP(&listFlags.filters,
+		"filter",
+		"f",
+		nil,
+		"Filter output based on conditions given (eg., distro=fedora, status=running)")
+
+	flags.BoolVarP(&listFlags.size,
+		"size",
+		"s",
+		false,
+		"Show the size of each Toolbx container")
+
+	flags.StringVar(&listFlags.sort,
+		"sort",
+		"",
+		"Sort Toolbx containers by created, last-used, name or size")
+
+	if err := listCmd.RegisterFlagCompletionFunc("filter", completionListFilters); err != nil {
+		panicMsg := fmt.Sprintf("failed to register flag completion function: %v", err)
+		panic(panicMsg)
+	}
+
+	if err := listCmd.RegisterFlagCompletionFunc("sort", completionListSortKeys); err != nil {
+		panicMsg := fmt.Sprintf("failed to register flag completion function: %v", err)
+		panic(panicMsg)
+	}
+
 	listCmd.SetHelpFunc(listHelp)
 	rootCmd.AddCommand(listCmd)
 }
@@ -79,6 +118,21 @@ func list(cmd *cobra.Command, args []string) error {
 		return &exitError{exitCode, err}
 	}
 
+	filters, err := parseListFilters(listFlags.filters)
+	if err != nil {
+		return err
+	}
+
+	if listFlags.sort != "" && !slices.Contains(listSortKeys, listFlags.sort) {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "invalid argument for '--sort': %s\n", listFlags.sort)
+		fmt.Fprintf(&builder, "Supported values are: %s\n", strings.Join(listSortKeys, ", "))
+		fmt.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
 	lsContainers := true
 	lsImages := true
 
@@ -88,15 +142,23 @@ func list(cmd *cobra.Command, args []string) error {
 		lsImages = false
 	}
 
+	for _, filter := range filters {
+		if filter.key == "name" || filter.key == "status" {
+			lsImages = false
+		}
+	}
+
 	var images []podman.Image
 	var containers []podman.Container
-	var err error
+	var sizes map[string]podman.ContainerSize
 
 	if lsImages {
 		images, err = getImages(false)
 		if err != nil {
 			return err
 		}
+
+		images = filterImages(images, filters)
 	}
 
 	if lsContainers {
@@ -104,25 +166,192 @@ func list(cmd *cobra.Command, args []string) error {
 		if err != nil {
 			return err
 		}
+
+		containers = filterContainers(containers, filters)
+
+		if len(containers) != 0 && (listFlags.size || listFlags.sort == "size") {
+			sizes, err = podman.GetContainerSizes()
+			if err != nil {
+				return fmt.Errorf("failed to get the sizes of containers: %w", err)
+			}
+		}
+
+		sortContainers(containers, listFlags.sort, sizes)
 	}
 
-	listOutput(images, containers)
+	listOutput(images, containers, sizes)
 	return nil
 }
 
+// parseListFilters parses the KEY=VALUE arguments of '--filter'.
+func parseListFilters(args []string) ([]listFilter, error) {
+	var filters []listFilter
+
+	for _, arg := range args {
+		key, value, found := strings.Cut(arg, "=")
+		if !found || value == "" || !slices.Contains(listFilterKeys, key) {
+			var builder strings.Builder
+			fmt.Fprintf(&builder, "invalid argument for '--filter': %s\n", arg)
+			fmt.Fprintf(&builder, "Filters must be KEY=VALUE, with KEY one of: %s\n",
+				strings.Join(listFilterKeys, ", "))
+			fmt.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+			errMsg := builder.String()
+			return nil, errors.New(errMsg)
+		}
+
+		filters = append(filters, listFilter{key, value})
+	}
+
+	return filters, nil
+}
+
+// matchListFilters follows 'podman ps --filter': filters with the same key
+// match if any of them does, and filters with different keys must all match.
+// The getter returns the value to compare against for a key, and false if the
+// key doesn't apply.
+func matchListFilters(filters []listFilter, get func(key string) (string, bool)) bool {
+	matched := make(map[string]bool)
+
+	for _, filter := range filters {
+		if _, ok := matched[filter.key]; !ok {
+			matched[filter.key] = false
+		}
+
+		value, ok := get(filter.key)
+		if !ok {
+			continue
+		}
+
+		switch filter.key {
+		case "image", "name":
+			if matches, err := path.Match(filter.value, value); err == nil && matches {
+				matched[filter.key] = true
+			}
+		default:
+			if filter.value == value {
+				matched[filter.key] = true
+			}
+		}
+	}
+
+	for _, ok := range matched {
+		if !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+func filterContainers(containers []podman.Container, filters []listFilter) []podman.Container {
+	if len(filters) == 0 {
+		return containers
+	}
+
+	var filtered []podman.Container
+
+	for _, container := range containers {
+		image := container.Image()
+		distro, release := utils.GetDistroAndReleaseForImage(image)
+
+		match := matchListFilters(filters, func(key string) (string, bool) {
+			switch key {
+			case "distro":
+				return distro, true
+			case "image":
+				return image, true
+			case "name":
+				return container.Name(), true
+			case "release":
+				return release, true
+			case "status":
+				return container.Status(), true
+			}
+
+			return "", false
+		})
+
+		if match {
+			filtered = append(filtered, container)
+		}
+	}
+
+	return filtered
+}
+
+func filterImages(images []podman.Image, filters []listFilter) []podman.Image {
+	if len(filters) == 0 {
+		return images
+	}
+
+	var filtered []podman.Image
+
+	for _, image := range images {
+		if len(image.Names) != 1 {
+			panic("cannot filter unflattened Image")
+		}
+
+		name := image.Names[0]
+		distro, release := utils.GetDistroAndReleaseForImage(name)
+
+		match := matchListFilters(filters, func(key string) (string, bool) {
+			switch key {
+			case "distro":
+				return distro, true
+			case "image":
+				return name, true
+			case "release":
+				return release, true
+			}
+
+			return "", false
+		})
+
+		if match {
+			filtered = append(filtered, image)
+		}
+	}
+
+	return filtered
+}
+
+// sortContainers sorts by name alphabetically, and by creation time, last-used
+// time and size with the newest, most recent and largest first.  Containers
+// that were never used go last.  Without a key, the order from Podman is kept.
+func sortContainers(containers []podman.Container, key string, sizes map[string]podman.ContainerSize) {
+	switch key {
+	case "created":
+		sort.SliceStable(containers, func(i, j int) bool {
+			return containers[i].CreatedTime().After(containers[j].CreatedTime())
+		})
+	case "last-used":
+		sort.SliceStable(containers, func(i, j int) bool {
+			lastUsedI, _ := toolbox.GetLastUsed(containers[i].ID())
+			lastUsedJ, _ := toolbox.GetLastUsed(containers[j].ID())
+			return lastUsedI.After(lastUsedJ)
+		})
+	case "name":
+		sort.SliceStable(containers, func(i, j int) bool {
+			return containers[i].Name() < containers[j].Name()
+		})
+	case "size":
+		sort.SliceStable(containers, func(i, j int) bool {
+			return sizes[containers[i].ID()].RwSize > sizes[containers[j].ID()].RwSize
+		})
+	}
+}
+
 func getContainers() ([]podman.Container, error) {
-	logrus.Debug("Fetching all containers")
-	args := []string{"--all", "--sort", "names"}
-	containers, err := podman.GetContainers(args...)
+	containers, err := toolbox.List()
 	if err != nil {
-		logrus.Debugf("Fetching all containers failed: %s", err)
-		return nil, errors.New("failed to get containers")
+		return nil, err
 	}
 
 	var toolboxContainers []podman.Container
 
-	for containers.Next() {
-		if container := containers.Get(); container.IsToolbx() {
+	for _, container := range containers {
+		if isContainerVisible(container) {
 			toolboxContainers = append(toolboxContainers, container)
 		}
 	}
@@ -152,44 +381,17 @@ func listHelp(cmd *cobra.Command, args []string) {
 }
 
 func getImages(fillNameWithID bool) ([]podman.Image, error) {
-	logrus.Debug("Fetching all images")
-	var args []string
-	images, err := podman.GetImages(args...)
+	images, err := toolbox.ListImages(fillNameWithID)
 	if err != nil {
-		logrus.Debugf("Fetching all images failed: %s", err)
-		return nil, errors.New("failed to get images")
-	}
-
-	processed := make(map[string]struct{})
-	var toolboxImages []podman.Image
-
-	for _, image := range images {
-		if _, ok := processed[image.ID]; ok {
-			continue
-		}
-
-		processed[image.ID] = struct{}{}
-		var isToolboxImage bool
-
-		for label := range toolboxLabels {
-			if _, ok := image.Labels[label]; ok {
-				isToolboxImage = true
-				break
-			}
-		}
-
-		if isToolboxImage {
-			flattenedImages := image.FlattenNames(fillNameWithID)
-			toolboxImages = append(toolboxImages, flattenedImages...)
-		}
-
+		return nil, err
 	}
 
-	sort.Sort(podman.ImageSlice(toolboxImages))
-	return toolboxImages, nil
+	return images, nil
 }
 
-func listOutput(images []podman.Image, containers []podman.Container) {
+// listOutput adds a SIZE column to the containers if sizes isn't nil,
+// formatted like 'podman ps --size'.
+func listOutput(images []podman.Image, containers []podman.Container, sizes map[string]podman.ContainerSize) {
 	if len(images) != 0 {
 		writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
 		fmt.Fprintf(writer, "%s\t%s\t%s\n", "IMAGE ID", "IMAGE NAME", "CREATED")
@@ -217,21 +419,30 @@ func listOutput(images []podman.Image, containers []podman.Container) {
 		const defaultColor = "\033[0;00m" // identical to resetColor, but same length as boldGreenColor
 		const resetColor = "\033[0m"
 
+		// Color is never the only signal, because STATUS is always shown,
+		// but screen readers are better off without the escape sequences.
+		useColor := shouldUseColor(os.Stdout)
+
 		writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
 
-		if term.IsTerminal(os.Stdout) {
+		if useColor {
 			fmt.Fprintf(writer, "%s", defaultColor)
 		}
 
 		fmt.Fprintf(writer,
-			"%s\t%s\t%s\t%s\t%s",
+			"%s\t%s\t%s\t%s\t%s\t%s",
 			"CONTAINER ID",
 			"CONTAINER NAME",
 			"CREATED",
+			"LAST USED",
 			"STATUS",
 			"IMAGE NAME")
 
-		if term.IsTerminal(os.Stdout) {
+		if sizes != nil {
+			fmt.Fprintf(writer, "\t%s", "SIZE")
+		}
+
+		if useColor {
 			fmt.Fprintf(writer, "%s", resetColor)
 		}
 
@@ -244,7 +455,7 @@ func listOutput(images []podman.Image, containers []podman.Container) {
 				isRunning = status == "running"
 			}
 
-			if term.IsTerminal(os.Stdout) {
+			if useColor {
 				var color string
 				if isRunning {
 					color = boldGreenColor
@@ -260,9 +471,33 @@ func listOutput(images []podman.Image, containers []podman.Container) {
 			image := container.Image()
 			name := container.Name()
 			status := container.Status()
-			fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s", utils.ShortID(id), name, created, status, image)
 
-			if term.IsTerminal(os.Stdout) {
+			lastUsed := "never"
+			if lastUsedTime, ok := toolbox.GetLastUsed(id); ok {
+				lastUsed = utils.HumanDuration(lastUsedTime.Unix())
+			}
+
+			fmt.Fprintf(writer,
+				"%s\t%s\t%s\t%s\t%s\t%s",
+				utils.ShortID(id),
+				name,
+				created,
+				lastUsed,
+				status,
+				image)
+
+			if sizes != nil {
+				size := "-"
+				if containerSize, ok := sizes[id]; ok {
+					size = fmt.Sprintf("%s (virtual %s)",
+						units.HumanSize(float64(containerSize.RwSize)),
+						units.HumanSize(float64(containerSize.RootFsSize)))
+				}
+
+				fmt.Fprintf(writer, "\t%s", size)
+			}
+
+			if useColor {
 				fmt.Fprintf(writer, "%s", resetColor)
 			}
 
@@ -0,0 +1,135 @@
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseListFilters(t *testing.T) {
+	testCases := []struct {
+		name    string
+		args    []string
+		filters []listFilter
+		err     bool
+	}{
+		{
+			name: "none",
+		},
+		{
+			name:    "distro and status",
+			args:    []string{"distro=fedora", "status=running"},
+			filters: []listFilter{{"distro", "fedora"}, {"status", "running"}},
+		},
+		{
+			name:    "value with equals sign",
+			args:    []string{"name=a=b"},
+			filters: []listFilter{{"name", "a=b"}},
+		},
+		{
+			name: "unknown key",
+			args: []string{"label=foo"},
+			err:  true,
+		},
+		{
+			name: "missing value",
+			args: []string{"distro="},
+			err:  true,
+		},
+		{
+			name: "missing equals sign",
+			args: []string{"fedora"},
+			err:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			filters, err := parseListFilters(tc.args)
+			if tc.err {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.filters, filters)
+		})
+	}
+}
+
+func TestMatchListFilters(t *testing.T) {
+	values := map[string]string{
+		"distro": "fedora",
+		"name":   "fedora-toolbox-40",
+		"status": "running",
+	}
+
+	get := func(key string) (string, bool) {
+		value, ok := values[key]
+		return value, ok
+	}
+
+	testCases := []struct {
+		name    string
+		filters []listFilter
+		match   bool
+	}{
+		{
+			name:  "no filters",
+			match: true,
+		},
+		{
+			name:    "different keys all match",
+			filters: []listFilter{{"distro", "fedora"}, {"status", "running"}},
+			match:   true,
+		},
+		{
+			name:    "different keys one mismatch",
+			filters: []listFilter{{"distro", "fedora"}, {"status", "exited"}},
+			match:   false,
+		},
+		{
+			name:    "same key any match",
+			filters: []listFilter{{"status", "exited"}, {"status", "running"}},
+			match:   true,
+		},
+		{
+			name:    "name glob",
+			filters: []listFilter{{"name", "fedora-*"}},
+			match:   true,
+		},
+		{
+			name:    "name glob mismatch",
+			filters: []listFilter{{"name", "ubuntu-*"}},
+			match:   false,
+		},
+		{
+			name:    "key not applicable",
+			filters: []listFilter{{"release", "40"}},
+			match:   false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			match := matchListFilters(tc.filters, get)
+			assert.Equal(t, tc.match, match)
+		})
+	}
+}
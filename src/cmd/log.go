@@ -0,0 +1,124 @@
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// logFieldsHook adds the same set of fields to every message logged with
+// '--log-format json', so that logs collected from many computers can be
+// grouped and searched by tooling without parsing the messages themselves.
+type logFieldsHook struct {
+	mutex     sync.Mutex
+	command   string
+	container string
+	image     string
+	start     time.Time
+}
+
+var (
+	logFields = &logFieldsHook{start: time.Now()}
+
+	logFormats = []string{"text", "json"}
+
+	// terminalLogLevel is the level asked for with '--log-level' and
+	// '--verbose'.  It can be lower than the level of the standard logger,
+	// which might let more messages through for a log file.
+	terminalLogLevel = logrus.ErrorLevel
+)
+
+func (hook *logFieldsHook) Fire(entry *logrus.Entry) error {
+	hook.mutex.Lock()
+	defer hook.mutex.Unlock()
+
+	entry.Data["command"] = hook.command
+	entry.Data["container"] = hook.container
+	entry.Data["image"] = hook.image
+
+	duration := time.Since(hook.start).Seconds()
+	entry.Data["duration"] = math.Round(duration*1000) / 1000
+	return nil
+}
+
+func (hook *logFieldsHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (hook *logFieldsHook) getCommand() string {
+	hook.mutex.Lock()
+	defer hook.mutex.Unlock()
+
+	return hook.command
+}
+
+func (hook *logFieldsHook) setCommand(command string) {
+	hook.mutex.Lock()
+	defer hook.mutex.Unlock()
+
+	hook.command = command
+}
+
+func (hook *logFieldsHook) setContainer(container, image string) {
+	hook.mutex.Lock()
+	defer hook.mutex.Unlock()
+
+	hook.container = container
+	hook.image = image
+}
+
+// showMessage prints an informational message on its own line, unless
+// '--quiet' was used.  Output that was asked for must not use it.
+func showMessage(format string, a ...interface{}) {
+	if rootFlags.quiet {
+		return
+	}
+
+	msg := fmt.Sprintf(format, a...)
+	fmt.Println(msg)
+}
+
+// showWarning prints a warning on its own line, unless '--quiet' was used.
+func showWarning(format string, a ...interface{}) {
+	if rootFlags.quiet {
+		return
+	}
+
+	msg := fmt.Sprintf(format, a...)
+	fmt.Fprintf(os.Stderr, "Warning: %s\n", msg)
+}
+
+// logErrorJSON logs the error that ends the command as a JSON message, if
+// '--log-format json' is in effect, and reports whether it did so.
+func logErrorJSON(errMsg string) bool {
+	if rootFlags.logFormat != "json" {
+		return false
+	}
+
+	if terminalLogLevel < logrus.ErrorLevel {
+		return false
+	}
+
+	logrus.Error(errMsg)
+	return true
+}
@@ -0,0 +1,180 @@
+//go:build darwin
+
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/containers/toolbox/pkg/utils"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	logFileMaxSize   = 5 * 1024 * 1024
+	logFileRotations = 3
+)
+
+var (
+	logFile *logFileHook
+)
+
+// logFileHook writes every message down to the debug level to the log file,
+// regardless of what is shown on the terminal.
+type logFileHook struct {
+	file      *os.File
+	formatter logrus.Formatter
+	mutex     sync.Mutex
+}
+
+// levelFormatter drops the messages above a level, so that the terminal only
+// shows what was asked for with '--log-level', while the standard logger lets
+// the debug messages through to the log file.
+type levelFormatter struct {
+	logrus.Formatter
+	level logrus.Level
+}
+
+func (hook *logFileHook) Fire(entry *logrus.Entry) error {
+	fields := logrus.Fields{"pid": os.Getpid()}
+	if command := logFields.getCommand(); command != "" {
+		fields["command"] = command
+	}
+
+	fileEntry := entry.WithFields(fields)
+	fileEntry.Level = entry.Level
+	fileEntry.Message = entry.Message
+
+	serialized, err := hook.formatter.Format(fileEntry)
+	if err != nil {
+		return nil
+	}
+
+	hook.mutex.Lock()
+	defer hook.mutex.Unlock()
+
+	// A full disk shouldn't make every message print an error on the
+	// terminal.
+	hook.file.Write(serialized)
+	return nil
+}
+
+func (hook *logFileHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (formatter *levelFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	if entry.Level > formatter.level {
+		return nil, nil
+	}
+
+	return formatter.Formatter.Format(entry)
+}
+
+// logErrorToFile writes the error that ends the command to the log file,
+// because it's printed on the terminal without going through the logger.
+func logErrorToFile(errMsg string) {
+	if logFile == nil {
+		return
+	}
+
+	entry := logrus.NewEntry(logrus.StandardLogger())
+	entry.Level = logrus.ErrorLevel
+	entry.Message = errMsg
+	entry.Time = time.Now()
+	logFile.Fire(entry)
+}
+
+func getLogFile() (string, error) {
+	homeDir := getCurrentUserHomeDir()
+	if homeDir == "" {
+		return "", fmt.Errorf("failed to find the home directory")
+	}
+
+	logFile := filepath.Join(homeDir, "Library", "Logs", "toolbox", "toolbox.log")
+	return logFile, nil
+}
+
+// rotateLogFile moves toolbox.log to toolbox.log.1, and so on, once it has
+// grown past logFileMaxSize, and drops the oldest one.
+func rotateLogFile(logFile string) {
+	fileInfo, err := os.Stat(logFile)
+	if err != nil || fileInfo.Size() < logFileMaxSize {
+		return
+	}
+
+	for i := logFileRotations - 1; i > 0; i-- {
+		oldPath := fmt.Sprintf("%s.%d", logFile, i)
+		newPath := fmt.Sprintf("%s.%d", logFile, i+1)
+		os.Rename(oldPath, newPath)
+	}
+
+	os.Rename(logFile, logFile+".1")
+}
+
+// setUpLogFile additionally sends the log messages to
+// ~/Library/Logs/toolbox/toolbox.log at the debug level, so that failures can
+// be looked into after the fact.  It's best effort, and never stops the
+// command from running.
+func setUpLogFile() {
+	if utils.IsInsideContainer() {
+		return
+	}
+
+	path, err := getLogFile()
+	if err != nil {
+		logrus.Debugf("Setting up the log file: %s", err)
+		return
+	}
+
+	logsDirectory := filepath.Dir(path)
+	if err := os.MkdirAll(logsDirectory, 0700); err != nil {
+		logrus.Debugf("Setting up the log file: failed to create %s: %s", logsDirectory, err)
+		return
+	}
+
+	rotateLogFile(path)
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		logrus.Debugf("Setting up the log file: failed to open %s: %s", path, err)
+		return
+	}
+
+	var formatter logrus.Formatter = &logrus.TextFormatter{
+		DisableColors: true,
+		FullTimestamp: true,
+	}
+
+	if rootFlags.logFormat == "json" {
+		formatter = &logrus.JSONFormatter{}
+	}
+
+	if terminalLogLevel < logrus.DebugLevel {
+		logger := logrus.StandardLogger()
+		logrus.SetFormatter(&levelFormatter{Formatter: logger.Formatter, level: terminalLogLevel})
+		logrus.SetLevel(logrus.DebugLevel)
+	}
+
+	logFile = &logFileHook{file: file, formatter: formatter}
+	logrus.AddHook(logFile)
+}
@@ -0,0 +1,196 @@
+//go:build darwin
+
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/containers/toolbox/pkg/i18n"
+	"github.com/containers/toolbox/pkg/podman"
+	"github.com/containers/toolbox/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	logsFlags struct {
+		file   bool
+		follow bool
+	}
+)
+
+var logsCmd = &cobra.Command{
+	Use:               "logs",
+	Short:             "Show the logs of a Toolbx container (macOS version)",
+	RunE:              logs,
+	ValidArgsFunction: completionContainerNamesFiltered,
+}
+
+func init() {
+	flags := logsCmd.Flags()
+
+	flags.BoolVar(&logsFlags.file,
+		"file",
+		false,
+		"Show the log file of the latest initialization inside the container")
+
+	flags.BoolVarP(&logsFlags.follow,
+		"follow",
+		"f",
+		false,
+		"Keep showing new log messages as they appear")
+
+	logsCmd.SetHelpFunc(logsHelp)
+	rootCmd.AddCommand(logsCmd)
+}
+
+func logs(cmd *cobra.Command, args []string) error {
+	if utils.IsInsideContainer() {
+		return errors.New("logs is not supported inside a container")
+	}
+
+	if len(args) != 1 {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "logs needs a container\n")
+		i18n.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	if logsFlags.file && logsFlags.follow {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "options --file and --follow cannot be used together\n")
+		i18n.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	container := args[0]
+
+	containerObj, err := podman.InspectContainer(container)
+	if err != nil {
+		return createErrorContainerNotFound(container)
+	}
+
+	if !containerObj.IsToolbx() {
+		return fmt.Errorf("%s is not a Toolbx container", container)
+	}
+
+	if err := checkContainerOwner(containerObj); err != nil {
+		return err
+	}
+
+	if logsFlags.file {
+		if err := showInitContainerLog(container); err != nil {
+			return err
+		}
+
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt)
+	defer signal.Stop(signals)
+
+	go func() {
+		select {
+		case <-signals:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	// The entry point logs to its standard error stream, which is what this
+	// shows, and on the standard output, so that it can be piped.
+	if err := podman.LogsContext(ctx, container, logsFlags.follow, time.Time{}, os.Stdout); err != nil {
+		if errors.Is(err, context.Canceled) {
+			return nil
+		}
+
+		logrus.Debugf("Getting the logs of container %s failed: %s", container, err)
+
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "failed to get the logs of container %s\n", container)
+		fmt.Fprintf(&builder, "Use '--file' to show the log file inside the container instead.")
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	return nil
+}
+
+func logsHelp(cmd *cobra.Command, args []string) {
+	if utils.IsInsideContainer() {
+		if !utils.IsInsideToolboxContainer() {
+			fmt.Fprintf(os.Stderr, "Error: this is not a Toolbx container\n")
+			return
+		}
+
+		if _, err := utils.ForwardToHost(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return
+		}
+
+		return
+	}
+
+	if err := showManual("toolbox-logs"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return
+	}
+}
+
+// showInitContainerLog copies the log file of init-container out of the
+// container.  This works even if the container is stopped, which is usually
+// the case if it failed to initialize.
+func showInitContainerLog(container string) error {
+	var archive bytes.Buffer
+	if err := podman.CopyFromContainer(container, initContainerLog, &archive); err != nil {
+		logrus.Debugf("Copying %s out of container %s failed: %s", initContainerLog, container, err)
+		return fmt.Errorf("failed to find %s in container %s", initContainerLog, container)
+	}
+
+	archiveReader := tar.NewReader(&archive)
+	if _, err := archiveReader.Next(); err != nil {
+		logrus.Debugf("Reading the archive of %s failed: %s", initContainerLog, err)
+		return fmt.Errorf("failed to read %s in container %s", initContainerLog, container)
+	}
+
+	if _, err := io.Copy(os.Stdout, archiveReader); err != nil {
+		logrus.Debugf("Writing %s failed: %s", initContainerLog, err)
+		return fmt.Errorf("failed to read %s in container %s", initContainerLog, container)
+	}
+
+	return nil
+}
@@ -0,0 +1,245 @@
+//go:build darwin
+
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/containers/toolbox/pkg/i18n"
+	"github.com/containers/toolbox/pkg/podman"
+	"github.com/containers/toolbox/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+const machineAutostartLabel = "com.github.containers.toolbox.machine-autostart"
+
+var (
+	machineAutostartEnableFlags struct {
+		containers []string
+	}
+)
+
+var machineCmd = &cobra.Command{
+	Use:   "machine",
+	Short: "Manage the Podman machine that Toolbx containers run in (macOS version)",
+	RunE:  machine,
+}
+
+var machineAutostartCmd = &cobra.Command{
+	Use:   "autostart",
+	Short: "Start the Podman machine at login",
+	RunE:  machineAutostart,
+}
+
+var machineAutostartDisableCmd = &cobra.Command{
+	Use:   "disable",
+	Short: "Stop starting the Podman machine at login",
+	RunE:  machineAutostartDisable,
+}
+
+var machineAutostartEnableCmd = &cobra.Command{
+	Use:   "enable",
+	Short: "Start the Podman machine, and optionally Toolbx containers, at login",
+	RunE:  machineAutostartEnable,
+}
+
+var machineAutostartRunCmd = &cobra.Command{
+	Use:    "run",
+	Short:  "Start the Podman machine and the given Toolbx containers",
+	Hidden: true,
+	RunE:   machineAutostartRun,
+}
+
+func init() {
+	flags := machineAutostartEnableCmd.Flags()
+
+	flags.StringArrayVarP(&machineAutostartEnableFlags.containers,
+		"container",
+		"c",
+		nil,
+		"Also start this Toolbx container at login")
+
+	if err := machineAutostartEnableCmd.RegisterFlagCompletionFunc("container", completionContainerNames); err != nil {
+		panicMsg := fmt.Sprintf("failed to register flag completion function: %v", err)
+		panic(panicMsg)
+	}
+
+	machineAutostartCmd.AddCommand(machineAutostartDisableCmd)
+	machineAutostartCmd.AddCommand(machineAutostartEnableCmd)
+	machineAutostartCmd.AddCommand(machineAutostartRunCmd)
+	machineCmd.AddCommand(machineAutostartCmd)
+
+	machineCmd.SetHelpFunc(machineHelp)
+	rootCmd.AddCommand(machineCmd)
+}
+
+func machine(cmd *cobra.Command, args []string) error {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "missing command for \"machine\", eg., autostart\n")
+	i18n.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+	errMsg := builder.String()
+	return errors.New(errMsg)
+}
+
+// machineAutostart shows whether the Podman machine is started at login.
+func machineAutostart(cmd *cobra.Command, args []string) error {
+	if utils.IsInsideContainer() {
+		return errors.New("machine is not supported inside a container")
+	}
+
+	if isLaunchAgentInstalled(machineAutostartLabel) {
+		fmt.Println("The Podman machine is started at login")
+	} else {
+		fmt.Println("The Podman machine is not started at login")
+	}
+
+	return nil
+}
+
+func machineAutostartDisable(cmd *cobra.Command, args []string) error {
+	if utils.IsInsideContainer() {
+		return errors.New("machine is not supported inside a container")
+	}
+
+	if err := uninstallLaunchAgent(machineAutostartLabel); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// machineAutostartEnable installs a launchd agent that runs 'toolbox machine
+// autostart run' at login.  The shared Podman machine of system mode belongs
+// to the administrator, so it's left alone.
+func machineAutostartEnable(cmd *cobra.Command, args []string) error {
+	if utils.IsInsideContainer() {
+		return errors.New("machine is not supported inside a container")
+	}
+
+	if len(args) != 0 {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "machine autostart enable does not take any arguments, use '--container'\n")
+		i18n.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	if systemMode {
+		return errors.New("'--system' is not supported by machine autostart")
+	}
+
+	for _, container := range machineAutostartEnableFlags.containers {
+		containerObj, err := podman.InspectContainer(container)
+		if err != nil {
+			return createErrorContainerNotFound(container)
+		}
+
+		if !containerObj.IsToolbx() {
+			return fmt.Errorf("%s is not a Toolbx container", container)
+		}
+	}
+
+	homeDir := getCurrentUserHomeDir()
+	if homeDir == "" {
+		return errors.New("failed to find the home directory")
+	}
+
+	arguments := []string{executable, "machine", "autostart", "run"}
+	arguments = append(arguments, machineAutostartEnableFlags.containers...)
+
+	agent := launchAgent{
+		arguments:   arguments,
+		environment: map[string]string{"PATH": os.Getenv("PATH")},
+		label:       machineAutostartLabel,
+		logFile:     filepath.Join(homeDir, "Library", "Logs", "toolbox-machine-autostart.log"),
+		runAtLoad:   true,
+	}
+
+	if err := installLaunchAgent(agent); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// machineAutostartRun is what the launchd agent runs at login.  A container
+// that fails to start doesn't keep the others from starting.
+func machineAutostartRun(cmd *cobra.Command, args []string) error {
+	if utils.IsInsideContainer() {
+		return errors.New("machine is not supported inside a container")
+	}
+
+	if state, err := podman.MachineState(); err != nil || state != "running" {
+		logrus.Debug("Starting the Podman machine")
+
+		if err := podman.MachineStart(); err != nil {
+			logrus.Debugf("Starting the Podman machine failed: %s", err)
+			return errors.New("failed to start the Podman machine")
+		}
+	}
+
+	var failed bool
+
+	for _, container := range args {
+		var stderr strings.Builder
+		if err := podman.Start(container, &stderr); err != nil {
+			logrus.Debugf("Starting container %s failed: %s", container, stderr.String())
+			fmt.Fprintf(os.Stderr, "Error: failed to start container %s\n", container)
+			failed = true
+		}
+	}
+
+	if len(args) != 0 {
+		startHostMonitor()
+	}
+
+	if failed {
+		return &exitError{1, nil}
+	}
+
+	return nil
+}
+
+func machineHelp(cmd *cobra.Command, args []string) {
+	if utils.IsInsideContainer() {
+		if !utils.IsInsideToolboxContainer() {
+			fmt.Fprintf(os.Stderr, "Error: this is not a Toolbx container\n")
+			return
+		}
+
+		if _, err := utils.ForwardToHost(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return
+		}
+
+		return
+	}
+
+	if err := showManual("toolbox-machine"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return
+	}
+}
@@ -0,0 +1,137 @@
+//go:build darwin
+
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+
+	"github.com/containers/toolbox/pkg/shell"
+	"github.com/sirupsen/logrus"
+)
+
+// manuals has the rendered manuals, if the build put them in the manuals
+// directory.  See manuals/README.md.
+//
+//go:embed manuals
+var manuals embed.FS
+
+// showManual shows a manual with man(1).  The copy embedded in the binary is
+// preferred, because Homebrew and other installations on macOS often don't put
+// the manuals where man(1) looks for them.
+func showManual(manual string) error {
+	if _, err := exec.LookPath("man"); err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			showManualFallback()
+			return nil
+		}
+
+		return errors.New("failed to look up man(1)")
+	}
+
+	page, data, err := getEmbeddedManual(manual)
+	if err == nil {
+		return showEmbeddedManual(page, data)
+	}
+
+	logrus.Debugf("Looking up embedded manual %s failed: %s", manual, err)
+
+	if err := shell.Run("man", nil, nil, nil, "-w", manual); err != nil {
+		logrus.Debugf("Looking up installed manual %s failed: %s", manual, err)
+		showManualFallback()
+		return nil
+	}
+
+	if err := runManual(manual); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func getEmbeddedManual(manual string) (string, []byte, error) {
+	for _, section := range []string{"1", "5"} {
+		page := manual + "." + section
+		data, err := manuals.ReadFile("manuals/" + page)
+		if err == nil {
+			return page, data, nil
+		}
+
+		if !errors.Is(err, fs.ErrNotExist) {
+			return "", nil, err
+		}
+	}
+
+	return "", nil, fs.ErrNotExist
+}
+
+// runManual runs man(1) in the foreground.  The interrupt signal is caught
+// instead of ignored, because an ignored signal would be inherited by man(1)
+// and its pager, and the temporary directory of an embedded manual needs to
+// be removed afterwards.
+func runManual(manual string) error {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt)
+	defer signal.Stop(signals)
+
+	if err := shell.Run("man", os.Stdin, os.Stdout, os.Stderr, manual); err != nil {
+		logrus.Debugf("Invoking man(1) for %s failed: %s", manual, err)
+		return errors.New("failed to invoke man(1)")
+	}
+
+	return nil
+}
+
+// showEmbeddedManual writes the manual into a temporary directory and shows
+// it with man(1), which treats an argument with a slash as a path.
+func showEmbeddedManual(page string, data []byte) error {
+	dir, err := os.MkdirTemp("", "toolbox-manual-")
+	if err != nil {
+		logrus.Debugf("Creating a temporary directory for manual %s failed: %s", page, err)
+		return errors.New("failed to create a temporary directory")
+	}
+
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, page)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		logrus.Debugf("Writing manual %s failed: %s", path, err)
+		return fmt.Errorf("failed to write manual %s", page)
+	}
+
+	if err := runManual(path); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func showManualFallback() {
+	fmt.Printf("toolbox - Tool for interactive command line environments on macOS\n")
+	fmt.Printf("\n")
+
+	usage := getUsageForCommonCommands()
+	fmt.Printf("%s\n", usage)
+}
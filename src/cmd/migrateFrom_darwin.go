@@ -0,0 +1,556 @@
+//go:build darwin
+
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/containers/toolbox/pkg/i18n"
+	"github.com/containers/toolbox/pkg/podman"
+	"github.com/containers/toolbox/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+type migrationBundle struct {
+	config     []byte
+	containers []podman.ContainerDetails
+}
+
+type migrationMount struct {
+	destination string
+	readOnly    bool
+	source      string
+}
+
+type migrationPlan struct {
+	container       string
+	image           string
+	mounts          []migrationMount
+	skipped         []string
+	workspaceVolume bool
+}
+
+const (
+	// migrationBundleMaxFileSize limits how much of a file in a bundle is
+	// read, because both files are small, and the bundle comes from
+	// another machine.
+	migrationBundleMaxFileSize = 16 * 1024 * 1024
+)
+
+var (
+	migrateFromFlags struct {
+		dryRun bool
+	}
+
+	// linuxToolbxMountDestinations are set up by 'toolbox create' on Linux
+	// itself, and either have a macOS equivalent that 'toolbox create'
+	// sets up here, or mean nothing on a Mac.
+	linuxToolbxMountDestinations = []string{
+		"/dev",
+		"/etc/hostname",
+		"/etc/hosts",
+		"/etc/profile.d/toolbox.sh",
+		"/etc/resolv.conf",
+		"/media",
+		"/mnt",
+		"/run/host",
+		"/run/media",
+		"/usr/bin/toolbox",
+	}
+)
+
+var migrateFromCmd = &cobra.Command{
+	Use:   "migrate-from",
+	Short: "Recreate Toolbx containers exported from a Linux machine (macOS version)",
+	RunE:  migrateFrom,
+}
+
+func init() {
+	flags := migrateFromCmd.Flags()
+
+	flags.BoolVar(&migrateFromFlags.dryRun,
+		"dry-run",
+		false,
+		"Only show what would be created, without creating anything")
+
+	migrateFromCmd.SetHelpFunc(migrateFromHelp)
+	rootCmd.AddCommand(migrateFromCmd)
+}
+
+// migrateFrom recreates the Toolbx containers of a Linux machine from the
+// output of 'podman inspect' there.  Only the configuration is carried over,
+// not the contents of the containers, because those are built for Linux
+// paths and often another CPU architecture.
+func migrateFrom(cmd *cobra.Command, args []string) error {
+	if utils.IsInsideContainer() {
+		return errors.New("migrate-from is not supported inside a container")
+	}
+
+	if len(args) == 0 {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "migrate-from needs a bundle exported from a Linux machine\n")
+		i18n.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	bundlePath := args[0]
+
+	bundle, err := readMigrationBundle(bundlePath)
+	if err != nil {
+		return err
+	}
+
+	containers, err := getMigrationContainers(bundle, args[1:])
+	if err != nil {
+		return fmt.Errorf("%w in %s", err, bundlePath)
+	}
+
+	if len(containers) == 0 {
+		return fmt.Errorf("no Toolbx containers in %s", bundlePath)
+	}
+
+	homeDir := getCurrentUserHomeDir()
+	if homeDir == "" {
+		return errors.New("failed to get the home directory")
+	}
+
+	var plans []migrationPlan
+	for _, details := range containers {
+		plan := getMigrationPlan(details, homeDir, utils.PathExists)
+		plans = append(plans, plan)
+	}
+
+	showMigrationPlans(os.Stdout, plans)
+
+	if migrateFromFlags.dryRun {
+		return nil
+	}
+
+	if !rootFlags.assumeYes {
+		prompt := i18n.Sprintf("Create these containers on this Mac? [y/N]: ")
+		if !askForConfirmation(prompt) {
+			return nil
+		}
+	}
+
+	if bundle.config != nil {
+		installMigrationConfig(bundle.config)
+	}
+
+	var failed int
+
+	for _, plan := range plans {
+		if err := createMigrationContainer(plan); err != nil {
+			showWarning("failed to migrate container %s: %s", plan.container, err)
+			failed++
+		}
+	}
+
+	if failed != 0 {
+		return fmt.Errorf("failed to migrate %d of %d containers", failed, len(plans))
+	}
+
+	showMessage("Enter them with 'toolbox enter CONTAINER'")
+	return nil
+}
+
+func migrateFromHelp(cmd *cobra.Command, args []string) {
+	if utils.IsInsideContainer() {
+		if !utils.IsInsideToolboxContainer() {
+			fmt.Fprintf(os.Stderr, "Error: this is not a Toolbx container\n")
+			return
+		}
+
+		if _, err := utils.ForwardToHost(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return
+		}
+
+		return
+	}
+
+	if err := showManual("toolbox-migrate-from"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return
+	}
+}
+
+func createMigrationContainer(plan migrationPlan) error {
+	if exists, _ := podman.ContainerExists(plan.container); exists {
+		return fmt.Errorf("container %s already exists", plan.container)
+	}
+
+	var volumes []string
+	for _, mount := range plan.mounts {
+		volume := mount.source + ":" + mount.destination
+		if mount.readOnly {
+			volume += ":ro"
+		}
+
+		volumes = append(volumes, volume)
+	}
+
+	createFlags.volumes = volumes
+	createFlags.workspaceVolume = plan.workspaceVolume
+
+	err := createContainer(plan.container, plan.image, "", "", false)
+	return err
+}
+
+// getLinuxHomeDirectory finds the home directory on Linux from the arguments
+// of 'toolbox init-container', because the container has it mounted under
+// the same path.
+func getLinuxHomeDirectory(details podman.ContainerDetails) string {
+	createCommand := details.Config.CreateCommand
+
+	for i, arg := range createCommand {
+		if arg == "--home" && i+1 < len(createCommand) {
+			return createCommand[i+1]
+		}
+
+		if homeDir, found := strings.CutPrefix(arg, "--home="); found {
+			return homeDir
+		}
+	}
+
+	return ""
+}
+
+func getMigrationContainers(bundle migrationBundle, names []string) ([]podman.ContainerDetails, error) {
+	var containers []podman.ContainerDetails
+
+	for _, details := range bundle.containers {
+		if details.Config.Labels["com.github.containers.toolbox"] != "true" &&
+			details.Config.Labels["com.github.debarshiray.toolbox"] != "true" {
+			continue
+		}
+
+		if len(names) != 0 && !slices.Contains(names, details.Name) {
+			continue
+		}
+
+		containers = append(containers, details)
+	}
+
+	for _, name := range names {
+		found := slices.ContainsFunc(containers, func(details podman.ContainerDetails) bool {
+			return details.Name == name
+		})
+
+		if !found {
+			return nil, fmt.Errorf("no Toolbx container %s", name)
+		}
+	}
+
+	return containers, nil
+}
+
+// getMigrationPlan translates the mounts of a container on Linux into their
+// equivalents on this Mac.  Toolbx's own mounts are left to 'toolbox create',
+// and so is anything below the home directory, which is shared as a whole.
+// Removable media under /media, /mnt and /run/media are found under
+// /Volumes.
+func getMigrationPlan(details podman.ContainerDetails, homeDir string, pathExists func(string) bool) migrationPlan {
+	plan := migrationPlan{container: details.Name, image: details.ImageName}
+	linuxHomeDir := getLinuxHomeDirectory(details)
+
+	for _, mount := range details.Mounts {
+		if mount.Type == "volume" {
+			if mount.Destination == workspaceDirectory {
+				plan.workspaceVolume = true
+				continue
+			}
+
+			skipped := fmt.Sprintf("volume %s at %s: volumes are not copied", mount.Name, mount.Destination)
+			plan.skipped = append(plan.skipped, skipped)
+			continue
+		}
+
+		if mount.Type != "bind" || isLinuxToolbxMount(mount, linuxHomeDir) {
+			continue
+		}
+
+		source := translateLinuxPath(mount.Source, linuxHomeDir, homeDir)
+
+		destination := mount.Destination
+		if destination == mount.Source {
+			destination = source
+		} else {
+			destination = translateLinuxPath(destination, linuxHomeDir, homeDir)
+		}
+
+		if destination == source && strings.HasPrefix(source, homeDir+"/") {
+			continue
+		}
+
+		if !pathExists(source) {
+			skipped := fmt.Sprintf("%s at %s: %s doesn't exist on this Mac", mount.Source, mount.Destination, source)
+			plan.skipped = append(plan.skipped, skipped)
+			continue
+		}
+
+		migrationMount := migrationMount{destination: destination, readOnly: !mount.RW, source: source}
+		plan.mounts = append(plan.mounts, migrationMount)
+	}
+
+	return plan
+}
+
+func installMigrationConfig(config []byte) {
+	configPath, err := utils.GetUserConfigPath()
+	if err != nil {
+		showWarning("toolbox.conf from the bundle was not installed: %s", err)
+		return
+	}
+
+	if utils.PathExists(configPath) {
+		showWarning("toolbox.conf from the bundle was not installed, because %s already exists", configPath)
+		return
+	}
+
+	configDir := filepath.Dir(configPath)
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		logrus.Debugf("Creating directory %s failed: %s", configDir, err)
+		showWarning("toolbox.conf from the bundle was not installed: failed to create %s", configDir)
+		return
+	}
+
+	if err := os.WriteFile(configPath, config, 0644); err != nil {
+		logrus.Debugf("Writing %s failed: %s", configPath, err)
+		showWarning("toolbox.conf from the bundle was not installed: failed to write %s", configPath)
+		return
+	}
+
+	showMessage("Installed toolbox.conf from the bundle as %s", configPath)
+}
+
+func isLinuxToolbxMount(mount podman.ContainerMount, linuxHomeDir string) bool {
+	if slices.Contains(linuxToolbxMountDestinations, mount.Destination) {
+		return true
+	}
+
+	if linuxHomeDir != "" && mount.Source == linuxHomeDir {
+		return true
+	}
+
+	// The runtime directory, and the sockets of D-Bus, Avahi, Kerberos and
+	// PC/SC, but not removable media.
+	if strings.HasPrefix(mount.Source, "/run/") && !strings.HasPrefix(mount.Source, "/run/media/") {
+		return true
+	}
+
+	if strings.HasPrefix(mount.Source, "/dev/") {
+		return true
+	}
+
+	return false
+}
+
+// readMigrationBundle reads the output of 'podman inspect' on Linux, either
+// as a file of its own, or as containers.json in a directory or a tarball,
+// next to an optional toolbox.conf.
+func readMigrationBundle(path string) (migrationBundle, error) {
+	var bundle migrationBundle
+
+	fileInfo, err := os.Stat(path)
+	if err != nil {
+		return bundle, fmt.Errorf("failed to read bundle %s: %w", path, err)
+	}
+
+	files := make(map[string][]byte)
+
+	if fileInfo.IsDir() {
+		for _, name := range []string{"containers.json", "toolbox.conf"} {
+			data, err := readMigrationBundleFile(filepath.Join(path, name))
+			if err != nil {
+				if errors.Is(err, os.ErrNotExist) {
+					continue
+				}
+
+				return bundle, err
+			}
+
+			files[name] = data
+		}
+	} else {
+		data, err := readMigrationBundleFile(path)
+		if err != nil {
+			return bundle, err
+		}
+
+		if trimmed := bytes.TrimSpace(data); len(trimmed) != 0 && trimmed[0] == '[' {
+			files["containers.json"] = data
+		} else {
+			files, err = readMigrationBundleTarball(data)
+			if err != nil {
+				return bundle, fmt.Errorf("failed to read bundle %s: %w", path, err)
+			}
+		}
+	}
+
+	data, ok := files["containers.json"]
+	if !ok {
+		return bundle, fmt.Errorf("bundle %s has no containers.json", path)
+	}
+
+	if err := json.Unmarshal(data, &bundle.containers); err != nil {
+		return bundle, fmt.Errorf("failed to parse containers.json in %s: %w", path, err)
+	}
+
+	bundle.config = files["toolbox.conf"]
+	return bundle, nil
+}
+
+func readMigrationBundleFile(path string) ([]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, migrationBundleMaxFileSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	if len(data) > migrationBundleMaxFileSize {
+		return nil, fmt.Errorf("%s is too big", path)
+	}
+
+	return data, nil
+}
+
+func readMigrationBundleTarball(data []byte) (map[string][]byte, error) {
+	var reader io.Reader = bytes.NewReader(data)
+
+	if gzipReader, err := gzip.NewReader(reader); err == nil {
+		defer gzipReader.Close()
+		reader = gzipReader
+	} else {
+		reader = bytes.NewReader(data)
+	}
+
+	files := make(map[string][]byte)
+	tarReader := tar.NewReader(reader)
+
+	for {
+		header, err := tarReader.Next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			return nil, err
+		}
+
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		name := filepath.Base(header.Name)
+		if name != "containers.json" && name != "toolbox.conf" {
+			continue
+		}
+
+		if header.Size > migrationBundleMaxFileSize {
+			return nil, fmt.Errorf("%s is too big", header.Name)
+		}
+
+		fileData, err := io.ReadAll(tarReader)
+		if err != nil {
+			return nil, err
+		}
+
+		files[name] = fileData
+	}
+
+	return files, nil
+}
+
+func showMigrationPlans(w io.Writer, plans []migrationPlan) {
+	for i, plan := range plans {
+		if i != 0 {
+			fmt.Fprintln(w)
+		}
+
+		fmt.Fprintf(w, "Container %s from image %s\n", plan.container, plan.image)
+
+		if plan.workspaceVolume {
+			fmt.Fprintf(w, "  new volume at %s\n", workspaceDirectory)
+		}
+
+		for _, mount := range plan.mounts {
+			readOnly := ""
+			if mount.readOnly {
+				readOnly = " (read-only)"
+			}
+
+			fmt.Fprintf(w, "  %s at %s%s\n", mount.source, mount.destination, readOnly)
+		}
+
+		for _, skipped := range plan.skipped {
+			fmt.Fprintf(w, "  skipped %s\n", skipped)
+		}
+	}
+}
+
+// translateLinuxPath maps a path on Linux to where the same thing usually is
+// on a Mac.  Paths without an equivalent are returned unchanged.
+func translateLinuxPath(path, linuxHomeDir, homeDir string) string {
+	if linuxHomeDir != "" {
+		if path == linuxHomeDir {
+			return homeDir
+		}
+
+		if rest, found := strings.CutPrefix(path, linuxHomeDir+"/"); found {
+			return filepath.Join(homeDir, rest)
+		}
+	}
+
+	if rest, found := strings.CutPrefix(path, "/run/media/"); found {
+		// /run/media/USER/LABEL
+		if _, label, found := strings.Cut(rest, "/"); found {
+			return filepath.Join("/Volumes", label)
+		}
+
+		return path
+	}
+
+	for _, prefix := range []string{"/media/", "/mnt/"} {
+		if rest, found := strings.CutPrefix(path, prefix); found {
+			return filepath.Join("/Volumes", rest)
+		}
+	}
+
+	return path
+}
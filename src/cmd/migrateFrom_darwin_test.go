@@ -0,0 +1,177 @@
+//go:build darwin
+
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/containers/toolbox/pkg/podman"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const migrationContainersJSON = `[
+  {
+    "Config": {
+      "CreateCommand": ["podman", "create", "--name", "fedora-toolbox-40",
+        "registry.fedoraproject.org/fedora-toolbox:40",
+        "toolbox", "init-container", "--home", "/home/me", "--shell", "/bin/bash"],
+      "Labels": {"com.github.containers.toolbox": "true"}
+    },
+    "ImageName": "registry.fedoraproject.org/fedora-toolbox:40",
+    "Name": "fedora-toolbox-40",
+    "Mounts": [
+      {"Type": "bind", "Source": "/", "Destination": "/run/host", "RW": true},
+      {"Type": "bind", "Source": "/home/me", "Destination": "/home/me", "RW": true},
+      {"Type": "bind", "Source": "/run/user/1000", "Destination": "/run/user/1000", "RW": true},
+      {"Type": "bind", "Source": "/home/me/src", "Destination": "/src", "RW": true},
+      {"Type": "bind", "Source": "/run/media/me/backup", "Destination": "/backup", "RW": false},
+      {"Type": "bind", "Source": "/srv/data", "Destination": "/srv/data", "RW": true},
+      {"Type": "volume", "Name": "fedora-toolbox-40-workspace", "Destination": "/workspace", "RW": true},
+      {"Type": "volume", "Name": "cache", "Destination": "/var/cache/dnf", "RW": true}
+    ]
+  },
+  {
+    "Config": {"Labels": {}},
+    "ImageName": "docker.io/library/postgres:16",
+    "Name": "postgres"
+  }
+]`
+
+func TestGetMigrationPlan(t *testing.T) {
+	bundle := migrationBundle{}
+	err := json.Unmarshal([]byte(migrationContainersJSON), &bundle.containers)
+	require.NoError(t, err)
+
+	containers, err := getMigrationContainers(bundle, nil)
+	require.NoError(t, err)
+	require.Len(t, containers, 1)
+
+	pathExists := func(path string) bool {
+		return path == "/Users/me/src" || path == "/Volumes/backup"
+	}
+
+	plan := getMigrationPlan(containers[0], "/Users/me", pathExists)
+	assert.Equal(t, "fedora-toolbox-40", plan.container)
+	assert.Equal(t, "registry.fedoraproject.org/fedora-toolbox:40", plan.image)
+	assert.True(t, plan.workspaceVolume)
+
+	assert.Equal(t, []migrationMount{
+		{destination: "/src", source: "/Users/me/src"},
+		{destination: "/backup", readOnly: true, source: "/Volumes/backup"},
+	}, plan.mounts)
+
+	assert.Equal(t, []string{
+		"/srv/data at /srv/data: /srv/data doesn't exist on this Mac",
+		"volume cache at /var/cache/dnf: volumes are not copied",
+	}, plan.skipped)
+}
+
+func TestGetMigrationContainers(t *testing.T) {
+	bundle := migrationBundle{containers: []podman.ContainerDetails{{Name: "fedora-toolbox-40"}}}
+	bundle.containers[0].Config.Labels = map[string]string{"com.github.containers.toolbox": "true"}
+
+	_, err := getMigrationContainers(bundle, []string{"postgres"})
+	assert.EqualError(t, err, "no Toolbx container postgres")
+
+	containers, err := getMigrationContainers(bundle, []string{"fedora-toolbox-40"})
+	assert.NoError(t, err)
+	assert.Len(t, containers, 1)
+}
+
+func TestReadMigrationBundle(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("JSON file", func(t *testing.T) {
+		path := filepath.Join(dir, "containers.json")
+		err := os.WriteFile(path, []byte(migrationContainersJSON), 0644)
+		require.NoError(t, err)
+
+		bundle, err := readMigrationBundle(path)
+		require.NoError(t, err)
+		assert.Len(t, bundle.containers, 2)
+		assert.Nil(t, bundle.config)
+	})
+
+	t.Run("tarball", func(t *testing.T) {
+		var buffer bytes.Buffer
+		gzipWriter := gzip.NewWriter(&buffer)
+		tarWriter := tar.NewWriter(gzipWriter)
+
+		files := []struct {
+			name string
+			data string
+		}{
+			{"./containers.json", migrationContainersJSON},
+			{"./toolbox.conf", "[general]\n"},
+		}
+
+		for _, file := range files {
+			header := &tar.Header{Mode: 0644, Name: file.name, Size: int64(len(file.data)), Typeflag: tar.TypeReg}
+			require.NoError(t, tarWriter.WriteHeader(header))
+			_, err := tarWriter.Write([]byte(file.data))
+			require.NoError(t, err)
+		}
+
+		require.NoError(t, tarWriter.Close())
+		require.NoError(t, gzipWriter.Close())
+
+		path := filepath.Join(dir, "bundle.tar.gz")
+		err := os.WriteFile(path, buffer.Bytes(), 0644)
+		require.NoError(t, err)
+
+		bundle, err := readMigrationBundle(path)
+		require.NoError(t, err)
+		assert.Len(t, bundle.containers, 2)
+		assert.Equal(t, "[general]\n", string(bundle.config))
+	})
+
+	t.Run("directory without containers.json", func(t *testing.T) {
+		_, err := readMigrationBundle(t.TempDir())
+		assert.ErrorContains(t, err, "has no containers.json")
+	})
+}
+
+func TestTranslateLinuxPath(t *testing.T) {
+	testCases := []struct {
+		path     string
+		expected string
+	}{
+		{"/home/me", "/Users/me"},
+		{"/home/me/src/project", "/Users/me/src/project"},
+		{"/home/meme", "/home/meme"},
+		{"/run/media/me/backup", "/Volumes/backup"},
+		{"/media/usb", "/Volumes/usb"},
+		{"/mnt/nas/share", "/Volumes/nas/share"},
+		{"/srv/data", "/srv/data"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.path, func(t *testing.T) {
+			path := translateLinuxPath(tc.path, "/home/me", "/Users/me")
+			assert.Equal(t, tc.expected, path)
+		})
+	}
+}
@@ -20,6 +20,7 @@ package cmd
 
 import (
 	"errors"
+	"fmt"
 	"os"
 	"strings"
 
@@ -41,6 +42,33 @@ func migrate(cmd *cobra.Command, args []string) error {
 		logrus.Debugf("Migration not needed: command %s doesn't need it", cmdName)
 		return nil
 	}
+
+	if parent := cmd.Parent(); parent != nil && parent.Name() == completionCmd.Name() {
+		logrus.Debugf("Migration not needed: command %s %s doesn't need it", parent.Name(), cmd.Name())
+		return nil
+	}
+
+	// 'toolbox info' and 'toolbox debug-report' report a missing Podman
+	// instead of failing.
+	if cmd == infoCmd || cmd == debugReportCmd {
+		logrus.Debugf("Migration not needed: command %s doesn't need it", cmd.Name())
+		return nil
+	}
+
+	// 'toolbox reset' works while the Podman machine is wedged, and 'toolbox
+	// setup' installs Podman, if it's missing.
+	if cmd == resetCmd || cmd == setupCmd {
+		logrus.Debugf("Migration not needed: command %s doesn't need it", cmd.Name())
+		return nil
+	}
+
+	// 'toolbox machine' works while the Podman machine is stopped.
+	for parent := cmd; parent != nil; parent = parent.Parent() {
+		if parent == machineCmd {
+			logrus.Debugf("Migration not needed: command %s doesn't need it", cmd.CommandPath())
+			return nil
+		}
+	}
 	
 	// On macOS, Podman migration is typically less critical since containers
 	// run in a VM with different storage backends. We'll try a simpler approach.
@@ -102,6 +130,10 @@ func migrate(cmd *cobra.Command, args []string) error {
 	logrus.Debug("Attempting Podman system migrate (may skip on macOS)")
 	if err = podman.SystemMigrate(""); err != nil {
 		logrus.Debugf("Podman system migrate failed (expected on some macOS setups): %s", err)
+		if rootFlags.strict {
+			return fmt.Errorf("failed to migrate containers to Podman %s", podmanVersion)
+		}
+
 		// Don't return error - just log it and continue
 		logrus.Debug("Continuing without system migration (common on macOS)")
 	} else {
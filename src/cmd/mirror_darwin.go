@@ -0,0 +1,220 @@
+//go:build darwin
+
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/containers/toolbox/pkg/podman"
+	"github.com/containers/toolbox/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+// registryMirror is REGISTRY=MIRROR in 'mirrors' in the registries section of
+// the configuration.  Images below the registry, which can include a
+// namespace, are pulled from below the mirror first.
+type registryMirror struct {
+	mirror   string
+	registry string
+}
+
+// getMirrorImages returns the names to pull the image from before giving up
+// on the mirrors, ie., for each mirror whose registry matches it, longest
+// first, and in the order of the configuration.  Images referred to by
+// digest aren't pulled from mirrors, because they can't be tagged with their
+// original name afterwards.
+func getMirrorImages(image string, mirrors []registryMirror) []string {
+	if !utils.ImageReferenceHasDomain(image) || strings.Contains(image, "@") {
+		return nil
+	}
+
+	var matches []registryMirror
+
+	for _, mirror := range mirrors {
+		if strings.HasPrefix(image, mirror.registry+"/") {
+			matches = append(matches, mirror)
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return len(matches[i].registry) > len(matches[j].registry)
+	})
+
+	var mirrorImages []string
+
+	for _, mirror := range matches {
+		mirrorImage := mirror.mirror + strings.TrimPrefix(image, mirror.registry)
+		mirrorImages = append(mirrorImages, mirrorImage)
+	}
+
+	return mirrorImages
+}
+
+func getRegistryAliases() (map[string]string, error) {
+	specs := viper.GetStringSlice("registries.aliases")
+	if len(specs) == 0 {
+		return nil, nil
+	}
+
+	aliases := make(map[string]string)
+
+	for _, spec := range specs {
+		name, image, err := parseRegistryAlias(spec)
+		if err != nil {
+			return nil, fmt.Errorf("invalid alias %s in the configuration: %w", spec, err)
+		}
+
+		aliases[name] = image
+	}
+
+	return aliases, nil
+}
+
+func getRegistryMirrors() ([]registryMirror, error) {
+	specs := viper.GetStringSlice("registries.mirrors")
+
+	var mirrors []registryMirror
+
+	for _, spec := range specs {
+		mirror, err := parseRegistryMirror(spec)
+		if err != nil {
+			return nil, fmt.Errorf("invalid mirror %s in the configuration: %w", spec, err)
+		}
+
+		mirrors = append(mirrors, mirror)
+	}
+
+	return mirrors, nil
+}
+
+// parseRegistryAlias parses NAME=IMAGE, where NAME is a short name without a
+// tag, and IMAGE is a fully qualified image without one.
+func parseRegistryAlias(spec string) (string, string, error) {
+	name, image, ok := strings.Cut(spec, "=")
+	if !ok || name == "" || image == "" {
+		return "", "", errors.New("aliases must be NAME=IMAGE")
+	}
+
+	if utils.ImageReferenceHasDomain(name) || strings.ContainsAny(name, ":@") {
+		return "", "", errors.New("the name must not have a registry or a tag")
+	}
+
+	if !utils.ImageReferenceHasDomain(image) {
+		return "", "", errors.New("the image must have a registry")
+	}
+
+	if utils.ImageReferenceGetTag(image) != "" || strings.Contains(image, "@") {
+		return "", "", errors.New("the image must not have a tag")
+	}
+
+	return name, image, nil
+}
+
+// parseRegistryMirror parses REGISTRY=MIRROR, where both are a registry,
+// optionally followed by a namespace.
+func parseRegistryMirror(spec string) (registryMirror, error) {
+	registry, mirror, ok := strings.Cut(spec, "=")
+	registry = strings.TrimSuffix(registry, "/")
+	mirror = strings.TrimSuffix(mirror, "/")
+
+	if !ok || registry == "" || mirror == "" {
+		return registryMirror{}, errors.New("mirrors must be REGISTRY=MIRROR")
+	}
+
+	for _, location := range []string{registry, mirror} {
+		domain, namespace, _ := strings.Cut(location, "/")
+		if !utils.ImageReferenceHasDomain(domain + "/") {
+			return registryMirror{}, fmt.Errorf("%s is not a registry", location)
+		}
+
+		if strings.ContainsAny(namespace, ":@") {
+			return registryMirror{}, fmt.Errorf("%s must not have a tag", location)
+		}
+	}
+
+	return registryMirror{mirror: mirror, registry: registry}, nil
+}
+
+// pullImageFromMirror pulls the image from a mirror, and gives it the
+// original name, so that it can't be told apart from one pulled from the
+// registry itself.
+func pullImageFromMirror(image, mirrorImage, authFile string) error {
+	logrus.Debugf("Pulling image %s from mirror %s", image, mirrorImage)
+
+	if err := podman.Pull(mirrorImage, authFile); err != nil {
+		return err
+	}
+
+	if err := podman.Tag(mirrorImage, image); err != nil {
+		return fmt.Errorf("failed to tag image %s as %s: %w", mirrorImage, image, err)
+	}
+
+	if err := podman.Untag(mirrorImage, mirrorImage); err != nil {
+		logrus.Debugf("Untagging image %s failed: %s", mirrorImage, err)
+	}
+
+	return nil
+}
+
+// resolveImageAlias turns a short name into a fully qualified image through
+// 'aliases' in the registries section of the configuration.  Without an
+// alias, the images of the supported distros are resolved like on Linux,
+// because the Podman machine doesn't know the registries of all of them, eg.,
+// quay.io for ubuntu-toolbox, and only fully qualified images can be pulled
+// from a mirror.
+func resolveImageAlias(image, release string) (string, error) {
+	if utils.ImageReferenceHasDomain(image) {
+		return image, nil
+	}
+
+	aliases, err := getRegistryAliases()
+	if err != nil {
+		return "", err
+	}
+
+	name, digest, hasDigest := strings.Cut(image, "@")
+	tag := utils.ImageReferenceGetTag(name)
+	name = strings.TrimSuffix(name, ":"+tag)
+
+	if alias, ok := aliases[name]; ok {
+		imageFull := alias
+		if hasDigest {
+			imageFull += "@" + digest
+		} else if tag != "" {
+			imageFull += ":" + tag
+		}
+
+		logrus.Debugf("Resolved image %s to %s through an alias", image, imageFull)
+		return imageFull, nil
+	}
+
+	if release == "" || hasDigest || (tag != "" && tag != release) {
+		return image, nil
+	}
+
+	if imageFull, err := utils.GetFullyQualifiedImageFromDistros(image, release); err == nil {
+		return imageFull, nil
+	}
+
+	return image, nil
+}
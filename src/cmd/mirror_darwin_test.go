@@ -0,0 +1,151 @@
+//go:build darwin
+
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetMirrorImages(t *testing.T) {
+	mirrors := []registryMirror{
+		{mirror: "mirror.example.com/fedora", registry: "registry.fedoraproject.org"},
+		{mirror: "mirror.example.com/quay", registry: "quay.io"},
+		{mirror: "mirror.example.com/toolbx", registry: "quay.io/toolbx"},
+		{mirror: "backup.example.com:5000/quay", registry: "quay.io"},
+	}
+
+	testCases := []struct {
+		image    string
+		expected []string
+	}{
+		{
+			"registry.fedoraproject.org/fedora-toolbox:42",
+			[]string{"mirror.example.com/fedora/fedora-toolbox:42"},
+		},
+		{
+			"quay.io/toolbx/ubuntu-toolbox:24.04",
+			[]string{
+				"mirror.example.com/toolbx/ubuntu-toolbox:24.04",
+				"mirror.example.com/quay/toolbx/ubuntu-toolbox:24.04",
+				"backup.example.com:5000/quay/toolbx/ubuntu-toolbox:24.04",
+			},
+		},
+		{
+			"quay.io/toolbx-images/debian-toolbox:12",
+			[]string{
+				"mirror.example.com/quay/toolbx-images/debian-toolbox:12",
+				"backup.example.com:5000/quay/toolbx-images/debian-toolbox:12",
+			},
+		},
+		{
+			"docker.io/library/alpine:latest",
+			nil,
+		},
+		{
+			"fedora-toolbox:42",
+			nil,
+		},
+		{
+			"registry.fedoraproject.org/fedora-toolbox@sha256:0123456789abcdef",
+			nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.image, func(t *testing.T) {
+			mirrorImages := getMirrorImages(tc.image, mirrors)
+			assert.Equal(t, tc.expected, mirrorImages)
+		})
+	}
+}
+
+func TestParseRegistryAlias(t *testing.T) {
+	name, image, err := parseRegistryAlias("fedora-toolbox=registry.fedoraproject.org/fedora-toolbox")
+	require.NoError(t, err)
+	assert.Equal(t, "fedora-toolbox", name)
+	assert.Equal(t, "registry.fedoraproject.org/fedora-toolbox", image)
+
+	name, image, err = parseRegistryAlias("toolbx/dev=registry.example.com:5000/toolbx/dev")
+	require.NoError(t, err)
+	assert.Equal(t, "toolbx/dev", name)
+	assert.Equal(t, "registry.example.com:5000/toolbx/dev", image)
+
+	invalid := []string{
+		"",
+		"fedora-toolbox",
+		"fedora-toolbox=",
+		"=registry.fedoraproject.org/fedora-toolbox",
+		"fedora-toolbox:42=registry.fedoraproject.org/fedora-toolbox",
+		"quay.io/fedora-toolbox=registry.fedoraproject.org/fedora-toolbox",
+		"fedora-toolbox=fedora/fedora-toolbox",
+		"fedora-toolbox=registry.fedoraproject.org/fedora-toolbox:42",
+	}
+
+	for _, spec := range invalid {
+		t.Run(spec, func(t *testing.T) {
+			_, _, err := parseRegistryAlias(spec)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestParseRegistryMirror(t *testing.T) {
+	testCases := []struct {
+		spec     string
+		expected registryMirror
+	}{
+		{
+			"registry.fedoraproject.org=mirror.example.com/fedora",
+			registryMirror{mirror: "mirror.example.com/fedora", registry: "registry.fedoraproject.org"},
+		},
+		{
+			"quay.io/toolbx/=localhost:5000/",
+			registryMirror{mirror: "localhost:5000", registry: "quay.io/toolbx"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.spec, func(t *testing.T) {
+			mirror, err := parseRegistryMirror(tc.spec)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, mirror)
+		})
+	}
+
+	invalid := []string{
+		"",
+		"quay.io",
+		"quay.io=",
+		"=mirror.example.com",
+		"toolbx=mirror.example.com",
+		"quay.io=mirror",
+		"quay.io/toolbx:latest=mirror.example.com",
+		"quay.io=mirror.example.com/quay@sha256",
+	}
+
+	for _, spec := range invalid {
+		t.Run(spec, func(t *testing.T) {
+			_, err := parseRegistryMirror(spec)
+			assert.Error(t, err)
+		})
+	}
+}
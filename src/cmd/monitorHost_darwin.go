@@ -0,0 +1,756 @@
+//go:build darwin
+
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/containers/toolbox/pkg/podman"
+	"github.com/containers/toolbox/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+type hostConfiguration struct {
+	hosts        []byte
+	kube         bool
+	kubeconfig   []byte
+	proxyEnviron []string
+	resolvConf   []byte
+	timeZone     string
+}
+
+const (
+	containerStopTimeout = 10
+
+	hostHostsMarker = "# toolbox host"
+
+	// hostSleepMin is how much longer than the interval between two polls
+	// the Mac needs to have been asleep, before it's treated as waking up.
+	hostSleepMin = 30 * time.Second
+
+	monitorHostIdleTimeout = time.Minute
+	monitorHostInterval    = 5 * time.Second
+)
+
+var monitorHostCmd = &cobra.Command{
+	Use:    "monitor-host",
+	Short:  "Propagate changes to the host's configuration into Toolbx containers (macOS version)",
+	Hidden: true,
+	RunE:   monitorHost,
+}
+
+func init() {
+	rootCmd.AddCommand(monitorHostCmd)
+}
+
+// monitorHost polls the host's name servers, time zone and proxy settings,
+// and pushes them into the running Toolbx containers whenever they change, or
+// when a container starts running.  There is no inotify(7) for the files that
+// configd(8) manages, and FSEvents needs cgo, so polling is used.
+//
+// It exits once no Toolbx container has been running for a while.
+//
+// If 'auto-stop' is set in the general section of the configuration, then it
+// also stops containers that nobody has entered or run a command in for that
+// long, because they keep the Podman machine from giving memory back to the
+// Mac.
+//
+// If 'machine-auto-stop' is set in the general section of the configuration,
+// then it keeps running after that, and stops the Podman machine once no
+// Toolbx session and no other container has used it for that long.  The next
+// toolbox command starts the machine again.
+//
+// When the Mac wakes up from sleep, the clock of the Podman machine is
+// corrected right away, and the host's configuration is pushed again, because
+// the network has often changed in the meantime.  That's all that is done
+// for sleep.  There is no notification before the Mac goes to sleep without
+// cgo, which the macOS builds can't use, because they are cross-compiled, so
+// the containers aren't paused or checkpointed for it.
+//
+// The socket of the host's SSH agent that is chosen by 'ssh-agent' in the
+// host section of the configuration is forwarded into the Podman machine
+// while Toolbx containers are running.
+//
+// If 'battery-cpus' is set in the machine section of the configuration, then
+// CPUs of the Podman machine are taken offline while the Mac is on battery
+// power.
+//
+// The configuration files are read again on SIGHUP, or when they change, so
+// that the options in the host section take effect without restarting it.
+func monitorHost(cmd *cobra.Command, args []string) error {
+	if utils.IsInsideContainer() {
+		return errors.New("monitor-host is not supported inside a container")
+	}
+
+	lock, err := getHostMonitorLock()
+	if err != nil {
+		return err
+	}
+
+	lockFile, err := utils.Flock(lock, syscall.LOCK_EX|syscall.LOCK_NB)
+	if err != nil {
+		logrus.Debugf("Monitoring the host: %s", err)
+		return nil
+	}
+
+	defer lockFile.Close()
+
+	pushed := make(map[string]string)
+	containersIdleSince := make(map[string]time.Time)
+	idleSince := time.Now()
+	machineIdleSince := time.Now()
+	configStamp := getConfigurationStamp()
+	lastPoll := time.Now()
+	throttled := false
+
+	hangUp := make(chan os.Signal, 1)
+	signal.Notify(hangUp, syscall.SIGHUP)
+	defer signal.Stop(hangUp)
+
+	ticker := time.NewTicker(monitorHostInterval)
+	defer ticker.Stop()
+
+	var tunnel sshAgentTunnel
+	defer tunnel.stop()
+
+	for {
+		now := time.Now()
+		if slept := getHostSleepDuration(lastPoll, now); slept > hostSleepMin {
+			logrus.Debugf("Monitoring the host: woke up from sleep after %s", slept.Round(time.Second))
+
+			if err := syncMachineClock(); err != nil {
+				logrus.Debugf("Monitoring the host: %s", err)
+			}
+
+			clear(pushed)
+		}
+
+		lastPoll = now
+		throttled = applyPowerPolicy(throttled)
+
+		config := getHostConfiguration()
+		digest := config.digest()
+
+		containers, err := getContainers()
+		if err != nil {
+			logrus.Debugf("Monitoring the host: %s", err)
+			containers = nil
+			machineIdleSince = time.Now()
+		}
+
+		running := make(map[string]struct{})
+
+		for _, container := range containers {
+			if container.Status() != "running" {
+				continue
+			}
+
+			id := container.ID()
+			running[id] = struct{}{}
+			name := container.Name()
+
+			if stopIdleContainer(container, containersIdleSince) {
+				continue
+			}
+
+			if pushed[id] == digest {
+				continue
+			}
+
+			logrus.Debugf("Pushing the host's configuration into container %s", name)
+
+			if err := pushHostConfiguration(container, config); err != nil {
+				logrus.Debugf("Pushing the host's configuration into container %s failed: %s", name, err)
+				continue
+			}
+
+			pushed[id] = digest
+		}
+
+		if len(running) != 0 {
+			tunnel.update(getHostSSHAgent())
+		} else {
+			tunnel.stop()
+		}
+
+		for id := range pushed {
+			if _, ok := running[id]; !ok {
+				delete(pushed, id)
+			}
+		}
+
+		for id := range containersIdleSince {
+			if _, ok := running[id]; !ok {
+				delete(containersIdleSince, id)
+			}
+		}
+
+		if err == nil && stopIdleMachine(running, &machineIdleSince) {
+			logrus.Debug("Monitoring the host: stopped the Podman machine, exiting")
+			return nil
+		}
+
+		if len(running) != 0 {
+			idleSince = time.Now()
+		} else if time.Since(idleSince) > monitorHostIdleTimeout && getMachineAutoStopTimeout() == 0 {
+			logrus.Debug("Monitoring the host: no running containers, exiting")
+
+			if throttled {
+				restoreMachineCPUs()
+			}
+
+			return nil
+		}
+
+		reload := false
+
+		select {
+		case <-hangUp:
+			logrus.Debug("Monitoring the host: received SIGHUP")
+			reload = true
+		case <-ticker.C:
+		}
+
+		if stamp := getConfigurationStamp(); stamp != configStamp {
+			configStamp = stamp
+			reload = true
+		}
+
+		if reload {
+			logrus.Debug("Monitoring the host: reloading the configuration")
+
+			if err := utils.ReloadConfiguration(); err != nil {
+				logrus.Debugf("Reloading the configuration failed: %s", err)
+				continue
+			}
+
+			clear(pushed)
+		}
+	}
+}
+
+// getConfigurationStamp returns a string that changes whenever one of the
+// configuration files is created, modified or removed.
+func getConfigurationStamp() string {
+	configFiles, err := utils.GetConfigurationFiles()
+	if err != nil {
+		logrus.Debugf("Monitoring the host: %s", err)
+		return ""
+	}
+
+	var stamp strings.Builder
+
+	for _, configFile := range configFiles {
+		fileInfo, err := os.Stat(configFile)
+		if err != nil {
+			fmt.Fprintf(&stamp, "%s:-\n", configFile)
+			continue
+		}
+
+		fmt.Fprintf(&stamp, "%s:%d:%d\n", configFile, fileInfo.ModTime().UnixNano(), fileInfo.Size())
+	}
+
+	return stamp.String()
+}
+
+// getAutoStopTimeout returns how long a container may go without sessions
+// before it is stopped, or 0 if containers are never stopped.
+func getAutoStopTimeout() time.Duration {
+	autoStop := getDurationOption("auto-stop")
+	return autoStop
+}
+
+// getDurationOption returns the duration set for an option in the general
+// section of the configuration, or 0 if it's unset or invalid.
+func getDurationOption(option string) time.Duration {
+	key := "general." + option
+	if !viper.IsSet(key) {
+		return 0
+	}
+
+	durationString := viper.GetString(key)
+	duration, err := time.ParseDuration(durationString)
+	if err != nil || duration < 0 {
+		logrus.Debugf("Monitoring the host: invalid %s %s", option, durationString)
+		return 0
+	}
+
+	return duration
+}
+
+// getContainerSessionLock returns the lock file that every 'toolbox enter' and
+// 'toolbox run' session holds a shared lock on, for as long as it lasts.
+func getContainerSessionLock(id string) (string, error) {
+	toolboxRuntimeDirectory, err := utils.GetRuntimeDirectory(currentUser)
+	if err != nil {
+		return "", err
+	}
+
+	sessionsDirectory := filepath.Join(toolboxRuntimeDirectory, "sessions")
+	if err := os.MkdirAll(sessionsDirectory, 0700); err != nil {
+		return "", fmt.Errorf("failed to create directory %s: %w", sessionsDirectory, err)
+	}
+
+	lock := filepath.Join(sessionsDirectory, id+".lock")
+	return lock, nil
+}
+
+func getHostConfiguration() hostConfiguration {
+	var config hostConfiguration
+	var err error
+
+	if isHostOptionEnabled("dns") {
+		config.resolvConf, err = utils.GetHostResolvConf()
+		if err != nil {
+			logrus.Debugf("Reading the host's resolv.conf failed: %s", err)
+		}
+	}
+
+	// Unlike the other settings, /etc/hosts is only mirrored if asked for,
+	// because its entries often point at servers running on the Mac.
+	if viper.GetBool("host.hosts") {
+		config.hosts, err = utils.GetHostHosts()
+		if err != nil {
+			logrus.Debugf("Reading the host's /etc/hosts failed: %s", err)
+		}
+	}
+
+	// The clusters in ~/.kube/config are only made available if asked for,
+	// because it holds credentials.
+	if viper.GetBool("host.kube") {
+		config.kube = true
+		config.kubeconfig, err = getHostKubeconfig()
+		if err != nil {
+			logrus.Debugf("Reading the host's kubeconfig failed: %s", err)
+		}
+	}
+
+	if isHostOptionEnabled("time-zone") {
+		config.timeZone, err = utils.GetHostTimeZone()
+		if err != nil {
+			logrus.Debugf("Reading the host's time zone failed: %s", err)
+		}
+	}
+
+	if isHostOptionEnabled("proxy") {
+		config.proxyEnviron, err = utils.GetHostProxyEnvironment()
+		if err != nil {
+			logrus.Debugf("Reading the host's proxy settings failed: %s", err)
+		}
+	}
+
+	return config
+}
+
+// getMachineAutoStopTimeout returns how long the Podman machine may go
+// without being used before it is stopped, or 0 if it's never stopped.  The
+// machine is shared in system mode, so it's left alone there.
+func getMachineAutoStopTimeout() time.Duration {
+	if systemMode {
+		return 0
+	}
+
+	machineAutoStop := getDurationOption("machine-auto-stop")
+	return machineAutoStop
+}
+
+// getMachineStoppedStamp returns the file that 'toolbox monitor-host' leaves
+// behind after stopping the Podman machine, so that only a machine that was
+// stopped by it gets started again.
+func getMachineStoppedStamp() (string, error) {
+	toolboxRuntimeDirectory, err := utils.GetRuntimeDirectory(currentUser)
+	if err != nil {
+		return "", err
+	}
+
+	stamp := filepath.Join(toolboxRuntimeDirectory, "machine-auto-stopped")
+	return stamp, nil
+}
+
+func getHostMonitorLock() (string, error) {
+	toolboxRuntimeDirectory, err := utils.GetRuntimeDirectory(currentUser)
+	if err != nil {
+		return "", err
+	}
+
+	lock := filepath.Join(toolboxRuntimeDirectory, "monitor-host.lock")
+	return lock, nil
+}
+
+// getHostSleepDuration returns how long the Mac was asleep between two
+// readings of the clock.  The monotonic clock of macOS stops during sleep,
+// while the wall clock doesn't.
+func getHostSleepDuration(since, now time.Time) time.Duration {
+	wall := now.Round(0).Sub(since.Round(0))
+	monotonic := now.Sub(since)
+
+	slept := wall - monotonic
+	return slept
+}
+
+// isMachineInUse checks if any of the running Toolbx containers has a session,
+// or if any container that isn't a Toolbx container is running.
+func isMachineInUse(running map[string]struct{}) bool {
+	for id := range running {
+		if hasContainerSessions(id) {
+			return true
+		}
+	}
+
+	containers, err := podman.GetContainers("--filter", "status=running")
+	if err != nil {
+		logrus.Debugf("Monitoring the host: failed to get running containers: %s", err)
+		return true
+	}
+
+	for containers.Next() {
+		if container := containers.Get(); !container.IsToolbx() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isHostOptionEnabled returns whether a setting of the host should be
+// propagated into the containers, according to the host section of the
+// configuration.  Everything is propagated by default.
+func isHostOptionEnabled(option string) bool {
+	key := "host." + option
+	if !viper.IsSet(key) {
+		return true
+	}
+
+	enabled := viper.GetBool(key)
+	return enabled
+}
+
+func pushHostConfiguration(containerObj podman.Container, config hostConfiguration) error {
+	container := containerObj.Name()
+
+	if !hasCustomDNS(containerObj) {
+		if err := pushResolvConf(container, config.resolvConf); err != nil {
+			return fmt.Errorf("failed to update /etc/resolv.conf: %w", err)
+		}
+	}
+
+	if config.hosts != nil {
+		script := "{ grep -v ' " + hostHostsMarker + "$' /etc/hosts; sed 's/$/ " + hostHostsMarker + "/'; }" +
+			" >/etc/hosts.toolbox" +
+			" && cat /etc/hosts.toolbox >/etc/hosts" +
+			" && rm -f /etc/hosts.toolbox"
+
+		hostsReader := bytes.NewReader(config.hosts)
+		if err := podman.ExecAsRoot(container, hostsReader, "sh", "-c", script); err != nil {
+			return fmt.Errorf("failed to update /etc/hosts: %w", err)
+		}
+	}
+
+	if err := pushKubeconfig(container, config.kube, config.kubeconfig); err != nil {
+		return fmt.Errorf("failed to update %s: %w", kubeconfigInContainer, err)
+	}
+
+	if config.timeZone != "" {
+		const script = `test -e "/usr/share/zoneinfo/$1" || exit 0
+ln -sf "/usr/share/zoneinfo/$1" /etc/localtime
+echo "$1" >/etc/timezone`
+
+		if err := podman.ExecAsRoot(container, nil, "sh", "-c", script, "sh", config.timeZone); err != nil {
+			return fmt.Errorf("failed to update /etc/localtime: %w", err)
+		}
+	}
+
+	var proxySh strings.Builder
+	for _, env := range config.proxyEnviron {
+		key, value, _ := strings.Cut(env, "=")
+		value = strings.ReplaceAll(value, "'", `'\''`)
+		fmt.Fprintf(&proxySh, "export %s='%s'\n", key, value)
+	}
+
+	if err := writeFileInContainer(container, "/etc/profile.d/toolbox-proxy.sh", []byte(proxySh.String())); err != nil {
+		return fmt.Errorf("failed to update /etc/profile.d/toolbox-proxy.sh: %w", err)
+	}
+
+	return nil
+}
+
+// startAutoStoppedMachine starts the Podman machine again, if 'toolbox
+// monitor-host' stopped it for being idle, so that auto-stopping it is
+// transparent.  A machine that was stopped by hand is left alone.
+func startAutoStoppedMachine(cmd *cobra.Command) error {
+	switch cmd.Name() {
+	case cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd, infoCmd.Name(), monitorHostCmd.Name():
+		return nil
+	}
+
+	stamp, err := getMachineStoppedStamp()
+	if err != nil {
+		logrus.Debugf("Starting the Podman machine: %s", err)
+		return nil
+	}
+
+	if err := os.Remove(stamp); err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			logrus.Debugf("Removing %s failed: %s", stamp, err)
+		}
+
+		return nil
+	}
+
+	if state, err := podman.MachineState(); err == nil && state == "running" {
+		return nil
+	}
+
+	logrus.Debug("Starting the Podman machine stopped by 'toolbox monitor-host'")
+
+	s := showSpinner("Starting the Podman machine")
+	err = podman.MachineStart()
+	stopSpinner(s)
+
+	if err != nil {
+		logrus.Debugf("Starting the Podman machine failed: %s", err)
+		return errors.New("failed to start the Podman machine")
+	}
+
+	return nil
+}
+
+// startHostMonitor starts 'toolbox monitor-host' in the background, unless it
+// is already running.
+func startHostMonitor() {
+	lock, err := getHostMonitorLock()
+	if err != nil {
+		logrus.Debugf("Starting the host monitor: %s", err)
+		return
+	}
+
+	lockFile, err := utils.Flock(lock, syscall.LOCK_EX|syscall.LOCK_NB)
+	if err != nil {
+		logrus.Debugf("Starting the host monitor: skipping: %s", err)
+		return
+	}
+
+	lockFile.Close()
+
+	logrus.Debugf("Starting the host monitor")
+
+	monitorHostCmd := exec.Command(executable, "monitor-host")
+	monitorHostCmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+
+	if err := monitorHostCmd.Start(); err != nil {
+		logrus.Debugf("Starting the host monitor failed: %s", err)
+		return
+	}
+
+	monitorHostCmd.Process.Release()
+}
+
+// hasContainerSessions checks if any 'toolbox enter' or 'toolbox run' session
+// holds the lock of the container, or if any of the sessions that outlive
+// them is still running in it.  Commands run with 'podman exec' directly
+// aren't counted.
+func hasContainerSessions(id string) bool {
+	lock, err := getContainerSessionLock(id)
+	if err != nil {
+		logrus.Debugf("Monitoring the host: %s", err)
+		return true
+	}
+
+	if lockFile, err := os.Open(lock); err == nil {
+		defer lockFile.Close()
+
+		lockFD := int(lockFile.Fd())
+		if err := syscall.Flock(lockFD, syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+			return true
+		}
+	}
+
+	return hasBackgroundSessions(id)
+}
+
+// hasBackgroundSessions checks if a command started with 'toolbox run
+// --detach', or a tmux(1) or dtach(1) session started with 'toolbox enter
+// --session', is still running in the container.  These carry on after the
+// 'podman exec' that started them returns, and with it the lock of the
+// session, so the processes of the container are looked at instead.
+func hasBackgroundSessions(id string) bool {
+	processes, err := podman.Top(id, "args")
+	if err != nil {
+		logrus.Debugf("Monitoring the host: failed to get the processes of container %s: %s", id, err)
+		return true
+	}
+
+	detachedSessionsDirectory, err := getDetachedSessionsDirectory()
+	if err != nil {
+		logrus.Debugf("Monitoring the host: %s", err)
+	}
+
+	for _, process := range processes {
+		if strings.HasPrefix(process, "tmux") || strings.HasPrefix(process, "dtach") {
+			return true
+		}
+
+		if detachedSessionsDirectory != "" && strings.Contains(process, detachedSessionsDirectory) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// startContainerSession takes a shared lock for the container, so that
+// 'toolbox monitor-host' doesn't stop it while it's in use.  The returned
+// function ends the session.
+func startContainerSession(containerObj podman.Container) func() {
+	lock, err := getContainerSessionLock(containerObj.ID())
+	if err != nil {
+		logrus.Debugf("Starting a session in container %s: %s", containerObj.Name(), err)
+		return func() {}
+	}
+
+	lockFile, err := os.OpenFile(lock, os.O_CREATE|os.O_RDONLY, 0600)
+	if err != nil {
+		logrus.Debugf("Opening %s failed: %s", lock, err)
+		return func() {}
+	}
+
+	lockFD := int(lockFile.Fd())
+	if err := syscall.Flock(lockFD, syscall.LOCK_SH); err != nil {
+		logrus.Debugf("Locking %s failed: %s", lock, err)
+		lockFile.Close()
+		return func() {}
+	}
+
+	return func() {
+		lockFile.Close()
+	}
+}
+
+// stopIdleContainer stops a running container if it has had no sessions for
+// longer than 'auto-stop', and returns whether it did.  The time that each
+// container became idle is tracked in idleSince.
+func stopIdleContainer(container podman.Container, idleSince map[string]time.Time) bool {
+	autoStop := getAutoStopTimeout()
+	if autoStop == 0 {
+		return false
+	}
+
+	id := container.ID()
+
+	if hasContainerSessions(id) {
+		delete(idleSince, id)
+		return false
+	}
+
+	since, ok := idleSince[id]
+	if !ok {
+		idleSince[id] = time.Now()
+		return false
+	}
+
+	if time.Since(since) < autoStop {
+		return false
+	}
+
+	name := container.Name()
+	logrus.Debugf("Stopping container %s, idle since %s", name, since)
+
+	if err := podman.Stop(name, containerStopTimeout); err != nil {
+		logrus.Debugf("Stopping container %s failed: %s", name, err)
+		return false
+	}
+
+	delete(idleSince, id)
+	return true
+}
+
+// stopIdleMachine stops the Podman machine if it has not been used for longer
+// than 'machine-auto-stop', and returns whether it did.  The time that the
+// machine became idle is tracked in idleSince.
+func stopIdleMachine(running map[string]struct{}, idleSince *time.Time) bool {
+	machineAutoStop := getMachineAutoStopTimeout()
+	if machineAutoStop == 0 {
+		return false
+	}
+
+	if isMachineInUse(running) {
+		*idleSince = time.Now()
+		return false
+	}
+
+	if time.Since(*idleSince) < machineAutoStop {
+		return false
+	}
+
+	stamp, err := getMachineStoppedStamp()
+	if err != nil {
+		logrus.Debugf("Stopping the Podman machine: %s", err)
+		return false
+	}
+
+	logrus.Debugf("Stopping the Podman machine, idle since %s", *idleSince)
+
+	if err := podman.MachineStop(); err != nil {
+		logrus.Debugf("Stopping the Podman machine failed: %s", err)
+		return false
+	}
+
+	if err := os.WriteFile(stamp, nil, 0600); err != nil {
+		logrus.Debugf("Creating %s failed: %s", stamp, err)
+	}
+
+	return true
+}
+
+func (config hostConfiguration) digest() string {
+	hash := sha256.New()
+	hash.Write(config.resolvConf)
+	hash.Write([]byte{0})
+	hash.Write(config.hosts)
+	hash.Write([]byte{0})
+	hash.Write(config.kubeconfig)
+	fmt.Fprintf(hash, "\x00%t", config.kube)
+	fmt.Fprintf(hash, "\x00%s\x00%s", config.timeZone, strings.Join(config.proxyEnviron, "\x00"))
+
+	digest := fmt.Sprintf("%x", hash.Sum(nil))
+	return digest
+}
+
+// writeFileInContainer replaces the contents of a file inside a running
+// container in place, so that it keeps working if the file is a bind mount.
+func writeFileInContainer(container, path string, data []byte) error {
+	stdin := bytes.NewReader(data)
+	if err := podman.ExecAsRoot(container, stdin, "sh", "-c", `cat >"$1"`, "sh", path); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,233 @@
+//go:build darwin
+
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/containers/toolbox/pkg/utils"
+	"github.com/google/renameio/v2"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	nestedLabel = "com.github.containers.toolbox.nested"
+
+	// nestedStorageDirectory is where the named volume with the images
+	// and containers of Podman and Buildah inside the container is.  The
+	// file system of the container is an overlay, which can't be the
+	// lower directory of another one.
+	nestedStorageDirectory = "/var/lib/containers/nested"
+
+	// nestedContainersConf makes Podman inside the container share the
+	// namespaces and cgroups of the Toolbx container, like the
+	// quay.io/podman/stable image does, because it can't set up its own
+	// inside the user namespace of a rootless Podman machine.
+	nestedContainersConf = `# Written by 'toolbox init-container' for 'toolbox create --nested'.
+[containers]
+cgroupns = "host"
+cgroups = "disabled"
+ipcns = "host"
+log_driver = "k8s-file"
+netns = "host"
+userns = "host"
+utsns = "host"
+
+[engine]
+cgroup_manager = "cgroupfs"
+events_logger = "file"
+`
+
+	nestedContainersConfFile = "/etc/containers/containers.conf.d/toolbox-nested.conf"
+
+	nestedStorageConfFile = "/etc/containers/storage.conf"
+
+	// nestedSubIDsMinimum is the smallest range of subordinate IDs that
+	// is worth setting up, because images need 65536 to be pulled as they
+	// are, but most only use a few.
+	nestedSubIDsMinimum = 1024
+)
+
+// getNestedArgs returns the options for 'podman create' that '--nested'
+// needs.  The volume with the storage is carried over from the container
+// that is recreated, if any, like other mounts, so that the images aren't
+// lost.
+func getNestedArgs(container string) []string {
+	if !createFlags.nested {
+		return nil
+	}
+
+	args := []string{
+		"--device", "/dev/fuse",
+		"--label", nestedLabel + "=true",
+	}
+
+	if slices.ContainsFunc(createFlags.volumes, isNestedStorageVolume) {
+		return args
+	}
+
+	nestedVolume := getNestedVolumeName(container)
+	logrus.Debugf("Mounting named volume %s at %s", nestedVolume, nestedStorageDirectory)
+
+	args = append(args, "--volume", nestedVolume+":"+nestedStorageDirectory)
+	return args
+}
+
+func getNestedVolumeName(container string) string {
+	return "toolbox-" + container + "-containers"
+}
+
+// getNestedStorageConf points the storage of Podman and Buildah inside the
+// container, for root and every user, to the named volume.
+func getNestedStorageConf() string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "# Written by 'toolbox init-container' for 'toolbox create --nested'.\n")
+	fmt.Fprintf(&builder, "[storage]\n")
+	fmt.Fprintf(&builder, "driver = \"overlay\"\n")
+	fmt.Fprintf(&builder, "graphroot = \"%s/root\"\n", nestedStorageDirectory)
+	fmt.Fprintf(&builder, "rootless_storage_path = \"%s/$USER\"\n", nestedStorageDirectory)
+	fmt.Fprintf(&builder, "runroot = \"/run/containers/storage\"\n")
+
+	if utils.PathExists("/usr/bin/fuse-overlayfs") {
+		fmt.Fprintf(&builder, "\n[storage.options.overlay]\n")
+		fmt.Fprintf(&builder, "mount_program = \"/usr/bin/fuse-overlayfs\"\n")
+	}
+
+	storageConf := builder.String()
+	return storageConf
+}
+
+// getNestedSubIDs returns the range of subordinate IDs for the user inside the
+// container, out of those that the user namespace of the container has, which
+// aren't the user's own.
+func getNestedSubIDs(idMap string, ids ...int) (int, int, error) {
+	var mapped int
+
+	scanner := bufio.NewScanner(strings.NewReader(idMap))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 3 {
+			continue
+		}
+
+		count, err := strconv.Atoi(fields[2])
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid line in the ID map: %s", scanner.Text())
+		}
+
+		mapped += count
+	}
+
+	start := 1000
+	for _, id := range ids {
+		if id >= start {
+			start = id + 1
+		}
+	}
+
+	count := mapped - start
+	if count < nestedSubIDsMinimum {
+		return 0, 0, fmt.Errorf("only %d IDs are available in the user namespace", mapped)
+	}
+
+	return start, count, nil
+}
+
+// isNestedStorageVolume checks if a '--volume' option for 'podman create' is
+// the one with the storage for '--nested'.
+func isNestedStorageVolume(volume string) bool {
+	_, destination, _ := strings.Cut(volume, ":")
+	destination, _, _ = strings.Cut(destination, ":")
+	return destination == nestedStorageDirectory
+}
+
+// setupNested sets up Podman and Buildah inside the container for 'toolbox
+// create --nested', which only need to be installed.  The configuration is
+// written every time the container starts, because it's cheap and fixes
+// images that ship their own.
+func setupNested() error {
+	logrus.Debug("Setting up Podman and Buildah inside the container")
+
+	userStorage := filepath.Join(nestedStorageDirectory, initContainerFlags.user)
+	if err := os.MkdirAll(userStorage, 0700); err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", userStorage, err)
+	}
+
+	if err := os.Chown(userStorage, initContainerFlags.uid, initContainerFlags.gid); err != nil {
+		return fmt.Errorf("failed to change ownership of %s: %w", userStorage, err)
+	}
+
+	for _, file := range []struct {
+		content string
+		path    string
+	}{
+		{nestedContainersConf, nestedContainersConfFile},
+		{getNestedStorageConf(), nestedStorageConfFile},
+	} {
+		dir := filepath.Dir(file.path)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create directory %s: %w", dir, err)
+		}
+
+		if err := renameio.WriteFile(file.path, []byte(file.content), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", file.path, err)
+		}
+	}
+
+	for _, subIDs := range []struct {
+		idMap string
+		path  string
+	}{
+		{"/proc/self/uid_map", "/etc/subuid"},
+		{"/proc/self/gid_map", "/etc/subgid"},
+	} {
+		idMap, err := os.ReadFile(subIDs.idMap)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", subIDs.idMap, err)
+		}
+
+		start, count, err := getNestedSubIDs(string(idMap), initContainerFlags.uid, initContainerFlags.gid)
+		if err != nil {
+			logrus.Debugf("Setting up %s failed: %s", subIDs.path, err)
+			return errors.New("failed to find subordinate IDs for Podman inside the container")
+		}
+
+		if !utils.PathExists(subIDs.path) {
+			if err := os.WriteFile(subIDs.path, nil, 0644); err != nil {
+				return fmt.Errorf("failed to create %s: %w", subIDs.path, err)
+			}
+		}
+
+		entry := fmt.Sprintf("%s:%d:%d", initContainerFlags.user, start, count)
+		logrus.Debugf("Adding %s to %s", entry, subIDs.path)
+
+		if err := writeUserDatabaseEntry(subIDs.path, initContainerFlags.user, entry); err != nil {
+			return fmt.Errorf("failed to update %s: %w", subIDs.path, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,187 @@
+//go:build darwin
+
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/containers/toolbox/pkg/i18n"
+	"github.com/containers/toolbox/pkg/podman"
+	"github.com/containers/toolbox/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	netdumpFlags struct {
+		output string
+	}
+)
+
+var netdumpCmd = &cobra.Command{
+	Use:               "netdump",
+	Short:             "Capture the network traffic of a Toolbx container (macOS version)",
+	RunE:              netdump,
+	ValidArgsFunction: completionContainerNamesFiltered,
+}
+
+func init() {
+	flags := netdumpCmd.Flags()
+
+	flags.StringVarP(&netdumpFlags.output,
+		"output",
+		"o",
+		"",
+		"Write the captured packets to this file instead of CONTAINER-TIMESTAMP.pcap")
+
+	netdumpCmd.SetHelpFunc(netdumpHelp)
+	rootCmd.AddCommand(netdumpCmd)
+}
+
+// netdump runs tcpdump(1) inside the Podman machine, in the network namespace
+// of the container, and streams the packets in pcap format back to a file on
+// the host through 'podman machine ssh'.  Every packet is flushed right away,
+// so that the file is usable after interrupting the capture.
+func netdump(cmd *cobra.Command, args []string) error {
+	if utils.IsInsideContainer() {
+		return errors.New("netdump is not supported inside a container")
+	}
+
+	if len(args) == 0 {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "missing argument for \"netdump\"\n")
+		i18n.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	container := args[0]
+	filter := args[1:]
+
+	containerObj, err := podman.InspectContainer(container)
+	if err != nil {
+		return createErrorContainerNotFound(container)
+	}
+
+	if !containerObj.IsToolbx() {
+		return fmt.Errorf("%s is not a Toolbx container", container)
+	}
+
+	if status := containerObj.Status(); status != "running" {
+		return fmt.Errorf("container %s is not running", container)
+	}
+
+	entryPointPID := containerObj.EntryPointPID()
+	if entryPointPID <= 0 {
+		return fmt.Errorf("invalid entry point PID of container %s", container)
+	}
+
+	if err := podman.MachineSSH(nil, "command", "-v", "tcpdump"); err != nil {
+		logrus.Debugf("Looking for tcpdump(1) in the Podman machine failed: %s", err)
+
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "tcpdump(1) not found in the Podman machine\n")
+		fmt.Fprintf(&builder, "Install it with 'podman machine ssh sudo rpm-ostree install tcpdump'")
+		fmt.Fprintf(&builder, " and restart the machine.")
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	output := netdumpFlags.output
+	if output == "" {
+		timestamp := time.Now().Format("20060102-150405")
+		output = fmt.Sprintf("%s-%s.pcap", container, timestamp)
+	}
+
+	outputFile, err := os.Create(output)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", output, err)
+	}
+
+	defer outputFile.Close()
+
+	entryPointPIDString := strconv.Itoa(entryPointPID)
+	tcpdumpArgs := []string{
+		"sudo",
+		"nsenter", "--target", entryPointPIDString, "--net",
+		"tcpdump",
+		"--interface", "any",
+		"--packet-buffered",
+		"--quiet",
+		"-w", "-",
+	}
+
+	tcpdumpArgs = append(tcpdumpArgs, filter...)
+
+	// Ctrl+C reaches 'podman machine ssh' too, and ends the capture.  This
+	// process needs to outlive it to report where the packets went.  The
+	// signal is caught instead of ignored, because ignored signals stay
+	// ignored in child processes.
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt)
+	defer signal.Stop(interrupts)
+
+	fmt.Fprintf(os.Stderr, "Capturing the network traffic of container %s into %s\n", container, output)
+	fmt.Fprintf(os.Stderr, "Press Ctrl+C to stop.\n")
+
+	if err := podman.MachineSSH(outputFile, tcpdumpArgs...); err != nil {
+		logrus.Debugf("Running tcpdump(1) in the Podman machine finished: %s", err)
+	}
+
+	fileInfo, err := outputFile.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to get the size of %s: %w", output, err)
+	}
+
+	if fileInfo.Size() == 0 {
+		return errors.New("failed to capture network traffic")
+	}
+
+	fmt.Fprintf(os.Stderr, "Wrote %s\n", output)
+	return nil
+}
+
+func netdumpHelp(cmd *cobra.Command, args []string) {
+	if utils.IsInsideContainer() {
+		if !utils.IsInsideToolboxContainer() {
+			fmt.Fprintf(os.Stderr, "Error: this is not a Toolbx container\n")
+			return
+		}
+
+		if _, err := utils.ForwardToHost(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return
+		}
+
+		return
+	}
+
+	if err := showManual("toolbox-netdump"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return
+	}
+}
@@ -0,0 +1,309 @@
+//go:build darwin
+
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/containers/toolbox/pkg/i18n"
+	"github.com/containers/toolbox/pkg/podman"
+	"github.com/containers/toolbox/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+var (
+	pathFlags struct {
+		container string
+	}
+
+	// firmlinkedDirectories are the directories at the root of macOS that
+	// are really below /private.  os.Getwd() returns the /private path,
+	// but the containers have them mounted by their short names.
+	firmlinkedDirectories = []string{"/etc", "/tmp", "/var"}
+)
+
+var pathCmd = &cobra.Command{
+	Use:   "path",
+	Short: "Convert paths between the host and a Toolbx container (macOS version)",
+	RunE:  pathRun,
+}
+
+var pathToContainerCmd = &cobra.Command{
+	Use:               "to-container",
+	Short:             "Convert paths on the host to paths inside a Toolbx container",
+	RunE:              pathToContainer,
+	ValidArgsFunction: completionEmpty,
+}
+
+var pathToHostCmd = &cobra.Command{
+	Use:               "to-host",
+	Short:             "Convert paths inside a Toolbx container to paths on the host",
+	RunE:              pathToHost,
+	ValidArgsFunction: completionEmpty,
+}
+
+func init() {
+	flags := pathCmd.PersistentFlags()
+
+	flags.StringVarP(&pathFlags.container,
+		"container",
+		"c",
+		"",
+		"Convert paths for the Toolbx container with the given name")
+
+	if err := pathCmd.RegisterFlagCompletionFunc("container", completionContainerNames); err != nil {
+		panicMsg := fmt.Sprintf("failed to register flag completion function: %v", err)
+		panic(panicMsg)
+	}
+
+	pathCmd.AddCommand(pathToContainerCmd)
+	pathCmd.AddCommand(pathToHostCmd)
+
+	pathCmd.SetHelpFunc(pathHelp)
+	rootCmd.AddCommand(pathCmd)
+}
+
+func pathRun(cmd *cobra.Command, args []string) error {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "missing command for \"path\", eg., to-container or to-host\n")
+	i18n.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+	errMsg := builder.String()
+	return errors.New(errMsg)
+}
+
+// pathToContainer resolves relative paths against the current directory on
+// the host, so that 'toolbox path to-container .' works from a terminal.
+func pathToContainer(cmd *cobra.Command, args []string) error {
+	if utils.IsInsideContainer() {
+		if !utils.IsInsideToolboxContainer() {
+			return errors.New("this is not a Toolbx container")
+		}
+
+		exitCode, err := utils.ForwardToHost()
+		return &exitError{exitCode, err}
+	}
+
+	if len(args) == 0 {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "path to-container needs at least one path\n")
+		i18n.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	container, mounts, err := getPathContainerMounts()
+	if err != nil {
+		return err
+	}
+
+	for _, arg := range args {
+		hostPath, err := filepath.Abs(arg)
+		if err != nil {
+			return fmt.Errorf("failed to resolve %s: %w", arg, err)
+		}
+
+		containerPath := getContainerPath(hostPath, mounts)
+		if containerPath == "" {
+			return fmt.Errorf("%s is not shared with container %s", hostPath, container)
+		}
+
+		fmt.Println(containerPath)
+	}
+
+	return nil
+}
+
+// pathToHost needs absolute paths, because the current directory on the host
+// has nothing to do with the one inside the container.
+func pathToHost(cmd *cobra.Command, args []string) error {
+	if utils.IsInsideContainer() {
+		if !utils.IsInsideToolboxContainer() {
+			return errors.New("this is not a Toolbx container")
+		}
+
+		exitCode, err := utils.ForwardToHost()
+		return &exitError{exitCode, err}
+	}
+
+	if len(args) == 0 {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "path to-host needs at least one path\n")
+		i18n.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	for _, arg := range args {
+		if !filepath.IsAbs(arg) {
+			var builder strings.Builder
+			fmt.Fprintf(&builder, "invalid argument %s: needs to be an absolute path\n", arg)
+			i18n.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+			errMsg := builder.String()
+			return errors.New(errMsg)
+		}
+	}
+
+	container, mounts, err := getPathContainerMounts()
+	if err != nil {
+		return err
+	}
+
+	for _, arg := range args {
+		hostPath := getHostPath(arg, mounts)
+		if hostPath == "" {
+			return fmt.Errorf("%s of container %s is not shared with the host", arg, container)
+		}
+
+		fmt.Println(hostPath)
+	}
+
+	return nil
+}
+
+func pathHelp(cmd *cobra.Command, args []string) {
+	if utils.IsInsideContainer() {
+		if !utils.IsInsideToolboxContainer() {
+			fmt.Fprintf(os.Stderr, "Error: this is not a Toolbx container\n")
+			return
+		}
+
+		if _, err := utils.ForwardToHost(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return
+		}
+
+		return
+	}
+
+	if err := showManual("toolbox-path"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return
+	}
+}
+
+// getContainerPath returns the path inside a container that corresponds to
+// hostPath, by looking for the longest bind mount from the host that contains
+// it.  eg., /Users/jdoe/src/foo stays as it is, because the home directory is
+// mounted at the same path, while /Users/Shared/foo becomes
+// /host/Users/Shared/foo.  An empty string is returned if hostPath isn't
+// visible inside the container.
+func getContainerPath(hostPath string, mounts []podman.ContainerMount) string {
+	hostPath = filepath.Clean(hostPath)
+
+	for _, firmlink := range firmlinkedDirectories {
+		if privatePath := "/private" + firmlink; isPathBelow(hostPath, privatePath) {
+			hostPath = firmlink + strings.TrimPrefix(hostPath, privatePath)
+			break
+		}
+	}
+
+	mount := findLongestMount(hostPath, mounts, func(mount podman.ContainerMount) string {
+		return mount.Source
+	})
+
+	if mount == nil {
+		return ""
+	}
+
+	relativePath := strings.TrimPrefix(hostPath, mount.Source)
+	containerPath := filepath.Join(mount.Destination, relativePath)
+	return containerPath
+}
+
+// getHostPath is the reverse of getContainerPath.  An empty string is returned
+// if containerPath isn't on a bind mount from the host.
+func getHostPath(containerPath string, mounts []podman.ContainerMount) string {
+	containerPath = filepath.Clean(containerPath)
+
+	mount := findLongestMount(containerPath, mounts, func(mount podman.ContainerMount) string {
+		return mount.Destination
+	})
+
+	if mount == nil {
+		return ""
+	}
+
+	relativePath := strings.TrimPrefix(containerPath, mount.Destination)
+	hostPath := filepath.Join(mount.Source, relativePath)
+	return hostPath
+}
+
+func getPathContainerMounts() (string, []podman.ContainerMount, error) {
+	container, _, _, err := resolveContainerAndImageNames(pathFlags.container, "--container", "", "", "")
+	if err != nil {
+		return "", nil, err
+	}
+
+	containerObj, err := podman.InspectContainer(container)
+	if err != nil {
+		return "", nil, createErrorContainerNotFound(container)
+	}
+
+	if err := checkContainerOwner(containerObj); err != nil {
+		return "", nil, err
+	}
+
+	details, err := podman.InspectContainerDetails(container)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to inspect container %s", container)
+	}
+
+	return container, details.Mounts, nil
+}
+
+func findLongestMount(path string,
+	mounts []podman.ContainerMount,
+	mountPath func(mount podman.ContainerMount) string) *podman.ContainerMount {
+
+	var longestMount *podman.ContainerMount
+
+	for i, mount := range mounts {
+		if mount.Type != "bind" || mount.Source == "" || mount.Destination == "" {
+			continue
+		}
+
+		if !isPathBelow(path, mountPath(mount)) {
+			continue
+		}
+
+		if longestMount == nil || len(mountPath(mount)) > len(mountPath(*longestMount)) {
+			longestMount = &mounts[i]
+		}
+	}
+
+	return longestMount
+}
+
+// isPathBelow tells whether path is dir itself or somewhere inside it.
+func isPathBelow(path, dir string) bool {
+	dir = filepath.Clean(dir)
+	if dir == "/" {
+		return strings.HasPrefix(path, "/")
+	}
+
+	return path == dir || strings.HasPrefix(path, dir+"/")
+}
@@ -0,0 +1,139 @@
+//go:build darwin
+
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/containers/toolbox/pkg/podman"
+	"github.com/containers/toolbox/pkg/utils"
+	"github.com/containers/toolbox/pkg/version"
+	"github.com/sirupsen/logrus"
+)
+
+// pluginPrefix is prepended to an unknown command to find the executable
+// that provides it, like git(1) does.
+const pluginPrefix = "toolbox-"
+
+// setUpPluginEnvironment tells a plugin what the toolbox binary that runs it
+// knows, so that it uses the same Podman machine or connection without having
+// to parse toolbox.conf(5) itself.  In system mode, setUpSystemMode already
+// set CONTAINER_CONNECTION.
+func setUpPluginEnvironment() error {
+	environ := map[string]string{
+		"TOOLBOX_ASSUME_YES": strconv.FormatBool(rootFlags.assumeYes),
+		"TOOLBOX_COLOR":      rootFlags.color,
+		"TOOLBOX_LOG_FORMAT": rootFlags.logFormat,
+		"TOOLBOX_LOG_LEVEL":  rootFlags.logLevel,
+		"TOOLBOX_PATH":       executable,
+		"TOOLBOX_QUIET":      strconv.FormatBool(rootFlags.quiet),
+		"TOOLBOX_STRICT":     strconv.FormatBool(rootFlags.strict),
+		"TOOLBOX_SYSTEM":     strconv.FormatBool(systemMode),
+		"TOOLBOX_VERSION":    version.GetVersion(),
+	}
+
+	if !systemMode {
+		if machine, err := podman.MachineInspect(); err == nil {
+			environ["TOOLBOX_MACHINE"] = machine.Name
+		} else {
+			logrus.Debugf("Inspecting the Podman machine failed: %s", err)
+		}
+	}
+
+	for key, value := range environ {
+		if err := os.Setenv(key, value); err != nil {
+			return fmt.Errorf("failed to set %s: %w", key, err)
+		}
+	}
+
+	return nil
+}
+
+// runPlugin replaces the process with the toolbox-NAME executable from PATH,
+// if NAME isn't one of the built-in commands.  It only returns if there's no
+// such plugin, so that the usual error about an unknown command is shown, or
+// if running it failed.
+func runPlugin(args []string) error {
+	if utils.IsInsideContainer() {
+		return nil
+	}
+
+	if _, _, err := rootCmd.Find(args); err == nil {
+		return nil
+	}
+
+	// The global options before the name of the plugin are parsed, so that
+	// they apply to it too.  The rest belong to the plugin.
+	flags := rootCmd.PersistentFlags()
+	flags.SetInterspersed(false)
+
+	if err := flags.Parse(args); err != nil || flags.NArg() == 0 {
+		return nil
+	}
+
+	name := flags.Arg(0)
+	if strings.ContainsRune(name, os.PathSeparator) {
+		return nil
+	}
+
+	path, err := exec.LookPath(pluginPrefix + name)
+	if err != nil {
+		return nil
+	}
+
+	if err := setUpColor(); err != nil {
+		return err
+	}
+
+	if err := setUpLoggers(); err != nil {
+		return err
+	}
+
+	if err := setUpAssumeYes(rootCmd); err != nil {
+		return err
+	}
+
+	if err := utils.SetUpConfiguration(); err != nil {
+		return err
+	}
+
+	if err := setUpSystemMode(); err != nil {
+		return err
+	}
+
+	if err := setUpPluginEnvironment(); err != nil {
+		return err
+	}
+
+	argv := []string{path}
+	argv = append(argv, flags.Args()[1:]...)
+
+	logrus.Debugf("Running plugin %s", path)
+
+	if err := syscall.Exec(path, argv, os.Environ()); err != nil {
+		return fmt.Errorf("failed to run %s: %w", path, err)
+	}
+
+	return nil
+}
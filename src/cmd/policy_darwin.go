@@ -0,0 +1,225 @@
+//go:build darwin
+
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/containers/toolbox/pkg/shell"
+	"github.com/containers/toolbox/pkg/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// imagePolicy restricts the images that Toolbx containers can be created from, for
+// Macs that are managed by an organization.  Each list that is missing allows
+// everything, and each list that is empty allows nothing.
+type imagePolicy struct {
+	// AllowedDistros are the distros, eg., fedora, of the images.
+	AllowedDistros []string `json:"allowedDistros"`
+
+	// AllowedImages are patterns for path.Match, eg.,
+	// registry.example.com/toolbox/*, that are matched against the image
+	// with and without its tag.
+	AllowedImages []string `json:"allowedImages"`
+
+	// AllowedRegistries are registries, eg., quay.io, optionally followed by
+	// a namespace, eg., quay.io/toolbx.
+	AllowedRegistries []string `json:"allowedRegistries"`
+
+	// Message is added to the errors, eg., to say whom to ask.
+	Message string `json:"message"`
+
+	source string
+}
+
+const (
+	policyDomain = "com.github.containers.toolbox"
+	policyFile   = "/Library/Application Support/toolbox/policy.json"
+)
+
+// checkImage returns an error if the image isn't allowed.  Images without a
+// registry are resolved like 'toolbox create' does for the supported
+// distros, and are refused otherwise if the registries are restricted,
+// because Podman could pull them from any of its unqualified-search
+// registries.
+func (p *imagePolicy) checkImage(image, release string) error {
+	if p == nil {
+		return nil
+	}
+
+	distro, _ := utils.GetDistroAndReleaseForImage(image)
+	if p.AllowedDistros != nil && !slices.Contains(p.AllowedDistros, distro) {
+		var builder strings.Builder
+		if distro == "" {
+			fmt.Fprintf(&builder, "image %s is not for one of the allowed distros\n", image)
+		} else {
+			fmt.Fprintf(&builder, "distro %s is not allowed\n", distro)
+		}
+
+		p.describe(&builder, "Allowed distros", p.AllowedDistros)
+		return errors.New(builder.String())
+	}
+
+	if !utils.ImageReferenceHasDomain(image) && p.AllowedRegistries != nil {
+		tag := utils.ImageReferenceGetTag(image)
+		if release != "" && (tag == "" || tag == release) {
+			if imageFull, err := utils.GetFullyQualifiedImageFromDistros(image, release); err == nil {
+				image = imageFull
+			}
+		}
+	}
+
+	name, _, _ := strings.Cut(image, "@")
+	if tag := utils.ImageReferenceGetTag(name); tag != "" {
+		name = strings.TrimSuffix(name, ":"+tag)
+	}
+
+	if p.AllowedRegistries != nil {
+		if !utils.ImageReferenceHasDomain(image) {
+			var builder strings.Builder
+			fmt.Fprintf(&builder, "image %s has no registry\n", image)
+			fmt.Fprintf(&builder, "Use its fully qualified name instead.\n")
+			p.describe(&builder, "Allowed registries", p.AllowedRegistries)
+			return errors.New(builder.String())
+		}
+
+		allowed := slices.ContainsFunc(p.AllowedRegistries, func(registry string) bool {
+			registry = strings.TrimSuffix(registry, "/")
+			return name == registry || strings.HasPrefix(name, registry+"/")
+		})
+
+		if !allowed {
+			var builder strings.Builder
+			fmt.Fprintf(&builder, "registry %s is not allowed\n", utils.ImageReferenceGetDomain(image))
+			p.describe(&builder, "Allowed registries", p.AllowedRegistries)
+			return errors.New(builder.String())
+		}
+	}
+
+	if p.AllowedImages != nil {
+		allowed := slices.ContainsFunc(p.AllowedImages, func(pattern string) bool {
+			if matched, _ := path.Match(pattern, image); matched {
+				return true
+			}
+
+			matched, _ := path.Match(pattern, name)
+			return matched
+		})
+
+		if !allowed {
+			var builder strings.Builder
+			fmt.Fprintf(&builder, "image %s is not allowed\n", image)
+			p.describe(&builder, "Allowed images", p.AllowedImages)
+			return errors.New(builder.String())
+		}
+	}
+
+	return nil
+}
+
+// describe finishes an error about something that isn't allowed.
+func (p *imagePolicy) describe(builder *strings.Builder, what string, allowed []string) {
+	if len(allowed) == 0 {
+		fmt.Fprintf(builder, "%s: none\n", what)
+	} else {
+		fmt.Fprintf(builder, "%s: %s\n", what, strings.Join(allowed, ", "))
+	}
+
+	fmt.Fprintf(builder, "This is restricted by %s.", p.source)
+
+	if p.Message != "" {
+		fmt.Fprintf(builder, "\n%s", p.Message)
+	}
+}
+
+// filterDistros returns the distros that are allowed, to be offered when
+// choosing one.
+func (p *imagePolicy) filterDistros(distros []string) []string {
+	if p == nil || p.AllowedDistros == nil {
+		return distros
+	}
+
+	var filtered []string
+
+	for _, distro := range distros {
+		if slices.Contains(p.AllowedDistros, distro) {
+			filtered = append(filtered, distro)
+		}
+	}
+
+	return filtered
+}
+
+// getPolicy reads the policy from the preferences that are managed by MDM,
+// first for the current user, and then for the whole Mac, or from the policy
+// file.  The first one that exists is used.  It's nil if there is none.  A
+// policy that can't be read is an error, so that breaking it doesn't lift the
+// restrictions.
+func getPolicy() (*imagePolicy, error) {
+	managedPreferences := []string{
+		filepath.Join("/Library/Managed Preferences", currentUser.Username, policyDomain+".plist"),
+		filepath.Join("/Library/Managed Preferences", policyDomain+".plist"),
+	}
+
+	for _, plist := range managedPreferences {
+		if !utils.PathExists(plist) {
+			continue
+		}
+
+		var stdout strings.Builder
+
+		if err := shell.Run("plutil", nil, &stdout, nil, "-convert", "json", "-o", "-", plist); err != nil {
+			logrus.Debugf("Reading policy from %s failed: %s", plist, err)
+			return nil, fmt.Errorf("failed to read policy from %s", plist)
+		}
+
+		return parsePolicy([]byte(stdout.String()), plist)
+	}
+
+	data, err := os.ReadFile(policyFile)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+
+		logrus.Debugf("Reading policy from %s failed: %s", policyFile, err)
+		return nil, fmt.Errorf("failed to read policy from %s", policyFile)
+	}
+
+	return parsePolicy(data, policyFile)
+}
+
+func parsePolicy(data []byte, source string) (*imagePolicy, error) {
+	var p imagePolicy
+	if err := json.Unmarshal(data, &p); err != nil {
+		logrus.Debugf("Parsing policy from %s failed: %s", source, err)
+		return nil, fmt.Errorf("failed to parse policy from %s", source)
+	}
+
+	p.source = source
+	logrus.Debugf("Using policy from %s", source)
+	return &p, nil
+}
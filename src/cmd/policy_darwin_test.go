@@ -0,0 +1,184 @@
+//go:build darwin
+
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePolicy(t *testing.T) {
+	data := []byte(`{
+  "allowedDistros": ["fedora", "rhel"],
+  "allowedRegistries": ["registry.fedoraproject.org", "registry.access.redhat.com"],
+  "message": "Ask IT for other images."
+}`)
+
+	policy, err := parsePolicy(data, policyFile)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"fedora", "rhel"}, policy.AllowedDistros)
+	assert.Nil(t, policy.AllowedImages)
+	assert.Equal(t, []string{"registry.fedoraproject.org", "registry.access.redhat.com"}, policy.AllowedRegistries)
+	assert.Equal(t, "Ask IT for other images.", policy.Message)
+	assert.Equal(t, policyFile, policy.source)
+
+	_, err = parsePolicy([]byte(`{"allowedDistros": "fedora"}`), policyFile)
+	assert.Error(t, err)
+}
+
+func TestImagePolicyCheckImage(t *testing.T) {
+	testCases := []struct {
+		name    string
+		policy  *imagePolicy
+		image   string
+		release string
+		allowed bool
+	}{
+		{
+			name:    "no policy",
+			image:   "docker.io/library/alpine:latest",
+			allowed: true,
+		},
+		{
+			name:    "allowed distro",
+			policy:  &imagePolicy{AllowedDistros: []string{"fedora"}},
+			image:   "registry.fedoraproject.org/fedora-toolbox:42",
+			allowed: true,
+		},
+		{
+			name:    "other distro",
+			policy:  &imagePolicy{AllowedDistros: []string{"fedora"}},
+			image:   "quay.io/toolbx/ubuntu-toolbox:24.04",
+			allowed: false,
+		},
+		{
+			name:    "unknown distro",
+			policy:  &imagePolicy{AllowedDistros: []string{"fedora"}},
+			image:   "docker.io/library/alpine:latest",
+			allowed: false,
+		},
+		{
+			name:    "allowed registry",
+			policy:  &imagePolicy{AllowedRegistries: []string{"registry.fedoraproject.org"}},
+			image:   "registry.fedoraproject.org/fedora-toolbox:42",
+			allowed: true,
+		},
+		{
+			name:    "allowed namespace",
+			policy:  &imagePolicy{AllowedRegistries: []string{"quay.io/toolbx/"}},
+			image:   "quay.io/toolbx/ubuntu-toolbox:24.04",
+			allowed: true,
+		},
+		{
+			name:    "other namespace",
+			policy:  &imagePolicy{AllowedRegistries: []string{"quay.io/toolbx"}},
+			image:   "quay.io/toolbx-images/debian-toolbox:12",
+			allowed: false,
+		},
+		{
+			name:    "unqualified distro image",
+			policy:  &imagePolicy{AllowedRegistries: []string{"registry.fedoraproject.org"}},
+			image:   "fedora-toolbox:42",
+			release: "42",
+			allowed: true,
+		},
+		{
+			name:    "unqualified image",
+			policy:  &imagePolicy{AllowedRegistries: []string{"docker.io"}},
+			image:   "alpine:latest",
+			allowed: false,
+		},
+		{
+			name:    "no registries",
+			policy:  &imagePolicy{AllowedRegistries: []string{}},
+			image:   "registry.fedoraproject.org/fedora-toolbox:42",
+			allowed: false,
+		},
+		{
+			name:    "allowed image",
+			policy:  &imagePolicy{AllowedImages: []string{"registry.example.com/toolbox/*"}},
+			image:   "registry.example.com/toolbox/dev:1.0",
+			allowed: true,
+		},
+		{
+			name:    "allowed image by digest",
+			policy:  &imagePolicy{AllowedImages: []string{"registry.example.com/toolbox/*"}},
+			image:   "registry.example.com/toolbox/dev@sha256:0123456789abcdef",
+			allowed: true,
+		},
+		{
+			name:    "other tag",
+			policy:  &imagePolicy{AllowedImages: []string{"registry.example.com/toolbox/dev:1.*"}},
+			image:   "registry.example.com/toolbox/dev:2.0",
+			allowed: false,
+		},
+		{
+			name:    "other image",
+			policy:  &imagePolicy{AllowedImages: []string{"registry.example.com/toolbox/*"}},
+			image:   "registry.example.com/other/dev:1.0",
+			allowed: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.policy != nil {
+				tc.policy.source = policyFile
+			}
+
+			err := tc.policy.checkImage(tc.image, tc.release)
+			if tc.allowed {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestImagePolicyCheckImageError(t *testing.T) {
+	policy := &imagePolicy{
+		AllowedRegistries: []string{"registry.fedoraproject.org"},
+		Message:           "Ask IT for other images.",
+		source:            policyFile,
+	}
+
+	err := policy.checkImage("quay.io/toolbx/ubuntu-toolbox:24.04", "24.04")
+	require.Error(t, err)
+	assert.Equal(t, "registry quay.io is not allowed\n"+
+		"Allowed registries: registry.fedoraproject.org\n"+
+		"This is restricted by "+policyFile+".\n"+
+		"Ask IT for other images.",
+		err.Error())
+}
+
+func TestImagePolicyFilterDistros(t *testing.T) {
+	distros := []string{"arch", "fedora", "rhel", "ubuntu"}
+
+	var policy *imagePolicy
+	assert.Equal(t, distros, policy.filterDistros(distros))
+
+	policy = &imagePolicy{AllowedDistros: []string{"rhel", "fedora"}}
+	assert.Equal(t, []string{"fedora", "rhel"}, policy.filterDistros(distros))
+
+	policy = &imagePolicy{AllowedDistros: []string{}}
+	assert.Empty(t, policy.filterDistros(distros))
+}
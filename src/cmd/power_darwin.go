@@ -0,0 +1,303 @@
+//go:build darwin
+
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/containers/toolbox/pkg/i18n"
+	"github.com/containers/toolbox/pkg/podman"
+	"github.com/containers/toolbox/pkg/shell"
+	"github.com/containers/toolbox/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// defaultBatteryCPUs is how many CPUs the Podman machine keeps on battery
+// power, if 'toolbox machine power-policy enable' isn't told otherwise.
+const defaultBatteryCPUs = 2
+
+var (
+	machinePowerPolicyEnableFlags struct {
+		batteryCPUs int
+	}
+)
+
+var machinePowerPolicyCmd = &cobra.Command{
+	Use:   "power-policy",
+	Short: "Use fewer CPUs in the Podman machine on battery power",
+	RunE:  machinePowerPolicy,
+}
+
+var machinePowerPolicyDisableCmd = &cobra.Command{
+	Use:   "disable",
+	Short: "Use all the CPUs of the Podman machine on battery power",
+	RunE:  machinePowerPolicyDisable,
+}
+
+var machinePowerPolicyEnableCmd = &cobra.Command{
+	Use:   "enable",
+	Short: "Use fewer CPUs in the Podman machine on battery power",
+	RunE:  machinePowerPolicyEnable,
+}
+
+func init() {
+	flags := machinePowerPolicyEnableCmd.Flags()
+
+	flags.IntVar(&machinePowerPolicyEnableFlags.batteryCPUs,
+		"battery-cpus",
+		defaultBatteryCPUs,
+		"Number of CPUs to keep on battery power")
+
+	machinePowerPolicyCmd.AddCommand(machinePowerPolicyDisableCmd)
+	machinePowerPolicyCmd.AddCommand(machinePowerPolicyEnableCmd)
+	machineCmd.AddCommand(machinePowerPolicyCmd)
+}
+
+// applyPowerPolicy is used by 'toolbox monitor-host' to take CPUs of the
+// Podman machine offline on battery power, and bring them back on AC power or
+// when the policy is disabled.  Changing the CPUs with 'podman machine set'
+// would need the machine to be restarted, while Linux can take them offline
+// as it runs.  It returns whether CPUs are offline afterwards.
+func applyPowerPolicy(throttled bool) bool {
+	batteryCPUs := getBatteryCPUs()
+
+	onBattery := false
+	if batteryCPUs != 0 {
+		var err error
+		onBattery, err = isOnBatteryPower()
+		if err != nil {
+			logrus.Debugf("Monitoring the host: %s", err)
+			return throttled
+		}
+	}
+
+	if onBattery == throttled {
+		return throttled
+	}
+
+	online, total, err := getMachineCPUs()
+	if err != nil {
+		logrus.Debugf("Monitoring the host: failed to get the CPUs of the Podman machine: %s", err)
+		return throttled
+	}
+
+	wanted := total
+	if onBattery {
+		wanted = min(batteryCPUs, total)
+	}
+
+	if online == wanted {
+		return onBattery
+	}
+
+	logrus.Debugf("Monitoring the host: bringing %d of %d CPUs of the Podman machine online", wanted, total)
+
+	if err := setMachineOnlineCPUs(wanted, total); err != nil {
+		logrus.Debugf("Monitoring the host: failed to change the CPUs of the Podman machine: %s", err)
+		return throttled
+	}
+
+	return onBattery
+}
+
+// getBatteryCPUs returns how many CPUs the Podman machine keeps on battery
+// power, or 0 if the power policy is disabled.
+func getBatteryCPUs() int {
+	batteryCPUs := viper.GetInt("machine.battery-cpus")
+	if batteryCPUs < 0 {
+		logrus.Debugf("Invalid battery-cpus %d", batteryCPUs)
+		return 0
+	}
+
+	return batteryCPUs
+}
+
+// getMachineCPUs returns how many CPUs of the Podman machine are online, and
+// how many it has.
+func getMachineCPUs() (int, int, error) {
+	var stdout bytes.Buffer
+	if err := podman.MachineSSH(&stdout, "nproc ; nproc --all"); err != nil {
+		return 0, 0, err
+	}
+
+	fields := strings.Fields(stdout.String())
+	if len(fields) != 2 {
+		return 0, 0, errors.New("unexpected output from nproc(1)")
+	}
+
+	online, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to parse the number of online CPUs: %w", err)
+	}
+
+	total, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to parse the number of CPUs: %w", err)
+	}
+
+	return online, total, nil
+}
+
+// isOnBatteryPower asks pmset(1) where the Mac draws its power from.  Macs
+// without a battery are always on AC power.
+func isOnBatteryPower() (bool, error) {
+	var stdout bytes.Buffer
+	if err := shell.Run("pmset", nil, &stdout, nil, "-g", "batt"); err != nil {
+		return false, fmt.Errorf("failed to get the power source: %w", err)
+	}
+
+	onBattery := strings.Contains(stdout.String(), "'Battery Power'")
+	return onBattery, nil
+}
+
+// machinePowerPolicy shows the power policy, and what it currently does.
+func machinePowerPolicy(cmd *cobra.Command, args []string) error {
+	if utils.IsInsideContainer() {
+		return errors.New("machine is not supported inside a container")
+	}
+
+	policy := "disabled"
+	if batteryCPUs := getBatteryCPUs(); batteryCPUs != 0 {
+		policy = fmt.Sprintf("%d CPUs on battery power", batteryCPUs)
+	}
+
+	powerSource := "unknown"
+	if onBattery, err := isOnBatteryPower(); err != nil {
+		logrus.Debugf("Getting the power source failed: %s", err)
+	} else if onBattery {
+		powerSource = "battery"
+	} else {
+		powerSource = "AC"
+	}
+
+	cpus := "unknown, the Podman machine is not running"
+	if online, total, err := getMachineCPUs(); err != nil {
+		logrus.Debugf("Getting the CPUs of the Podman machine failed: %s", err)
+	} else {
+		cpus = fmt.Sprintf("%d of %d online", online, total)
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(writer, "Policy:\t%s\n", policy)
+	fmt.Fprintf(writer, "Power source:\t%s\n", powerSource)
+	fmt.Fprintf(writer, "Podman machine CPUs:\t%s\n", cpus)
+	writer.Flush()
+
+	return nil
+}
+
+func machinePowerPolicyDisable(cmd *cobra.Command, args []string) error {
+	if utils.IsInsideContainer() {
+		return errors.New("machine is not supported inside a container")
+	}
+
+	if err := utils.SetUserConfigValue("machine", "battery-cpus", "0"); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// machinePowerPolicyEnable records the policy in the user's toolbox.conf(5),
+// which 'toolbox monitor-host' reads again when it changes.
+func machinePowerPolicyEnable(cmd *cobra.Command, args []string) error {
+	if utils.IsInsideContainer() {
+		return errors.New("machine is not supported inside a container")
+	}
+
+	batteryCPUs := machinePowerPolicyEnableFlags.batteryCPUs
+	if batteryCPUs < 1 {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "invalid argument for '--battery-cpus'\n")
+		i18n.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	value := strconv.Itoa(batteryCPUs)
+	if err := utils.SetUserConfigValue("machine", "battery-cpus", value); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// restoreMachineCPUs brings all the CPUs of the Podman machine back online,
+// for when 'toolbox monitor-host' exits and won't be around to do it.
+func restoreMachineCPUs() {
+	online, total, err := getMachineCPUs()
+	if err != nil {
+		logrus.Debugf("Getting the CPUs of the Podman machine failed: %s", err)
+		return
+	}
+
+	if online == total {
+		return
+	}
+
+	if err := setMachineOnlineCPUs(total, total); err != nil {
+		logrus.Debugf("Bringing the CPUs of the Podman machine online failed: %s", err)
+	}
+}
+
+// setMachineOnlineCPUs brings the first wanted CPUs of the Podman machine
+// online, and takes the rest offline.  The first CPU can't be taken offline.
+func setMachineOnlineCPUs(wanted, total int) error {
+	// 'podman machine ssh' runs the command through the shell of the
+	// machine, so the script is quoted once for it.
+	script := fmt.Sprintf(`for cpu in $(seq 1 %d); do `+
+		`if [ "$cpu" -lt %d ]; then online=1; else online=0; fi; `+
+		`echo "$online" >"/sys/devices/system/cpu/cpu$cpu/online"; `+
+		`done`, total-1, wanted)
+
+	if err := podman.MachineSSH(nil, "sudo sh -c '"+script+"'"); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// warnIfOnBatteryPower points out that a build will be slow, and drain the
+// battery, while the power policy has CPUs of the Podman machine offline.
+func warnIfOnBatteryPower() {
+	if getBatteryCPUs() == 0 {
+		return
+	}
+
+	onBattery, err := isOnBatteryPower()
+	if err != nil {
+		logrus.Debugf("Getting the power source failed: %s", err)
+		return
+	}
+
+	if !onBattery {
+		return
+	}
+
+	showWarning("the Mac is on battery power, and the Podman machine only uses %d CPUs", getBatteryCPUs())
+}
@@ -0,0 +1,200 @@
+//go:build darwin
+
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/containers/toolbox/pkg/i18n"
+	"github.com/containers/toolbox/pkg/podman"
+	"github.com/containers/toolbox/pkg/toolbox"
+	"github.com/containers/toolbox/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var protectCmd = &cobra.Command{
+	Use:               "protect",
+	Short:             "Keep Toolbx containers from being removed by accident (macOS version)",
+	RunE:              protect,
+	ValidArgsFunction: completionContainerNamesFiltered,
+}
+
+var unprotectCmd = &cobra.Command{
+	Use:               "unprotect",
+	Short:             "Allow protected Toolbx containers to be removed again (macOS version)",
+	RunE:              unprotect,
+	ValidArgsFunction: completionContainerNamesFiltered,
+}
+
+func init() {
+	protectCmd.SetHelpFunc(protectHelp)
+	rootCmd.AddCommand(protectCmd)
+
+	unprotectCmd.SetHelpFunc(protectHelp)
+	rootCmd.AddCommand(unprotectCmd)
+}
+
+// protect marks long-lived containers, so that 'toolbox rm --all' or a
+// mistyped name doesn't take them and everything installed in them away.
+func protect(cmd *cobra.Command, args []string) error {
+	if utils.IsInsideContainer() {
+		return errors.New("protect is not supported inside a container")
+	}
+
+	if len(args) == 0 {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "missing argument for \"protect\"\n")
+		i18n.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	for _, container := range args {
+		containerObj, err := getProtectContainer(container)
+		if err != nil {
+			return err
+		}
+
+		stamp, err := toolbox.GetProtectedStamp(containerObj.ID())
+		if err != nil {
+			return err
+		}
+
+		stampDir := filepath.Dir(stamp)
+		if err := os.MkdirAll(stampDir, 0755); err != nil {
+			return fmt.Errorf("failed to create directory %s: %w", stampDir, err)
+		}
+
+		if err := os.WriteFile(stamp, nil, 0644); err != nil {
+			return fmt.Errorf("failed to protect container %s: %w", container, err)
+		}
+	}
+
+	return nil
+}
+
+// transferContainerProtected keeps a container protected after it was
+// recreated with the same name, eg., by 'toolbox cap set', because the mark
+// left by 'toolbox protect' goes by the ID of the container.
+func transferContainerProtected(oldID, container string) {
+	oldStamp, err := toolbox.GetProtectedStamp(oldID)
+	if err != nil {
+		logrus.Debugf("Transferring the protection of container %s: %s", container, err)
+		return
+	}
+
+	if _, err := os.Stat(oldStamp); err != nil {
+		return
+	}
+
+	containerObj, err := podman.InspectContainer(container)
+	if err != nil {
+		logrus.Debugf("Inspecting container %s failed: %s", container, err)
+		showWarning("container %s is not protected any more", container)
+		return
+	}
+
+	stamp, err := toolbox.GetProtectedStamp(containerObj.ID())
+	if err != nil {
+		logrus.Debugf("Transferring the protection of container %s: %s", container, err)
+		return
+	}
+
+	if err := os.Rename(oldStamp, stamp); err != nil {
+		logrus.Debugf("Renaming %s to %s failed: %s", oldStamp, stamp, err)
+		showWarning("container %s is not protected any more", container)
+	}
+}
+
+func unprotect(cmd *cobra.Command, args []string) error {
+	if utils.IsInsideContainer() {
+		return errors.New("unprotect is not supported inside a container")
+	}
+
+	if len(args) == 0 {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "missing argument for \"unprotect\"\n")
+		i18n.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	for _, container := range args {
+		containerObj, err := getProtectContainer(container)
+		if err != nil {
+			return err
+		}
+
+		stamp, err := toolbox.GetProtectedStamp(containerObj.ID())
+		if err != nil {
+			return err
+		}
+
+		if err := os.Remove(stamp); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to unprotect container %s: %w", container, err)
+		}
+	}
+
+	return nil
+}
+
+func getProtectContainer(container string) (podman.Container, error) {
+	containerObj, err := podman.InspectContainer(container)
+	if err != nil {
+		return nil, createErrorContainerNotFound(container)
+	}
+
+	if !containerObj.IsToolbx() {
+		return nil, fmt.Errorf("%s is not a Toolbx container", container)
+	}
+
+	if err := checkContainerOwner(containerObj); err != nil {
+		return nil, err
+	}
+
+	return containerObj, nil
+}
+
+func protectHelp(cmd *cobra.Command, args []string) {
+	if utils.IsInsideContainer() {
+		if !utils.IsInsideToolboxContainer() {
+			fmt.Fprintf(os.Stderr, "Error: this is not a Toolbx container\n")
+			return
+		}
+
+		if _, err := utils.ForwardToHost(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return
+		}
+
+		return
+	}
+
+	if err := showManual("toolbox-protect"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return
+	}
+}
@@ -0,0 +1,280 @@
+//go:build darwin
+
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/containers/toolbox/pkg/i18n"
+	"github.com/containers/toolbox/pkg/podman"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+const (
+	// hostContainersInternal is the name that reaches the Mac from inside
+	// containers of the Podman machine.  gvproxy forwards connections to
+	// it to localhost on the Mac.
+	hostContainersInternal = "host.containers.internal"
+
+	// publishHostIP is where published ports are reachable by default.
+	// The Podman machine's gvproxy forwards them from this address on
+	// macOS, so that they are reachable from browsers at localhost, but
+	// not from the network.
+	publishHostIP = "127.0.0.1"
+)
+
+// getContainerHostAddress returns the address, as a URL or as HOST[:PORT], of
+// a server listening on localhost of the Mac as it's reached from inside a
+// container, and the host name that it had.  Other addresses are returned as
+// they are, with an empty host name.
+func getContainerHostAddress(address string) (string, string) {
+	if strings.Contains(address, "://") {
+		addressURL, err := url.Parse(address)
+		if err != nil {
+			return address, ""
+		}
+
+		hostname := addressURL.Hostname()
+		if !isLoopbackHost(hostname) {
+			return address, ""
+		}
+
+		if port := addressURL.Port(); port != "" {
+			addressURL.Host = net.JoinHostPort(hostContainersInternal, port)
+		} else {
+			addressURL.Host = hostContainersInternal
+		}
+
+		return addressURL.String(), hostname
+	}
+
+	hostname, port, err := net.SplitHostPort(address)
+	if err != nil {
+		hostname = address
+		port = ""
+	}
+
+	if !isLoopbackHost(hostname) {
+		return address, ""
+	}
+
+	if port == "" {
+		return hostContainersInternal, hostname
+	}
+
+	return net.JoinHostPort(hostContainersInternal, port), hostname
+}
+
+// getPublishArgs returns the '--publish' arguments for 'podman create' from
+// the '--publish' options, or from 'publish' in the general section of the
+// configuration if there are none.
+func getPublishArgs() ([]string, error) {
+	specs := createFlags.publish
+	fromFlags := len(specs) != 0 || createFlags.publishFromContainer
+	if !fromFlags {
+		specs = viper.GetStringSlice("general.publish")
+	}
+
+	if len(specs) == 0 {
+		return nil, nil
+	}
+
+	if createFlags.networkFrom != "" && !fromFlags {
+		logrus.Debugf("Ignoring published ports from the configuration with a shared network")
+		return nil, nil
+	}
+
+	if network := createFlags.network; network == "host" || network == "none" {
+		if !fromFlags {
+			logrus.Debugf("Ignoring published ports from the configuration with network %s", network)
+			return nil, nil
+		}
+
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "options --network %s and --publish cannot be used together\n", network)
+		i18n.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return nil, errors.New(errMsg)
+	}
+
+	var args []string
+
+	for _, spec := range specs {
+		publish, err := parsePublish(spec)
+		if err != nil {
+			if !fromFlags {
+				return nil, fmt.Errorf("invalid publish %s in the configuration: %w", spec, err)
+			}
+
+			var builder strings.Builder
+			fmt.Fprintf(&builder, "invalid argument for '--publish': %s\n", err)
+			i18n.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+			errMsg := builder.String()
+			return nil, errors.New(errMsg)
+		}
+
+		logrus.Debugf("Publishing %s as %s", spec, publish)
+		args = append(args, "--publish", publish)
+	}
+
+	return args, nil
+}
+
+// getContainerPublish returns the ports published by an existing container,
+// in the form taken by '--publish', so that they can be published again by a
+// new container that replaces it.
+func getContainerPublish(details podman.ContainerDetails) []string {
+	var specs []string
+
+	for containerPort, bindings := range details.HostConfig.PortBindings {
+		for _, binding := range bindings {
+			ip := binding.HostIP
+			if ip == "" {
+				ip = "0.0.0.0"
+			} else if strings.Contains(ip, ":") {
+				ip = "[" + ip + "]"
+			}
+
+			spec := ip + ":" + binding.HostPort + ":" + containerPort
+			specs = append(specs, spec)
+		}
+	}
+
+	sort.Strings(specs)
+	return specs
+}
+
+// isLoopbackHost tells whether a host name or IP address means the Mac itself
+// to a server listening on it.
+func isLoopbackHost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+
+	isLoopback := ip.IsLoopback() || ip.IsUnspecified()
+	return isLoopback
+}
+
+// parsePublish checks a port mapping of the form
+// [[IP:][HOST-PORT]:]CONTAINER-PORT[/PROTOCOL], where the ports can be ranges,
+// and returns it in the form understood by 'podman create --publish'.  Unlike
+// Podman, a missing IP means localhost, and a missing host port means the same
+// one as inside the container, so that 'toolbox create --publish 8080' makes
+// http://localhost:8080 work.
+func parsePublish(spec string) (string, error) {
+	mapping, protocol, found := strings.Cut(spec, "/")
+	if found {
+		if protocol != "tcp" && protocol != "udp" && protocol != "sctp" {
+			return "", fmt.Errorf("unknown protocol %s in %s", protocol, spec)
+		}
+	} else {
+		protocol = "tcp"
+	}
+
+	var ip string
+	if strings.HasPrefix(mapping, "[") {
+		var rest string
+		ip, rest, found = strings.Cut(mapping[1:], "]:")
+		if !found || !strings.Contains(rest, ":") {
+			return "", fmt.Errorf("missing ports after IP address in %s", spec)
+		}
+
+		mapping = rest
+	}
+
+	parts := strings.Split(mapping, ":")
+	if len(parts) > 3 || (ip != "" && len(parts) > 2) {
+		return "", fmt.Errorf("too many colons in %s", spec)
+	}
+
+	containerPort := parts[len(parts)-1]
+	hostPort := containerPort
+	if len(parts) > 1 {
+		hostPort = parts[len(parts)-2]
+	}
+
+	if len(parts) == 3 {
+		ip = parts[0]
+	}
+
+	if ip == "" {
+		ip = publishHostIP
+	} else if net.ParseIP(ip) == nil {
+		return "", fmt.Errorf("invalid IP address %s in %s", ip, spec)
+	}
+
+	containerPorts, err := parsePortRange(containerPort)
+	if err != nil {
+		return "", fmt.Errorf("%w in %s", err, spec)
+	}
+
+	// An empty host port lets Podman choose a free one.
+	if hostPort != "" {
+		hostPorts, err := parsePortRange(hostPort)
+		if err != nil {
+			return "", fmt.Errorf("%w in %s", err, spec)
+		}
+
+		if hostPorts != containerPorts {
+			return "", fmt.Errorf("ranges of different sizes in %s", spec)
+		}
+	}
+
+	if strings.Contains(ip, ":") {
+		ip = "[" + ip + "]"
+	}
+
+	publish := fmt.Sprintf("%s:%s:%s/%s", ip, hostPort, containerPort, protocol)
+	return publish, nil
+}
+
+// parsePortRange checks a port, or a range of ports like 8000-8009, and
+// returns how many ports there are.
+func parsePortRange(portRange string) (int, error) {
+	first, last, isRange := strings.Cut(portRange, "-")
+	if !isRange {
+		last = first
+	}
+
+	firstPort, err := strconv.Atoi(first)
+	if err != nil || firstPort < 1 || firstPort > 65535 {
+		return 0, fmt.Errorf("invalid port %s", first)
+	}
+
+	lastPort, err := strconv.Atoi(last)
+	if err != nil || lastPort < firstPort || lastPort > 65535 {
+		return 0, fmt.Errorf("invalid port %s", last)
+	}
+
+	return lastPort - firstPort + 1, nil
+}
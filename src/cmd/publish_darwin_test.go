@@ -0,0 +1,74 @@
+//go:build darwin
+
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/containers/toolbox/pkg/podman"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPublishSurvivesRecreate(t *testing.T) {
+	viper.Set("general.publish", []string{"9000"})
+	defer viper.Set("general.publish", nil)
+
+	testCases := []struct {
+		name         string
+		portBindings map[string][]podman.PortBinding
+		expected     []string
+	}{
+		{
+			name:         "No ports",
+			portBindings: nil,
+			expected:     nil,
+		},
+		{
+			name: "Ports",
+			portBindings: map[string][]podman.PortBinding{
+				"8080/tcp": {{HostIP: "127.0.0.1", HostPort: "8080"}},
+				"53/udp":   {{HostIP: "::1", HostPort: "5353"}},
+				"3000/tcp": {{HostIP: "", HostPort: "3001"}},
+			},
+			expected: []string{
+				"--publish", "0.0.0.0:3001:3000/tcp",
+				"--publish", "127.0.0.1:8080:8080/tcp",
+				"--publish", "[::1]:5353:53/udp",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var details podman.ContainerDetails
+			details.HostConfig.PortBindings = tc.portBindings
+
+			setCreateFlagsFromContainer(details)
+			defer func() {
+				createFlags.publish = nil
+				createFlags.publishFromContainer = false
+			}()
+
+			args, err := getPublishArgs()
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, args)
+		})
+	}
+}
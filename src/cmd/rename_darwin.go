@@ -0,0 +1,197 @@
+//go:build darwin
+
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/containers/toolbox/pkg/i18n"
+	"github.com/containers/toolbox/pkg/podman"
+	"github.com/containers/toolbox/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var renameCmd = &cobra.Command{
+	Use:               "rename",
+	Short:             "Rename a Toolbx container (macOS version)",
+	RunE:              rename,
+	ValidArgsFunction: completionContainerNameFirst,
+}
+
+func init() {
+	renameCmd.SetHelpFunc(renameHelp)
+	rootCmd.AddCommand(renameCmd)
+}
+
+// rename renames the container in place with 'podman rename', so that what
+// was installed in it is kept.  What Toolbx keeps about a container outside of
+// it, like the mark left by 'toolbox protect', the last-used time and the
+// per-container settings, goes by its ID and stays with it.  Only the alias on
+// the network of 'toolbox link' goes by its name, and is updated.
+func rename(cmd *cobra.Command, args []string) error {
+	if utils.IsInsideContainer() {
+		return errors.New("rename is not supported inside a container")
+	}
+
+	if len(args) != 2 {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "rename needs a container and a new name\n")
+		i18n.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	container := args[0]
+	newName := args[1]
+
+	if !utils.IsContainerNameValid(newName) {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "invalid argument for the new name: %s\n", newName)
+		fmt.Fprintf(&builder, "Container names must match '%s'.\n", utils.ContainerNameRegexp)
+		i18n.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	if newName == container {
+		return fmt.Errorf("container %s already has that name", container)
+	}
+
+	details, err := podman.InspectContainerDetails(container)
+	if err != nil {
+		return createErrorContainerNotFound(container)
+	}
+
+	if details.Config.Labels["com.github.containers.toolbox"] != "true" &&
+		details.Config.Labels["com.github.debarshiray.toolbox"] != "true" {
+		return fmt.Errorf("%s is not a Toolbx container", container)
+	}
+
+	containerObj, err := podman.InspectContainer(container)
+	if err != nil {
+		return createErrorContainerNotFound(container)
+	}
+
+	if err := checkContainerOwner(containerObj); err != nil {
+		return err
+	}
+
+	if exists, _ := podman.ContainerExists(newName); exists {
+		return fmt.Errorf("container %s already exists", newName)
+	}
+
+	if err := podman.Rename(container, newName); err != nil {
+		return fmt.Errorf("failed to rename container %s: %w", container, err)
+	}
+
+	renameLinkedContainer(details, newName)
+	renameSnapshots(container, newName)
+
+	showMessage("Renamed container %s to %s", container, newName)
+
+	// Podman sets the host name when the container is created, and can't
+	// change it afterwards.
+	if details.Config.Hostname == container {
+		showWarning("the host name of container %s stays %s, because Podman can't change it", newName, container)
+	}
+
+	return nil
+}
+
+func renameHelp(cmd *cobra.Command, args []string) {
+	if utils.IsInsideContainer() {
+		if !utils.IsInsideToolboxContainer() {
+			fmt.Fprintf(os.Stderr, "Error: this is not a Toolbx container\n")
+			return
+		}
+
+		if _, err := utils.ForwardToHost(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return
+		}
+
+		return
+	}
+
+	if err := showManual("toolbox-rename"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return
+	}
+}
+
+// renameLinkedContainer reconnects a container that was linked with 'toolbox
+// link', so that the other containers reach it by its new name.  The alias
+// can't be changed while it's connected.
+func renameLinkedContainer(details podman.ContainerDetails, newName string) {
+	container := details.Name
+
+	if _, linked := details.NetworkSettings.Networks[linkNetwork]; !linked {
+		logrus.Debugf("Container %s is not linked to other containers", container)
+		return
+	}
+
+	logrus.Debugf("Changing the alias of container %s on network %s to %s", container, linkNetwork, newName)
+
+	if err := podman.NetworkDisconnect(linkNetwork, newName); err != nil {
+		logrus.Debugf("Disconnecting container %s from network %s failed: %s", newName, linkNetwork, err)
+		showWarning("other containers still reach container %s as %s", newName, container)
+		return
+	}
+
+	if err := podman.NetworkConnect(linkNetwork, newName, newName); err != nil {
+		logrus.Debugf("Connecting container %s to network %s failed: %s", newName, linkNetwork, err)
+		showWarning("container %s is not linked to other containers any more", newName)
+		return
+	}
+
+	if details.State.Status == "running" {
+		updateLinkedHosts(newName)
+	}
+}
+
+// renameSnapshots moves the snapshots from 'toolbox snapshot' along with the
+// container, because they go by its name.
+func renameSnapshots(container, newName string) {
+	snapshots, err := getSnapshots(container)
+	if err != nil {
+		logrus.Debugf("Renaming the snapshots of container %s: %s", container, err)
+		return
+	}
+
+	for _, snapshot := range snapshots {
+		image := getSnapshotImage(container, snapshot.name)
+		newImage := getSnapshotImage(newName, snapshot.name)
+
+		if err := podman.Tag(image, newImage); err != nil {
+			logrus.Debugf("Tagging image %s as %s failed: %s", image, newImage, err)
+			showWarning("snapshot %s was not renamed", snapshot.name)
+			continue
+		}
+
+		if err := podman.RemoveImage(image, false); err != nil {
+			logrus.Debugf("Removing image %s failed: %s", image, err)
+		}
+	}
+}
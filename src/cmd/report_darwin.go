@@ -0,0 +1,527 @@
+//go:build darwin
+
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/briandowns/spinner"
+	"github.com/containers/toolbox/pkg/i18n"
+	"github.com/containers/toolbox/pkg/podman"
+	"github.com/containers/toolbox/pkg/toolbox"
+	"github.com/containers/toolbox/pkg/utils"
+	"github.com/docker/go-units"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+type hygieneFinding struct {
+	detail string
+	name   string
+}
+
+// hygieneReport is what 'toolbox report hygiene' found worth cleaning up.
+type hygieneReport struct {
+	driftedContainers  []hygieneFinding
+	machineDiskFree    uint64
+	machineDiskSize    uint64
+	outdatedContainers []hygieneFinding
+	reclaimable        []podman.DiskUsage
+	unusedContainers   []hygieneFinding
+	unusedImages       []hygieneFinding
+}
+
+const (
+	// machineDiskLowPercent is how much of the Podman machine's disk needs to
+	// be free before the report points it out.
+	machineDiskLowPercent = 10
+
+	reportHygieneLabel = "com.github.containers.toolbox.report-hygiene"
+)
+
+var (
+	reportHygieneFlags struct {
+		notify     bool
+		schedule   bool
+		unschedule bool
+		unusedDays int
+	}
+)
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Report on the state of Toolbx containers and images (macOS version)",
+	RunE:  report,
+}
+
+var reportHygieneCmd = &cobra.Command{
+	Use:   "hygiene",
+	Short: "Report unused containers, outdated images and reclaimable space",
+	RunE:  reportHygiene,
+}
+
+func init() {
+	flags := reportHygieneCmd.Flags()
+
+	flags.BoolVar(&reportHygieneFlags.notify,
+		"notify",
+		false,
+		"Show a notification instead of the report, if there is anything to clean up")
+
+	flags.BoolVar(&reportHygieneFlags.schedule,
+		"schedule",
+		false,
+		"Run the report every week with launchd, and notify about it")
+
+	flags.BoolVar(&reportHygieneFlags.unschedule,
+		"unschedule",
+		false,
+		"Stop running the report every week")
+
+	flags.IntVar(&reportHygieneFlags.unusedDays,
+		"unused-days",
+		30,
+		"Count containers that were not used for this many days as unused")
+
+	reportCmd.AddCommand(reportHygieneCmd)
+
+	reportCmd.SetHelpFunc(reportHelp)
+	rootCmd.AddCommand(reportCmd)
+}
+
+func report(cmd *cobra.Command, args []string) error {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "missing command for \"report\", eg., hygiene\n")
+	i18n.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+	errMsg := builder.String()
+	return errors.New(errMsg)
+}
+
+// reportHygiene nudges users to clean up before the disk of the Podman
+// machine fills up, because a full disk breaks every container at once, and
+// growing it is not always possible.  Nothing is removed.
+func reportHygiene(cmd *cobra.Command, args []string) error {
+	if utils.IsInsideContainer() {
+		return errors.New("report is not supported inside a container")
+	}
+
+	if len(args) != 0 {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "report hygiene does not take any arguments\n")
+		i18n.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	var modes int
+	for _, mode := range []bool{reportHygieneFlags.notify, reportHygieneFlags.schedule, reportHygieneFlags.unschedule} {
+		if mode {
+			modes++
+		}
+	}
+
+	if modes > 1 {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "options --notify, --schedule and --unschedule cannot be used together\n")
+		i18n.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	if reportHygieneFlags.unusedDays < 1 {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "invalid argument for '--unused-days'\n")
+		i18n.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	if reportHygieneFlags.schedule {
+		if err := scheduleReportHygiene(); err != nil {
+			return err
+		}
+
+		fmt.Println("The report will run every Monday at 10:00, and notify if there is anything to clean up")
+		return nil
+	}
+
+	if reportHygieneFlags.unschedule {
+		if err := uninstallLaunchAgent(reportHygieneLabel); err != nil {
+			return err
+		}
+
+		return nil
+	}
+
+	unusedFor := time.Duration(reportHygieneFlags.unusedDays) * 24 * time.Hour
+
+	var s *spinner.Spinner
+	if !reportHygieneFlags.notify {
+		s = showSpinner("Looking for things to clean up")
+	}
+
+	hygiene, err := getHygieneReport(unusedFor)
+	stopSpinner(s)
+
+	if err != nil {
+		return err
+	}
+
+	if reportHygieneFlags.notify {
+		notifyHygieneReport(hygiene)
+		return nil
+	}
+
+	showHygieneReport(os.Stdout, hygiene)
+	return nil
+}
+
+// getHygieneReport looks at all the Toolbx containers and images of the
+// user.  Containers that can't be inspected are skipped, so that one broken
+// container doesn't hide the rest.
+func getHygieneReport(unusedFor time.Duration) (hygieneReport, error) {
+	var hygiene hygieneReport
+
+	containers, err := getContainers()
+	if err != nil {
+		return hygieneReport{}, err
+	}
+
+	images, err := podman.GetImages()
+	if err != nil {
+		logrus.Debugf("Fetching all images failed: %s", err)
+		return hygieneReport{}, errors.New("failed to get images")
+	}
+
+	imageIDs := make(map[string]string)
+	for _, image := range images {
+		for _, flattenedImage := range image.FlattenNames(false) {
+			imageIDs[flattenedImage.Names[0]] = image.ID
+		}
+	}
+
+	for _, container := range containers {
+		name := container.Name()
+
+		details, err := podman.InspectContainerDetails(name)
+		if err != nil {
+			logrus.Debugf("Inspecting container %s failed: %s", name, err)
+			continue
+		}
+
+		if finding, ok := getUnusedContainerFinding(details, unusedFor); ok {
+			hygiene.unusedContainers = append(hygiene.unusedContainers, finding)
+		}
+
+		if imageID, ok := imageIDs[details.ImageName]; ok && imageID != details.Image {
+			detail := fmt.Sprintf("%s was updated since the container was created", details.ImageName)
+			finding := hygieneFinding{detail: detail, name: name}
+			hygiene.outdatedContainers = append(hygiene.outdatedContainers, finding)
+		}
+
+		if finding, ok := getDriftedContainerFinding(details); ok {
+			hygiene.driftedContainers = append(hygiene.driftedContainers, finding)
+		}
+	}
+
+	unusedImages, err := podman.GetImages("--filter", "label=com.github.containers.toolbox=true",
+		"--filter", "containers=false")
+	if err != nil {
+		logrus.Debugf("Fetching unused images failed: %s", err)
+	}
+
+	for _, image := range unusedImages {
+		for _, flattenedImage := range image.FlattenNames(true) {
+			finding := hygieneFinding{detail: "created " + image.Created, name: flattenedImage.Names[0]}
+			hygiene.unusedImages = append(hygiene.unusedImages, finding)
+		}
+	}
+
+	hygiene.reclaimable, err = podman.GetDiskUsage()
+	if err != nil {
+		logrus.Debugf("Getting the disk usage failed: %s", err)
+	}
+
+	hygiene.machineDiskSize, hygiene.machineDiskFree, err = getMachineDiskSpace()
+	if err != nil {
+		logrus.Debugf("Getting the disk space of the Podman machine failed: %s", err)
+	}
+
+	return hygiene, nil
+}
+
+// getDriftedContainerFinding checks if the container still matches how
+// 'toolbox create' would set it up now.  The toolbox binary is mounted by its
+// resolved path, which goes away when a package manager like Homebrew
+// upgrades it.
+func getDriftedContainerFinding(details podman.ContainerDetails) (hygieneFinding, bool) {
+	for _, mount := range details.Mounts {
+		if mount.Destination != "/usr/bin/toolbox" {
+			continue
+		}
+
+		if mount.Source == executable {
+			return hygieneFinding{}, false
+		}
+
+		var detail string
+		if _, err := os.Stat(mount.Source); err != nil {
+			detail = fmt.Sprintf("uses %s, which is gone", mount.Source)
+		} else {
+			detail = fmt.Sprintf("uses %s instead of %s", mount.Source, executable)
+		}
+
+		finding := hygieneFinding{detail: detail, name: details.Name}
+		return finding, true
+	}
+
+	finding := hygieneFinding{detail: "has no toolbox binary mounted", name: details.Name}
+	return finding, true
+}
+
+// getMachineDiskSpace returns the size of the disk of the Podman machine, and
+// how much of it is free, in bytes.
+func getMachineDiskSpace() (uint64, uint64, error) {
+	var stdout bytes.Buffer
+	if err := podman.MachineSSH(&stdout, "df", "-P", "-k", "/var"); err != nil {
+		return 0, 0, err
+	}
+
+	size, free, err := parseMachineDiskSpace(stdout.String())
+	return size, free, err
+}
+
+func getUnusedContainerFinding(details podman.ContainerDetails, unusedFor time.Duration) (hygieneFinding, bool) {
+	if details.State.Status == "running" {
+		return hygieneFinding{}, false
+	}
+
+	lastUsed := details.Created
+	detail := "never used, created " + utils.HumanDuration(details.Created.Unix())
+
+	if stampTime, ok := toolbox.GetLastUsed(details.ID); ok {
+		lastUsed = stampTime
+		detail = "last used " + utils.HumanDuration(lastUsed.Unix())
+	}
+
+	if time.Since(lastUsed) < unusedFor {
+		return hygieneFinding{}, false
+	}
+
+	finding := hygieneFinding{detail: detail, name: details.Name}
+	return finding, true
+}
+
+// notifyHygieneReport shows a notification in the Notification Center, for
+// when the report runs from launchd and there's no terminal to show it in.
+func notifyHygieneReport(hygiene hygieneReport) {
+	count := hygiene.count()
+	if count == 0 && !hygiene.isMachineDiskLow() {
+		logrus.Debug("Nothing to clean up, not notifying")
+		return
+	}
+
+	var message strings.Builder
+
+	if count != 0 {
+		fmt.Fprintf(&message, "%d things to clean up", count)
+		if reclaimable := hygiene.totalReclaimable(); reclaimable != 0 {
+			fmt.Fprintf(&message, ", %s reclaimable", units.HumanSize(float64(reclaimable)))
+		}
+		fmt.Fprintf(&message, ". ")
+	}
+
+	if hygiene.isMachineDiskLow() {
+		fmt.Fprintf(&message, "The Podman machine is running out of disk space. ")
+	}
+
+	fmt.Fprintf(&message, "Run 'toolbox report hygiene' for details.")
+
+	showNotification(message.String())
+}
+
+// parseMachineDiskSpace parses the output of 'df -P -k' for one file system.
+func parseMachineDiskSpace(output string) (uint64, uint64, error) {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) != 2 {
+		return 0, 0, errors.New("unexpected output from df(1)")
+	}
+
+	fields := strings.Fields(lines[1])
+	if len(fields) < 4 {
+		return 0, 0, errors.New("unexpected output from df(1)")
+	}
+
+	size, err := strconv.ParseUint(fields[1], 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to parse the size from df(1): %w", err)
+	}
+
+	free, err := strconv.ParseUint(fields[3], 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to parse the free space from df(1): %w", err)
+	}
+
+	return size * 1024, free * 1024, nil
+}
+
+func reportHelp(cmd *cobra.Command, args []string) {
+	if utils.IsInsideContainer() {
+		if !utils.IsInsideToolboxContainer() {
+			fmt.Fprintf(os.Stderr, "Error: this is not a Toolbx container\n")
+			return
+		}
+
+		if _, err := utils.ForwardToHost(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return
+		}
+
+		return
+	}
+
+	if err := showManual("toolbox-report"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return
+	}
+}
+
+// scheduleReportHygiene installs a launchd job that runs the report with
+// '--notify' every Monday morning.  launchd starts jobs with a minimal PATH,
+// so the current one is kept to find podman(1).
+func scheduleReportHygiene() error {
+	monday := 1
+
+	arguments := []string{executable, "report", "hygiene", "--notify"}
+	if reportHygieneFlags.unusedDays != 30 {
+		arguments = append(arguments, "--unused-days", strconv.Itoa(reportHygieneFlags.unusedDays))
+	}
+
+	agent := launchAgent{
+		arguments:   arguments,
+		environment: map[string]string{"PATH": os.Getenv("PATH")},
+		hour:        10,
+		label:       reportHygieneLabel,
+		weekday:     &monday,
+	}
+
+	if err := installLaunchAgent(agent); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func showHygieneFindings(writer io.Writer, title string, findings []hygieneFinding) {
+	if len(findings) == 0 {
+		return
+	}
+
+	fmt.Fprintf(writer, "%s:\n", title)
+	for _, finding := range findings {
+		fmt.Fprintf(writer, "  %s\t%s\n", finding.name, finding.detail)
+	}
+
+	fmt.Fprintf(writer, "\n")
+}
+
+func showHygieneReport(w io.Writer, hygiene hygieneReport) {
+	writer := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+
+	unusedTitle := fmt.Sprintf("Containers not used for %d days", reportHygieneFlags.unusedDays)
+	showHygieneFindings(writer, unusedTitle, hygiene.unusedContainers)
+	showHygieneFindings(writer, "Containers on outdated images", hygiene.outdatedContainers)
+	showHygieneFindings(writer, "Toolbx images not used by any container", hygiene.unusedImages)
+	showHygieneFindings(writer, "Containers that drifted from 'toolbox create'", hygiene.driftedContainers)
+
+	if hygiene.totalReclaimable() != 0 {
+		fmt.Fprintf(writer, "Reclaimable space:\n")
+		for _, usage := range hygiene.reclaimable {
+			if usage.RawReclaimable == 0 {
+				continue
+			}
+
+			reclaimable := units.HumanSize(float64(usage.RawReclaimable))
+			size := units.HumanSize(float64(usage.RawSize))
+			fmt.Fprintf(writer, "  %s\t%s of %s\n", usage.Type, reclaimable, size)
+		}
+
+		fmt.Fprintf(writer, "\n")
+	}
+
+	if hygiene.machineDiskSize != 0 {
+		free := units.HumanSize(float64(hygiene.machineDiskFree))
+		size := units.HumanSize(float64(hygiene.machineDiskSize))
+		fmt.Fprintf(writer, "Podman machine disk:\t%s free of %s\n", free, size)
+		fmt.Fprintf(writer, "\n")
+	}
+
+	writer.Flush()
+
+	if hygiene.count() == 0 && hygiene.totalReclaimable() == 0 {
+		fmt.Fprintf(w, "Nothing to clean up\n")
+		return
+	}
+
+	fmt.Fprintf(w, "Use 'toolbox rm' and 'toolbox rmi' to remove what isn't needed any more,\n")
+	fmt.Fprintf(w, "and 'podman system prune' to reclaim the rest of the space.\n")
+
+	if hygiene.isMachineDiskLow() {
+		fmt.Fprintf(w, "The Podman machine is running out of disk space.\n")
+	}
+}
+
+func (hygiene hygieneReport) count() int {
+	count := len(hygiene.driftedContainers) +
+		len(hygiene.outdatedContainers) +
+		len(hygiene.unusedContainers) +
+		len(hygiene.unusedImages)
+
+	return count
+}
+
+func (hygiene hygieneReport) isMachineDiskLow() bool {
+	if hygiene.machineDiskSize == 0 {
+		return false
+	}
+
+	low := hygiene.machineDiskFree*100 < hygiene.machineDiskSize*machineDiskLowPercent
+	return low
+}
+
+func (hygiene hygieneReport) totalReclaimable() int64 {
+	var total int64
+	for _, usage := range hygiene.reclaimable {
+		total += usage.RawReclaimable
+	}
+
+	return total
+}
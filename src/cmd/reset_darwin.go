@@ -0,0 +1,356 @@
+//go:build darwin
+
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/containers/toolbox/pkg/i18n"
+	"github.com/containers/toolbox/pkg/podman"
+	"github.com/containers/toolbox/pkg/toolbox"
+	"github.com/containers/toolbox/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	resetFlags struct {
+		forceProtected bool
+		keepImages     bool
+	}
+)
+
+var resetCmd = &cobra.Command{
+	Use:   "reset",
+	Short: "Remove all Toolbx containers, images and state (macOS version)",
+	RunE:  reset,
+}
+
+func init() {
+	flags := resetCmd.Flags()
+
+	flags.BoolVar(&resetFlags.forceProtected,
+		"force-protected",
+		false,
+		"Remove the containers protected with 'toolbox protect' too")
+
+	flags.BoolVar(&resetFlags.keepImages,
+		"keep-images",
+		false,
+		"Keep the Toolbx images, instead of removing them")
+
+	resetCmd.SetHelpFunc(resetHelp)
+	rootCmd.AddCommand(resetCmd)
+}
+
+// reset carries on past every failure, because it's meant for when something
+// is already broken, eg., a wedged Podman machine that can't list its
+// containers.  The state on the host is removed regardless, but the command
+// still fails at the end, so that the user knows that something was left
+// behind.
+func reset(cmd *cobra.Command, args []string) error {
+	if utils.IsInsideContainer() {
+		return errors.New("reset is not supported inside a container")
+	}
+
+	if len(args) != 0 {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "too many arguments for \"reset\"\n")
+		i18n.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	directories, err := getResetDirectories()
+	if err != nil {
+		return err
+	}
+
+	stateDirectories, err := getResetStateDirectories()
+	if err != nil {
+		return err
+	}
+
+	directories = append(directories, stateDirectories...)
+
+	allContainers, err := getContainers()
+	if err != nil {
+		showWarning("%s, they will be left behind", err)
+	}
+
+	var containers []podman.Container
+	var protectedContainers []podman.Container
+
+	for _, container := range allContainers {
+		if !resetFlags.forceProtected && toolbox.IsProtected(container) {
+			protectedContainers = append(protectedContainers, container)
+			continue
+		}
+
+		containers = append(containers, container)
+	}
+
+	var images []podman.Image
+	if !resetFlags.keepImages {
+		if images, err = getImages(false); err != nil {
+			showWarning("%s, they will be left behind", err)
+		}
+
+		images = getResetImages(images, protectedContainers)
+	}
+
+	if !rootFlags.assumeYes {
+		var builder strings.Builder
+		if resetFlags.keepImages {
+			i18n.Fprintf(&builder,
+				"This will remove %d containers and %s.\n",
+				len(containers),
+				strings.Join(directories, ", "))
+		} else {
+			i18n.Fprintf(&builder,
+				"This will remove %d containers, %d images and %s.\n",
+				len(containers),
+				len(images),
+				strings.Join(directories, ", "))
+		}
+
+		if len(protectedContainers) != 0 {
+			i18n.Fprintf(&builder,
+				"%d protected containers will be kept: %s\n",
+				len(protectedContainers),
+				strings.Join(getContainerNames(protectedContainers), ", "))
+		}
+
+		i18n.Fprintf(&builder, "Reset Toolbx? [y/N]: ")
+
+		prompt := builder.String()
+		if !askForConfirmation(prompt) {
+			return nil
+		}
+	}
+
+	failed := false
+	options := toolbox.RemoveOptions{
+		Force:          true,
+		ForceProtected: resetFlags.forceProtected,
+		Owner:          getRemoveOwner(),
+	}
+
+	for _, container := range containers {
+		if err := toolbox.Remove(container, options); err != nil {
+			if errors.Is(err, toolbox.ErrContainerProtected) {
+				protectedContainers = append(protectedContainers, container)
+				continue
+			}
+
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			failed = true
+			continue
+		}
+
+		showMessage("Removed container %s", container.Name())
+	}
+
+	for _, image := range images {
+		if err := podman.RemoveImage(image.ID, true); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			failed = true
+			continue
+		}
+
+		imageName := image.ID
+		if len(image.Names) != 0 {
+			imageName = image.Names[0]
+		}
+
+		showMessage("Removed image %s", imageName)
+	}
+
+	keep := make(map[string]struct{})
+	for _, container := range protectedContainers {
+		keep[container.ID()] = struct{}{}
+	}
+
+	for _, directory := range directories {
+		removeAll := os.RemoveAll
+		if len(keep) != 0 && slices.Contains(stateDirectories, directory) {
+			removeAll = func(directory string) error {
+				return removeResetStateDirectory(directory, keep)
+			}
+		}
+
+		if err := removeAll(directory); err != nil {
+			logrus.Debugf("Removing %s failed: %s", directory, err)
+			fmt.Fprintf(os.Stderr, "Error: failed to remove %s\n", directory)
+			failed = true
+			continue
+		}
+
+		showMessage("Removed %s", directory)
+	}
+
+	if len(protectedContainers) != 0 {
+		showMessage("Kept protected containers: %s",
+			strings.Join(getContainerNames(protectedContainers), ", "))
+		showMessage("Use '--force-protected' to remove them too.")
+	}
+
+	if failed {
+		return errors.New("failed to reset Toolbx completely")
+	}
+
+	return nil
+}
+
+// getResetDirectories returns where Toolbx keeps its state on the host, other
+// than the per-container state from getResetStateDirectories.  It's not
+// toolbox.conf(5), because that's written by the user.
+func getResetDirectories() ([]string, error) {
+	homeDir := getCurrentUserHomeDir()
+	if homeDir == "" {
+		return nil, errors.New("failed to find the home directory")
+	}
+
+	userConfigDir, err := os.UserConfigDir()
+	if err != nil {
+		logrus.Debugf("Resetting Toolbx: failed to get the user config directory: %s", err)
+		return nil, errors.New("failed to get the user config directory")
+	}
+
+	toolboxRuntimeDirectory, err := utils.GetRuntimeDirectory(currentUser)
+	if err != nil {
+		return nil, err
+	}
+
+	directories := []string{
+		filepath.Join(userConfigDir, "toolbox"),
+		toolboxRuntimeDirectory,
+	}
+
+	return directories, nil
+}
+
+// getResetStateDirectories returns where Toolbx keeps the state of each
+// container on the host, with one entry named after the container's ID.  The
+// shares directory next to them is left alone, because it has the user's own
+// files moved there by 'toolbox share-path'.
+func getResetStateDirectories() ([]string, error) {
+	homeDir := getCurrentUserHomeDir()
+	if homeDir == "" {
+		return nil, errors.New("failed to find the home directory")
+	}
+
+	dataDir := filepath.Join(homeDir, ".local", "share", "toolbox")
+
+	directories := []string{
+		filepath.Join(dataDir, "config"),
+		filepath.Join(dataDir, "last-used"),
+		filepath.Join(dataDir, "protected"),
+	}
+
+	return directories, nil
+}
+
+func getContainerNames(containers []podman.Container) []string {
+	names := make([]string, 0, len(containers))
+	for _, container := range containers {
+		names = append(names, container.Name())
+	}
+
+	return names
+}
+
+// getResetImages leaves out the images used by the kept containers, because
+// forcibly removing an image removes its containers too.
+func getResetImages(images []podman.Image, keptContainers []podman.Container) []podman.Image {
+	if len(keptContainers) == 0 {
+		return images
+	}
+
+	var resetImages []podman.Image
+
+	for _, image := range images {
+		used := false
+		for _, container := range keptContainers {
+			if container.Image() == image.ID || slices.Contains(image.Names, container.Image()) {
+				used = true
+				break
+			}
+		}
+
+		if !used {
+			resetImages = append(resetImages, image)
+		}
+	}
+
+	return resetImages
+}
+
+// removeResetStateDirectory removes the state of all containers in the
+// directory, other than the ones whose IDs are in keep.
+func removeResetStateDirectory(directory string, keep map[string]struct{}) error {
+	entries, err := os.ReadDir(directory)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+
+		return err
+	}
+
+	for _, entry := range entries {
+		id := strings.TrimSuffix(entry.Name(), ".json")
+		if _, ok := keep[id]; ok {
+			continue
+		}
+
+		if err := os.RemoveAll(filepath.Join(directory, entry.Name())); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func resetHelp(cmd *cobra.Command, args []string) {
+	if utils.IsInsideContainer() {
+		if !utils.IsInsideToolboxContainer() {
+			fmt.Fprintf(os.Stderr, "Error: this is not a Toolbx container\n")
+			return
+		}
+
+		if _, err := utils.ForwardToHost(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return
+		}
+
+		return
+	}
+
+	if err := showManual("toolbox-reset"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return
+	}
+}
@@ -0,0 +1,202 @@
+//go:build darwin
+
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/containers/toolbox/pkg/podman"
+	"github.com/containers/toolbox/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+const (
+	// entitlementDirectory is where UBI images look for the entitlement
+	// certificates of the host, and where Podman puts them on RHEL.
+	entitlementDirectory = "/run/secrets/etc-pki-entitlement"
+
+	entitlementLabel = "com.github.containers.toolbox.entitlement"
+)
+
+// isRHELImage checks if image is one of the RHEL or UBI images of Toolbx,
+// which need a subscription for the RHEL repositories beyond UBI.
+func isRHELImage(image string) bool {
+	distro, _ := utils.GetDistroAndReleaseForImage(image)
+	return distro == "rhel"
+}
+
+// getEntitlementDirectory returns the directory with the entitlement
+// certificates given with '--entitlement', or 'entitlement' in the rhel
+// section of the configuration.
+func getEntitlementDirectory() (string, error) {
+	directory := createFlags.entitlement
+	if directory == "" {
+		directory = viper.GetString("rhel.entitlement")
+	}
+
+	if directory == "" {
+		return "", nil
+	}
+
+	directory = expandHomeDir(directory)
+
+	directoryAbs, err := filepath.Abs(directory)
+	if err != nil {
+		return "", fmt.Errorf("failed to get the absolute path of %s: %w", directory, err)
+	}
+
+	return directoryAbs, nil
+}
+
+// checkEntitlementDirectory makes sure that directory has an entitlement
+// certificate and its key, like /etc/pki/entitlement on a subscribed RHEL
+// system, because dnf(8) silently ignores the RHEL repositories otherwise.
+func checkEntitlementDirectory(directory string) error {
+	entries, err := os.ReadDir(directory)
+	if err != nil {
+		return fmt.Errorf("failed to read entitlement certificates from %s: %w", directory, err)
+	}
+
+	var foundCertificate, foundKey bool
+
+	for _, entry := range entries {
+		name := entry.Name()
+		switch {
+		case entry.IsDir():
+			continue
+		case strings.HasSuffix(name, "-key.pem"):
+			foundKey = true
+		case strings.HasSuffix(name, ".pem"):
+			foundCertificate = true
+		}
+	}
+
+	if foundCertificate && foundKey {
+		return nil
+	}
+
+	var builder strings.Builder
+
+	if !foundCertificate {
+		fmt.Fprintf(&builder, "no entitlement certificate in %s\n", directory)
+	} else {
+		fmt.Fprintf(&builder, "no key for the entitlement certificate in %s\n", directory)
+	}
+
+	fmt.Fprintf(&builder, "Download them from the Red Hat Customer Portal, or copy /etc/pki/entitlement from a subscribed RHEL system.")
+
+	errMsg := builder.String()
+	return errors.New(errMsg)
+}
+
+// getEntitlementArgs returns the options for 'podman create' that share the
+// entitlement certificates with a RHEL container.  A Mac has no subscription
+// of its own that the container could use, unlike a RHEL host.
+func getEntitlementArgs(image string) ([]string, error) {
+	directory, err := getEntitlementDirectory()
+	if err != nil {
+		return nil, err
+	}
+
+	if directory == "" {
+		return nil, nil
+	}
+
+	if !isRHELImage(image) {
+		if createFlags.entitlement != "" {
+			return nil, fmt.Errorf("image %s is not RHEL, and only RHEL uses entitlement certificates", image)
+		}
+
+		logrus.Debugf("Not sharing entitlement certificates with image %s", image)
+		return nil, nil
+	}
+
+	if err := checkEntitlementDirectory(directory); err != nil {
+		return nil, err
+	}
+
+	logrus.Debugf("Sharing entitlement certificates from %s", directory)
+
+	args := []string{
+		"--label", entitlementLabel + "=" + directory,
+		"--volume", directory + ":" + entitlementDirectory + ":ro",
+	}
+
+	return args, nil
+}
+
+// setUpSubscription registers a RHEL container with Red Hat Subscription
+// Management using the activation key in the rhel section of the
+// configuration, because the container can't use the subscription of the
+// host, like it does on RHEL.  Without an activation key or entitlement
+// certificates, it only points out how to register, if showHint is set.
+func setUpSubscription(container, image string, showHint bool) error {
+	if !isRHELImage(image) {
+		return nil
+	}
+
+	if directory, err := getEntitlementDirectory(); err != nil || directory != "" {
+		return nil
+	}
+
+	organization := viper.GetString("rhel.organization")
+	activationKey := viper.GetString("rhel.activation-key")
+
+	if organization == "" || activationKey == "" {
+		if showHint {
+			showMessage("Container %s is not subscribed, so only the UBI repositories are available.",
+				container)
+			showMessage("Register it with: toolbox run --container %s sudo subscription-manager register",
+				container)
+		}
+
+		return nil
+	}
+
+	if isOffline() {
+		logrus.Debugf("Not registering container %s offline", container)
+		return nil
+	}
+
+	if err := startContainerAndWait(container); err != nil {
+		return warnOrFail(err)
+	}
+
+	s := showSpinner(fmt.Sprintf("Registering container %s with Red Hat Subscription Management", container))
+	err := podman.ExecAsRoot(container,
+		nil,
+		"subscription-manager",
+		"register",
+		"--org", organization,
+		"--activationkey", activationKey)
+	stopSpinner(s)
+
+	if err != nil {
+		logrus.Debugf("Registering container %s failed: %s", container, err)
+		err := fmt.Errorf("failed to register container %s with Red Hat Subscription Management", container)
+		return warnOrFail(err)
+	}
+
+	return nil
+}
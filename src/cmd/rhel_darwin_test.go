@@ -0,0 +1,84 @@
+//go:build darwin
+
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckEntitlementDirectory(t *testing.T) {
+	testCases := []struct {
+		name  string
+		files []string
+		err   string
+	}{
+		{
+			name:  "certificate and key",
+			files: []string{"1234.pem", "1234-key.pem"},
+		},
+		{
+			name: "empty",
+			err:  "no entitlement certificate in ",
+		},
+		{
+			name:  "key only",
+			files: []string{"1234-key.pem"},
+			err:   "no entitlement certificate in ",
+		},
+		{
+			name:  "certificate only",
+			files: []string{"1234.pem", "README"},
+			err:   "no key for the entitlement certificate in ",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			directory := t.TempDir()
+			for _, file := range tc.files {
+				err := os.WriteFile(filepath.Join(directory, file), nil, 0600)
+				require.NoError(t, err)
+			}
+
+			err := checkEntitlementDirectory(directory)
+			if tc.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.err+directory)
+		})
+	}
+
+	err := checkEntitlementDirectory(filepath.Join(t.TempDir(), "missing"))
+	assert.Error(t, err)
+}
+
+func TestIsRHELImage(t *testing.T) {
+	assert.True(t, isRHELImage("registry.access.redhat.com/ubi9/toolbox:9.5"))
+	assert.True(t, isRHELImage("registry.access.redhat.com/ubi8/toolbox:8.10"))
+	assert.False(t, isRHELImage("registry.fedoraproject.org/fedora-toolbox:42"))
+	assert.False(t, isRHELImage("quay.io/toolbx/ubuntu-toolbox:24.04"))
+}
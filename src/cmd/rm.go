@@ -23,14 +23,16 @@ import (
 	"strings"
 
 	"github.com/containers/toolbox/pkg/podman"
+	"github.com/containers/toolbox/pkg/toolbox"
 	"github.com/containers/toolbox/pkg/utils"
 	"github.com/spf13/cobra"
 )
 
 var (
 	rmFlags struct {
-		deleteAll   bool
-		forceDelete bool
+		deleteAll      bool
+		forceDelete    bool
+		forceProtected bool
 	}
 )
 
@@ -52,6 +54,11 @@ func init() {
 		false,
 		"Force the removal of running and paused Toolbx containers")
 
+	flags.BoolVar(&rmFlags.forceProtected,
+		"force-protected",
+		false,
+		"Remove Toolbx containers even if they are protected")
+
 	rmCmd.SetHelpFunc(rmHelp)
 	rootCmd.AddCommand(rmCmd)
 }
@@ -66,6 +73,12 @@ func rm(cmd *cobra.Command, args []string) error {
 		return &exitError{exitCode, err}
 	}
 
+	options := toolbox.RemoveOptions{
+		Force:          rmFlags.forceDelete,
+		ForceProtected: rmFlags.forceProtected,
+		Owner:          getRemoveOwner(),
+	}
+
 	if rmFlags.deleteAll {
 		toolboxContainers, err := getContainers()
 		if err != nil {
@@ -73,11 +86,17 @@ func rm(cmd *cobra.Command, args []string) error {
 		}
 
 		for _, container := range toolboxContainers {
-			containerID := container.ID()
-			if err := podman.RemoveContainer(containerID, rmFlags.forceDelete); err != nil {
-				fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			if err := toolbox.Remove(container, options); err != nil {
+				if errors.Is(err, toolbox.ErrContainerProtected) {
+					showWarning("skipping protected container %s", container.Name())
+				} else {
+					fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+				}
+
 				continue
 			}
+
+			forgetContainerSettings(container)
 		}
 	} else {
 		if len(args) == 0 {
@@ -96,15 +115,22 @@ func rm(cmd *cobra.Command, args []string) error {
 				continue
 			}
 
-			if !containerObj.IsToolbx() {
-				fmt.Fprintf(os.Stderr, "Error: %s is not a Toolbx container\n", container)
+			if err := checkContainerOwner(containerObj); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %s\n", err)
 				continue
 			}
 
-			if err := podman.RemoveContainer(container, rmFlags.forceDelete); err != nil {
-				fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			if err := toolbox.Remove(containerObj, options); err != nil {
+				if errors.Is(err, toolbox.ErrContainerProtected) {
+					fmt.Fprintf(os.Stderr, "Error: container %s is protected, use '--force-protected' to remove it\n", container)
+				} else {
+					fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+				}
+
 				continue
 			}
+
+			forgetContainerSettings(containerObj)
 		}
 	}
 
@@ -22,10 +22,14 @@ import (
 	"os"
 	"os/user"
 	"path/filepath"
+	"runtime/debug"
+	"strconv"
 	"strings"
 
 	"github.com/containers/toolbox/pkg/nvidia"
 	"github.com/containers/toolbox/pkg/podman"
+	"github.com/containers/toolbox/pkg/shell"
+	"github.com/containers/toolbox/pkg/toolbox"
 	"github.com/containers/toolbox/pkg/utils"
 	"github.com/containers/toolbox/pkg/version"
 	"github.com/sirupsen/logrus"
@@ -50,15 +54,30 @@ var (
 	}
 
 	rootFlags struct {
-		assumeYes bool
-		logLevel  string
-		logPodman bool
-		verbose   int
+		accessible bool
+		assumeYes  bool
+		color      string
+		logFormat  string
+		logLevel   string
+		logPodman  bool
+		quiet      bool
+		strict     bool
+		system     bool
+		verbose    int
 	}
 
 	workingDirectory string
 )
 
+// The exit codes of Toolbx itself, as documented in toolbox(1).  Commands that
+// run something inside a container, like 'toolbox run', also use 125, 126, 127
+// and the exit code of what they ran.
+const (
+	exitCodeError    = 1
+	exitCodeUsage    = 2
+	exitCodeInternal = 70
+)
+
 type exitError struct {
 	code int
 	err  error
@@ -73,22 +92,62 @@ func (e *exitError) Error() string {
 }
 
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
-		if rootCmd.SilenceErrors {
-			if errMsg := err.Error(); errMsg != "" {
-				fmt.Fprintf(os.Stderr, "Error: %s\n", errMsg)
-			}
-		}
+	if err := runPlugin(os.Args[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		os.Exit(exitCodeError)
+	}
 
-		var errExit *exitError
-		if errors.As(err, &errExit) {
-			os.Exit(errExit.code)
-		}
+	err := executeRootCmd()
+	if err == nil {
+		os.Exit(0)
+	}
 
-		os.Exit(1)
+	// Cobra already showed the error, because it's about the command line,
+	// and was found before preRun.
+	if !rootCmd.SilenceErrors {
+		os.Exit(exitCodeUsage)
+	}
+
+	if errMsg := err.Error(); errMsg != "" && !logErrorJSON(errMsg) {
+		logErrorToFile(errMsg)
+		fmt.Fprintf(os.Stderr, "Error: %s\n", errMsg)
 	}
 
-	os.Exit(0)
+	var errExit *exitError
+	if errors.As(err, &errExit) {
+		os.Exit(errExit.code)
+	}
+
+	os.Exit(exitCodeError)
+}
+
+func createErrorInternal(recovered interface{}) error {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "internal error: %v\n", recovered)
+	fmt.Fprintf(&builder, "This is a bug in Toolbx. %s", getBugReportHint())
+
+	errMsg := builder.String()
+	return &exitError{exitCodeInternal, errors.New(errMsg)}
+}
+
+// executeRootCmd turns a panic into an internal error, so that users see what
+// to do about it instead of a Go stack trace.  The stack trace is still
+// logged at the debug level.
+func executeRootCmd() (err error) {
+	defer func() {
+		recovered := recover()
+		if recovered == nil {
+			return
+		}
+
+		logrus.Debugf("Recovered from a panic: %v\n%s", recovered, debug.Stack())
+
+		rootCmd.SilenceErrors = true
+		err = createErrorInternal(recovered)
+	}()
+
+	err = rootCmd.Execute()
+	return err
 }
 
 func init() {
@@ -99,12 +158,27 @@ func init() {
 
 	persistentFlags := rootCmd.PersistentFlags()
 
+	persistentFlags.BoolVar(&rootFlags.accessible,
+		"accessible",
+		false,
+		"Show progress as plain lines with timestamps, without spinners or colors")
+
 	persistentFlags.BoolVarP(&rootFlags.assumeYes,
 		"assumeyes",
 		"y",
 		false,
 		"Automatically answer yes for all questions")
 
+	persistentFlags.StringVar(&rootFlags.color,
+		"color",
+		"auto",
+		"Use colors and spinners: auto, always or never")
+
+	persistentFlags.StringVar(&rootFlags.logFormat,
+		"log-format",
+		"text",
+		"Log messages in the specified format: text or json")
+
 	persistentFlags.StringVar(&rootFlags.logLevel,
 		"log-level",
 		"error",
@@ -115,8 +189,34 @@ func init() {
 		false,
 		"Show the log output of Podman. The log level is handled by the log-level option")
 
+	persistentFlags.BoolVarP(&rootFlags.quiet,
+		"quiet",
+		"q",
+		false,
+		"Only show essential output and errors, without progress, warnings or hints")
+
+	persistentFlags.BoolVar(&rootFlags.strict,
+		"strict",
+		false,
+		"Fail instead of warning when something doesn't work as well as it should")
+
+	persistentFlags.BoolVar(&rootFlags.system,
+		"system",
+		false,
+		"Use the shared Podman machine of this computer instead of your own")
+
 	persistentFlags.CountVarP(&rootFlags.verbose, "verbose", "v", "Set log-level to 'debug'")
 
+	if err := rootCmd.RegisterFlagCompletionFunc("color", completionColorModes); err != nil {
+		panicMsg := fmt.Sprintf("failed to register flag completion function: %v", err)
+		panic(panicMsg)
+	}
+
+	if err := rootCmd.RegisterFlagCompletionFunc("log-format", completionLogFormats); err != nil {
+		panicMsg := fmt.Sprintf("failed to register flag completion function: %v", err)
+		panic(panicMsg)
+	}
+
 	if err := rootCmd.RegisterFlagCompletionFunc("log-level", completionLogLevels); err != nil {
 		panicMsg := fmt.Sprintf("failed to register flag completion function: %v", err)
 		panic(panicMsg)
@@ -132,10 +232,22 @@ func preRun(cmd *cobra.Command, args []string) error {
 	cmd.Root().SilenceErrors = true
 	cmd.Root().SilenceUsage = true
 
+	logFields.setCommand(cmd.CommandPath())
+
+	if err := setUpColor(); err != nil {
+		return err
+	}
+
 	if err := setUpLoggers(); err != nil {
 		return err
 	}
 
+	if err := setUpAssumeYes(cmd); err != nil {
+		return err
+	}
+
+	setUpLanguage()
+
 	logrus.Debugf("Running as real user ID %s", currentUser.Uid)
 	logrus.Debugf("Resolved absolute path to the executable as %s", executable)
 
@@ -170,11 +282,27 @@ func preRun(cmd *cobra.Command, args []string) error {
 
 	logrus.Debugf("TOOLBOX_PATH is %s", toolboxPath)
 
-	if err := migrate(cmd, args); err != nil {
+	if err := utils.SetUpConfiguration(); err != nil {
 		return err
 	}
 
-	if err := utils.SetUpConfiguration(); err != nil {
+	if err := setUpStrictMode(); err != nil {
+		return err
+	}
+
+	// This picks the Podman machine, so it must come before anything
+	// that talks to Podman.
+	if err := setUpSystemMode(); err != nil {
+		return err
+	}
+
+	if !utils.IsInsideContainer() {
+		if err := startAutoStoppedMachine(cmd); err != nil {
+			return err
+		}
+	}
+
+	if err := migrate(cmd, args); err != nil {
 		return err
 	}
 
@@ -252,6 +380,7 @@ func setUpGlobals() error {
 	}
 
 	executableBase = filepath.Base(executable)
+	toolbox.SetExecutable(executable)
 
 	workingDirectory, err = os.Getwd()
 	if err != nil {
@@ -261,11 +390,58 @@ func setUpGlobals() error {
 	return nil
 }
 
+// setUpAssumeYes lets TOOLBOX_ASSUME_YES stand in for '--assumeyes' in
+// scripts, like installers and CI jobs, that run many commands.  The option
+// takes precedence over the environment variable.
+func setUpAssumeYes(cmd *cobra.Command) error {
+	if flag := cmd.Flag("assumeyes"); flag != nil && flag.Changed {
+		return nil
+	}
+
+	value, found := os.LookupEnv("TOOLBOX_ASSUME_YES")
+	if !found || value == "" {
+		return nil
+	}
+
+	logrus.Debugf("TOOLBOX_ASSUME_YES is %s", value)
+
+	assumeYes, err := strconv.ParseBool(value)
+	if err != nil {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "invalid value for TOOLBOX_ASSUME_YES: %s\n", value)
+		fmt.Fprintf(&builder, "It must be a boolean, like 1, true, 0 or false.")
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	rootFlags.assumeYes = assumeYes
+	return nil
+}
+
 func setUpLoggers() error {
 	logrus.SetOutput(os.Stderr)
-	logrus.SetFormatter(&logrus.TextFormatter{
-		DisableTimestamp: true,
-	})
+
+	switch rootFlags.logFormat {
+	case "json":
+		logrus.SetFormatter(&logrus.JSONFormatter{})
+		logrus.AddHook(logFields)
+	case "text":
+		useColor := shouldUseColor(os.Stderr)
+		logrus.SetFormatter(&logrus.TextFormatter{
+			DisableColors:    !useColor,
+			DisableTimestamp: true,
+			ForceColors:      useColor,
+		})
+	default:
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "invalid argument for '--log-format': %s\n", rootFlags.logFormat)
+		fmt.Fprintf(&builder, "Supported values are: %s\n", strings.Join(logFormats, ", "))
+		fmt.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
 
 	if rootFlags.verbose > 0 {
 		rootFlags.logLevel = "debug"
@@ -277,6 +453,10 @@ func setUpLoggers() error {
 	}
 
 	logrus.SetLevel(logLevel)
+	shell.SetLogLevel(logLevel)
+	terminalLogLevel = logLevel
+
+	setUpLogFile()
 
 	if rootFlags.verbose > 1 {
 		nvidia.SetLogLevel(logLevel)
@@ -68,7 +68,7 @@ func rootRunImpl(cmd *cobra.Command, args []string) error {
 
 	command := []string{userShell, "-l"}
 
-	if err := runCommand(container, true, image, release, 0, command, true, true, false); err != nil {
+	if err := runCommand(container, true, image, release, 0, "", "", command, nil, true, true, false); err != nil {
 		return err
 	}
 
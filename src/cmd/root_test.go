@@ -73,3 +73,13 @@ func TestExitError(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateErrorInternal(t *testing.T) {
+	err := createErrorInternal("unexpected *errors.errorString: oops")
+	var errExit *exitError
+
+	assert.ErrorAs(t, err, &errExit)
+	assert.Equal(t, exitCodeInternal, errExit.code)
+	assert.Contains(t, errExit.Error(), "internal error: unexpected *errors.errorString: oops\n")
+	assert.Contains(t, errExit.Error(), getBugReportHint())
+}
@@ -25,6 +25,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 	"syscall"
@@ -34,6 +35,7 @@ import (
 	"github.com/containers/toolbox/pkg/podman"
 	"github.com/containers/toolbox/pkg/shell"
 	"github.com/containers/toolbox/pkg/term"
+	"github.com/containers/toolbox/pkg/toolbox"
 	"github.com/containers/toolbox/pkg/utils"
 	"github.com/fsnotify/fsnotify"
 	"github.com/go-logfmt/logfmt"
@@ -53,13 +55,28 @@ type entryPointError struct {
 var (
 	runFlags struct {
 		container   string
+		detach      bool
 		distro      string
+		env         []string
+		envFile     []string
+		ephemeral   bool
+		file        string
+		image       string
+		name        string
 		preserveFDs uint
 		release     string
+		secrets     []string
+		shell       string
+		user        string
+		workDir     string
 	}
 
-	runFallbackCommands = [][]string{{"/bin/bash", "-l"}}
+	runFallbackCommands = [][]string{{"/bin/bash", "-l"}, {"/bin/sh", "-l"}}
 	runFallbackWorkDirs = []string{"" /* $HOME */}
+
+	// userEnvironmentVariables describe the user invoking Toolbx, and are
+	// not forwarded when running a command as a different user.
+	userEnvironmentVariables = []string{"HOME", "LOGNAME", "MAIL", "SHELL", "USER"}
 )
 
 var runCmd = &cobra.Command{
@@ -79,12 +96,50 @@ func init() {
 		"",
 		"Run command inside a Toolbx container with the given name")
 
+	flags.BoolVar(&runFlags.detach,
+		"detach",
+		false,
+		"Run command in the background, and follow it later with 'toolbox attach'")
+
 	flags.StringVarP(&runFlags.distro,
 		"distro",
 		"d",
 		"",
 		"Run command inside a Toolbx container for a different operating system distribution than the host")
 
+	flags.StringArrayVarP(&runFlags.env,
+		"env",
+		"e",
+		nil,
+		"Set this environment variable, as KEY=VALUE or KEY to use the value from the host")
+
+	flags.StringArrayVar(&runFlags.envFile,
+		"env-file",
+		nil,
+		"Set the environment variables in this file, which has a KEY=VALUE or KEY on each line")
+
+	flags.BoolVar(&runFlags.ephemeral,
+		"ephemeral",
+		false,
+		"Run command inside a new Toolbx container, which is removed afterwards")
+
+	flags.StringVarP(&runFlags.file,
+		"file",
+		"f",
+		"",
+		"Run this script from the host, or - for standard input, passing it the arguments")
+
+	flags.StringVarP(&runFlags.image,
+		"image",
+		"i",
+		"",
+		"Create the ephemeral Toolbx container from this image")
+
+	flags.StringVar(&runFlags.name,
+		"name",
+		"",
+		"Name the session of a command run with --detach")
+
 	flags.UintVar(&runFlags.preserveFDs,
 		"preserve-fds",
 		0,
@@ -96,6 +151,28 @@ func init() {
 		"",
 		"Run command inside a Toolbx container for a different operating system release than the host")
 
+	flags.StringArrayVar(&runFlags.secrets,
+		"secret",
+		nil,
+		"Inject this secret from the keychain, as NAME[=VARIABLE|=/PATH]")
+
+	flags.StringVar(&runFlags.shell,
+		"shell",
+		"",
+		"Run the script from --file with this shell, instead of its #! line or /bin/sh")
+
+	flags.StringVarP(&runFlags.user,
+		"user",
+		"u",
+		"",
+		"Run command as this user inside the container, eg., root")
+
+	flags.StringVarP(&runFlags.workDir,
+		"workdir",
+		"w",
+		"",
+		"Run command in this directory inside the container, instead of the current one")
+
 	runCmd.SetHelpFunc(runHelp)
 
 	if err := runCmd.RegisterFlagCompletionFunc("container", completionContainerNames); err != nil {
@@ -106,6 +183,18 @@ func init() {
 		panicMsg := fmt.Sprintf("failed to register flag completion function: %v", err)
 		panic(panicMsg)
 	}
+	if err := runCmd.RegisterFlagCompletionFunc("image", completionImageNames); err != nil {
+		panicMsg := fmt.Sprintf("failed to register flag completion function: %v", err)
+		panic(panicMsg)
+	}
+	if err := runCmd.RegisterFlagCompletionFunc("release", completionReleases); err != nil {
+		panicMsg := fmt.Sprintf("failed to register flag completion function: %v", err)
+		panic(panicMsg)
+	}
+	if err := runCmd.RegisterFlagCompletionFunc("secret", completionSecretNames); err != nil {
+		panicMsg := fmt.Sprintf("failed to register flag completion function: %v", err)
+		panic(panicMsg)
+	}
 
 	rootCmd.AddCommand(runCmd)
 }
@@ -120,17 +209,23 @@ func run(cmd *cobra.Command, args []string) error {
 		return &exitError{exitCode, err}
 	}
 
+	var containerArg string
 	var defaultContainer bool = true
 
 	if runFlags.container != "" {
+		containerArg = "--container"
 		defaultContainer = false
 	}
 
+	if err := checkEphemeralOptions(cmd, runFlags.ephemeral, containerArg); err != nil {
+		return err
+	}
+
 	if runFlags.release != "" {
 		defaultContainer = false
 	}
 
-	if len(args) == 0 {
+	if len(args) == 0 && runFlags.file == "" {
 		var builder strings.Builder
 		fmt.Fprintf(&builder, "missing argument for \"run\"\n")
 		fmt.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
@@ -139,24 +234,138 @@ func run(cmd *cobra.Command, args []string) error {
 		return errors.New(errMsg)
 	}
 
-	command := args
+	if runFlags.shell != "" && runFlags.file == "" {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "option --shell needs --file\n")
+		fmt.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	if runFlags.name != "" && !runFlags.detach {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "option --name needs --detach\n")
+		fmt.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	if runFlags.detach && runFlags.ephemeral {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "options --detach and --ephemeral cannot be used together\n")
+		fmt.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	if runFlags.workDir != "" && !filepath.IsAbs(runFlags.workDir) {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "invalid argument for '--workdir': %s\n", runFlags.workDir)
+		fmt.Fprintf(&builder, "The directory must be an absolute path.\n")
+		fmt.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	if err := checkSecretOptions(runFlags.secrets); err != nil {
+		return err
+	}
+
+	environ, err := utils.GetEnvironmentFromOptions(runFlags.env, runFlags.envFile)
+	if err != nil {
+		return err
+	}
 
 	container, image, release, err := resolveContainerAndImageNames(runFlags.container,
 		"--container",
 		runFlags.distro,
-		"",
+		runFlags.image,
 		runFlags.release)
 
 	if err != nil {
 		return err
 	}
 
+	command := args
+
+	var session detachedSession
+	var scriptDirectory string
+
+	if runFlags.detach {
+		nameHint := runFlags.file
+		if nameHint == "" || nameHint == "-" {
+			nameHint = "script"
+		}
+
+		if len(args) != 0 && runFlags.file == "" {
+			nameHint = args[0]
+		}
+
+		session, err = createDetachedSession(runFlags.name, nameHint, container)
+		if err != nil {
+			return err
+		}
+
+		// The script needs to outlive this process, and goes away with
+		// the session.
+		scriptDirectory = session.directory
+	}
+
+	if runFlags.file != "" {
+		script, err := copyScript(runFlags.file, scriptDirectory)
+		if err != nil {
+			return err
+		}
+
+		if !runFlags.detach {
+			defer os.Remove(script)
+		}
+
+		command = getScriptCommand(script, runFlags.shell, args)
+	}
+
+	if runFlags.detach {
+		err := runDetached(session,
+			container,
+			defaultContainer,
+			image,
+			release,
+			runFlags.user,
+			runFlags.workDir,
+			command,
+			environ)
+
+		return err
+	}
+
+	if runFlags.ephemeral {
+		err := runEphemeral(container,
+			image,
+			release,
+			runFlags.preserveFDs,
+			runFlags.user,
+			runFlags.workDir,
+			command,
+			environ,
+			false,
+			false)
+
+		return err
+	}
+
 	if err := runCommand(container,
 		defaultContainer,
 		image,
 		release,
 		runFlags.preserveFDs,
+		runFlags.user,
+		runFlags.workDir,
 		command,
+		environ,
 		false,
 		false,
 		true); err != nil {
@@ -170,7 +379,8 @@ func runCommand(container string,
 	defaultContainer bool,
 	image, release string,
 	preserveFDs uint,
-	command []string,
+	user, workDir string,
+	command, extraEnviron []string,
 	emitEscapeSequence, fallbackToBash, pedantic bool) error {
 
 	if !pedantic {
@@ -249,6 +459,10 @@ func runCommand(container string,
 		return fmt.Errorf("failed to inspect container %s", container)
 	}
 
+	if err := checkContainerOwner(containerObj); err != nil {
+		return err
+	}
+
 	entryPoint := containerObj.EntryPoint()
 	entryPointPID := containerObj.EntryPointPID()
 	logrus.Debugf("Entry point of container %s is %s (PID=%d)", container, entryPoint, entryPointPID)
@@ -266,6 +480,10 @@ func runCommand(container string,
 		return err
 	}
 
+	if err := syncMachineClock(); err != nil {
+		showWarning("%s", err)
+	}
+
 	var cdiEnviron []string
 
 	cdiSpecForNvidia, err := nvidia.GenerateCDISpec()
@@ -341,9 +559,70 @@ func runCommand(container string,
 
 	logrus.Debugf("Container %s is initialized", container)
 
+	startHostMonitor()
+	updateLinkedHosts(container)
+	recordContainerUsed(containerObj)
+
+	endContainerSession := startContainerSession(containerObj)
+	defer endContainerSession()
+
+	var titleEnviron []string
+	if emitEscapeSequence {
+		var restoreTerminalTitle func()
+		titleEnviron, restoreTerminalTitle = setTerminalTitle(container, containerObj.Image())
+		defer restoreTerminalTitle()
+
+		showEnterBanner(containerObj)
+	}
+
+	secretEnviron, err := injectSecrets(containerObj, runFlags.secrets, user)
+	if err != nil {
+		return err
+	}
+
 	environ := append(cdiEnviron, p11KitServerEnviron...)
+	environ = append(environ, titleEnviron...)
+	environ = append(environ, "TOOLBOX_NAME="+container)
+	environ = append(environ, getCredentialsEnviron(containerObj)...)
+	environ = append(environ, getKeyboardEnviron()...)
+	environ = append(environ, getLocaleEnviron()...)
+	environ = append(environ, getSSHAgentEnviron(containerObj)...)
+	environ = append(environ, getTermEnviron(container)...)
+	environ = append(environ, getTimeZoneEnviron()...)
+	environ = append(environ, secretEnviron...)
+	environ = append(environ, extraEnviron...)
+
+	fallbackWorkDirs := runFallbackWorkDirs
+
+	if workDir == "" {
+		workDir = getContainerWorkingDirectory(container, workingDirectory)
+		if workDir == "" {
+			fmt.Fprintf(os.Stderr,
+				"Error: directory %s not shared with container %s\n",
+				workingDirectory,
+				container)
+
+			workDir = getCurrentUserHomeDir()
+			fmt.Fprintf(os.Stderr, "Using %s instead.\n", workDir)
+			fallbackWorkDirs = nil
+		}
+	} else {
+		fallbackWorkDirs = nil
+	}
+
+	if user == "" {
+		user = currentUser.Username
+	}
+
+	if fallbackToBash {
+		offerToInstallShell(container, command)
+	}
+
 	if err := runCommandWithFallbacks(container,
 		preserveFDs,
+		user,
+		workDir,
+		fallbackWorkDirs,
 		command,
 		environ,
 		emitEscapeSequence,
@@ -356,6 +635,8 @@ func runCommand(container string,
 
 func runCommandWithFallbacks(container string,
 	preserveFDs uint,
+	user, workDir string,
+	fallbackWorkDirs []string,
 	command, environ []string,
 	emitEscapeSequence, fallbackToBash bool) error {
 
@@ -369,8 +650,21 @@ func runCommandWithFallbacks(container string,
 	}
 
 	envOptions := utils.GetEnvOptionsForPreservedVariables()
+	if user != currentUser.Username {
+		envOptions = slices.DeleteFunc(envOptions, func(envOption string) bool {
+			for _, variable := range userEnvironmentVariables {
+				if strings.HasPrefix(envOption, "--env="+variable+"=") {
+					return true
+				}
+			}
+
+			return false
+		})
+	}
+
 	for _, env := range environ {
-		logrus.Debugf("%s", env)
+		name, _, _ := strings.Cut(env, "=")
+		logrus.Debugf("%s", name)
 		envOption := "--env=" + env
 		envOptions = append(envOptions, envOption)
 	}
@@ -382,7 +676,7 @@ func runCommandWithFallbacks(container string,
 
 	if term.IsTerminal(os.Stdin) && term.IsTerminal(os.Stdout) {
 		ttyNeeded = true
-		if logLevel := logrus.GetLevel(); logLevel >= logrus.DebugLevel {
+		if terminalLogLevel >= logrus.DebugLevel {
 			stderr = os.Stderr
 		}
 	} else {
@@ -391,7 +685,6 @@ func runCommandWithFallbacks(container string,
 
 	runFallbackCommandsIndex := 0
 	runFallbackWorkDirsIndex := 0
-	workDir := workingDirectory
 
 	for {
 		execArgs := constructExecArgs(container,
@@ -401,6 +694,7 @@ func runCommandWithFallbacks(container string,
 			envOptions,
 			fallbackToBash,
 			ttyNeeded,
+			user,
 			workDir)
 
 		if emitEscapeSequence {
@@ -409,7 +703,7 @@ func runCommandWithFallbacks(container string,
 
 		logrus.Debugf("Running in container %s:", container)
 		logrus.Debug("podman")
-		for _, arg := range execArgs {
+		for _, arg := range utils.RedactEnvOptions(execArgs) {
 			logrus.Debugf("%s", arg)
 		}
 
@@ -440,13 +734,13 @@ func runCommandWithFallbacks(container string,
 			return &exitError{exitCode, err}
 		case 127:
 			if pathPresent, _ := isPathPresent(container, workDir); !pathPresent {
-				if runFallbackWorkDirsIndex < len(runFallbackWorkDirs) {
+				if runFallbackWorkDirsIndex < len(fallbackWorkDirs) {
 					fmt.Fprintf(os.Stderr,
 						"Error: directory %s not found in container %s\n",
 						workDir,
 						container)
 
-					workDir = runFallbackWorkDirs[runFallbackWorkDirsIndex]
+					workDir = fallbackWorkDirs[runFallbackWorkDirsIndex]
 					if workDir == "" {
 						workDir = getCurrentUserHomeDir()
 					}
@@ -489,6 +783,45 @@ func runCommandWithFallbacks(container string,
 	// code should not be reached
 }
 
+// runDetached starts command in the background in a detached session, which
+// 'toolbox attach' follows.  The session is set up like any other through
+// runCommand, which returns as soon as the command was started.
+func runDetached(session detachedSession,
+	container string,
+	defaultContainer bool,
+	image, release string,
+	user, workDir string,
+	command, extraEnviron []string) error {
+
+	sessionCommand, err := getDetachedSessionCommand(session, command)
+	if err == nil {
+		err = runCommand(container,
+			defaultContainer,
+			image,
+			release,
+			0,
+			user,
+			workDir,
+			sessionCommand,
+			extraEnviron,
+			false,
+			false,
+			true)
+	}
+
+	if err != nil {
+		if err := os.RemoveAll(session.directory); err != nil {
+			logrus.Debugf("Removing %s failed: %s", session.directory, err)
+		}
+
+		return err
+	}
+
+	showMessage("Started session %s in container %s", session.name, container)
+	showMessage("Follow it with 'toolbox attach %s'", session.name)
+	return nil
+}
+
 func runHelp(cmd *cobra.Command, args []string) {
 	if utils.IsInsideContainer() {
 		if !utils.IsInsideToolboxContainer() {
@@ -529,8 +862,10 @@ func callFlatpakSessionHelper(container podman.Container) error {
 		return nil
 	}
 
-	fmt.Fprintf(os.Stderr, "Warning: container %s uses deprecated features\n", name)
-	fmt.Fprintf(os.Stderr, "Consider recreating it with Toolbx version 0.0.97 or newer.\n")
+	if !rootFlags.quiet {
+		fmt.Fprintf(os.Stderr, "Warning: container %s uses deprecated features\n", name)
+		fmt.Fprintf(os.Stderr, "Consider recreating it with Toolbx version 0.0.97 or newer.\n")
+	}
 
 	if _, err := utils.CallFlatpakSessionHelper(); err != nil {
 		return err
@@ -558,7 +893,7 @@ func constructExecArgs(container, preserveFDs string,
 	envOptions []string,
 	fallbackToBash bool,
 	ttyNeeded bool,
-	workDir string) []string {
+	user, workDir string) []string {
 
 	logLevelString := podman.LogLevel.String()
 
@@ -587,7 +922,7 @@ func constructExecArgs(container, preserveFDs string,
 	}
 
 	execArgs = append(execArgs, []string{
-		"--user", currentUser.Username,
+		"--user", user,
 		"--workdir", workDir,
 	}...)
 
@@ -601,6 +936,67 @@ func constructExecArgs(container, preserveFDs string,
 	return execArgs
 }
 
+// copyScript copies the script from 'toolbox run --file' into directory, or
+// else into the cache directory, which is inside the home directory, and so is
+// shared with the containers at the same path.  This avoids quoting it through
+// 'podman exec' and the shells in the container, and works for standard input
+// too.  The copy is executable, so that its #! line is used.
+func copyScript(file, directory string) (string, error) {
+	var data []byte
+	var err error
+
+	if file == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(file)
+	}
+
+	if err != nil {
+		if file == "-" {
+			return "", fmt.Errorf("failed to read the script from standard input: %w", err)
+		}
+
+		return "", fmt.Errorf("failed to read script %s: %w", file, err)
+	}
+
+	if directory == "" {
+		cacheDirectory, err := os.UserCacheDir()
+		if err != nil {
+			logrus.Debugf("Getting the cache directory failed: %s", err)
+			return "", errors.New("failed to get the cache directory")
+		}
+
+		directory = filepath.Join(cacheDirectory, "toolbox", "scripts")
+		if err := os.MkdirAll(directory, 0700); err != nil {
+			return "", fmt.Errorf("failed to create directory %s: %w", directory, err)
+		}
+	}
+
+	scriptFile, err := os.CreateTemp(directory, "run-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create a file in %s: %w", directory, err)
+	}
+
+	script := scriptFile.Name()
+
+	_, err = scriptFile.Write(data)
+	if err == nil {
+		err = scriptFile.Chmod(0700)
+	}
+
+	if closeErr := scriptFile.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		os.Remove(script)
+		return "", fmt.Errorf("failed to write %s: %w", script, err)
+	}
+
+	logrus.Debugf("Copied the script to %s", script)
+	return script, nil
+}
+
 func ensureContainerIsInitialized(container string, entryPointPID int, timestamp time.Time) error {
 	initializedStamp, err := utils.GetInitializedStamp(entryPointPID, currentUser)
 	if err != nil {
@@ -788,6 +1184,24 @@ func followEntryPointLogsAsync(ctx context.Context, container string, since time
 	return retValCh, errCh
 }
 
+// getScriptCommand returns the command to run the script copied by
+// copyScript with shell, or else with the interpreter in its #! line, or else
+// with /bin/sh.
+func getScriptCommand(script, shell string, args []string) []string {
+	var command []string
+
+	if shell != "" {
+		command = []string{shell, script}
+	} else if hasScriptInterpreter(script) {
+		command = []string{script}
+	} else {
+		command = []string{"/bin/sh", script}
+	}
+
+	command = append(command, args...)
+	return command
+}
+
 func handleEntryPointLog(ctx context.Context,
 	container string,
 	end bool,
@@ -840,6 +1254,23 @@ func handlePollingTickForStamp(time time.Time, initializedStamp string) bool {
 	return false
 }
 
+// hasScriptInterpreter checks if a script starts with a #! line.
+func hasScriptInterpreter(script string) bool {
+	file, err := os.Open(script)
+	if err != nil {
+		return false
+	}
+
+	defer file.Close()
+
+	magic := make([]byte, 2)
+	if _, err := io.ReadFull(file, magic); err != nil {
+		return false
+	}
+
+	return string(magic) == "#!"
+}
+
 func isCommandPresent(container, command string) (bool, error) {
 	logrus.Debugf("Looking up command %s in container %s", command, container)
 
@@ -891,6 +1322,14 @@ func isUsePollingSet() bool {
 	return true
 }
 
+// recordContainerUsed updates the last-used time that 'toolbox list' and
+// 'toolbox inspect' show.  It's only for showing, so failures are not errors.
+func recordContainerUsed(containerObj podman.Container) {
+	if err := toolbox.MarkUsed(containerObj.ID()); err != nil {
+		logrus.Debugf("Recording the use of container %s: %s", containerObj.Name(), err)
+	}
+}
+
 func saveCDISpecTo(spec *specs.Spec, path string) error {
 	if path == "" {
 		panic("path not specified")
@@ -1043,6 +1482,44 @@ func startContainer(container string) error {
 	return nil
 }
 
+// startContainerAndWait starts a container, unless it's running already, and
+// waits for init-container to set up the user, like 'toolbox enter' would, for
+// commands that use the container without entering it.
+func startContainerAndWait(container string) error {
+	containerObj, err := podman.InspectContainer(container)
+	if err != nil {
+		return fmt.Errorf("failed to inspect container %s", container)
+	}
+
+	startContainerTimestamp := time.Unix(-1, 0)
+	entryPointPID := containerObj.EntryPointPID()
+
+	if entryPointPID <= 0 {
+		startContainerTimestamp = time.Now()
+
+		logrus.Debugf("Starting container %s", container)
+		if err := startContainer(container); err != nil {
+			return err
+		}
+
+		containerObj, err := podman.InspectContainer(container)
+		if err != nil {
+			return fmt.Errorf("failed to inspect container %s", container)
+		}
+
+		entryPointPID = containerObj.EntryPointPID()
+		if entryPointPID <= 0 {
+			return fmt.Errorf("invalid entry point PID of container %s", container)
+		}
+	}
+
+	if err := ensureContainerIsInitialized(container, entryPointPID, startContainerTimestamp); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func startP11KitServer() ([]string, error) {
 	serverSocket, err := utils.GetP11KitServerSocket(currentUser)
 	if err != nil {
@@ -0,0 +1,546 @@
+//go:build darwin
+
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bufio"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/containers/toolbox/pkg/i18n"
+	"github.com/containers/toolbox/pkg/podman"
+	"github.com/containers/toolbox/pkg/shell"
+	"github.com/containers/toolbox/pkg/term"
+	"github.com/containers/toolbox/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// secretSpec is a '--secret NAME[=VARIABLE|=/PATH]' option: the secret called
+// NAME in the keychain, and the environment variable or the file that it's
+// injected as.
+type secretSpec struct {
+	file     string
+	name     string
+	variable string
+}
+
+const (
+	// secretKeychainService is the service of the generic passwords in the
+	// login keychain that hold the secrets, and their accounts are the
+	// names.
+	secretKeychainService = "com.github.containers.toolbox"
+
+	secretNameRegexp = "^[a-zA-Z0-9][a-zA-Z0-9_.-]*$"
+
+	secretVariableRegexp = "^[a-zA-Z_][a-zA-Z0-9_]*$"
+
+	// secretsDirectory is a tmpfs in every container for the secrets that
+	// are injected as files, so that they never reach the disk.
+	secretsDirectory = "/run/toolbox/secrets"
+
+	secretsLabel = "com.github.containers.toolbox.secrets"
+)
+
+var secretCmd = &cobra.Command{
+	Use:   "secret",
+	Short: "Manage secrets in the macOS keychain for Toolbx containers (macOS version)",
+	RunE:  secretRun,
+}
+
+var secretAddCmd = &cobra.Command{
+	Use:               "add",
+	Short:             "Add a secret to the keychain, or replace it",
+	RunE:              secretAdd,
+	ValidArgsFunction: completionSecretNames,
+}
+
+var secretListCmd = &cobra.Command{
+	Use:               "list",
+	Short:             "List the secrets in the keychain",
+	RunE:              secretList,
+	ValidArgsFunction: completionEmpty,
+}
+
+var secretRmCmd = &cobra.Command{
+	Use:               "rm",
+	Short:             "Remove secrets from the keychain",
+	RunE:              secretRm,
+	ValidArgsFunction: completionSecretNames,
+}
+
+func init() {
+	secretCmd.AddCommand(secretAddCmd)
+	secretCmd.AddCommand(secretListCmd)
+	secretCmd.AddCommand(secretRmCmd)
+
+	secretCmd.SetHelpFunc(secretHelp)
+	rootCmd.AddCommand(secretCmd)
+}
+
+func secretRun(cmd *cobra.Command, args []string) error {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "missing command for \"secret\", eg., add, list or rm\n")
+	i18n.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+	errMsg := builder.String()
+	return errors.New(errMsg)
+}
+
+// secretAdd reads the secret from the terminal without echoing it, or from
+// the standard input, so that it's never an argument of a command.
+func secretAdd(cmd *cobra.Command, args []string) error {
+	if utils.IsInsideContainer() {
+		return errors.New("secret is not supported inside a container")
+	}
+
+	if len(args) != 1 {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "secret add needs a name for the secret\n")
+		i18n.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	name := args[0]
+	if err := validateSecretName(name); err != nil {
+		return err
+	}
+
+	value, err := readSecret(name)
+	if err != nil {
+		return err
+	}
+
+	if value == "" {
+		return fmt.Errorf("secret %s is empty", name)
+	}
+
+	if err := addKeychainSecret(name, value); err != nil {
+		return err
+	}
+
+	showMessage("Added secret %s to the keychain", name)
+	return nil
+}
+
+func secretList(cmd *cobra.Command, args []string) error {
+	if utils.IsInsideContainer() {
+		return errors.New("secret is not supported inside a container")
+	}
+
+	if len(args) != 0 {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "secret list takes no arguments\n")
+		i18n.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	names, err := getKeychainSecrets()
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		fmt.Println(name)
+	}
+
+	return nil
+}
+
+func secretRm(cmd *cobra.Command, args []string) error {
+	if utils.IsInsideContainer() {
+		return errors.New("secret is not supported inside a container")
+	}
+
+	if len(args) == 0 {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "secret rm needs at least one secret\n")
+		i18n.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	for _, name := range args {
+		args := []string{"delete-generic-password", "-s", secretKeychainService, "-a", name}
+		if err := shell.Run("security", nil, nil, nil, args...); err != nil {
+			logrus.Debugf("Removing secret %s from the keychain failed: %s", name, err)
+			return fmt.Errorf("secret %s not found in the keychain", name)
+		}
+	}
+
+	return nil
+}
+
+// addKeychainSecret stores the secret as a generic password in the login
+// keychain.  The command is fed to 'security -i' on its standard input, with
+// the secret in hexadecimal, so that it's not visible in ps(1).  Then the
+// secret is read back, because 'security -i' doesn't fail when a command
+// does.
+func addKeychainSecret(name, value string) error {
+	command := fmt.Sprintf("add-generic-password -U -s %s -a %s -X %s\n",
+		secretKeychainService,
+		name,
+		hex.EncodeToString([]byte(value)))
+
+	var stderr strings.Builder
+	if err := shell.Run("security", strings.NewReader(command), nil, &stderr, "-i"); err != nil {
+		logrus.Debugf("Adding secret %s to the keychain failed: %s", name, err)
+	}
+
+	if stored, err := getKeychainSecret(name); err != nil || stored != value {
+		logrus.Debugf("Adding secret %s to the keychain failed: %s", name, strings.TrimSpace(stderr.String()))
+		return fmt.Errorf("failed to add secret %s to the keychain", name)
+	}
+
+	return nil
+}
+
+// checkSecretOptions checks the '--secret' options of 'toolbox create' and
+// 'toolbox run'.
+func checkSecretOptions(specs []string) error {
+	for _, spec := range specs {
+		if _, err := parseSecretSpec(spec); err != nil {
+			var builder strings.Builder
+			fmt.Fprintf(&builder, "invalid argument for '--secret': %s\n", spec)
+			fmt.Fprintf(&builder, "%s\n", err)
+			i18n.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+			errMsg := builder.String()
+			return errors.New(errMsg)
+		}
+	}
+
+	return nil
+}
+
+func completionSecretNames(cmd *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+	names, err := getKeychainSecrets()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	if cmd.Name() == "add" {
+		if len(args) != 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+	}
+
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+func getKeychainSecret(name string) (string, error) {
+	var stdout strings.Builder
+
+	args := []string{"find-generic-password", "-s", secretKeychainService, "-a", name, "-w"}
+	if err := shell.Run("security", nil, &stdout, nil, args...); err != nil {
+		logrus.Debugf("Reading secret %s from the keychain failed: %s", name, err)
+		return "", fmt.Errorf("secret %s not found in the keychain", name)
+	}
+
+	value := strings.TrimSuffix(stdout.String(), "\n")
+	return value, nil
+}
+
+func getKeychainSecrets() ([]string, error) {
+	var stdout strings.Builder
+
+	if err := shell.Run("security", nil, &stdout, nil, "dump-keychain"); err != nil {
+		logrus.Debugf("Reading the keychain failed: %s", err)
+		return nil, errors.New("failed to read the keychain")
+	}
+
+	names := parseKeychainSecrets(stdout.String(), secretKeychainService)
+	return names, nil
+}
+
+// getSecretArgs returns the options for 'podman create' that '--secret' and
+// the secrets that are injected as files need.  Only the names of the secrets
+// are recorded in the container, and the secrets themselves are injected
+// when it's entered or a command is run in it.
+func getSecretArgs() ([]string, error) {
+	args := []string{"--tmpfs", secretsDirectory + ":mode=0755"}
+
+	if len(createFlags.secrets) == 0 {
+		return args, nil
+	}
+
+	for _, spec := range createFlags.secrets {
+		secret, err := parseSecretSpec(spec)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := getKeychainSecret(secret.name); err != nil {
+			var builder strings.Builder
+			fmt.Fprintf(&builder, "%s\n", err)
+			fmt.Fprintf(&builder, "Use 'toolbox secret add %s' to add it.", secret.name)
+
+			errMsg := builder.String()
+			return nil, errors.New(errMsg)
+		}
+	}
+
+	args = append(args, "--label", secretsLabel+"="+strings.Join(createFlags.secrets, ","))
+	return args, nil
+}
+
+// injectSecrets reads the secrets of the container, and those given to
+// 'toolbox run', from the keychain.  Those that are injected as environment
+// variables are set in the environment of this process, and only their names
+// are returned for 'podman exec --env', which takes the values from there.
+// The others are written to their files, which have to be on a tmpfs, and
+// belong to the user that the command runs as.
+func injectSecrets(containerObj podman.Container, specs []string, user string) ([]string, error) {
+	if labelSpecs := containerObj.Labels()[secretsLabel]; labelSpecs != "" {
+		specs = append(strings.Split(labelSpecs, ","), specs...)
+	}
+
+	if user == "" {
+		user = currentUser.Username
+	}
+
+	var environ []string
+
+	for _, spec := range specs {
+		secret, err := parseSecretSpec(spec)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := getKeychainSecret(secret.name)
+		if err != nil {
+			return nil, err
+		}
+
+		if secret.variable != "" {
+			if err := os.Setenv(secret.variable, value); err != nil {
+				return nil, fmt.Errorf("failed to set secret %s: %w", secret.name, err)
+			}
+
+			environ = append(environ, secret.variable)
+			continue
+		}
+
+		if err := writeSecretFile(containerObj.Name(), secret.file, value, user); err != nil {
+			return nil, fmt.Errorf("failed to write secret %s to %s: %w", secret.name, secret.file, err)
+		}
+	}
+
+	return environ, nil
+}
+
+// parseKeychainSecrets returns the accounts of the generic passwords of the
+// service in the output of 'security dump-keychain', sorted and without
+// duplicates.
+func parseKeychainSecrets(dump, service string) []string {
+	var names []string
+
+	var account string
+	var class string
+	var matches bool
+
+	flush := func() {
+		if class == "genp" && matches && account != "" && !slices.Contains(names, account) {
+			names = append(names, account)
+		}
+
+		account = ""
+		class = ""
+		matches = false
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(dump))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case strings.HasPrefix(line, "keychain: "):
+			flush()
+		case strings.HasPrefix(line, "class: "):
+			class = strings.Trim(strings.TrimPrefix(line, "class: "), `"`)
+		case strings.HasPrefix(line, `"acct"<blob>="`):
+			account = strings.TrimSuffix(strings.TrimPrefix(line, `"acct"<blob>="`), `"`)
+		case strings.HasPrefix(line, `"svce"<blob>="`):
+			matches = strings.TrimSuffix(strings.TrimPrefix(line, `"svce"<blob>="`), `"`) == service
+		}
+	}
+
+	flush()
+
+	sort.Strings(names)
+	return names
+}
+
+// parseSecretSpec parses NAME[=VARIABLE|=/PATH].  Without VARIABLE, the name
+// is turned into one, eg., npm-token into NPM_TOKEN.
+func parseSecretSpec(spec string) (secretSpec, error) {
+	name, target, hasTarget := strings.Cut(spec, "=")
+
+	if matched, _ := regexp.MatchString(secretNameRegexp, name); !matched {
+		return secretSpec{}, fmt.Errorf("secret names must match '%s'", secretNameRegexp)
+	}
+
+	secret := secretSpec{name: name}
+
+	switch {
+	case !hasTarget:
+		variable := strings.ToUpper(name)
+		variable = strings.NewReplacer("-", "_", ".", "_").Replace(variable)
+		if variable[0] >= '0' && variable[0] <= '9' {
+			variable = "_" + variable
+		}
+
+		secret.variable = variable
+	case strings.HasPrefix(target, "/"):
+		if strings.Contains(target, ",") {
+			return secretSpec{}, errors.New("files for secrets can't have commas in their paths")
+		}
+
+		secret.file = path.Clean(target)
+	default:
+		if matched, _ := regexp.MatchString(secretVariableRegexp, target); !matched {
+			return secretSpec{}, fmt.Errorf("environment variables must match '%s'", secretVariableRegexp)
+		}
+
+		secret.variable = target
+	}
+
+	return secret, nil
+}
+
+// readSecret reads a secret from the terminal, or all of the standard input
+// without the final newline.
+func readSecret(name string) (string, error) {
+	if !term.IsTerminal(os.Stdin) {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return "", fmt.Errorf("failed to read secret %s: %w", name, err)
+		}
+
+		value := strings.TrimSuffix(string(data), "\n")
+		return value, nil
+	}
+
+	oldState, err := term.GetState(os.Stdin)
+	if err != nil {
+		return "", fmt.Errorf("failed to read secret %s: %w", name, err)
+	}
+
+	newState := term.NewStateFrom(oldState, term.WithoutECHO())
+	if err := term.SetState(os.Stdin, newState); err != nil {
+		return "", fmt.Errorf("failed to read secret %s: %w", name, err)
+	}
+
+	defer term.SetState(os.Stdin, oldState)
+
+	fmt.Fprintf(os.Stderr, "Secret for %s: ", name)
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	fmt.Fprintf(os.Stderr, "\n")
+
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("failed to read secret %s: %w", name, err)
+	}
+
+	value := strings.TrimSuffix(line, "\n")
+	return value, nil
+}
+
+func secretHelp(cmd *cobra.Command, args []string) {
+	if utils.IsInsideContainer() {
+		if !utils.IsInsideToolboxContainer() {
+			fmt.Fprintf(os.Stderr, "Error: this is not a Toolbx container\n")
+			return
+		}
+
+		if _, err := utils.ForwardToHost(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return
+		}
+
+		return
+	}
+
+	if err := showManual("toolbox-secret"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return
+	}
+}
+
+func validateSecretName(name string) error {
+	if matched, _ := regexp.MatchString(secretNameRegexp, name); matched {
+		return nil
+	}
+
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "invalid argument for the secret name: %s\n", name)
+	fmt.Fprintf(&builder, "Secret names must match '%s'.\n", secretNameRegexp)
+	i18n.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+	errMsg := builder.String()
+	return errors.New(errMsg)
+}
+
+// writeSecretFile writes a secret into a file inside the container, through
+// the standard input of 'podman exec', after making sure that the file is on
+// a tmpfs.
+func writeSecretFile(container, file, value, user string) error {
+	var stdout strings.Builder
+
+	args := []string{
+		"--log-level", podman.LogLevel.String(),
+		"exec",
+		"--user", "root:root",
+		container,
+		"sh", "-c", `directory=$(dirname "$1") && mkdir -p "$directory" && stat -f -c %T "$directory"`,
+		"sh", file,
+	}
+
+	if err := shell.Run("podman", nil, &stdout, nil, args...); err != nil {
+		return err
+	}
+
+	if fileSystem := strings.TrimSpace(stdout.String()); fileSystem != "tmpfs" {
+		return fmt.Errorf("%s is on %s instead of a tmpfs, like %s", path.Dir(file), fileSystem, secretsDirectory)
+	}
+
+	const script = `umask 077
+cat >"$1.toolbox"
+chown "$2" "$1.toolbox"
+mv --force "$1.toolbox" "$1"`
+
+	stdin := strings.NewReader(value)
+	if err := podman.ExecAsRoot(container, stdin, "sh", "-c", script, "sh", file, user); err != nil {
+		return err
+	}
+
+	return nil
+}
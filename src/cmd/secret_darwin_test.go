@@ -0,0 +1,100 @@
+//go:build darwin
+
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testKeychainDump = `keychain: "/Users/alice/Library/Keychains/login.keychain-db"
+version: 512
+class: "genp"
+attributes:
+    0x00000007 <blob>="com.github.containers.toolbox"
+    "acct"<blob>="npm-token"
+    "svce"<blob>="com.github.containers.toolbox"
+keychain: "/Users/alice/Library/Keychains/login.keychain-db"
+version: 512
+class: "genp"
+attributes:
+    "acct"<blob>="alice"
+    "svce"<blob>="com.example.other"
+keychain: "/Users/alice/Library/Keychains/login.keychain-db"
+version: 512
+class: "inet"
+attributes:
+    "acct"<blob>="github"
+    "svce"<blob>="com.github.containers.toolbox"
+keychain: "/Users/alice/Library/Keychains/login.keychain-db"
+version: 512
+class: "genp"
+attributes:
+    "acct"<blob>="aws.key"
+    "svce"<blob>="com.github.containers.toolbox"
+`
+
+func TestParseKeychainSecrets(t *testing.T) {
+	names := parseKeychainSecrets(testKeychainDump, secretKeychainService)
+	assert.Equal(t, []string{"aws.key", "npm-token"}, names)
+
+	assert.Empty(t, parseKeychainSecrets("", secretKeychainService))
+}
+
+func TestParseSecretSpec(t *testing.T) {
+	testCases := []struct {
+		spec     string
+		expected secretSpec
+	}{
+		{"npm-token", secretSpec{name: "npm-token", variable: "NPM_TOKEN"}},
+		{"aws.key", secretSpec{name: "aws.key", variable: "AWS_KEY"}},
+		{"1password", secretSpec{name: "1password", variable: "_1PASSWORD"}},
+		{"npm-token=NODE_AUTH_TOKEN", secretSpec{name: "npm-token", variable: "NODE_AUTH_TOKEN"}},
+		{"npmrc=/run/toolbox/secrets/npmrc", secretSpec{name: "npmrc", file: "/run/toolbox/secrets/npmrc"}},
+		{"npmrc=/dev/shm//npmrc", secretSpec{name: "npmrc", file: "/dev/shm/npmrc"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.spec, func(t *testing.T) {
+			secret, err := parseSecretSpec(tc.spec)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, secret)
+		})
+	}
+}
+
+func TestParseSecretSpecInvalid(t *testing.T) {
+	testCases := []string{
+		"",
+		"-token",
+		"npm token",
+		"npm-token=",
+		"npm-token=NODE-AUTH-TOKEN",
+		"npmrc=/run/toolbox/secrets/a,b",
+	}
+
+	for _, spec := range testCases {
+		t.Run(spec, func(t *testing.T) {
+			_, err := parseSecretSpec(spec)
+			assert.Error(t, err)
+		})
+	}
+}
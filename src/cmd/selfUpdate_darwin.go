@@ -0,0 +1,431 @@
+//go:build darwin
+
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	goversion "github.com/HarryMichal/go-version"
+	"github.com/containers/toolbox/pkg/i18n"
+	"github.com/containers/toolbox/pkg/shell"
+	"github.com/containers/toolbox/pkg/utils"
+	"github.com/containers/toolbox/pkg/version"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// selfUpdateURL is where the binaries built by build-macos.sh are
+	// published for each release.
+	selfUpdateURL = "https://github.com/nickmerrett/toolbox-mac/releases/latest/download/toolbox-darwin-"
+
+	// selfUpdateChecksums is the file with the SHA-256 checksums of the
+	// binaries, in the format of shasum(1), which is published next to
+	// them.
+	selfUpdateChecksums = "SHA256SUMS"
+)
+
+var (
+	selfUpdateFlags struct {
+		url string
+	}
+)
+
+var selfUpdateCmd = &cobra.Command{
+	Use:   "self-update",
+	Short: "Update Toolbx to the latest version (macOS version)",
+	RunE:  selfUpdate,
+}
+
+func init() {
+	flags := selfUpdateCmd.Flags()
+
+	flags.StringVar(&selfUpdateFlags.url,
+		"url",
+		"",
+		"Download the binary from this URL, instead of the latest release")
+
+	selfUpdateCmd.SetHelpFunc(selfUpdateHelp)
+	rootCmd.AddCommand(selfUpdateCmd)
+}
+
+// selfUpdate leaves a Homebrew installation to Homebrew, so that it knows
+// about the new version, and replaces anything else with the binary of the
+// latest release.
+func selfUpdate(cmd *cobra.Command, args []string) error {
+	if utils.IsInsideContainer() {
+		return errors.New("self-update is not supported inside a container")
+	}
+
+	if len(args) != 0 {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "too many arguments for \"self-update\"\n")
+		i18n.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	formula := getHomebrewFormula(executable)
+
+	if formula != "" && selfUpdateFlags.url != "" {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "'--url' is not supported when Toolbx is installed with Homebrew\n")
+		i18n.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	if selfUpdateFlags.url != "" && !strings.HasPrefix(selfUpdateFlags.url, "https://") {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "invalid argument for '--url': %s\n", selfUpdateFlags.url)
+		fmt.Fprintf(&builder, "Only https URLs are supported.\n")
+		i18n.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	var newExecutable string
+	var err error
+
+	if formula != "" {
+		newExecutable, err = selfUpdateHomebrew(formula)
+	} else {
+		newExecutable, err = selfUpdateBinary()
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if newExecutable == "" {
+		showMessage("Toolbx is already up to date")
+		return nil
+	}
+
+	newVersion, err := getExecutableVersion(newExecutable)
+	if err != nil {
+		return err
+	}
+
+	showMessage("Updated Toolbx from %s to %s", version.GetVersion(), newVersion)
+
+	selfUpdateProvision(newExecutable)
+	return nil
+}
+
+// getExecutableVersion asks a Toolbx binary for its version, which also
+// checks that a downloaded binary can run on this Mac.
+func getExecutableVersion(path string) (string, error) {
+	var stdout strings.Builder
+	if err := shell.Run(path, nil, &stdout, nil, "--version"); err != nil {
+		logrus.Debugf("Running %s --version failed: %s", path, err)
+		return "", fmt.Errorf("failed to run %s", path)
+	}
+
+	output := strings.Fields(stdout.String())
+	if len(output) == 0 {
+		return "", fmt.Errorf("failed to get the version of %s", path)
+	}
+
+	newVersion := output[len(output)-1]
+	return newVersion, nil
+}
+
+// getHomebrewFormula returns the name of the Homebrew formula that installed
+// the executable at path, which is resolved into the Cellar, eg.,
+// /opt/homebrew/Cellar/toolbox/0.2/bin/toolbox.  It's empty for anything
+// else.
+func getHomebrewFormula(path string) string {
+	components := strings.Split(path, string(filepath.Separator))
+
+	for i, component := range components {
+		if component == "Cellar" && i+1 < len(components) {
+			formula := components[i+1]
+			logrus.Debugf("Toolbx is installed by Homebrew formula %s", formula)
+			return formula
+		}
+	}
+
+	return ""
+}
+
+// getChecksumsURL returns the URL of the SHA256SUMS file that is published
+// in the same directory as the binary at url.
+func getChecksumsURL(url string) string {
+	i := strings.LastIndex(url, "/")
+	checksumsURL := url[:i+1] + selfUpdateChecksums
+	return checksumsURL
+}
+
+// parseChecksums returns the checksum of the file called name from the
+// output of 'shasum -a 256', which has lines like 'CHECKSUM  NAME', or
+// 'CHECKSUM *NAME' for binary mode.
+func parseChecksums(r io.Reader, name string) (string, error) {
+	scanner := bufio.NewScanner(r)
+
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+
+		if strings.TrimPrefix(fields[1], "*") != name {
+			continue
+		}
+
+		checksum := strings.ToLower(fields[0])
+		if _, err := hex.DecodeString(checksum); err != nil || len(checksum) != sha256.Size*2 {
+			return "", fmt.Errorf("invalid checksum for %s in %s", name, selfUpdateChecksums)
+		}
+
+		return checksum, nil
+	}
+
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+
+	return "", fmt.Errorf("no checksum for %s in %s", name, selfUpdateChecksums)
+}
+
+// checkDownloadChecksum compares the SHA-256 checksum of the file at path,
+// which was downloaded from url, with the one in the SHA256SUMS file next to
+// it, so that a tampered or truncated binary is never run.
+func checkDownloadChecksum(path, url string) error {
+	checksumsURL := getChecksumsURL(url)
+	logrus.Debugf("Downloading %s", checksumsURL)
+
+	var stdout, stderr strings.Builder
+	if err := shell.Run("curl",
+		nil,
+		&stdout,
+		&stderr,
+		"--fail",
+		"--location",
+		"--proto", "=https",
+		"--show-error",
+		"--silent",
+		checksumsURL); err != nil {
+		logrus.Debugf("Downloading %s failed: %s", checksumsURL, stderr.String())
+		return fmt.Errorf("failed to download %s", checksumsURL)
+	}
+
+	name := url[strings.LastIndex(url, "/")+1:]
+	expected, err := parseChecksums(strings.NewReader(stdout.String()), name)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	checksum := hex.EncodeToString(hash.Sum(nil))
+	if checksum != expected {
+		logrus.Debugf("Checksum of %s is %s, expected %s", url, checksum, expected)
+		return fmt.Errorf("checksum of %s doesn't match %s", url, checksumsURL)
+	}
+
+	logrus.Debugf("Checksum of %s is %s", url, checksum)
+	return nil
+}
+
+// selfUpdateBinary downloads the new binary next to the old one, so that it
+// can be renamed over it, which doesn't disturb the commands that are still
+// running the old one.  The binary isn't run before its checksum is checked.
+// It returns an empty path if the downloaded binary is of the same version.
+// An older one is refused, unless it was asked for with '--url'.
+func selfUpdateBinary() (string, error) {
+	url := selfUpdateFlags.url
+	if url == "" {
+		url = selfUpdateURL + runtime.GOARCH
+	}
+
+	dir := filepath.Dir(executable)
+
+	file, err := os.CreateTemp(dir, ".toolbox-self-update-")
+	if err != nil {
+		logrus.Debugf("Creating a temporary file in %s failed: %s", dir, err)
+		return "", fmt.Errorf("failed to write to %s", dir)
+	}
+
+	path := file.Name()
+	file.Close()
+	defer os.Remove(path)
+
+	logrus.Debugf("Downloading %s to %s", url, path)
+
+	var stderr strings.Builder
+	if err := shell.Run("curl",
+		nil,
+		nil,
+		&stderr,
+		"--fail",
+		"--location",
+		"--output", path,
+		"--proto", "=https",
+		"--show-error",
+		"--silent",
+		url); err != nil {
+		logrus.Debugf("Downloading %s failed: %s", url, stderr.String())
+		return "", fmt.Errorf("failed to download %s", url)
+	}
+
+	if err := checkDownloadChecksum(path, url); err != nil {
+		return "", err
+	}
+
+	if err := os.Chmod(path, 0755); err != nil {
+		return "", fmt.Errorf("failed to make %s executable: %w", path, err)
+	}
+
+	newVersion, err := getExecutableVersion(path)
+	if err != nil {
+		return "", err
+	}
+
+	currentVersion := version.GetVersion()
+
+	switch compareVersions(newVersion, currentVersion) {
+	case 0:
+		return "", nil
+	case -1:
+		if selfUpdateFlags.url == "" {
+			var builder strings.Builder
+			fmt.Fprintf(&builder, "the latest release %s is older than %s\n", newVersion, currentVersion)
+			fmt.Fprintf(&builder, "Use '--url' to install an older release on purpose.")
+
+			errMsg := builder.String()
+			return "", errors.New(errMsg)
+		}
+
+		logrus.Debugf("Downgrading Toolbx from %s to %s", currentVersion, newVersion)
+	}
+
+	if err := os.Rename(path, executable); err != nil {
+		return "", fmt.Errorf("failed to replace %s: %w", executable, err)
+	}
+
+	return executable, nil
+}
+
+// compareVersions returns -1, 0 or 1 if version1 is older than, the same as,
+// or newer than version2, eg., 0.2 is older than 0.10.
+func compareVersions(version1, version2 string) int {
+	version1 = goversion.Normalize(version1)
+	version2 = goversion.Normalize(version2)
+	return goversion.CompareSimple(version1, version2)
+}
+
+func selfUpdateHelp(cmd *cobra.Command, args []string) {
+	if utils.IsInsideContainer() {
+		if !utils.IsInsideToolboxContainer() {
+			fmt.Fprintf(os.Stderr, "Error: this is not a Toolbx container\n")
+			return
+		}
+
+		if _, err := utils.ForwardToHost(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return
+		}
+
+		return
+	}
+
+	if err := showManual("toolbox-self-update"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return
+	}
+}
+
+// selfUpdateHomebrew returns the path to the new executable, which is in a
+// different directory of the Cellar than the old one, or an empty path if
+// Homebrew had nothing to upgrade.
+func selfUpdateHomebrew(formula string) (string, error) {
+	if err := shell.Run("brew", nil, os.Stdout, os.Stderr, "upgrade", formula); err != nil {
+		return "", fmt.Errorf("failed to upgrade Homebrew formula %s: %w", formula, err)
+	}
+
+	var stdout strings.Builder
+	if err := shell.Run("brew", nil, &stdout, nil, "--prefix", formula); err != nil {
+		return "", fmt.Errorf("failed to find Homebrew formula %s: %w", formula, err)
+	}
+
+	prefix := strings.TrimSpace(stdout.String())
+	newExecutable, err := filepath.EvalSymlinks(filepath.Join(prefix, "bin", executableBase))
+	if err != nil {
+		return "", fmt.Errorf("failed to find the upgraded executable: %w", err)
+	}
+
+	if newExecutable == executable {
+		return "", nil
+	}
+
+	return newExecutable, nil
+}
+
+// selfUpdateProvision brings what earlier versions set up outside the binary
+// up to date: the launchd jobs that run the old executable, and toolbox.sh
+// inside the containers, which was copied when they were created.  Failures
+// are only warnings, because the update itself already worked.
+func selfUpdateProvision(newExecutable string) {
+	oldExecutable := executable
+
+	if newExecutable != oldExecutable {
+		for _, label := range []string{machineAutostartLabel, reportHygieneLabel} {
+			if err := updateLaunchAgentExecutable(label, oldExecutable, newExecutable); err != nil {
+				showWarning("%s", err)
+			}
+		}
+	}
+
+	executable = newExecutable
+
+	containers, err := getContainers()
+	if err != nil {
+		showWarning("%s, toolbox.sh was not updated in them", err)
+		return
+	}
+
+	for _, container := range containers {
+		if err := copyToolboxSh(container.Name()); err != nil {
+			showWarning("%s", err)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+//go:build darwin
+
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetChecksumsURL(t *testing.T) {
+	url := getChecksumsURL(selfUpdateURL + "arm64")
+	assert.Equal(t, "https://github.com/nickmerrett/toolbox-mac/releases/latest/download/SHA256SUMS", url)
+}
+
+func TestParseChecksums(t *testing.T) {
+	const checksumArm64 = "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+	const checksumAmd64 = "60303ae22b998861bce3b28f33eec1be758a213c86c93c076dbe9f558c11c752"
+
+	checksums := checksumAmd64 + "  toolbox-darwin-amd64\n" + checksumArm64 + " *toolbox-darwin-arm64\n"
+
+	checksum, err := parseChecksums(strings.NewReader(checksums), "toolbox-darwin-arm64")
+	require.NoError(t, err)
+	assert.Equal(t, checksumArm64, checksum)
+
+	checksum, err = parseChecksums(strings.NewReader(checksums), "toolbox-darwin-amd64")
+	require.NoError(t, err)
+	assert.Equal(t, checksumAmd64, checksum)
+
+	_, err = parseChecksums(strings.NewReader(checksums), "toolbox-darwin-riscv64")
+	assert.EqualError(t, err, "no checksum for toolbox-darwin-riscv64 in SHA256SUMS")
+
+	_, err = parseChecksums(strings.NewReader("abc  toolbox-darwin-arm64\n"), "toolbox-darwin-arm64")
+	assert.EqualError(t, err, "invalid checksum for toolbox-darwin-arm64 in SHA256SUMS")
+}
+
+func TestCompareVersions(t *testing.T) {
+	assert.Equal(t, 0, compareVersions("0.2", "0.2"))
+	assert.Equal(t, 0, compareVersions("0.2", "0.2.0"))
+	assert.Equal(t, 1, compareVersions("0.10", "0.2"))
+	assert.Equal(t, -1, compareVersions("0.2", "0.10"))
+	assert.Equal(t, -1, compareVersions("0.2.1", "0.3"))
+}
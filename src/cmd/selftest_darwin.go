@@ -0,0 +1,341 @@
+//go:build darwin
+
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/containers/toolbox/pkg/i18n"
+	"github.com/containers/toolbox/pkg/podman"
+	"github.com/containers/toolbox/pkg/shell"
+	"github.com/containers/toolbox/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+type selftestStage struct {
+	name string
+	run  func() error
+}
+
+const (
+	selftestBaseImage = "docker.io/library/busybox:latest"
+	selftestImage     = "localhost/toolbox-selftest:latest"
+)
+
+var (
+	selftestFlags struct {
+		image string
+		keep  bool
+	}
+)
+
+var selftestCmd = &cobra.Command{
+	Use:   "selftest",
+	Short: "Check that Toolbx works from end to end (macOS version)",
+	RunE:  selftest,
+}
+
+func init() {
+	flags := selftestCmd.Flags()
+
+	flags.StringVarP(&selftestFlags.image,
+		"image",
+		"i",
+		"",
+		"Test with this image instead of one built from "+selftestBaseImage)
+
+	flags.BoolVar(&selftestFlags.keep,
+		"keep",
+		false,
+		"Keep the test container, instead of removing it at the end")
+
+	selftestCmd.SetHelpFunc(selftestHelp)
+	rootCmd.AddCommand(selftestCmd)
+}
+
+// selftest runs the same toolbox binary as a user would, against a throwaway
+// container, and reports each stage separately, so that a broken upgrade of
+// Podman, the Podman machine or Toolbx itself is narrowed down to the stage
+// that fails.  The stages after a failed one are skipped, but the container is
+// removed regardless.
+func selftest(cmd *cobra.Command, args []string) error {
+	if utils.IsInsideContainer() {
+		return errors.New("selftest is not supported inside a container")
+	}
+
+	if len(args) != 0 {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "too many arguments for \"selftest\"\n")
+		i18n.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	image := selftestFlags.image
+	container := fmt.Sprintf("toolbox-selftest-%d", os.Getpid())
+	created := false
+
+	stages := []selftestStage{
+		{"image", func() error {
+			return selftestPrepareImage(image)
+		}},
+		{"create", func() error {
+			testImage := image
+			if testImage == "" {
+				testImage = selftestImage
+			}
+
+			if err := selftestRunToolbox(nil, "--assumeyes", "create", "--image", testImage, container); err != nil {
+				return err
+			}
+
+			created = true
+			return nil
+		}},
+		{"init", func() error {
+			return selftestRunToolbox(nil, "run", "--container", container, "true")
+		}},
+		{"exec", func() error {
+			return selftestExec(container)
+		}},
+		{"shared-mount", func() error {
+			return selftestSharedMount(container)
+		}},
+	}
+
+	if !selftestFlags.keep {
+		stages = append(stages, selftestStage{"rm", func() error {
+			if err := selftestRunToolbox(nil, "rm", "--force", container); err != nil {
+				return err
+			}
+
+			created = false
+
+			if exists, _ := podman.ContainerExists(container); exists {
+				return fmt.Errorf("container %s still exists", container)
+			}
+
+			return nil
+		}})
+	}
+
+	failed := false
+
+	for _, stage := range stages {
+		if failed {
+			fmt.Printf("SKIP  %s\n", stage.name)
+			continue
+		}
+
+		start := time.Now()
+		err := stage.run()
+		duration := time.Since(start).Round(100 * time.Millisecond)
+
+		if err != nil {
+			failed = true
+			fmt.Printf("FAIL  %s (%s)\n", stage.name, duration)
+
+			errString := strings.ReplaceAll(err.Error(), "\n", "\n      ")
+			fmt.Printf("      %s\n", errString)
+			continue
+		}
+
+		fmt.Printf("PASS  %s (%s)\n", stage.name, duration)
+	}
+
+	if created {
+		if selftestFlags.keep {
+			fmt.Fprintf(os.Stderr, "Kept container %s\n", container)
+		} else if err := podman.RemoveContainer(container, true); err != nil {
+			logrus.Debugf("Removing container %s failed: %s", container, err)
+			showWarning("failed to remove container %s", container)
+		}
+	}
+
+	if failed {
+		return errors.New("self-test failed")
+	}
+
+	return nil
+}
+
+func selftestExec(container string) error {
+	const token = "toolbox-selftest"
+
+	var stdout strings.Builder
+	if err := selftestRunToolbox(&stdout, "run", "--container", container, "echo", token); err != nil {
+		return err
+	}
+
+	if output := strings.TrimSpace(stdout.String()); output != token {
+		return fmt.Errorf("expected %q from the container, got %q", token, output)
+	}
+
+	return nil
+}
+
+func selftestHelp(cmd *cobra.Command, args []string) {
+	if utils.IsInsideContainer() {
+		if !utils.IsInsideToolboxContainer() {
+			fmt.Fprintf(os.Stderr, "Error: this is not a Toolbx container\n")
+			return
+		}
+
+		if _, err := utils.ForwardToHost(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return
+		}
+
+		return
+	}
+
+	if err := showManual("toolbox-selftest"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return
+	}
+}
+
+// selftestPrepareImage builds the test image from busybox, which is small
+// enough to not make the test slow, or pulls the image asked for with
+// '--image'.
+func selftestPrepareImage(image string) error {
+	if image != "" {
+		if exists, _ := podman.ImageExists(image); exists {
+			return nil
+		}
+
+		if err := podman.Pull(image, ""); err != nil {
+			return fmt.Errorf("failed to pull image %s: %w", image, err)
+		}
+
+		return nil
+	}
+
+	contextDirectory, err := os.MkdirTemp("", "toolbox-selftest-")
+	if err != nil {
+		return fmt.Errorf("failed to create a build context: %w", err)
+	}
+
+	defer os.RemoveAll(contextDirectory)
+
+	containerfile := filepath.Join(contextDirectory, "Containerfile")
+	containerfileData := fmt.Sprintf("FROM %s\n", selftestBaseImage)
+	if err := os.WriteFile(containerfile, []byte(containerfileData), 0644); err != nil {
+		return fmt.Errorf("failed to create %s: %w", containerfile, err)
+	}
+
+	var stderr strings.Builder
+	buildArgs := []string{"--label", "com.github.containers.toolbox=true", "--tag", selftestImage, contextDirectory}
+
+	if err := podman.Build("", io.Discard, &stderr, buildArgs...); err != nil {
+		errString := strings.TrimSpace(stderr.String())
+		return fmt.Errorf("failed to build image %s: %s", selftestImage, errString)
+	}
+
+	return nil
+}
+
+// selftestRunToolbox runs a toolbox command with the same global options as
+// this one.  Its standard error is only shown if it fails.
+func selftestRunToolbox(stdout io.Writer, args ...string) error {
+	toolboxArgs := []string{"--log-level", rootFlags.logLevel}
+
+	if rootFlags.strict {
+		toolboxArgs = append(toolboxArgs, "--strict")
+	}
+
+	if rootFlags.system {
+		toolboxArgs = append(toolboxArgs, "--system")
+	}
+
+	toolboxArgs = append(toolboxArgs, args...)
+	logrus.Debugf("Running %s %s", executable, strings.Join(toolboxArgs, " "))
+
+	var stderr strings.Builder
+	if err := shell.Run(executable, nil, stdout, &stderr, toolboxArgs...); err != nil {
+		errString := strings.TrimSpace(stderr.String())
+		if errString == "" {
+			return fmt.Errorf("'toolbox %s' failed: %w", strings.Join(args, " "), err)
+		}
+
+		return fmt.Errorf("'toolbox %s' failed: %s", strings.Join(args, " "), errString)
+	}
+
+	return nil
+}
+
+// selftestSharedMount checks that files written on either side of the home
+// directory that is shared with the container show up on the other side.
+func selftestSharedMount(container string) error {
+	homeDir := getCurrentUserHomeDir()
+	if homeDir == "" {
+		return errors.New("failed to find the home directory")
+	}
+
+	sharedDir, err := os.MkdirTemp(homeDir, ".toolbox-selftest-")
+	if err != nil {
+		return fmt.Errorf("failed to create a directory in %s: %w", homeDir, err)
+	}
+
+	defer os.RemoveAll(sharedDir)
+
+	fromHost := filepath.Join(sharedDir, "from-host")
+	fromHostData := fmt.Sprintf("written by the host at %s", time.Now().Format(time.RFC3339Nano))
+	if err := os.WriteFile(fromHost, []byte(fromHostData), 0644); err != nil {
+		return fmt.Errorf("failed to create %s: %w", fromHost, err)
+	}
+
+	var stdout strings.Builder
+	if err := selftestRunToolbox(&stdout, "run", "--container", container, "cat", fromHost); err != nil {
+		return err
+	}
+
+	if output := stdout.String(); output != fromHostData {
+		return fmt.Errorf("expected %q in %s inside the container, got %q", fromHostData, fromHost, output)
+	}
+
+	fromContainer := filepath.Join(sharedDir, "from-container")
+	fromContainerData := "written by the container"
+	script := `printf '%s' "$1" >"$2"`
+
+	if err := selftestRunToolbox(nil,
+		"run", "--container", container,
+		"sh", "-c", script, "sh", fromContainerData, fromContainer); err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(fromContainer)
+	if err != nil {
+		return fmt.Errorf("failed to read %s written by the container: %w", fromContainer, err)
+	}
+
+	if output := string(data); output != fromContainerData {
+		return fmt.Errorf("expected %q in %s on the host, got %q", fromContainerData, fromContainer, output)
+	}
+
+	return nil
+}
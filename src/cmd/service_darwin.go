@@ -0,0 +1,528 @@
+//go:build darwin
+
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/containers/toolbox/pkg/i18n"
+	"github.com/containers/toolbox/pkg/podman"
+	"github.com/containers/toolbox/pkg/toolbox"
+	"github.com/containers/toolbox/pkg/utils"
+	"github.com/containers/toolbox/pkg/version"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"golang.org/x/sys/unix"
+)
+
+// serviceRequestMax limits the size of request bodies, which are small JSON
+// objects.
+const serviceRequestMax = 1 << 20
+
+// serviceContainer is how the service shows a container.  The names of the
+// fields are used as they are as JSON keys, like for 'toolbox inspect'.
+type serviceContainer struct {
+	Created string
+	ID      string
+	Image   string
+	Name    string
+	Status  string
+}
+
+type serviceCreateRequest struct {
+	Distro  string
+	Env     []string
+	Image   string
+	Name    string
+	Release string
+}
+
+// serviceListener only accepts connections from processes of the user that
+// runs the service.
+type serviceListener struct {
+	net.Listener
+	uid uint32
+}
+
+type serviceError struct {
+	Error string
+}
+
+type serviceExecRequest struct {
+	Command []string
+	Env     []string
+	User    string
+	WorkDir string
+}
+
+type serviceExecResponse struct {
+	ExitCode int
+	Stderr   string
+	Stdout   string
+}
+
+type serviceImage struct {
+	Created string
+	ID      string
+	Name    string
+}
+
+var (
+	serviceFlags struct {
+		socket string
+	}
+)
+
+var serviceCmd = &cobra.Command{
+	Use:   "service",
+	Short: "Serve an API to manage Toolbx containers on a socket (macOS version)",
+	RunE:  service,
+}
+
+func init() {
+	flags := serviceCmd.Flags()
+
+	flags.StringVar(&serviceFlags.socket,
+		"socket",
+		"",
+		"Listen on this Unix socket, instead of service.sock in the runtime directory")
+
+	serviceCmd.SetHelpFunc(serviceHelp)
+	rootCmd.AddCommand(serviceCmd)
+}
+
+// service serves an HTTP API on a Unix socket, so that editors and menu bar
+// apps can manage containers without running toolbox for every request.  Only
+// the user can access the socket, and the credentials of every connection are
+// checked too, because the permissions of a socket are not enforced
+// everywhere.
+func service(cmd *cobra.Command, args []string) error {
+	if utils.IsInsideContainer() {
+		return errors.New("service is not supported inside a container")
+	}
+
+	if len(args) != 0 {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "too many arguments for \"service\"\n")
+		i18n.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	if systemMode {
+		return errors.New("'--system' is not supported by service")
+	}
+
+	toolboxRuntimeDirectory, err := utils.GetRuntimeDirectory(currentUser)
+	if err != nil {
+		return err
+	}
+
+	lock := filepath.Join(toolboxRuntimeDirectory, "service.lock")
+	lockFile, err := utils.Flock(lock, syscall.LOCK_EX|syscall.LOCK_NB)
+	if err != nil {
+		logrus.Debugf("Serving the API: %s", err)
+		return errors.New("the service is already running")
+	}
+
+	defer lockFile.Close()
+
+	socket := serviceFlags.socket
+	if socket == "" {
+		socket = filepath.Join(toolboxRuntimeDirectory, "service.sock")
+	}
+
+	// The lock is held, so a socket that is left over is from a service
+	// that didn't exit cleanly.
+	if err := os.Remove(socket); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove %s: %w", socket, err)
+	}
+
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", socket, err)
+	}
+
+	defer os.Remove(socket)
+
+	if err := os.Chmod(socket, 0600); err != nil {
+		listener.Close()
+		return fmt.Errorf("failed to change the permissions of %s: %w", socket, err)
+	}
+
+	uid, err := strconv.ParseUint(currentUser.Uid, 10, 32)
+	if err != nil {
+		listener.Close()
+		return fmt.Errorf("failed to convert user ID to integer: %w", err)
+	}
+
+	server := &http.Server{
+		Handler:           newServiceHandler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go func() {
+		<-ctx.Done()
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			logrus.Debugf("Shutting down the service failed: %s", err)
+		}
+	}()
+
+	fmt.Fprintf(os.Stderr, "Listening on %s\n", socket)
+
+	if err := server.Serve(&serviceListener{listener, uint32(uid)}); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("failed to serve the API: %w", err)
+	}
+
+	return nil
+}
+
+// getPeerUID returns the user ID of the process at the other end of a
+// connection to a Unix socket.
+func getPeerUID(conn net.Conn) (uint32, error) {
+	unixConn, ok := conn.(*net.UnixConn)
+	if !ok {
+		return 0, errors.New("not a Unix socket")
+	}
+
+	rawConn, err := unixConn.SyscallConn()
+	if err != nil {
+		return 0, fmt.Errorf("failed to access the socket: %w", err)
+	}
+
+	var cred *unix.Xucred
+	var credErr error
+
+	err = rawConn.Control(func(fd uintptr) {
+		cred, credErr = unix.GetsockoptXucred(int(fd), unix.SOL_LOCAL, unix.LOCAL_PEERCRED)
+	})
+
+	if err == nil {
+		err = credErr
+	}
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to get the credentials of the peer: %w", err)
+	}
+
+	return cred.Uid, nil
+}
+
+func newServiceContainer(containerObj podman.Container) serviceContainer {
+	return serviceContainer{
+		Created: containerObj.Created(),
+		ID:      containerObj.ID(),
+		Image:   containerObj.Image(),
+		Name:    containerObj.Name(),
+		Status:  containerObj.Status(),
+	}
+}
+
+func newServiceHandler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /v1/containers", serviceListContainers)
+	mux.HandleFunc("POST /v1/containers", serviceCreateContainer)
+	mux.HandleFunc("DELETE /v1/containers/{name}", serviceRemoveContainer)
+	mux.HandleFunc("GET /v1/containers/{name}/enter", serviceEnterContainer)
+	mux.HandleFunc("POST /v1/containers/{name}/exec", serviceExecContainer)
+	mux.HandleFunc("GET /v1/images", serviceListImages)
+	mux.HandleFunc("GET /v1/version", serviceVersion)
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logrus.Debugf("Serving %s %s", r.Method, r.URL.Path)
+		mux.ServeHTTP(w, r)
+	})
+
+	return handler
+}
+
+// readServiceRequest decodes the JSON body of a request into request, and
+// writes the error response if that fails.
+func readServiceRequest(w http.ResponseWriter, r *http.Request, request interface{}) bool {
+	body := http.MaxBytesReader(w, r.Body, serviceRequestMax)
+
+	decoder := json.NewDecoder(body)
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(request); err != nil {
+		err := fmt.Errorf("invalid request: %w", err)
+		writeServiceError(w, http.StatusBadRequest, err)
+		return false
+	}
+
+	return true
+}
+
+func serviceCreateContainer(w http.ResponseWriter, r *http.Request) {
+	var request serviceCreateRequest
+	if !readServiceRequest(w, r, &request) {
+		return
+	}
+
+	if request.Name == "" {
+		writeServiceError(w, http.StatusBadRequest, errors.New("missing name"))
+		return
+	}
+
+	if exists, _ := podman.ContainerExists(request.Name); exists {
+		err := fmt.Errorf("container %s already exists", request.Name)
+		writeServiceError(w, http.StatusConflict, err)
+		return
+	}
+
+	var stderr bytes.Buffer
+
+	options := toolbox.CreateOptions{
+		Distro:  request.Distro,
+		Env:     request.Env,
+		Image:   request.Image,
+		Release: request.Release,
+		Stderr:  &stderr,
+	}
+
+	if err := toolbox.CreateContainer(r.Context(), request.Name, options); err != nil {
+		if errString := strings.TrimSpace(stderr.String()); errString != "" {
+			err = errors.New(strings.TrimPrefix(errString, "Error: "))
+		}
+
+		writeServiceError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	containerObj, err := podman.InspectContainer(request.Name)
+	if err != nil {
+		writeServiceError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeServiceResponse(w, http.StatusCreated, newServiceContainer(containerObj))
+}
+
+// serviceEnterContainer can't attach to the client's terminal, so it returns
+// the command that a terminal emulator should run instead.
+func serviceEnterContainer(w http.ResponseWriter, r *http.Request) {
+	containerObj, ok := serviceInspectContainer(w, r)
+	if !ok {
+		return
+	}
+
+	command := []string{executable, "enter", containerObj.Name()}
+	writeServiceResponse(w, http.StatusOK, map[string][]string{"Command": command})
+}
+
+func serviceExecContainer(w http.ResponseWriter, r *http.Request) {
+	containerObj, ok := serviceInspectContainer(w, r)
+	if !ok {
+		return
+	}
+
+	var request serviceExecRequest
+	if !readServiceRequest(w, r, &request) {
+		return
+	}
+
+	if len(request.Command) == 0 {
+		writeServiceError(w, http.StatusBadRequest, errors.New("missing command"))
+		return
+	}
+
+	var stderr, stdout bytes.Buffer
+
+	options := toolbox.ExecOptions{
+		Command: request.Command,
+		Env:     request.Env,
+		Stderr:  &stderr,
+		Stdout:  &stdout,
+		User:    request.User,
+		WorkDir: request.WorkDir,
+	}
+
+	exitCode, err := toolbox.Exec(r.Context(), containerObj.Name(), options)
+	if err != nil {
+		writeServiceError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	response := serviceExecResponse{
+		ExitCode: exitCode,
+		Stderr:   stderr.String(),
+		Stdout:   stdout.String(),
+	}
+
+	writeServiceResponse(w, http.StatusOK, response)
+}
+
+func serviceHelp(cmd *cobra.Command, args []string) {
+	if utils.IsInsideContainer() {
+		if !utils.IsInsideToolboxContainer() {
+			fmt.Fprintf(os.Stderr, "Error: this is not a Toolbx container\n")
+			return
+		}
+
+		if _, err := utils.ForwardToHost(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return
+		}
+
+		return
+	}
+
+	if err := showManual("toolbox-service"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return
+	}
+}
+
+// serviceInspectContainer looks up the Toolbx container named in the path of
+// the request, and writes the error response if there's none.
+func serviceInspectContainer(w http.ResponseWriter, r *http.Request) (podman.Container, bool) {
+	container := r.PathValue("name")
+
+	containerObj, err := podman.InspectContainer(container)
+	if err != nil || !containerObj.IsToolbx() || !isContainerVisible(containerObj) {
+		writeServiceError(w, http.StatusNotFound, createErrorContainerNotFound(container))
+		return nil, false
+	}
+
+	return containerObj, true
+}
+
+func serviceListContainers(w http.ResponseWriter, r *http.Request) {
+	containers, err := getContainers()
+	if err != nil {
+		writeServiceError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	response := make([]serviceContainer, 0, len(containers))
+	for _, containerObj := range containers {
+		response = append(response, newServiceContainer(containerObj))
+	}
+
+	writeServiceResponse(w, http.StatusOK, response)
+}
+
+func serviceListImages(w http.ResponseWriter, r *http.Request) {
+	images, err := getImages(true)
+	if err != nil {
+		writeServiceError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	response := make([]serviceImage, 0, len(images))
+	for _, image := range images {
+		response = append(response, serviceImage{
+			Created: image.Created,
+			ID:      image.ID,
+			Name:    image.Names[0],
+		})
+	}
+
+	writeServiceResponse(w, http.StatusOK, response)
+}
+
+func serviceRemoveContainer(w http.ResponseWriter, r *http.Request) {
+	containerObj, ok := serviceInspectContainer(w, r)
+	if !ok {
+		return
+	}
+
+	force, _ := strconv.ParseBool(r.URL.Query().Get("force"))
+	options := toolbox.RemoveOptions{Force: force, Owner: getRemoveOwner()}
+
+	if err := toolbox.Remove(containerObj, options); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, toolbox.ErrContainerProtected) {
+			status = http.StatusConflict
+		}
+
+		writeServiceError(w, status, err)
+		return
+	}
+
+	forgetContainerSettings(containerObj)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func serviceVersion(w http.ResponseWriter, r *http.Request) {
+	response := map[string]string{"Version": version.GetVersion()}
+	writeServiceResponse(w, http.StatusOK, response)
+}
+
+func writeServiceError(w http.ResponseWriter, status int, err error) {
+	logrus.Debugf("Serving the API: %s", err)
+	writeServiceResponse(w, status, serviceError{Error: err.Error()})
+}
+
+func writeServiceResponse(w http.ResponseWriter, status int, response interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logrus.Debugf("Writing the response failed: %s", err)
+	}
+}
+
+// Accept drops connections from other users, instead of returning an error,
+// which would stop the server.
+func (listener *serviceListener) Accept() (net.Conn, error) {
+	for {
+		conn, err := listener.Listener.Accept()
+		if err != nil {
+			return nil, err
+		}
+
+		uid, err := getPeerUID(conn)
+		if err != nil {
+			logrus.Debugf("Serving the API: %s", err)
+			conn.Close()
+			continue
+		}
+
+		if uid != listener.uid {
+			logrus.Debugf("Serving the API: refused connection from user ID %d", uid)
+			conn.Close()
+			continue
+		}
+
+		return conn, nil
+	}
+}
@@ -0,0 +1,328 @@
+//go:build darwin
+
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/containers/toolbox/pkg/i18n"
+	"github.com/containers/toolbox/pkg/podman"
+	"github.com/containers/toolbox/pkg/shell"
+	"github.com/containers/toolbox/pkg/utils"
+	"github.com/docker/go-units"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+type setupResources struct {
+	cpus     int
+	diskSize uint64
+	memory   uint64
+}
+
+// setupMinimumResources is what a Podman machine needs to comfortably run a
+// Toolbx container next to the rest of a desktop.  DiskSize is in GiB and
+// Memory in MiB, like for 'podman machine init'.
+var setupMinimumResources = setupResources{
+	cpus:     2,
+	diskSize: 20,
+	memory:   2048,
+}
+
+var (
+	setupFlags struct {
+		cpus     int
+		diskSize uint64
+		memory   uint64
+	}
+)
+
+var setupCmd = &cobra.Command{
+	Use:   "setup",
+	Short: "Set up Podman and the Podman machine for Toolbx (macOS version)",
+	RunE:  setup,
+}
+
+func init() {
+	flags := setupCmd.Flags()
+
+	flags.IntVar(&setupFlags.cpus,
+		"cpus",
+		0,
+		"Number of CPUs for a new Podman machine (default: half of the host's)")
+
+	flags.Uint64Var(&setupFlags.diskSize,
+		"disk-size",
+		0,
+		"Disk size in GiB for a new Podman machine (default: 100)")
+
+	flags.Uint64Var(&setupFlags.memory,
+		"memory",
+		0,
+		"Memory in MiB for a new Podman machine (default: a quarter of the host's)")
+
+	setupCmd.SetHelpFunc(setupHelp)
+	rootCmd.AddCommand(setupCmd)
+}
+
+// setup walks a new user through everything that Toolbx needs on macOS, and
+// only changes what is missing, so that running it again is harmless.  It ends
+// with 'toolbox selftest', because a machine that looks right can still fail
+// to run containers.
+func setup(cmd *cobra.Command, args []string) error {
+	if utils.IsInsideContainer() {
+		return errors.New("setup is not supported inside a container")
+	}
+
+	if len(args) != 0 {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "too many arguments for \"setup\"\n")
+		i18n.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	if setupFlags.cpus < 0 {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "invalid argument for '--cpus': must be positive\n")
+		i18n.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	if err := setupPodman(); err != nil {
+		return err
+	}
+
+	if err := setupMachine(); err != nil {
+		return err
+	}
+
+	if err := setupCheckHomeDirectory(); err != nil {
+		return err
+	}
+
+	showMessage("Checking that Toolbx works")
+
+	if err := selftestRunToolbox(os.Stdout, "selftest"); err != nil {
+		return err
+	}
+
+	showMessage("Toolbx is ready. Run '%s create' to create a container.", executableBase)
+	return nil
+}
+
+// getSetupResources recommends the resources of a new Podman machine from
+// the size of the host, so that the machine is neither starved nor takes
+// over the Mac.
+func getSetupResources() setupResources {
+	resources := setupResources{
+		cpus:     runtime.NumCPU() / 2,
+		diskSize: 100,
+		memory:   4096,
+	}
+
+	if memory, err := getHostValue("sysctl", "-n", "hw.memsize"); err == nil {
+		if hostMemory, err := strconv.ParseUint(memory, 10, 64); err == nil {
+			resources.memory = hostMemory / 4 / units.MiB
+		}
+	}
+
+	resources.cpus = max(resources.cpus, setupMinimumResources.cpus)
+	resources.memory = max(resources.memory, setupMinimumResources.memory)
+
+	if setupFlags.cpus != 0 {
+		resources.cpus = setupFlags.cpus
+	}
+
+	if setupFlags.diskSize != 0 {
+		resources.diskSize = setupFlags.diskSize
+	}
+
+	if setupFlags.memory != 0 {
+		resources.memory = setupFlags.memory
+	}
+
+	return resources
+}
+
+func setupCheckHomeDirectory() error {
+	homeDir := getCurrentUserHomeDir()
+	showMessage("Checking that %s is shared with the Podman machine", homeDir)
+
+	if err := podman.MachineSSH(nil, "test", "-d", homeDir); err != nil {
+		logrus.Debugf("Looking for %s in the Podman machine failed: %s", homeDir, err)
+
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "%s is not shared with the Podman machine\n", homeDir)
+		fmt.Fprintf(&builder, "Re-create the machine with 'podman machine init --volume %s:%s'.", homeDir, homeDir)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	return nil
+}
+
+// setupCheckMachineResources only warns about a machine that is too small,
+// because resizing it means stopping it, which would interrupt the
+// containers that are already using it.
+func setupCheckMachineResources(machine podman.Machine) {
+	var small []string
+
+	if machine.Resources.CPUs < setupMinimumResources.cpus {
+		small = append(small, fmt.Sprintf("--cpus %d", setupMinimumResources.cpus))
+	}
+
+	if machine.Resources.DiskSize < setupMinimumResources.diskSize {
+		small = append(small, fmt.Sprintf("--disk-size %d", setupMinimumResources.diskSize))
+	}
+
+	if machine.Resources.Memory < setupMinimumResources.memory {
+		small = append(small, fmt.Sprintf("--memory %d", setupMinimumResources.memory))
+	}
+
+	if len(small) == 0 {
+		return
+	}
+
+	if rootFlags.quiet {
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "Warning: Podman machine %s is smaller than recommended\n", machine.Name)
+	fmt.Fprintf(os.Stderr, "Resize it with 'podman machine stop' and 'podman machine set %s'.\n",
+		strings.Join(small, " "))
+}
+
+func setupHelp(cmd *cobra.Command, args []string) {
+	if utils.IsInsideContainer() {
+		if !utils.IsInsideToolboxContainer() {
+			fmt.Fprintf(os.Stderr, "Error: this is not a Toolbx container\n")
+			return
+		}
+
+		if _, err := utils.ForwardToHost(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return
+		}
+
+		return
+	}
+
+	if err := showManual("toolbox-setup"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return
+	}
+}
+
+func setupMachine() error {
+	machine, err := podman.MachineInspect()
+	if err != nil {
+		logrus.Debugf("Inspecting the Podman machine failed: %s", err)
+
+		resources := getSetupResources()
+		memory := units.BytesSize(float64(resources.memory * units.MiB))
+		diskSize := units.BytesSize(float64(resources.diskSize * units.GiB))
+
+		if !rootFlags.assumeYes {
+			prompt := i18n.Sprintf("Create a Podman machine with %d CPUs, %s of memory and %s of disk? [y/N]: ",
+				resources.cpus,
+				memory,
+				diskSize)
+
+			if !askForConfirmation(prompt) {
+				return errors.New("a Podman machine is required")
+			}
+		}
+
+		initArgs := []string{
+			"--cpus", strconv.Itoa(resources.cpus),
+			"--disk-size", strconv.FormatUint(resources.diskSize, 10),
+			"--memory", strconv.FormatUint(resources.memory, 10),
+		}
+
+		if err := podman.MachineInit(initArgs...); err != nil {
+			return fmt.Errorf("failed to create a Podman machine: %w", err)
+		}
+
+		if machine, err = podman.MachineInspect(); err != nil {
+			return fmt.Errorf("failed to inspect the Podman machine: %w", err)
+		}
+	}
+
+	setupCheckMachineResources(machine)
+
+	if machine.State == "running" {
+		return nil
+	}
+
+	showMessage("Starting Podman machine %s", machine.Name)
+
+	if err := podman.MachineStart(); err != nil {
+		return fmt.Errorf("failed to start Podman machine %s: %w", machine.Name, err)
+	}
+
+	return nil
+}
+
+// setupPodman installs Podman with Homebrew, because that's how most Macs
+// used for development get their command line tools.  Anything else is left
+// to the user.
+func setupPodman() error {
+	if _, err := exec.LookPath("podman"); err != nil {
+		logrus.Debugf("Looking up podman failed: %s", err)
+
+		if _, err := exec.LookPath("brew"); err != nil {
+			var builder strings.Builder
+			fmt.Fprintf(&builder, "podman(1) not found\n")
+			fmt.Fprintf(&builder, "Install it from https://podman.io or Homebrew from https://brew.sh.")
+
+			errMsg := builder.String()
+			return errors.New(errMsg)
+		}
+
+		if !rootFlags.assumeYes {
+			prompt := i18n.Sprintf("Podman is not installed. Install it with Homebrew? [y/N]: ")
+			if !askForConfirmation(prompt) {
+				return errors.New("podman(1) not found")
+			}
+		}
+
+		if err := shell.Run("brew", nil, os.Stdout, os.Stderr, "install", "podman"); err != nil {
+			return fmt.Errorf("failed to install Podman: %w", err)
+		}
+	}
+
+	version, err := podman.GetVersion()
+	if err != nil {
+		return fmt.Errorf("failed to get the Podman version: %w", err)
+	}
+
+	showMessage("Found Podman %s", version)
+	return nil
+}
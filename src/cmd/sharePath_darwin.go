@@ -0,0 +1,237 @@
+//go:build darwin
+
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/containers/toolbox/pkg/i18n"
+	"github.com/containers/toolbox/pkg/podman"
+	"github.com/containers/toolbox/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+const (
+	sharePathCheckScript = `if [ -L "$1" ]; then
+    [ "$(readlink "$1")" = "$2" ]
+elif [ -d "$1" ]; then
+    [ -z "$(ls -A "$1")" ]
+else
+    ! [ -e "$1" ]
+fi`
+
+	sharePathMoveScript = `set -e
+if [ -L "$1" ] && [ "$(readlink "$1")" = "$2" ]; then
+    exit 0
+fi
+if [ -e "$1" ]; then
+    if ! cp -R "$1"/. "$2"/; then
+        echo "failed to copy $1 to $2" >&2
+        exit 1
+    fi
+    rm -rf "$1"
+fi
+mkdir -p "$(dirname "$1")"
+ln -s "$2" "$1"`
+)
+
+var (
+	// sharePathSystemDirectories belong to the operating system of the
+	// containers, which breaks if they or anything in them is moved.
+	sharePathSystemDirectories = []string{
+		"/bin",
+		"/boot",
+		"/dev",
+		"/etc",
+		"/lib",
+		"/lib32",
+		"/lib64",
+		"/proc",
+		"/run",
+		"/sbin",
+		"/sys",
+		"/usr",
+		"/var",
+	}
+)
+
+var sharePathCmd = &cobra.Command{
+	Use:               "share-path",
+	Short:             "Share a directory of a Toolbx container with another one (macOS version)",
+	RunE:              sharePath,
+	ValidArgsFunction: completionEmpty,
+}
+
+func init() {
+	sharePathCmd.SetHelpFunc(sharePathHelp)
+	rootCmd.AddCommand(sharePathCmd)
+}
+
+// sharePath moves a directory of one container into the home directory, which
+// every container of the user mounts at the same location, and replaces it
+// with a symbolic link in both containers.  Podman can't add a volume to an
+// existing container, so this is the only way to share a directory without
+// recreating the containers.
+func sharePath(cmd *cobra.Command, args []string) error {
+	if utils.IsInsideContainer() {
+		return errors.New("share-path is not supported inside a container")
+	}
+
+	if len(args) != 2 {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "share-path needs a container with a path, and another container\n")
+		i18n.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	source, path, found := strings.Cut(args[0], ":")
+	if !found || source == "" || !filepath.IsAbs(path) {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "invalid argument %s: needs to be CONTAINER:PATH with an absolute PATH\n", args[0])
+		i18n.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	path = filepath.Clean(path)
+	target := args[1]
+
+	if source == target {
+		return errors.New("cannot share a path of a container with itself")
+	}
+
+	homeDir := getCurrentUserHomeDir()
+	if homeDir == "" {
+		return errors.New("failed to get the home directory")
+	}
+
+	if path == "/" || path == homeDir || strings.HasPrefix(homeDir, path+"/") {
+		return fmt.Errorf("cannot share %s", path)
+	}
+
+	if strings.HasPrefix(path, homeDir+"/") {
+		return fmt.Errorf("%s is already shared through the home directory", path)
+	}
+
+	if isSharePathSystemDirectory(path) {
+		return fmt.Errorf("cannot share %s, because it belongs to the operating system", path)
+	}
+
+	for _, container := range []string{source, target} {
+		if err := startSharePathContainer(container); err != nil {
+			return err
+		}
+	}
+
+	shareDir := filepath.Join(homeDir, ".local", "share", "toolbox", "shares", source, path)
+
+	if err := podman.ExecAsRoot(target, nil, "sh", "-c", sharePathCheckScript, "sh", path, shareDir); err != nil {
+		logrus.Debugf("Checking %s in container %s failed: %s", path, target, err)
+		return fmt.Errorf("%s already exists in container %s and is not empty", path, target)
+	}
+
+	if err := os.MkdirAll(shareDir, 0755); err != nil {
+		logrus.Debugf("Creating directory %s failed: %s", shareDir, err)
+		return fmt.Errorf("failed to create directory %s", shareDir)
+	}
+
+	logrus.Debugf("Moving %s of container %s to %s", path, source, shareDir)
+
+	if err := podman.ExecAsRoot(source, nil, "sh", "-c", sharePathMoveScript, "sh", path, shareDir); err != nil {
+		logrus.Debugf("Moving %s of container %s to %s failed: %s", path, source, shareDir, err)
+		return fmt.Errorf("failed to move %s of container %s to %s", path, source, shareDir)
+	}
+
+	if err := podman.ExecAsRoot(target, nil, "sh", "-c", sharePathMoveScript, "sh", path, shareDir); err != nil {
+		logrus.Debugf("Linking %s in container %s to %s failed: %s", path, target, shareDir, err)
+		return fmt.Errorf("failed to link %s in container %s to %s", path, target, shareDir)
+	}
+
+	fmt.Printf("Shared %s of container %s with container %s\n", path, source, target)
+	fmt.Printf("The files are in %s\n", shareDir)
+	return nil
+}
+
+func sharePathHelp(cmd *cobra.Command, args []string) {
+	if utils.IsInsideContainer() {
+		if !utils.IsInsideToolboxContainer() {
+			fmt.Fprintf(os.Stderr, "Error: this is not a Toolbx container\n")
+			return
+		}
+
+		if _, err := utils.ForwardToHost(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return
+		}
+
+		return
+	}
+
+	if err := showManual("toolbox-share-path"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return
+	}
+}
+
+func isSharePathSystemDirectory(path string) bool {
+	for _, directory := range sharePathSystemDirectories {
+		if path == directory || strings.HasPrefix(path, directory+"/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func startSharePathContainer(container string) error {
+	containerObj, err := podman.InspectContainer(container)
+	if err != nil {
+		return createErrorContainerNotFound(container)
+	}
+
+	if !containerObj.IsToolbx() {
+		return fmt.Errorf("%s is not a Toolbx container", container)
+	}
+
+	if err := checkContainerOwner(containerObj); err != nil {
+		return err
+	}
+
+	if containerObj.Status() == "running" {
+		return nil
+	}
+
+	logrus.Debugf("Starting container %s", container)
+
+	var stderr strings.Builder
+	if err := podman.Start(container, &stderr); err != nil {
+		logrus.Debugf("Starting container %s failed: %s", container, stderr.String())
+		return fmt.Errorf("failed to start container %s", container)
+	}
+
+	return nil
+}
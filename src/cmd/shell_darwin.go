@@ -0,0 +1,103 @@
+//go:build darwin
+
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/containers/toolbox/pkg/i18n"
+	"github.com/containers/toolbox/pkg/podman"
+	"github.com/containers/toolbox/pkg/term"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+// installPackageScript installs the package named $1 with whichever package
+// manager the image has.  It exits with 127 if there's none that it knows.
+const installPackageScript = `if command -v dnf >/dev/null 2>&1; then
+    dnf install --assumeyes "$1"
+elif command -v apt-get >/dev/null 2>&1; then
+    apt-get update && DEBIAN_FRONTEND=noninteractive apt-get install --yes "$1"
+elif command -v apk >/dev/null 2>&1; then
+    apk add "$1"
+elif command -v pacman >/dev/null 2>&1; then
+    pacman --sync --noconfirm "$1"
+elif command -v zypper >/dev/null 2>&1; then
+    zypper --non-interactive install "$1"
+else
+    exit 127
+fi`
+
+// isInstallShellEnabled checks the 'install-shell' option in the general
+// section of the configuration.  Installing is offered by default.
+func isInstallShellEnabled() bool {
+	if !viper.IsSet("general.install-shell") {
+		return true
+	}
+
+	enabled := viper.GetBool("general.install-shell")
+	return enabled
+}
+
+// offerToInstallShell offers to install the user's shell inside the
+// container, if it's missing.  macOS defaults to zsh, which most images don't
+// have, so 'toolbox enter' would otherwise always fall back to bash or sh.
+// The package is assumed to be named after the shell, which holds for bash,
+// fish, tcsh and zsh in all the distributions that Toolbx supports.
+func offerToInstallShell(container string, command []string) {
+	if !isInstallShellEnabled() {
+		return
+	}
+
+	if !rootFlags.assumeYes && (!term.IsTerminal(os.Stdin) || !term.IsTerminal(os.Stdout)) {
+		return
+	}
+
+	userShell := command[0]
+	if _, err := isCommandPresent(container, userShell); err == nil {
+		return
+	}
+
+	shellName := filepath.Base(userShell)
+	logrus.Debugf("Shell %s not found in container %s", userShell, container)
+
+	if !rootFlags.assumeYes {
+		prompt := i18n.Sprintf("Shell %s not found in container %s. Install it now? [y/N]", shellName, container)
+		if !askForConfirmation(prompt) {
+			i18n.Fprintf(os.Stderr, "Set 'install-shell = false' in toolbox.conf(5) to stop asking.\n")
+			return
+		}
+	}
+
+	s := showSpinner(fmt.Sprintf("Installing %s in container %s", shellName, container))
+	err := podman.ExecAsRoot(container, nil, "sh", "-c", installPackageScript, "sh", shellName)
+	stopSpinner(s)
+
+	if err != nil {
+		logrus.Debugf("Installing %s in container %s failed: %s", shellName, container, err)
+		fmt.Fprintf(os.Stderr, "Error: failed to install %s in container %s\n", shellName, container)
+		return
+	}
+
+	if _, err := isCommandPresent(container, userShell); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s is still not found in container %s\n", userShell, container)
+	}
+}
@@ -0,0 +1,409 @@
+//go:build darwin
+
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/containers/toolbox/pkg/i18n"
+	"github.com/containers/toolbox/pkg/podman"
+	"github.com/containers/toolbox/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// containerSnapshot is a checkpoint of a container's file system, committed
+// into an image called localhost/CONTAINER:snapshot-NAME.
+type containerSnapshot struct {
+	created string
+	id      string
+	name    string
+}
+
+const (
+	snapshotNameRegexp = "^[a-zA-Z0-9][a-zA-Z0-9_.-]*$"
+
+	snapshotTagPrefix = "snapshot-"
+)
+
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Checkpoint and roll back the file system of a Toolbx container (macOS version)",
+	RunE:  snapshotRun,
+}
+
+var snapshotCreateCmd = &cobra.Command{
+	Use:               "create",
+	Short:             "Checkpoint the file system of a Toolbx container",
+	RunE:              snapshotCreate,
+	ValidArgsFunction: completionContainerNameFirst,
+}
+
+var snapshotListCmd = &cobra.Command{
+	Use:               "list",
+	Short:             "List the snapshots of a Toolbx container",
+	RunE:              snapshotList,
+	ValidArgsFunction: completionContainerNameFirst,
+}
+
+var snapshotRestoreCmd = &cobra.Command{
+	Use:               "restore",
+	Short:             "Roll a Toolbx container back to a snapshot",
+	RunE:              snapshotRestore,
+	ValidArgsFunction: completionSnapshotNames,
+}
+
+var snapshotRmCmd = &cobra.Command{
+	Use:               "rm",
+	Short:             "Remove snapshots of a Toolbx container",
+	RunE:              snapshotRm,
+	ValidArgsFunction: completionSnapshotNames,
+}
+
+func init() {
+	snapshotCmd.AddCommand(snapshotCreateCmd)
+	snapshotCmd.AddCommand(snapshotListCmd)
+	snapshotCmd.AddCommand(snapshotRestoreCmd)
+	snapshotCmd.AddCommand(snapshotRmCmd)
+
+	snapshotCmd.SetHelpFunc(snapshotHelp)
+	rootCmd.AddCommand(snapshotCmd)
+}
+
+func snapshotRun(cmd *cobra.Command, args []string) error {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "missing command for \"snapshot\", eg., create, list or restore\n")
+	i18n.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+	errMsg := builder.String()
+	return errors.New(errMsg)
+}
+
+// snapshotCreate commits the container into an image, which Podman can do
+// while it's running, because the container is paused meanwhile.
+func snapshotCreate(cmd *cobra.Command, args []string) error {
+	if utils.IsInsideContainer() {
+		return errors.New("snapshot is not supported inside a container")
+	}
+
+	if len(args) != 1 && len(args) != 2 {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "snapshot create needs a container, and optionally a name for the snapshot\n")
+		i18n.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	container := args[0]
+
+	name := time.Now().Format("20060102150405")
+	if len(args) == 2 {
+		name = args[1]
+		if err := validateSnapshotName(name); err != nil {
+			return err
+		}
+	}
+
+	if _, err := getCapContainerDetails(container); err != nil {
+		return err
+	}
+
+	image := getSnapshotImage(container, name)
+
+	if exists, _ := podman.ImageExists(image); exists {
+		return fmt.Errorf("snapshot %s of container %s already exists", name, container)
+	}
+
+	s := showSpinner(fmt.Sprintf("Committing container %s", container))
+	err := podman.Commit(container, image)
+	stopSpinner(s)
+
+	if err != nil {
+		return fmt.Errorf("failed to commit container %s: %w", container, err)
+	}
+
+	showMessage("Created snapshot %s of container %s", name, container)
+	return nil
+}
+
+func snapshotList(cmd *cobra.Command, args []string) error {
+	if utils.IsInsideContainer() {
+		return errors.New("snapshot is not supported inside a container")
+	}
+
+	if len(args) != 1 {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "snapshot list needs a container\n")
+		i18n.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	container := args[0]
+
+	details, err := getCapContainerDetails(container)
+	if err != nil {
+		return err
+	}
+
+	snapshots, err := getSnapshots(container)
+	if err != nil {
+		return err
+	}
+
+	if len(snapshots) == 0 {
+		showMessage("Container %s has no snapshots", container)
+		return nil
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(writer, "%s\t%s\t%s\n", "NAME", "IMAGE ID", "CREATED")
+
+	for _, snapshot := range snapshots {
+		name := snapshot.name
+		if snapshot.id == details.Image {
+			name += " (current)"
+		}
+
+		fmt.Fprintf(writer, "%s\t%s\t%s\n", name, utils.ShortID(snapshot.id), snapshot.created)
+	}
+
+	writer.Flush()
+	return nil
+}
+
+// snapshotRestore can't roll back the file system of the existing container,
+// because Podman can't.  Instead, the container is recreated from the image
+// of the snapshot, like with 'toolbox cap set', and everything that changed
+// since is lost.
+func snapshotRestore(cmd *cobra.Command, args []string) error {
+	if utils.IsInsideContainer() {
+		return errors.New("snapshot is not supported inside a container")
+	}
+
+	if len(args) != 2 {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "snapshot restore needs a container and a snapshot\n")
+		i18n.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	container := args[0]
+	name := args[1]
+
+	details, err := getCapContainerDetails(container)
+	if err != nil {
+		return err
+	}
+
+	image := getSnapshotImage(container, name)
+
+	if exists, _ := podman.ImageExists(image); !exists {
+		return fmt.Errorf("snapshot %s of container %s not found", name, container)
+	}
+
+	changes, err := podman.Diff(container, image)
+	if err != nil {
+		logrus.Debugf("Comparing container %s with image %s failed: %s", container, image, err)
+	} else if changes.Len() == 0 {
+		showMessage("Container %s has not changed since snapshot %s", container, name)
+		return nil
+	} else {
+		showMessage("%d added, %d changed and %d deleted files will be lost",
+			len(changes.Added),
+			len(changes.Changed),
+			len(changes.Deleted))
+	}
+
+	if !rootFlags.assumeYes {
+		prompt := i18n.Sprintf("Roll container %s back to snapshot %s? [y/N]: ", container, name)
+		if !askForConfirmation(prompt) {
+			return nil
+		}
+	}
+
+	if details.State.Status == "running" {
+		s := showSpinner(fmt.Sprintf("Stopping container %s", container))
+		err := podman.Stop(container, 10)
+		stopSpinner(s)
+
+		if err != nil {
+			return fmt.Errorf("failed to stop container %s: %w", container, err)
+		}
+	}
+
+	timestamp := time.Now().Format("20060102150405")
+	privileges := getContainerPrivileges(details)
+
+	if err := recreateContainer(container, details, image, "restore-"+timestamp, privileges); err != nil {
+		return err
+	}
+
+	// An image committed by 'toolbox cap set' is only used by the container
+	// that was just removed, unlike the snapshots, which are kept.
+	if strings.HasPrefix(details.ImageName, "localhost/"+container+":cap-") {
+		if err := podman.RemoveImage(details.ImageName, false); err != nil {
+			logrus.Debugf("Removing image %s failed: %s", details.ImageName, err)
+		}
+	}
+
+	showMessage("Rolled container %s back to snapshot %s", container, name)
+	return nil
+}
+
+func snapshotRm(cmd *cobra.Command, args []string) error {
+	if utils.IsInsideContainer() {
+		return errors.New("snapshot is not supported inside a container")
+	}
+
+	if len(args) < 2 {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "snapshot rm needs a container and at least one snapshot\n")
+		i18n.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	container := args[0]
+
+	for _, name := range args[1:] {
+		image := getSnapshotImage(container, name)
+
+		if exists, _ := podman.ImageExists(image); !exists {
+			fmt.Fprintf(os.Stderr, "Error: snapshot %s of container %s not found\n", name, container)
+			continue
+		}
+
+		if err := podman.RemoveImage(image, false); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			continue
+		}
+	}
+
+	return nil
+}
+
+func snapshotHelp(cmd *cobra.Command, args []string) {
+	if utils.IsInsideContainer() {
+		if !utils.IsInsideToolboxContainer() {
+			fmt.Fprintf(os.Stderr, "Error: this is not a Toolbx container\n")
+			return
+		}
+
+		if _, err := utils.ForwardToHost(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return
+		}
+
+		return
+	}
+
+	if err := showManual("toolbox-snapshot"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return
+	}
+}
+
+func completionSnapshotNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 0 {
+		return completionContainerNames(cmd, args, toComplete)
+	}
+
+	if cmd.Name() == "restore" && len(args) >= 2 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var names []string
+	if snapshots, err := getSnapshots(args[0]); err == nil {
+		for _, snapshot := range snapshots {
+			names = append(names, snapshot.name)
+		}
+	}
+
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+func getSnapshotImage(container, name string) string {
+	image := fmt.Sprintf("localhost/%s:%s%s", container, snapshotTagPrefix, name)
+	return image
+}
+
+// getSnapshots returns the snapshots of a container sorted by name, which
+// puts those named after the time they were created in order.
+func getSnapshots(container string) ([]containerSnapshot, error) {
+	images, err := podman.GetImages("--filter", "label=com.github.containers.toolbox=true")
+	if err != nil {
+		logrus.Debugf("Listing the images failed: %s", err)
+		return nil, errors.New("failed to get snapshots")
+	}
+
+	prefix := "localhost/" + container + ":" + snapshotTagPrefix
+
+	var snapshots []containerSnapshot
+	for _, image := range images {
+		for _, imageName := range image.Names {
+			name, found := strings.CutPrefix(imageName, prefix)
+			if !found {
+				continue
+			}
+
+			snapshot := containerSnapshot{
+				created: image.Created,
+				id:      image.ID,
+				name:    name,
+			}
+
+			snapshots = append(snapshots, snapshot)
+		}
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].name < snapshots[j].name
+	})
+
+	return snapshots, nil
+}
+
+func validateSnapshotName(name string) error {
+	matched, _ := regexp.MatchString(snapshotNameRegexp, name)
+	if matched && len(snapshotTagPrefix+name) <= 128 {
+		return nil
+	}
+
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "invalid argument for the snapshot name: %s\n", name)
+	fmt.Fprintf(&builder, "Snapshot names must match '%s', and be shorter than 120 characters.\n",
+		snapshotNameRegexp)
+	i18n.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+	errMsg := builder.String()
+	return errors.New(errMsg)
+}
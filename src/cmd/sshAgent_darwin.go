@@ -0,0 +1,335 @@
+//go:build darwin
+
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/containers/toolbox/pkg/podman"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+// sshAgentTunnel forwards the socket of the host's SSH agent into the Podman
+// machine with 'ssh -R', because sockets on the Mac can't be shared with it
+// like files.
+type sshAgentTunnel struct {
+	agent  string
+	cmd    *exec.Cmd
+	exited chan struct{}
+}
+
+const (
+	// sshAgentDirectory is where the directory with the forwarded socket
+	// of the host's SSH agent is mounted inside containers.  The
+	// directory, rather than the socket, is mounted, so that the socket
+	// can come and go while the container runs.
+	sshAgentDirectory = "/run/toolbox/ssh-agent"
+
+	// sshAgentMachineDirectory is where the forwarded socket is inside the
+	// Podman machine, relative to the home directory of its user.  It's
+	// kept across restarts of the machine, unlike /run, because the
+	// containers can't start without it.
+	sshAgentMachineDirectory = ".local/share/toolbox/ssh-agent"
+
+	sshAgentSocket = "agent.sock"
+)
+
+var (
+	// sshAgents are the SSH agents that 'ssh-agent = "auto"' looks for,
+	// in order.  Their sockets are relative to the home directory, and the
+	// system one is in SSH_AUTH_SOCK.
+	sshAgents = []struct {
+		name   string
+		socket string
+	}{
+		{"1password", "Library/Group Containers/2BUA8C4S2C.com.1password/t/agent.sock"},
+		{"secretive", "Library/Containers/com.maxgoedjen.Secretive.SecretAgent/Data/socket.ssh"},
+		{"system", ""},
+	}
+)
+
+// getHostSSHAgent returns the socket of the host's SSH agent that is chosen
+// by 'ssh-agent' in the host section of the configuration, or an empty string
+// if there is none.
+func getHostSSHAgent() string {
+	option := viper.GetString("host.ssh-agent")
+	homeDir := getCurrentUserHomeDir()
+
+	agent, err := getHostSSHAgentSocket(option, homeDir, os.Getenv, isSocketListening)
+	if err != nil {
+		logrus.Debugf("Finding the host's SSH agent failed: %s", err)
+		return ""
+	}
+
+	return agent
+}
+
+// getHostSSHAgentSocket resolves the value of 'ssh-agent': the name of a known
+// agent, the path to the socket of another one, "auto" for the first known
+// agent that is running, or "none".
+func getHostSSHAgentSocket(option, homeDir string,
+	getenv func(string) string,
+	isListening func(string) bool) (string, error) {
+
+	switch option {
+	case "none":
+		return "", nil
+	case "", "auto":
+		for _, agent := range sshAgents {
+			socket, _ := getKnownSSHAgentSocket(agent.name, homeDir, getenv)
+			if socket != "" && isListening(socket) {
+				return socket, nil
+			}
+		}
+
+		return "", nil
+	}
+
+	socket, known := getKnownSSHAgentSocket(option, homeDir, getenv)
+
+	switch {
+	case known:
+	case strings.HasPrefix(option, "~/") && homeDir != "":
+		socket = filepath.Join(homeDir, option[2:])
+	case filepath.IsAbs(option):
+		socket = option
+	default:
+		return "", fmt.Errorf("invalid SSH agent %s", option)
+	}
+
+	if socket == "" || !isListening(socket) {
+		return "", fmt.Errorf("SSH agent %s is not running", option)
+	}
+
+	return socket, nil
+}
+
+// getKnownSSHAgentSocket returns the socket of one of sshAgents, and false if
+// there's no agent with that name.
+func getKnownSSHAgentSocket(name, homeDir string, getenv func(string) string) (string, bool) {
+	for _, agent := range sshAgents {
+		if agent.name != name {
+			continue
+		}
+
+		if agent.socket == "" {
+			return getenv("SSH_AUTH_SOCK"), true
+		}
+
+		if homeDir == "" {
+			return "", true
+		}
+
+		return filepath.Join(homeDir, agent.socket), true
+	}
+
+	return "", false
+}
+
+// getSSHAgentArgs returns the options for 'podman create' that share the
+// directory with the forwarded socket of the host's SSH agent with the
+// container.  It's shared, unless 'ssh-agent' is "none", even if no agent is
+// running yet, so that one can be chosen later without recreating the
+// container.  Only rootless Podman machines are supported, because the socket
+// belongs to the user of the machine.
+func getSSHAgentArgs() ([]string, error) {
+	if viper.GetString("host.ssh-agent") == "none" {
+		return nil, nil
+	}
+
+	machine, err := podman.MachineInspect()
+	if err != nil {
+		logrus.Debugf("Inspecting the Podman machine failed: %s", err)
+		return nil, warnOrFail(errors.New("failed to share the host's SSH agent with the container"))
+	}
+
+	if machine.Rootful {
+		logrus.Debug("Not sharing the host's SSH agent with a rootful Podman machine")
+		return nil, nil
+	}
+
+	directory, err := getSSHAgentMachineDirectory()
+	if err != nil {
+		logrus.Debugf("Creating the directory for the SSH agent in the Podman machine failed: %s", err)
+		return nil, warnOrFail(errors.New("failed to share the host's SSH agent with the container"))
+	}
+
+	logrus.Debugf("Sharing the host's SSH agent from %s in the Podman machine", directory)
+
+	args := []string{"--volume", directory + ":" + sshAgentDirectory}
+	return args, nil
+}
+
+// getSSHAgentEnviron points SSH_AUTH_SOCK at the forwarded socket of the
+// host's SSH agent, if the container has it.
+func getSSHAgentEnviron(containerObj podman.Container) []string {
+	if viper.GetString("host.ssh-agent") == "none" {
+		return nil
+	}
+
+	if !slices.Contains(containerObj.Mounts(), sshAgentDirectory) {
+		return nil
+	}
+
+	socket := path.Join(sshAgentDirectory, sshAgentSocket)
+	return []string{"SSH_AUTH_SOCK=" + socket}
+}
+
+// getSSHAgentMachineDirectory creates the directory for the forwarded socket
+// of the host's SSH agent inside the Podman machine, if needed, and returns
+// its absolute path there.
+func getSSHAgentMachineDirectory() (string, error) {
+	var stdout strings.Builder
+
+	script := fmt.Sprintf("mkdir -p -m 700 %s && cd %s && pwd", sshAgentMachineDirectory, sshAgentMachineDirectory)
+	if err := podman.MachineSSH(&stdout, script); err != nil {
+		return "", err
+	}
+
+	directory := strings.TrimSpace(stdout.String())
+	if !path.IsAbs(directory) {
+		return "", fmt.Errorf("invalid directory %s", directory)
+	}
+
+	return directory, nil
+}
+
+func isSocketListening(socket string) bool {
+	conn, err := net.DialTimeout("unix", socket, time.Second)
+	if err != nil {
+		return false
+	}
+
+	conn.Close()
+	return true
+}
+
+// start forwards the socket of the agent to the directory in the Podman
+// machine that containers share.  A socket left behind by an earlier tunnel
+// is removed first, because sshd(8) doesn't replace it.
+func (tunnel *sshAgentTunnel) start(agent string) error {
+	machine, err := podman.MachineInspect()
+	if err != nil {
+		return err
+	}
+
+	if machine.Rootful {
+		return errors.New("the Podman machine is rootful")
+	}
+
+	directory, err := getSSHAgentMachineDirectory()
+	if err != nil {
+		return err
+	}
+
+	socket := path.Join(directory, sshAgentSocket)
+	if err := podman.MachineSSH(nil, "rm -f "+socket); err != nil {
+		return err
+	}
+
+	args := []string{
+		"-N",
+		"-i", machine.SSHConfig.IdentityPath,
+		"-o", "BatchMode=yes",
+		"-o", "ExitOnForwardFailure=yes",
+		"-o", "LogLevel=ERROR",
+		"-o", "ServerAliveInterval=30",
+		"-o", "StrictHostKeyChecking=no",
+		"-o", "UserKnownHostsFile=/dev/null",
+		"-p", strconv.Itoa(machine.SSHConfig.Port),
+		"-R", socket + ":" + agent,
+		machine.SSHConfig.RemoteUsername + "@localhost",
+	}
+
+	cmd := exec.Command("ssh", args...)
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	exited := make(chan struct{})
+
+	go func() {
+		if err := cmd.Wait(); err != nil {
+			logrus.Debugf("Forwarding the SSH agent %s: %s", agent, err)
+		}
+
+		close(exited)
+	}()
+
+	tunnel.agent = agent
+	tunnel.cmd = cmd
+	tunnel.exited = exited
+	return nil
+}
+
+func (tunnel *sshAgentTunnel) stop() {
+	if tunnel.cmd == nil {
+		return
+	}
+
+	logrus.Debugf("Stopping the forwarding of the SSH agent %s", tunnel.agent)
+
+	if err := tunnel.cmd.Process.Kill(); err != nil {
+		logrus.Debugf("Killing ssh failed: %s", err)
+	}
+
+	<-tunnel.exited
+	tunnel.agent = ""
+	tunnel.cmd = nil
+	tunnel.exited = nil
+}
+
+// update makes the tunnel forward the agent, or nothing if it's empty.  The
+// tunnel is started again if it exited, eg., because the Podman machine was
+// restarted.
+func (tunnel *sshAgentTunnel) update(agent string) {
+	if tunnel.cmd != nil {
+		select {
+		case <-tunnel.exited:
+			tunnel.cmd = nil
+		default:
+			if tunnel.agent == agent {
+				return
+			}
+
+			tunnel.stop()
+		}
+	}
+
+	if agent == "" {
+		return
+	}
+
+	logrus.Debugf("Forwarding the SSH agent %s into the Podman machine", agent)
+
+	if err := tunnel.start(agent); err != nil {
+		logrus.Debugf("Forwarding the SSH agent %s failed: %s", agent, err)
+	}
+}
@@ -0,0 +1,124 @@
+//go:build darwin
+
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testOnePasswordSocket = "/Users/alice/Library/Group Containers/2BUA8C4S2C.com.1password/t/agent.sock"
+	testSecretiveSocket   = "/Users/alice/Library/Containers/com.maxgoedjen.Secretive.SecretAgent/Data/socket.ssh"
+	testSystemSocket      = "/private/tmp/com.apple.launchd.abc/Listeners"
+)
+
+func TestGetHostSSHAgentSocket(t *testing.T) {
+	getenv := func(name string) string {
+		if name == "SSH_AUTH_SOCK" {
+			return testSystemSocket
+		}
+
+		return ""
+	}
+
+	testCases := []struct {
+		name      string
+		option    string
+		listening []string
+		expected  string
+		err       string
+	}{
+		{
+			name:      "auto prefers 1Password",
+			option:    "auto",
+			listening: []string{testOnePasswordSocket, testSecretiveSocket, testSystemSocket},
+			expected:  testOnePasswordSocket,
+		},
+		{
+			name:      "auto falls back to Secretive",
+			option:    "",
+			listening: []string{testSecretiveSocket, testSystemSocket},
+			expected:  testSecretiveSocket,
+		},
+		{
+			name:      "auto falls back to the system agent",
+			option:    "auto",
+			listening: []string{testSystemSocket},
+			expected:  testSystemSocket,
+		},
+		{
+			name:     "auto without agents",
+			option:   "auto",
+			expected: "",
+		},
+		{
+			name:      "none",
+			option:    "none",
+			listening: []string{testSystemSocket},
+			expected:  "",
+		},
+		{
+			name:      "system",
+			option:    "system",
+			listening: []string{testOnePasswordSocket, testSystemSocket},
+			expected:  testSystemSocket,
+		},
+		{
+			name:      "path in the home directory",
+			option:    "~/.gnupg/S.gpg-agent.ssh",
+			listening: []string{"/Users/alice/.gnupg/S.gpg-agent.ssh"},
+			expected:  "/Users/alice/.gnupg/S.gpg-agent.ssh",
+		},
+		{
+			name:   "agent that is not running",
+			option: "secretive",
+			err:    "SSH agent secretive is not running",
+		},
+		{
+			name:   "invalid",
+			option: "keychain",
+			err:    "invalid SSH agent keychain",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			isListening := func(socket string) bool {
+				for _, listening := range tc.listening {
+					if socket == listening {
+						return true
+					}
+				}
+
+				return false
+			}
+
+			socket, err := getHostSSHAgentSocket(tc.option, "/Users/alice", getenv, isListening)
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, socket)
+		})
+	}
+}
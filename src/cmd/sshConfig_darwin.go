@@ -0,0 +1,231 @@
+//go:build darwin
+
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/containers/toolbox/pkg/i18n"
+	"github.com/containers/toolbox/pkg/shell"
+	"github.com/containers/toolbox/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	sshConfigFlags struct {
+		port  int
+		setup bool
+	}
+)
+
+var sshConfigCmd = &cobra.Command{
+	Use:               "ssh-config",
+	Short:             "Print an SSH configuration to reach Toolbx containers by name (macOS version)",
+	RunE:              sshConfig,
+	ValidArgsFunction: completionContainerNames,
+}
+
+var sshProxyCmd = &cobra.Command{
+	Use:    "ssh-proxy",
+	Short:  "Connect the standard input and output to SSH inside a Toolbx container",
+	Hidden: true,
+	RunE:   sshProxy,
+}
+
+func init() {
+	flags := sshConfigCmd.Flags()
+
+	flags.IntVarP(&sshConfigFlags.port,
+		"port",
+		"p",
+		0,
+		"Reach the Toolbx container at this port on localhost, as forwarded by 'toolbox gateway'")
+
+	flags.BoolVar(&sshConfigFlags.setup,
+		"setup",
+		false,
+		"Set up the OpenSSH server inside the Toolbx containers, like 'toolbox create --ssh'")
+
+	sshConfigCmd.SetHelpFunc(sshConfigHelp)
+	rootCmd.AddCommand(sshConfigCmd)
+
+	rootCmd.AddCommand(sshProxyCmd)
+}
+
+// sshConfig prints a Host block of ssh_config(5) for each container, to be
+// saved in a file that ~/.ssh/config includes, so that ssh(1), and everything
+// built on it, reach the containers as toolbox-CONTAINER.  By default, the
+// connections go through 'toolbox ssh-proxy', and so through 'podman exec',
+// and need nothing to be published or forwarded.
+func sshConfig(cmd *cobra.Command, args []string) error {
+	if utils.IsInsideContainer() {
+		return errors.New("ssh-config is not supported inside a container")
+	}
+
+	if cmd.Flag("port").Changed {
+		if len(args) != 1 {
+			var builder strings.Builder
+			fmt.Fprintf(&builder, "option --port needs exactly one container\n")
+			i18n.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+			errMsg := builder.String()
+			return errors.New(errMsg)
+		}
+
+		if sshConfigFlags.port < 1 || sshConfigFlags.port > 65535 {
+			var builder strings.Builder
+			fmt.Fprintf(&builder, "invalid argument for '--port': %d\n", sshConfigFlags.port)
+			i18n.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+			errMsg := builder.String()
+			return errors.New(errMsg)
+		}
+	}
+
+	containers := args
+	if len(containers) == 0 {
+		toolboxContainers, err := getContainers()
+		if err != nil {
+			return err
+		}
+
+		for _, container := range toolboxContainers {
+			containers = append(containers, container.Name())
+		}
+	} else {
+		for _, container := range containers {
+			if _, err := getCapContainerDetails(container); err != nil {
+				return err
+			}
+		}
+	}
+
+	keyFile, err := getSSHKeyFile()
+	if err != nil {
+		return err
+	}
+
+	if sshConfigFlags.setup {
+		if _, err := ensureSSHKey(); err != nil {
+			return err
+		}
+
+		for _, container := range containers {
+			if err := setUpSSHDInContainer(container, keyFile); err != nil {
+				return err
+			}
+		}
+	}
+
+	fmt.Printf("# Generated by 'toolbox ssh-config'.  Include this file from ~/.ssh/config.\n")
+
+	for _, container := range containers {
+		var proxyCommand string
+		if !cmd.Flag("port").Changed {
+			proxyCommand = getSSHProxyCommand(container)
+		}
+
+		host := "toolbox-" + container
+		fmt.Printf("\n%s", getSSHHostConfig(host, keyFile, proxyCommand, sshConfigFlags.port))
+	}
+
+	return nil
+}
+
+func sshConfigHelp(cmd *cobra.Command, args []string) {
+	if utils.IsInsideContainer() {
+		if !utils.IsInsideToolboxContainer() {
+			fmt.Fprintf(os.Stderr, "Error: this is not a Toolbx container\n")
+			return
+		}
+
+		if _, err := utils.ForwardToHost(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return
+		}
+
+		return
+	}
+
+	if err := showManual("toolbox-ssh-config"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return
+	}
+}
+
+// sshProxy is the ProxyCommand of the hosts that 'toolbox ssh-config' prints.
+// It starts the container, if it isn't running, and hands the standard input
+// and output over to sshd(8) in inetd mode inside it.  Nothing else can be
+// printed to the standard output, because that is where SSH talks.
+func sshProxy(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return errors.New("ssh-proxy needs a container")
+	}
+
+	container := args[0]
+
+	if err := startContainerAndWait(container); err != nil {
+		return err
+	}
+
+	endContainerSession := startSSHDSession(container)
+	defer endContainerSession()
+
+	if err := shell.Run("podman", os.Stdin, os.Stdout, os.Stderr, getSSHDExecArgs(container)...); err != nil {
+		logrus.Debugf("Running sshd in container %s failed: %s", container, err)
+		return fmt.Errorf("failed to connect to SSH in container %s", container)
+	}
+
+	return nil
+}
+
+// getSSHProxyCommand returns the ProxyCommand for a container, which ssh(1)
+// runs with the user's shell.
+func getSSHProxyCommand(container string) string {
+	toolboxCommand := executable
+	if strings.ContainsAny(toolboxCommand, " '\"") {
+		toolboxCommand = "'" + strings.ReplaceAll(toolboxCommand, "'", `'\''`) + "'"
+	}
+
+	proxyCommand := fmt.Sprintf("%s ssh-proxy %s", toolboxCommand, container)
+	return proxyCommand
+}
+
+func setUpSSHDInContainer(container, keyFile string) error {
+	s := showSpinner(fmt.Sprintf("Starting container %s", container))
+	err := startContainerAndWait(container)
+	stopSpinner(s)
+
+	if err != nil {
+		return err
+	}
+
+	logrus.Debugf("Setting up the OpenSSH server in container %s with %s", container, filepath.Base(keyFile))
+
+	if err := setUpSSHD(container, keyFile); err != nil {
+		return err
+	}
+
+	return nil
+}
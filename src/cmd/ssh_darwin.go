@@ -0,0 +1,230 @@
+//go:build darwin
+
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/containers/toolbox/pkg/podman"
+	"github.com/containers/toolbox/pkg/shell"
+	"github.com/containers/toolbox/pkg/utils"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// sshKeyName is the SSH key in ~/.ssh that is authorized inside Toolbx
+	// containers.
+	sshKeyName = "toolbox_ed25519"
+
+	// sshdConfig is the configuration of sshd(8) inside the container.
+	// It only ever runs in inetd mode for a connection that comes through
+	// 'podman exec', so it doesn't listen on any port.  The authorized key
+	// can't be in ~/.ssh, because the home directory is shared with macOS.
+	sshdConfig = "/etc/ssh/sshd_config_toolbox"
+
+	// sshdSetupScript generates the host keys of sshd(8), and writes its
+	// configuration and the authorized key in $1.  Without PAM, sshd(8)
+	// refuses users whose password is locked, as it is for those that
+	// useradd(8) adds, even with a key.
+	sshdSetupScript = `set -e
+mkdir -p /run/sshd
+ssh-keygen -A >/dev/null
+printf '%s\n' "$1" >/etc/ssh/toolbox_authorized_keys
+chmod 644 /etc/ssh/toolbox_authorized_keys
+cat >` + sshdConfig + ` <<'END'
+AuthorizedKeysFile /etc/ssh/toolbox_authorized_keys
+KbdInteractiveAuthentication no
+PasswordAuthentication no
+PermitRootLogin no
+PrintMotd no
+Subsystem sftp internal-sftp
+END
+if [ -f /etc/pam.d/sshd ]; then
+    echo 'UsePAM yes' >>` + sshdConfig + `
+fi`
+)
+
+// ensureSSHKey returns the private SSH key that is authorized inside Toolbx
+// containers, and generates it without a passphrase if it doesn't exist.  It
+// only ever opens Toolbx containers through Podman or localhost.
+func ensureSSHKey() (string, error) {
+	keyFile, err := getSSHKeyFile()
+	if err != nil {
+		return "", err
+	}
+
+	if utils.PathExists(keyFile) && utils.PathExists(keyFile+".pub") {
+		return keyFile, nil
+	}
+
+	sshDir := filepath.Dir(keyFile)
+
+	if err := os.MkdirAll(sshDir, 0700); err != nil {
+		return "", fmt.Errorf("failed to create directory %s: %w", sshDir, err)
+	}
+
+	logrus.Debugf("Generating SSH key %s", keyFile)
+
+	args := []string{"-q", "-t", "ed25519", "-N", "", "-C", "toolbox", "-f", keyFile}
+	if err := shell.Run("ssh-keygen", nil, nil, os.Stderr, args...); err != nil {
+		return "", fmt.Errorf("failed to generate SSH key %s: %w", keyFile, err)
+	}
+
+	return keyFile, nil
+}
+
+// getSSHDExecArgs returns the arguments for podman(1) that run sshd(8) in
+// inetd mode inside the container, talking SSH over the standard input and
+// output.
+func getSSHDExecArgs(container string) []string {
+	args := []string{
+		"--log-level", podman.LogLevel.String(),
+		"exec",
+		"--interactive",
+		"--user", "root:root",
+		container,
+		"/usr/sbin/sshd", "-i", "-f", sshdConfig,
+	}
+
+	return args
+}
+
+func getSSHKeyFile() (string, error) {
+	homeDir := getCurrentUserHomeDir()
+	if homeDir == "" {
+		return "", errors.New("failed to find the home directory")
+	}
+
+	keyFile := filepath.Join(homeDir, ".ssh", sshKeyName)
+	return keyFile, nil
+}
+
+// getSSHHostConfig returns a Host block of ssh_config(5) for a Toolbx
+// container, which is reached either through proxyCommand, or at port on
+// localhost.  The host keys are generated inside each container, and change
+// when it's recreated, so they aren't checked for what never leaves the Mac.
+func getSSHHostConfig(host, keyFile, proxyCommand string, port int) string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "Host %s\n", host)
+
+	if proxyCommand != "" {
+		fmt.Fprintf(&builder, "    ProxyCommand %s\n", proxyCommand)
+	} else {
+		fmt.Fprintf(&builder, "    HostName localhost\n")
+		fmt.Fprintf(&builder, "    Port %s\n", strconv.Itoa(port))
+	}
+
+	fmt.Fprintf(&builder, "    User %s\n", currentUser.Username)
+	fmt.Fprintf(&builder, "    IdentityFile %s\n", keyFile)
+	fmt.Fprintf(&builder, "    IdentitiesOnly yes\n")
+	fmt.Fprintf(&builder, "    StrictHostKeyChecking no\n")
+	fmt.Fprintf(&builder, "    UserKnownHostsFile /dev/null\n")
+	fmt.Fprintf(&builder, "    LogLevel ERROR\n")
+
+	hostConfig := builder.String()
+	return hostConfig
+}
+
+func installSSHD(container string) error {
+	var err error
+
+	// Arch Linux doesn't split the OpenSSH server into its own package.
+	for _, packageName := range []string{"openssh-server", "openssh"} {
+		s := showSpinner(fmt.Sprintf("Installing %s in container %s", packageName, container))
+		err = podman.ExecAsRoot(container, nil, "sh", "-c", installPackageScript, "sh", packageName)
+		stopSpinner(s)
+
+		if err == nil {
+			return nil
+		}
+
+		logrus.Debugf("Installing %s in container %s failed: %s", packageName, container, err)
+	}
+
+	return fmt.Errorf("failed to install the OpenSSH server in container %s", container)
+}
+
+// provisionSSH sets up SSH inside a new container with 'toolbox create
+// --ssh'.  A failure only leads to a warning, unless '--strict' was given,
+// because the container itself is fine, and 'toolbox ssh-config --setup' can
+// try again.
+func provisionSSH(container string) error {
+	if !createFlags.ssh {
+		return nil
+	}
+
+	if err := startContainerAndWait(container); err != nil {
+		return warnOrFail(err)
+	}
+
+	keyFile, err := ensureSSHKey()
+	if err != nil {
+		return warnOrFail(err)
+	}
+
+	if err := setUpSSHD(container, keyFile); err != nil {
+		return warnOrFail(err)
+	}
+
+	return nil
+}
+
+// setUpSSHD installs the OpenSSH server inside the container, if it's
+// missing, and sets it up to accept the key.  The container needs to be
+// running.
+func setUpSSHD(container, keyFile string) error {
+	publicKey, err := os.ReadFile(keyFile + ".pub")
+	if err != nil {
+		return fmt.Errorf("failed to read SSH key %s.pub: %w", keyFile, err)
+	}
+
+	authorizedKey := strings.TrimSpace(string(publicKey))
+
+	if err := podman.ExecAsRoot(container, nil, "test", "-x", "/usr/sbin/sshd"); err != nil {
+		if err := installSSHD(container); err != nil {
+			return err
+		}
+	}
+
+	if err := podman.ExecAsRoot(container, nil, "sh", "-c", sshdSetupScript, "sh", authorizedKey); err != nil {
+		logrus.Debugf("Setting up sshd in container %s failed: %s", container, err)
+		return fmt.Errorf("failed to set up the OpenSSH server in container %s", container)
+	}
+
+	return nil
+}
+
+// startSSHDSession takes the lock of a session in the container for as long as
+// an SSH connection to it lasts, like 'toolbox enter' does, so that it isn't
+// stopped while in use.
+func startSSHDSession(container string) func() {
+	containerObj, err := podman.InspectContainer(container)
+	if err != nil {
+		logrus.Debugf("Inspecting container %s failed: %s", container, err)
+		return func() {}
+	}
+
+	endContainerSession := startContainerSession(containerObj)
+	return endContainerSession
+}
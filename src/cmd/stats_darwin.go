@@ -0,0 +1,396 @@
+//go:build darwin
+
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/containers/toolbox/pkg/i18n"
+	"github.com/containers/toolbox/pkg/podman"
+	"github.com/containers/toolbox/pkg/term"
+	"github.com/containers/toolbox/pkg/utils"
+	"github.com/docker/go-units"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+type machineStats struct {
+	cpus         int
+	loadAverage  string
+	memAvailable uint64
+	memTotal     uint64
+	swapFree     uint64
+	swapTotal    uint64
+}
+
+// machineMemoryLowPercent is how much of the Podman machine's memory needs to
+// be available before it is pointed out as the bottleneck.
+const machineMemoryLowPercent = 10
+
+var (
+	topFlags struct {
+		delay int
+	}
+)
+
+var statsCmd = &cobra.Command{
+	Use:               "stats",
+	Short:             "Show the resource usage of Toolbx containers (macOS version)",
+	RunE:              stats,
+	ValidArgsFunction: completionContainerNamesFiltered,
+}
+
+var topCmd = &cobra.Command{
+	Use:               "top",
+	Short:             "Keep showing the resource usage of Toolbx containers (macOS version)",
+	RunE:              top,
+	ValidArgsFunction: completionContainerNamesFiltered,
+}
+
+func init() {
+	flags := topCmd.Flags()
+
+	flags.IntVarP(&topFlags.delay,
+		"delay",
+		"d",
+		2,
+		"Seconds to wait between updates")
+
+	statsCmd.SetHelpFunc(statsHelp)
+	rootCmd.AddCommand(statsCmd)
+
+	topCmd.SetHelpFunc(topHelp)
+	rootCmd.AddCommand(topCmd)
+}
+
+func stats(cmd *cobra.Command, args []string) error {
+	if utils.IsInsideContainer() {
+		return errors.New("stats is not supported inside a container")
+	}
+
+	if err := showStats(os.Stdout, args); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func top(cmd *cobra.Command, args []string) error {
+	if utils.IsInsideContainer() {
+		return errors.New("top is not supported inside a container")
+	}
+
+	if topFlags.delay <= 0 {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "invalid argument for '--delay'\n")
+		i18n.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt)
+	defer signal.Stop(interrupts)
+
+	delay := time.Duration(topFlags.delay) * time.Second
+	ticker := time.NewTicker(delay)
+	defer ticker.Stop()
+
+	clearScreen := term.IsTerminal(os.Stdout) && !rootFlags.accessible
+
+	for {
+		// Each snapshot takes a while to collect, so it is drawn all at
+		// once to avoid flickering.
+		var snapshot bytes.Buffer
+		if err := showStats(&snapshot, args); err != nil {
+			return err
+		}
+
+		if clearScreen {
+			fmt.Fprintf(os.Stdout, "\033[H\033[2J")
+		} else {
+			fmt.Fprintf(os.Stdout, "\n")
+		}
+
+		if _, err := snapshot.WriteTo(os.Stdout); err != nil {
+			return err
+		}
+
+		select {
+		case <-interrupts:
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// getMachineStats reads the number of CPUs, the load average and the memory
+// usage of the Podman machine in one round trip through 'podman machine
+// ssh'.
+func getMachineStats() (machineStats, error) {
+	var stdout strings.Builder
+	if err := podman.MachineSSH(&stdout, "nproc", ";", "cat", "/proc/loadavg", "/proc/meminfo"); err != nil {
+		return machineStats{}, err
+	}
+
+	output := stdout.String()
+	machine, err := parseMachineStats(output)
+	if err != nil {
+		return machineStats{}, err
+	}
+
+	return machine, nil
+}
+
+// getStatsContainers returns the names of the running Toolbx containers,
+// after making sure that the ones asked for are running Toolbx containers.
+func getStatsContainers(containers []string) ([]string, error) {
+	if len(containers) == 0 {
+		toolboxContainers, err := getContainers()
+		if err != nil {
+			return nil, err
+		}
+
+		var names []string
+
+		for _, container := range toolboxContainers {
+			if container.Status() == "running" {
+				name := container.Name()
+				names = append(names, name)
+			}
+		}
+
+		return names, nil
+	}
+
+	for _, container := range containers {
+		containerObj, err := podman.InspectContainer(container)
+		if err != nil {
+			return nil, createErrorContainerNotFound(container)
+		}
+
+		if !containerObj.IsToolbx() {
+			return nil, fmt.Errorf("%s is not a Toolbx container", container)
+		}
+
+		if status := containerObj.Status(); status != "running" {
+			return nil, fmt.Errorf("container %s is not running", container)
+		}
+	}
+
+	return containers, nil
+}
+
+func parseMachineStats(output string) (machineStats, error) {
+	var machine machineStats
+
+	scanner := bufio.NewScanner(strings.NewReader(output))
+
+	if !scanner.Scan() {
+		return machineStats{}, errors.New("missing number of CPUs")
+	}
+
+	cpusString := strings.TrimSpace(scanner.Text())
+	cpus, err := strconv.Atoi(cpusString)
+	if err != nil {
+		return machineStats{}, fmt.Errorf("failed to parse number of CPUs %s: %w", cpusString, err)
+	}
+
+	machine.cpus = cpus
+
+	if !scanner.Scan() {
+		return machineStats{}, errors.New("missing load average")
+	}
+
+	loadAverage := strings.Fields(scanner.Text())
+	if len(loadAverage) < 3 {
+		return machineStats{}, errors.New("failed to parse load average")
+	}
+
+	machine.loadAverage = strings.Join(loadAverage[:3], " ")
+
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+
+		fields := strings.Fields(value)
+		if len(fields) == 0 {
+			continue
+		}
+
+		kiB, err := strconv.ParseUint(fields[0], 10, 64)
+		if err != nil {
+			return machineStats{}, fmt.Errorf("failed to parse %s: %w", key, err)
+		}
+
+		size := kiB * 1024
+
+		switch key {
+		case "MemAvailable":
+			machine.memAvailable = size
+		case "MemTotal":
+			machine.memTotal = size
+		case "SwapFree":
+			machine.swapFree = size
+		case "SwapTotal":
+			machine.swapTotal = size
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return machineStats{}, err
+	}
+
+	if machine.memTotal == 0 {
+		return machineStats{}, errors.New("missing total memory")
+	}
+
+	return machine, nil
+}
+
+// showStats writes the resource usage of the containers, followed by the
+// totals of the Podman machine.  The containers can't use more than the
+// machine has, regardless of how much the Mac has, so running low on it is
+// pointed out.
+func showStats(w io.Writer, containers []string) error {
+	names, err := getStatsContainers(containers)
+	if err != nil {
+		return err
+	}
+
+	var containerStats []podman.ContainerStats
+
+	if len(names) != 0 {
+		containerStats, err = podman.GetContainerStats(names...)
+		if err != nil {
+			logrus.Debugf("Getting the resource usage of containers failed: %s", err)
+			return errors.New("failed to get the resource usage of containers")
+		}
+	}
+
+	writer := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+		"CONTAINER NAME",
+		"CPU %",
+		"MEM USAGE / LIMIT",
+		"MEM %",
+		"NET I/O",
+		"BLOCK I/O",
+		"PIDS")
+
+	for _, containerStat := range containerStats {
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			containerStat.Name,
+			containerStat.CPUPercent,
+			containerStat.MemUsage,
+			containerStat.MemPercent,
+			containerStat.NetIO,
+			containerStat.BlockIO,
+			containerStat.PIDs)
+	}
+
+	writer.Flush()
+
+	machine, err := getMachineStats()
+	if err != nil {
+		logrus.Debugf("Getting the resource usage of the Podman machine failed: %s", err)
+		showWarning("failed to get the resource usage of the Podman machine")
+		return nil
+	}
+
+	memUsed := machine.memTotal - machine.memAvailable
+	memUsedPercent := memUsed * 100 / machine.memTotal
+
+	fmt.Fprintf(w, "\n")
+	fmt.Fprintf(w, "Podman machine: %d CPUs, load average %s\n", machine.cpus, machine.loadAverage)
+	fmt.Fprintf(w, "Memory: %s / %s (%d%%)\n",
+		units.HumanSize(float64(memUsed)),
+		units.HumanSize(float64(machine.memTotal)),
+		memUsedPercent)
+
+	if machine.swapTotal != 0 {
+		swapUsed := machine.swapTotal - machine.swapFree
+		fmt.Fprintf(w, "Swap: %s / %s\n",
+			units.HumanSize(float64(swapUsed)),
+			units.HumanSize(float64(machine.swapTotal)))
+	}
+
+	if machine.memAvailable*100/machine.memTotal < machineMemoryLowPercent {
+		fmt.Fprintf(w, "\n")
+		fmt.Fprintf(w, "The Podman machine is running out of memory, not this Mac.\n")
+		fmt.Fprintf(w, "Give it more with: podman machine stop; podman machine set --memory MiB; podman machine start\n")
+	}
+
+	return nil
+}
+
+func statsHelp(cmd *cobra.Command, args []string) {
+	if utils.IsInsideContainer() {
+		if !utils.IsInsideToolboxContainer() {
+			fmt.Fprintf(os.Stderr, "Error: this is not a Toolbx container\n")
+			return
+		}
+
+		if _, err := utils.ForwardToHost(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return
+		}
+
+		return
+	}
+
+	if err := showManual("toolbox-stats"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return
+	}
+}
+
+func topHelp(cmd *cobra.Command, args []string) {
+	if utils.IsInsideContainer() {
+		if !utils.IsInsideToolboxContainer() {
+			fmt.Fprintf(os.Stderr, "Error: this is not a Toolbx container\n")
+			return
+		}
+
+		if _, err := utils.ForwardToHost(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return
+		}
+
+		return
+	}
+
+	if err := showManual("toolbox-top"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return
+	}
+}
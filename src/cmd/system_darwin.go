@@ -0,0 +1,133 @@
+//go:build darwin
+
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+
+	"github.com/containers/toolbox/pkg/podman"
+	"github.com/containers/toolbox/pkg/toolbox"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+const (
+	// defaultSystemConnection is the Podman connection to the shared
+	// machine, which an administrator sets up for everybody on the Mac.
+	defaultSystemConnection = "toolbox-system"
+
+	ownerLabel = toolbox.OwnerLabel
+)
+
+var (
+	systemMode bool
+)
+
+// checkContainerOwner makes sure that, in system mode, users only enter their
+// own containers.
+func checkContainerOwner(containerObj podman.Container) error {
+	if !systemMode {
+		return nil
+	}
+
+	owner := containerObj.Labels()[ownerLabel]
+	if owner != currentUser.Username {
+		return fmt.Errorf("container %s belongs to %s", containerObj.Name(), owner)
+	}
+
+	return nil
+}
+
+// getContainerOwner returns the user that a new container is set up for.
+// That's the current user, unless an administrator is provisioning a
+// container for somebody else in system mode.
+func getContainerOwner() (*user.User, error) {
+	if createFlags.owner == "" {
+		return currentUser, nil
+	}
+
+	if !systemMode {
+		return nil, fmt.Errorf("'--owner' needs '--system'")
+	}
+
+	owner, err := user.Lookup(createFlags.owner)
+	if err != nil {
+		logrus.Debugf("Looking up user %s failed: %s", createFlags.owner, err)
+		return nil, fmt.Errorf("user %s not found", createFlags.owner)
+	}
+
+	return owner, nil
+}
+
+func getOwnerLabelArgs(owner *user.User) []string {
+	if !systemMode {
+		return nil
+	}
+
+	ownerLabelArg := ownerLabel + "=" + owner.Username
+	return []string{"--label", ownerLabelArg}
+}
+
+// getRemoveOwner returns the user whose containers toolbox.Remove may remove,
+// or nothing outside system mode, where all containers are the user's own.
+func getRemoveOwner() string {
+	if !systemMode {
+		return ""
+	}
+
+	return currentUser.Username
+}
+
+// isContainerVisible hides the containers of other users on the shared
+// machine.
+func isContainerVisible(containerObj podman.Container) bool {
+	if !systemMode {
+		return true
+	}
+
+	owner := containerObj.Labels()[ownerLabel]
+	return owner == currentUser.Username
+}
+
+// setUpSystemMode points Podman at the shared machine if asked for with
+// '--system', or by the administrator with 'system = true' in the general
+// section of /etc/containers/toolbox.conf.  Its images are shared by all
+// users, so that a lab Mac can be provisioned once, while the containers
+// carry the name of their owner in a label.
+func setUpSystemMode() error {
+	systemMode = rootFlags.system || viper.GetBool("general.system")
+	if !systemMode {
+		return nil
+	}
+
+	connection := defaultSystemConnection
+	if viper.IsSet("general.system-connection") {
+		connection = viper.GetString("general.system-connection")
+	}
+
+	logrus.Debugf("Using the shared Podman machine through connection %s", connection)
+
+	if err := os.Setenv("CONTAINER_CONNECTION", connection); err != nil {
+		return fmt.Errorf("failed to set CONTAINER_CONNECTION: %w", err)
+	}
+
+	return nil
+}
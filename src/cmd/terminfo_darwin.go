@@ -0,0 +1,144 @@
+//go:build darwin
+
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"regexp"
+
+	"github.com/containers/toolbox/pkg/podman"
+	"github.com/containers/toolbox/pkg/shell"
+	"github.com/containers/toolbox/pkg/term"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	fallbackTerm = "xterm-256color"
+
+	// installTerminfo exits successfully if the entry is already present,
+	// or could be compiled from the source on its standard input.  If the
+	// present entry disagrees with the terminal about the backspace key,
+	// a corrected copy is compiled into /etc/terminfo, which ncurses
+	// searches first.
+	installTerminfo = `term="$1"
+kbs="$2"
+first=$(printf '%.1s' "$term")
+for dir in /etc/terminfo /lib/terminfo /usr/lib/terminfo /usr/share/terminfo; do
+    [ -e "$dir/$first/$term" ] || continue
+    [ "$kbs" = "" ] && exit 0
+    command -v infocmp >/dev/null 2>&1 || exit 0
+    command -v tic >/dev/null 2>&1 || exit 0
+    infocmp -x -1 "$term" | grep -qF "kbs=$kbs," && exit 0
+    infocmp -x -1 "$term" | sed "s/kbs=[^,]*,/kbs=$kbs,/" | tic -x -o /etc/terminfo - || :
+    exit 0
+done
+command -v tic >/dev/null 2>&1 || exit 1
+tic -x -`
+)
+
+var (
+	sttyEraseRegexp   = regexp.MustCompile(`(^|[\s;])erase = (\S+);`)
+	terminfoKbsRegexp = regexp.MustCompile(`kbs=[^,]*,`)
+)
+
+// getTermEnviron makes sure that the container has a terminfo(5) entry for
+// the host's TERM.  Terminals like kitty, WezTerm and Ghostty use their own
+// TERM values that most images lack, which breaks backspace and colors.  The
+// entry is copied from the host with infocmp(1) and compiled with tic(1)
+// inside the container, and if that fails TERM falls back to xterm-256color.
+//
+// The backspace key of the entry is made to match what the terminal actually
+// sends.  Terminal and iTerm2 send ^? while the xterm entries of ncurses say
+// ^H, which makes backspace delete the wrong way in some editors.
+func getTermEnviron(container string) []string {
+	term := os.Getenv("TERM")
+	if term == "" || term == "dumb" {
+		return nil
+	}
+
+	kbs := getTerminalBackspace()
+
+	var source bytes.Buffer
+	if term != fallbackTerm {
+		if err := shell.Run("infocmp", nil, &source, nil, "-x", term); err != nil {
+			logrus.Debugf("Reading terminfo entry %s on the host failed: %s", term, err)
+			source.Reset()
+		} else if kbs != "" {
+			data := terminfoKbsRegexp.ReplaceAllLiteral(source.Bytes(), []byte("kbs="+kbs+","))
+			source.Reset()
+			source.Write(data)
+		}
+	}
+
+	if err := podman.ExecAsRoot(container, &source, "sh", "-c", installTerminfo, "sh", term, kbs); err != nil {
+		logrus.Debugf("Installing terminfo entry %s in container %s failed: %s", term, container, err)
+
+		if term == fallbackTerm {
+			return nil
+		}
+
+		logrus.Debugf("Falling back to TERM=%s", fallbackTerm)
+
+		if err := podman.ExecAsRoot(container,
+			nil,
+			"sh",
+			"-c",
+			installTerminfo,
+			"sh",
+			fallbackTerm,
+			kbs); err != nil {
+			logrus.Debugf("Installing terminfo entry %s in container %s failed: %s",
+				fallbackTerm,
+				container,
+				err)
+		}
+
+		return []string{"TERM=" + fallbackTerm}
+	}
+
+	return nil
+}
+
+// getTerminalBackspace returns what the backspace key of the terminal sends,
+// in the notation of terminfo(5), as told by the erase character of stty(1).
+// An empty string is returned if it's not known.
+func getTerminalBackspace() string {
+	if !term.IsTerminal(os.Stdin) {
+		return ""
+	}
+
+	var stdout bytes.Buffer
+	if err := shell.Run("stty", os.Stdin, &stdout, nil, "-a"); err != nil {
+		logrus.Debugf("Reading the terminal settings failed: %s", err)
+		return ""
+	}
+
+	matches := sttyEraseRegexp.FindStringSubmatch(stdout.String())
+	if matches == nil {
+		return ""
+	}
+
+	erase := matches[2]
+	if erase != "^?" && erase != "^H" {
+		return ""
+	}
+
+	return erase
+}
@@ -0,0 +1,78 @@
+//go:build darwin
+
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+
+	"github.com/containers/toolbox/pkg/term"
+	"github.com/containers/toolbox/pkg/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// setTerminalTitle sets the title of the terminal window or tab to the name
+// of the container and its image, and for iTerm2 a badge too, so that
+// sessions in different containers can be told apart.  The returned
+// environment variables let toolbox.sh set the title again at every prompt,
+// because the shell inside the container often overwrites it, and the returned
+// function restores the title and badge.
+//
+// The title is saved on the xterm(1) title stack, which iTerm2 and most other
+// terminals support, except Terminal.app, which goes back to its own title if
+// an empty one is set.
+func setTerminalTitle(container, image string) ([]string, func()) {
+	if !term.IsTerminal(os.Stdout) {
+		return nil, func() {}
+	}
+
+	basename := utils.ImageReferenceGetBasename(image)
+	if tag := utils.ImageReferenceGetTag(image); tag != "" {
+		basename = basename + ":" + tag
+	}
+
+	title := fmt.Sprintf("⬢ %s (%s)", container, basename)
+	logrus.Debugf("Setting the terminal title to %s", title)
+
+	termProgram := os.Getenv("TERM_PROGRAM")
+
+	fmt.Printf("\033[22;0t")
+	fmt.Printf("\033]0;%s\007", title)
+
+	if termProgram == "iTerm.app" {
+		badge := fmt.Sprintf("⬢ %s\n%s", container, basename)
+		badgeBase64 := base64.StdEncoding.EncodeToString([]byte(badge))
+		fmt.Printf("\033]1337;SetBadgeFormat=%s\007", badgeBase64)
+	}
+
+	environ := []string{"TOOLBOX_TITLE=" + title}
+
+	return environ, func() {
+		if termProgram == "iTerm.app" {
+			fmt.Printf("\033]1337;SetBadgeFormat=\007")
+		}
+
+		if termProgram == "Apple_Terminal" {
+			fmt.Printf("\033]0;\007")
+		} else {
+			fmt.Printf("\033[23;0t")
+		}
+	}
+}
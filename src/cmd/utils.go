@@ -33,8 +33,10 @@ import (
 	"strings"
 	"syscall"
 
+	"github.com/containers/toolbox/pkg/podman"
 	"github.com/containers/toolbox/pkg/utils"
 	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
 	"golang.org/x/sys/unix"
 )
 
@@ -359,6 +361,11 @@ func createErrorSudoersDNotFound() error {
 	return errors.New(errMsg)
 }
 
+// forgetContainerSettings does nothing on Linux, because 'toolbox config'
+// is only available on macOS.
+func forgetContainerSettings(containerObj podman.Container) {
+}
+
 func getCDIFileForNvidia(targetUser *user.User) (string, error) {
 	toolboxRuntimeDirectory, err := utils.GetRuntimeDirectory(targetUser)
 	if err != nil {
@@ -385,6 +392,83 @@ func getCurrentUserHomeDir() string {
 	return currentUser.HomeDir
 }
 
+// checkContainerOwner is a no-op on Linux, because there's no system mode.
+func checkContainerOwner(containerObj podman.Container) error {
+	return nil
+}
+
+// getRemoveOwner returns nothing on Linux, because there's no system mode.
+func getRemoveOwner() string {
+	return ""
+}
+
+// checkSecretOptions rejects '--secret' on Linux, where there's no keychain to
+// take the secrets from.
+func checkSecretOptions(specs []string) error {
+	if len(specs) == 0 {
+		return nil
+	}
+
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "option --secret is only supported on macOS\n")
+	fmt.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+	errMsg := builder.String()
+	return errors.New(errMsg)
+}
+
+func completionSecretNames(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+	return nil, cobra.ShellCompDirectiveNoFileComp
+}
+
+// getContainerWorkingDirectory returns dir as it is on Linux, because the
+// host's file system is available at the same paths inside the container.
+func getContainerWorkingDirectory(container, dir string) string {
+	return dir
+}
+
+// getCredentialsEnviron returns nothing on Linux, because the home directory
+// and the host's sockets are shared with the container as they are.
+func getCredentialsEnviron(containerObj podman.Container) []string {
+	return nil
+}
+
+// getKeyboardEnviron returns nothing on Linux, because the container shares
+// the host's display server, which knows the keyboard layout.
+func getKeyboardEnviron() []string {
+	return nil
+}
+
+// getLocaleEnviron returns nothing on Linux, because LANG is forwarded as it
+// is and the host's locales are usable inside the container.
+func getLocaleEnviron() []string {
+	return nil
+}
+
+// getShellCommand returns the user's shell as a login shell on Linux, where
+// containers don't have settings for it.
+func getShellCommand(container, userShell string) []string {
+	return []string{userShell, "-l"}
+}
+
+// getSSHAgentEnviron returns nothing on Linux, because SSH_AUTH_SOCK is
+// forwarded as it is and the host's sockets are shared with the container.
+func getSSHAgentEnviron(containerObj podman.Container) []string {
+	return nil
+}
+
+// getTermEnviron returns nothing on Linux, because the images usually match
+// the host's distribution and ship the same terminfo(5) entries.
+func getTermEnviron(container string) []string {
+	return nil
+}
+
+// getTimeZoneEnviron returns nothing on Linux, because /etc/localtime is bind
+// mounted from the host.
+func getTimeZoneEnviron() []string {
+	return nil
+}
+
 func getUsageForCommonCommands() string {
 	var builder strings.Builder
 	builder.WriteString("create    Create a new Toolbx container\n")
@@ -395,6 +479,22 @@ func getUsageForCommonCommands() string {
 	return usage
 }
 
+// injectSecrets does nothing on Linux, because checkSecretOptions rejects
+// secrets, and containers never have any.
+func injectSecrets(containerObj podman.Container, specs []string, user string) ([]string, error) {
+	return nil, nil
+}
+
+// isContainerVisible is always true on Linux, because there's no system mode.
+func isContainerVisible(containerObj podman.Container) bool {
+	return true
+}
+
+// offerToInstallShell does nothing on Linux, because the images usually match
+// the host's distribution and ship the same shells.
+func offerToInstallShell(container string, command []string) {
+}
+
 func poll(pollFn pollFunc, eventFD int32, fds ...int32) error {
 	if len(fds) == 0 {
 		panic("file descriptors not specified")
@@ -503,9 +603,65 @@ func resolveContainerAndImageNames(container, containerArg, distroCLI, imageCLI,
 		}
 	}
 
+	logFields.setContainer(container, image)
 	return container, image, release, nil
 }
 
+func getBugReportHint() string {
+	return "Please report it at https://github.com/containers/toolbox/issues"
+}
+
+// logErrorToFile is a no-op on Linux, because the persistent log file is only
+// kept on macOS.
+func logErrorToFile(errMsg string) {
+}
+
+// setUpLanguage is a no-op on Linux, because the messages are only
+// translated on macOS.
+func setUpLanguage() {
+}
+
+// setUpLogFile is a no-op on Linux, because the persistent log file is only
+// kept on macOS.
+func setUpLogFile() {
+}
+
+// runPlugin is a no-op on Linux, because plugins are only supported on macOS.
+func runPlugin(args []string) error {
+	return nil
+}
+
+// setUpSystemMode rejects '--system' on Linux, because it's only meant for
+// the shared Podman machine of a Mac.
+func setUpSystemMode() error {
+	if rootFlags.system {
+		return errors.New("'--system' is only supported on macOS")
+	}
+
+	return nil
+}
+
+// setUpStrictMode rejects '--strict' on Linux, because none of the degraded
+// behaviour that it turns into failures on macOS happens there.
+func setUpStrictMode() error {
+	if rootFlags.strict {
+		return errors.New("'--strict' is only supported on macOS")
+	}
+
+	return nil
+}
+
+// setTerminalTitle is a no-op on Linux, because 'toolbox enter' tells VTE
+// based terminals about the container with an escape sequence instead.
+func setTerminalTitle(container, image string) ([]string, func()) {
+	return nil, func() {}
+}
+
+// showEnterBanner is a no-op on Linux, because the host and the containers
+// share so much that there's little to tell apart.
+func showEnterBanner(containerObj podman.Container) {
+}
+
 // showManual tries to open the specified manual page using man on stdout
 func showManual(manual string) error {
 	manBinary, err := exec.LookPath("man")
@@ -544,6 +700,29 @@ func showManual(manual string) error {
 	return nil
 }
 
+// startAutoStoppedMachine is a no-op on Linux, because there is no Podman
+// machine.
+func startAutoStoppedMachine(cmd *cobra.Command) error {
+	return nil
+}
+
+// startContainerSession is a no-op on Linux, because idle containers are
+// only stopped on macOS.
+func startContainerSession(containerObj podman.Container) func() {
+	return func() {}
+}
+
+// startHostMonitor is a no-op on Linux, because the host's configuration
+// files are bind mounted into the containers and 'init-container' watches them
+// itself.
+func startHostMonitor() {
+}
+
+// updateLinkedHosts is a no-op on Linux, because 'toolbox link' is only
+// available on macOS.
+func updateLinkedHosts(container string) {
+}
+
 func watchContextForEventFD(ctx context.Context, eventFD int) {
 	done := ctx.Done()
 	if done == nil {
@@ -25,11 +25,13 @@ import (
 	"fmt"
 	"os"
 	"os/user"
-	"path/filepath"
 	"strings"
 	"time"
 
+	"github.com/containers/toolbox/pkg/i18n"
+	"github.com/containers/toolbox/pkg/shell"
 	"github.com/containers/toolbox/pkg/utils"
+	"github.com/sirupsen/logrus"
 )
 
 func askForConfirmation(prompt string) bool {
@@ -38,18 +40,31 @@ func askForConfirmation(prompt string) bool {
 	scanner := bufio.NewScanner(os.Stdin)
 	for scanner.Scan() {
 		answer := strings.ToLower(strings.TrimSpace(scanner.Text()))
-		if answer == "y" || answer == "yes" {
+		if isAnswer(answer, "y", "yes") {
 			return true
-		} else if answer == "n" || answer == "no" {
+		} else if isAnswer(answer, "n", "no") {
 			return false
 		} else {
-			fmt.Print("Please enter y/yes or n/no: ")
+			i18n.Printf("Please enter y/yes or n/no: ")
 		}
 	}
 	
 	return false
 }
 
+// isAnswer checks answer against the English short and long forms, and their
+// translations, so that the English ones always work.
+func isAnswer(answer, short, long string) bool {
+	answers := []string{short, long, i18n.Translate(short), i18n.Translate(long)}
+	for _, candidate := range answers {
+		if answer == candidate {
+			return true
+		}
+	}
+
+	return false
+}
+
 func askForConfirmationAsync(ctx context.Context, prompt string) (<-chan bool, <-chan error) {
 	confirmationChan := make(chan bool)
 	errChan := make(chan error)
@@ -114,7 +129,16 @@ func createErrorProfileDNotFound() error {
 }
 
 func createErrorSudoersDNotFound() error {
-	return errors.New("sudoers.d not found")
+	const sudoersD = "/etc/sudoers.d"
+
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "directory %s not found in container\n", sudoersD)
+	fmt.Fprintf(&builder, "The sudoers(5) policy must include files from %s for the\n", sudoersD)
+	fmt.Fprintf(&builder, "user to use sudo(8) without a password.\n")
+	fmt.Fprintf(&builder, "Go to https://containertoolbx.org/ for further information.")
+
+	errMsg := builder.String()
+	return errors.New(errMsg)
 }
 
 func getCDIFileForNvidia(targetUser *user.User) (string, error) {
@@ -135,6 +159,38 @@ func getCurrentUserHomeDir() string {
 	return currentUser.HomeDir
 }
 
+// getLocaleEnviron returns the host's LANG and LC_* variables rewritten for
+// glibc, because the ones set by macOS make Perl, Python and Git complain.
+func getLocaleEnviron() []string {
+	environ := utils.GetHostLocaleEnvironment()
+	return environ
+}
+
+// getTimeZoneEnviron returns TZ set to the host's current time zone, so that
+// commands see changes to it without waiting for 'toolbox monitor-host'.
+func getTimeZoneEnviron() []string {
+	timeZone, err := utils.GetHostTimeZone()
+	if err != nil {
+		logrus.Debugf("Getting the host's time zone failed: %s", err)
+		return nil
+	}
+
+	return []string{"TZ=" + timeZone}
+}
+
+// warnOrFail reports something that didn't work as well as it should have,
+// but that doesn't stop Toolbx from working.  With --strict it's an error
+// instead, so that provisioning scripts don't end up with half-configured
+// containers.
+func warnOrFail(err error) error {
+	if rootFlags.strict {
+		return err
+	}
+
+	showWarning("%s", err)
+	return nil
+}
+
 func getUsageForCommonCommands() string {
 	return `Common commands are:
     create      Create a new Toolbx container
@@ -165,17 +221,62 @@ func poll(pollFn pollFunc, eventFD int32, fds ...int32) error {
 func resolveContainerAndImageNames(container, containerArg, distroCLI, imageCLI, releaseCLI string) (
 	resolvedContainerName, resolvedImageName, resolvedRelease string,
 	err error) {
-	
-	return utils.ResolveContainerAndImageNames(container, distroCLI, imageCLI, releaseCLI)
+
+	resolvedContainerName, resolvedImageName, resolvedRelease, err = utils.ResolveContainerAndImageNames(container,
+		distroCLI,
+		imageCLI,
+		releaseCLI)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	logFields.setContainer(resolvedContainerName, resolvedImageName)
+	return resolvedContainerName, resolvedImageName, resolvedRelease, nil
 }
 
-func showManual(manual string) error {
-	// On macOS, try to use 'man' command
-	manPath := filepath.Join("/usr/share/man/man1", manual+".1")
-	if _, err := os.Stat(manPath); err == nil {
-		return fmt.Errorf("manual page for %s not implemented on macOS", manual)
+// setUpLanguage picks the language of the messages that have translations,
+// from the locale or the preferred languages of macOS.
+func setUpLanguage() {
+	languages := utils.GetHostLanguages()
+	if err := i18n.SetLanguages(languages); err != nil {
+		logrus.Debugf("Setting up the language of messages failed: %s", err)
 	}
-	return fmt.Errorf("manual page %s not found", manual)
+
+	logrus.Debugf("Showing messages in language %s", i18n.GetLanguage())
+}
+
+// setUpStrictMode needs nothing on macOS, because '--strict' is looked at by
+// warnOrFail wherever something doesn't work as well as it should.
+func setUpStrictMode() error {
+	return nil
+}
+
+// showNotification shows a message in the Notification Center, for when
+// there's no terminal to show it in, or it might not be looked at.
+func showNotification(message string) {
+	script := fmt.Sprintf("display notification %q with title \"Toolbx\"", message)
+	if err := shell.Run("osascript", nil, nil, nil, "-e", script); err != nil {
+		logrus.Debugf("Showing a notification failed: %s", err)
+	}
+}
+
+// showStatus prints a progress message on its own line.  In accessible mode
+// the line is prefixed with a timestamp, so that screen readers announce a
+// sequence of distinct, self-contained updates.
+func showStatus(format string, a ...interface{}) {
+	if rootFlags.quiet {
+		return
+	}
+
+	msg := fmt.Sprintf(format, a...)
+
+	if rootFlags.accessible {
+		timestamp := time.Now().Format("15:04:05")
+		fmt.Fprintf(os.Stderr, "[%s] %s\n", timestamp, msg)
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "%s\n", msg)
 }
 
 func watchContextForEventFD(ctx context.Context, eventFD int) {
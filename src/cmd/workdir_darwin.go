@@ -0,0 +1,47 @@
+//go:build darwin
+
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"github.com/containers/toolbox/pkg/podman"
+	"github.com/sirupsen/logrus"
+)
+
+// getContainerWorkingDirectory returns the path inside the container that
+// corresponds to the directory dir on the host.  An empty string is returned
+// if the directory isn't visible inside the container, so that the caller can
+// fall back to the home directory.
+func getContainerWorkingDirectory(container, dir string) string {
+	logrus.Debugf("Inspecting mounts of container %s", container)
+
+	details, err := podman.InspectContainerDetails(container)
+	if err != nil {
+		logrus.Debugf("Inspecting mounts of container %s failed: %s", container, err)
+		return ""
+	}
+
+	workDir := getContainerPath(dir, details.Mounts)
+	if workDir == "" {
+		logrus.Debugf("Directory %s is not shared with container %s", dir, container)
+	} else {
+		logrus.Debugf("Directory %s is %s in container %s", dir, workDir, container)
+	}
+
+	return workDir
+}
@@ -0,0 +1,241 @@
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package docker talks to the Docker Engine API of Docker Desktop over its
+// socket, so that images and containers can be moved to Podman without
+// installing the docker(1) command line.
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Container is a container as listed by 'docker ps --all'.
+type Container struct {
+	ID      string `json:"Id"`
+	Image   string
+	ImageID string
+	Names   []string
+	State   string
+}
+
+// Image is an image as listed by 'docker images'.
+type Image struct {
+	Created  int64
+	ID       string `json:"Id"`
+	RepoTags []string
+	Size     int64
+}
+
+// apiVersion is the oldest version of the Docker Engine API with everything
+// that is used here.  It's supported by Docker 20.10 and newer.
+const apiVersion = "v1.41"
+
+var (
+	ErrNotRunning = errors.New("Docker is not running")
+)
+
+// Commit is a wrapper around 'docker commit', and returns the ID of the new
+// image.
+func Commit(container, repository, tag string) (string, error) {
+	query := url.Values{}
+	query.Set("container", container)
+	query.Set("repo", repository)
+	query.Set("tag", tag)
+
+	var response struct {
+		ID string `json:"Id"`
+	}
+
+	if err := request(http.MethodPost, "/commit", query, &response); err != nil {
+		return "", err
+	}
+
+	return response.ID, nil
+}
+
+// GetContainers is a wrapper around 'docker ps --all'.
+func GetContainers() ([]Container, error) {
+	query := url.Values{}
+	query.Set("all", "true")
+
+	var containers []Container
+	if err := request(http.MethodGet, "/containers/json", query, &containers); err != nil {
+		return nil, err
+	}
+
+	for i := range containers {
+		for j, name := range containers[i].Names {
+			containers[i].Names[j] = strings.TrimPrefix(name, "/")
+		}
+	}
+
+	return containers, nil
+}
+
+// GetImages is a wrapper around 'docker images'.
+func GetImages() ([]Image, error) {
+	var images []Image
+	if err := request(http.MethodGet, "/images/json", nil, &images); err != nil {
+		return nil, err
+	}
+
+	return images, nil
+}
+
+// GetSocket follows DOCKER_HOST, if it's a Unix socket, and otherwise looks
+// for the socket of Docker Desktop, which moved from /var/run/docker.sock to
+// ~/.docker/run/docker.sock in version 4.13.
+func GetSocket() (string, error) {
+	if dockerHost := os.Getenv("DOCKER_HOST"); dockerHost != "" {
+		socket, found := strings.CutPrefix(dockerHost, "unix://")
+		if !found {
+			return "", fmt.Errorf("DOCKER_HOST %s is not supported, only unix:// is", dockerHost)
+		}
+
+		return socket, nil
+	}
+
+	var sockets []string
+
+	if homeDir, err := os.UserHomeDir(); err == nil {
+		sockets = append(sockets, filepath.Join(homeDir, ".docker", "run", "docker.sock"))
+	}
+
+	sockets = append(sockets, "/var/run/docker.sock")
+
+	for _, socket := range sockets {
+		if _, err := os.Stat(socket); err == nil {
+			return socket, nil
+		}
+	}
+
+	return "", ErrNotRunning
+}
+
+// RemoveImage is a wrapper around 'docker rmi'.
+func RemoveImage(image string) error {
+	err := request(http.MethodDelete, "/images/"+url.PathEscape(image), nil, nil)
+	return err
+}
+
+// Save is a wrapper around 'docker save', and writes a Docker archive that
+// 'podman load' understands.
+func Save(image string, stdout io.Writer) error {
+	query := url.Values{}
+	query.Set("names", image)
+
+	response, err := do(http.MethodGet, "/images/get", query)
+	if err != nil {
+		return err
+	}
+
+	defer response.Body.Close()
+
+	if _, err := io.Copy(stdout, response.Body); err != nil {
+		return fmt.Errorf("failed to read image %s: %w", image, err)
+	}
+
+	return nil
+}
+
+func do(method, path string, query url.Values) (*http.Response, error) {
+	socket, err := GetSocket()
+	if err != nil {
+		return nil, err
+	}
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var dialer net.Dialer
+				return dialer.DialContext(ctx, "unix", socket)
+			},
+		},
+	}
+
+	requestURL := url.URL{
+		Scheme:   "http",
+		Host:     "docker",
+		Path:     "/" + apiVersion + path,
+		RawQuery: query.Encode(),
+	}
+
+	logrus.Debugf("Calling %s %s on %s", method, requestURL.RequestURI(), socket)
+
+	httpRequest, err := http.NewRequest(method, requestURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := client.Do(httpRequest)
+	if err != nil {
+		var opErr *net.OpError
+		if errors.As(err, &opErr) && opErr.Op == "dial" {
+			logrus.Debugf("Connecting to %s failed: %s", socket, err)
+			return nil, ErrNotRunning
+		}
+
+		return nil, err
+	}
+
+	if response.StatusCode >= http.StatusBadRequest {
+		defer response.Body.Close()
+
+		var apiErr struct {
+			Message string
+		}
+
+		if err := json.NewDecoder(response.Body).Decode(&apiErr); err != nil || apiErr.Message == "" {
+			return nil, fmt.Errorf("%s %s failed: %s", method, path, response.Status)
+		}
+
+		return nil, errors.New(apiErr.Message)
+	}
+
+	return response, nil
+}
+
+func request(method, path string, query url.Values, result interface{}) error {
+	response, err := do(method, path, query)
+	if err != nil {
+		return err
+	}
+
+	defer response.Body.Close()
+
+	if result == nil {
+		return nil
+	}
+
+	if err := json.NewDecoder(response.Body).Decode(result); err != nil {
+		return fmt.Errorf("failed to parse the response to %s %s: %w", method, path, err)
+	}
+
+	return nil
+}
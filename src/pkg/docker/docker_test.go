@@ -0,0 +1,114 @@
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package docker
+
+import (
+	"bytes"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func serveDocker(t *testing.T, handler http.Handler) {
+	socket := filepath.Join(t.TempDir(), "docker.sock")
+
+	listener, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+
+	server := &http.Server{Handler: handler}
+	go server.Serve(listener)
+	t.Cleanup(func() { server.Close() })
+
+	t.Setenv("DOCKER_HOST", "unix://"+socket)
+}
+
+func TestGetSocket(t *testing.T) {
+	t.Run("unix DOCKER_HOST", func(t *testing.T) {
+		t.Setenv("DOCKER_HOST", "unix:///tmp/docker.sock")
+		socket, err := GetSocket()
+		assert.NoError(t, err)
+		assert.Equal(t, "/tmp/docker.sock", socket)
+	})
+
+	t.Run("tcp DOCKER_HOST", func(t *testing.T) {
+		t.Setenv("DOCKER_HOST", "tcp://localhost:2375")
+		_, err := GetSocket()
+		assert.Error(t, err)
+	})
+
+	t.Run("no socket", func(t *testing.T) {
+		t.Setenv("DOCKER_HOST", "")
+		t.Setenv("HOME", t.TempDir())
+
+		if _, err := os.Stat("/var/run/docker.sock"); err == nil {
+			t.Skip("/var/run/docker.sock exists")
+		}
+
+		_, err := GetSocket()
+		assert.ErrorIs(t, err, ErrNotRunning)
+	})
+}
+
+func TestGetContainers(t *testing.T) {
+	serveDocker(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1.41/containers/json", r.URL.Path)
+		assert.Equal(t, "true", r.URL.Query().Get("all"))
+		w.Write([]byte(`[{"Id": "0123abcd", "Image": "alpine:3", "Names": ["/web"], "State": "exited"}]`))
+	}))
+
+	containers, err := GetContainers()
+	require.NoError(t, err)
+	require.Len(t, containers, 1)
+	assert.Equal(t, "0123abcd", containers[0].ID)
+	assert.Equal(t, []string{"web"}, containers[0].Names)
+	assert.Equal(t, "exited", containers[0].State)
+}
+
+func TestError(t *testing.T) {
+	serveDocker(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message": "No such image: foo:latest"}`))
+	}))
+
+	err := RemoveImage("foo:latest")
+	assert.EqualError(t, err, "No such image: foo:latest")
+}
+
+func TestNotRunning(t *testing.T) {
+	t.Setenv("DOCKER_HOST", "unix://"+filepath.Join(t.TempDir(), "docker.sock"))
+
+	_, err := GetImages()
+	assert.ErrorIs(t, err, ErrNotRunning)
+}
+
+func TestSave(t *testing.T) {
+	serveDocker(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1.41/images/get", r.URL.Path)
+		assert.Equal(t, "alpine:3", r.URL.Query().Get("names"))
+		w.Write([]byte("archive"))
+	}))
+
+	var stdout bytes.Buffer
+	err := Save("alpine:3", &stdout)
+	assert.NoError(t, err)
+	assert.Equal(t, "archive", stdout.String())
+}
@@ -0,0 +1,145 @@
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package i18n translates the messages that Toolbx shows to users.  The
+// English message is the key to look up its translation in the catalog of the
+// chosen language, like with gettext(3), so that messages without a
+// translation are still shown in English.
+//
+// Catalogs are JSON objects in the catalogs directory, one per language, named
+// after its ISO 639-1 code.  A translation must use the same format verbs, in
+// the same order, as the English message.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+//go:embed catalogs/*.json
+var catalogFiles embed.FS
+
+var (
+	catalog  map[string]string
+	language = "en"
+	mutex    sync.RWMutex
+)
+
+// Fprintf is like fmt.Fprintf, but translates format first.
+func Fprintf(w io.Writer, format string, a ...interface{}) (int, error) {
+	return fmt.Fprintf(w, Translate(format), a...)
+}
+
+// GetLanguage returns the code of the language that messages are shown in.
+func GetLanguage() string {
+	mutex.RLock()
+	defer mutex.RUnlock()
+
+	return language
+}
+
+// Printf is like fmt.Printf, but translates format first.
+func Printf(format string, a ...interface{}) (int, error) {
+	return fmt.Printf(Translate(format), a...)
+}
+
+// SetLanguages picks the first of languages, in order of preference, that
+// has a catalog or is English.  They can be locale names, like de_DE.UTF-8,
+// or language tags, like de-DE.  The C and POSIX locales mean English.
+func SetLanguages(languages []string) error {
+	for _, name := range languages {
+		code := parseLanguage(name)
+		if code == "" {
+			continue
+		}
+
+		if code == "en" {
+			setCatalog("en", nil)
+			return nil
+		}
+
+		data, err := catalogFiles.ReadFile("catalogs/" + code + ".json")
+		if err != nil {
+			continue
+		}
+
+		var messages map[string]string
+		if err := json.Unmarshal(data, &messages); err != nil {
+			return fmt.Errorf("failed to parse the catalog for %s: %w", code, err)
+		}
+
+		setCatalog(code, messages)
+		return nil
+	}
+
+	setCatalog("en", nil)
+	return nil
+}
+
+// Sprintf is like fmt.Sprintf, but translates format first.
+func Sprintf(format string, a ...interface{}) string {
+	return fmt.Sprintf(Translate(format), a...)
+}
+
+// Translate returns the translation of message, or message itself if there
+// is none.
+func Translate(message string) string {
+	mutex.RLock()
+	defer mutex.RUnlock()
+
+	if translation, ok := catalog[message]; ok && translation != "" {
+		return translation
+	}
+
+	return message
+}
+
+// parseLanguage returns the ISO 639-1 code of a locale name or language tag,
+// or an empty string if it has none, like a bare codeset such as UTF-8.
+func parseLanguage(name string) string {
+	if name == "C" || name == "POSIX" || strings.HasPrefix(name, "C.") {
+		return "en"
+	}
+
+	if i := strings.IndexAny(name, "_-.@"); i != -1 {
+		name = name[:i]
+	}
+
+	name = strings.ToLower(name)
+	if len(name) != 2 {
+		return ""
+	}
+
+	for _, r := range name {
+		if r < 'a' || r > 'z' {
+			return ""
+		}
+	}
+
+	return name
+}
+
+func setCatalog(code string, messages map[string]string) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	catalog = messages
+	language = code
+}
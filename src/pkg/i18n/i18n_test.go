@@ -0,0 +1,122 @@
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package i18n
+
+import (
+	"encoding/json"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var formatVerbRegexp = regexp.MustCompile(`%[-+# 0]*[0-9]*(?:\.[0-9]+)?[a-zA-Z%]`)
+
+func TestCatalogs(t *testing.T) {
+	entries, err := catalogFiles.ReadDir("catalogs")
+	require.NoError(t, err)
+	require.NotEmpty(t, entries)
+
+	for _, entry := range entries {
+		t.Run(entry.Name(), func(t *testing.T) {
+			data, err := catalogFiles.ReadFile("catalogs/" + entry.Name())
+			require.NoError(t, err)
+
+			var messages map[string]string
+			err = json.Unmarshal(data, &messages)
+			require.NoError(t, err)
+
+			for message, translation := range messages {
+				verbs := formatVerbRegexp.FindAllString(message, -1)
+				translationVerbs := formatVerbRegexp.FindAllString(translation, -1)
+				assert.Equal(t, verbs, translationVerbs, message)
+			}
+		})
+	}
+}
+
+func TestParseLanguage(t *testing.T) {
+	testCases := []struct {
+		name     string
+		expected string
+	}{
+		{"C", "en"},
+		{"C.UTF-8", "en"},
+		{"POSIX", "en"},
+		{"UTF-8", ""},
+		{"de", "de"},
+		{"de-DE", "de"},
+		{"de_DE.UTF-8", "de"},
+		{"en_GB@currency=EUR", "en"},
+		{"fr_CA", "fr"},
+		{"zh-Hans-CN", "zh"},
+		{"", ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			language := parseLanguage(tc.name)
+			assert.Equal(t, tc.expected, language)
+		})
+	}
+}
+
+func TestSetLanguages(t *testing.T) {
+	t.Cleanup(func() {
+		setCatalog("en", nil)
+	})
+
+	testCases := []struct {
+		name        string
+		languages   []string
+		expected    string
+		continueMsg string
+	}{
+		{"none", nil, "en", "Continue? [y/N]: "},
+		{"English", []string{"en_US.UTF-8", "de_DE.UTF-8"}, "en", "Continue? [y/N]: "},
+		{"German", []string{"de_DE.UTF-8"}, "de", "Fortfahren? [j/N]: "},
+		{"first with a catalog", []string{"zh-Hans-CN", "fr-FR", "de-DE"}, "fr", "Continuer ? [o/N] : "},
+		{"C locale", []string{"C"}, "en", "Continue? [y/N]: "},
+		{"unknown only", []string{"zh-Hans-CN"}, "en", "Continue? [y/N]: "},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := SetLanguages(tc.languages)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expected, GetLanguage())
+			assert.Equal(t, tc.continueMsg, Translate("Continue? [y/N]: "))
+		})
+	}
+}
+
+func TestSprintf(t *testing.T) {
+	t.Cleanup(func() {
+		setCatalog("en", nil)
+	})
+
+	err := SetLanguages([]string{"es_ES.UTF-8"})
+	require.NoError(t, err)
+
+	message := Sprintf("Run '%s --help' for usage.", "toolbox")
+	assert.Equal(t, "Ejecute 'toolbox --help' para ver el uso.", message)
+
+	message = Sprintf("Not translated %d", 42)
+	assert.Equal(t, "Not translated 42", message)
+}
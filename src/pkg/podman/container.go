@@ -25,6 +25,7 @@ import (
 
 type Container interface {
 	Created() string
+	CreatedTime() time.Time
 	EntryPoint() string
 	EntryPointPID() int
 	ID() string
@@ -39,6 +40,7 @@ type Container interface {
 
 type containerInspect struct {
 	created       string
+	createdTime   time.Time
 	entryPoint    string
 	entryPointPID int
 	id            string
@@ -51,6 +53,7 @@ type containerInspect struct {
 
 type containerPS struct {
 	created       string
+	createdTime   time.Time
 	entryPoint    string
 	entryPointPID int
 	id            string
@@ -70,6 +73,10 @@ func (container *containerInspect) Created() string {
 	return container.created
 }
 
+func (container *containerInspect) CreatedTime() time.Time {
+	return container.createdTime
+}
+
 func (container *containerInspect) EntryPoint() string {
 	return container.entryPoint
 }
@@ -145,6 +152,7 @@ func (container *containerInspect) UnmarshalJSON(data []byte) error {
 
 	created := raw.Created.Unix()
 	container.created = utils.HumanDuration(created)
+	container.createdTime = raw.Created
 
 	container.id = raw.ID
 	container.image = raw.ImageName
@@ -165,6 +173,10 @@ func (container *containerPS) Created() string {
 	return container.created
 }
 
+func (container *containerPS) CreatedTime() time.Time {
+	return container.createdTime
+}
+
 func (container *containerPS) EntryPoint() string {
 	return container.entryPoint
 }
@@ -245,6 +257,7 @@ func (container *containerPS) UnmarshalJSON(data []byte) error {
 		container.created = value
 	case float64:
 		container.created = utils.HumanDuration(int64(value))
+		container.createdTime = time.Unix(int64(value), 0)
 	}
 
 	container.id = raw.ID
@@ -0,0 +1,96 @@
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package podman
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/containers/toolbox/pkg/shell"
+)
+
+// DiskUsage is how much space one type of object takes, as formatted by
+// 'podman system df', eg., for images, containers or local volumes.
+type DiskUsage struct {
+	Active         int
+	RawReclaimable int64
+	RawSize        int64
+	Total          int
+	Type           string
+}
+
+// ContainerSize is how much space one container takes, as formatted by
+// 'podman ps --size'.  RwSize is only the container's writable layer, while
+// RootFsSize also includes the layers shared with its image.
+type ContainerSize struct {
+	RootFsSize int64
+	RwSize     int64
+}
+
+// GetContainerSizes is a wrapper around 'podman ps --all --size' and returns
+// the sizes of all containers indexed by their IDs.  It gives the same figures
+// as 'podman system df --verbose', which can't be formatted as JSON.  Both are
+// slow with many containers, so only use it when the sizes will be shown.
+func GetContainerSizes() (map[string]ContainerSize, error) {
+	var stdout bytes.Buffer
+
+	logLevelString := LogLevel.String()
+	args := []string{"--log-level", logLevelString, "ps", "--all", "--size", "--format", "json"}
+
+	if err := shell.Run("podman", nil, &stdout, nil, args...); err != nil {
+		return nil, err
+	}
+
+	data := stdout.Bytes()
+	var containers []struct {
+		ID   string
+		Size *ContainerSize
+	}
+
+	if err := json.Unmarshal(data, &containers); err != nil {
+		return nil, err
+	}
+
+	sizes := make(map[string]ContainerSize, len(containers))
+	for _, container := range containers {
+		if container.Size != nil {
+			sizes[container.ID] = *container.Size
+		}
+	}
+
+	return sizes, nil
+}
+
+// GetDiskUsage is a wrapper around 'podman system df'.
+func GetDiskUsage() ([]DiskUsage, error) {
+	var stdout bytes.Buffer
+
+	logLevelString := LogLevel.String()
+	args := []string{"--log-level", logLevelString, "system", "df", "--format", "json"}
+
+	if err := shell.Run("podman", nil, &stdout, nil, args...); err != nil {
+		return nil, err
+	}
+
+	data := stdout.Bytes()
+	var usage []DiskUsage
+	if err := json.Unmarshal(data, &usage); err != nil {
+		return nil, err
+	}
+
+	return usage, nil
+}
@@ -0,0 +1,63 @@
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package podman
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/containers/toolbox/pkg/shell"
+)
+
+// Changes are the paths that were added, changed or deleted in a container's
+// file system, as formatted by 'podman diff'.
+type Changes struct {
+	Added   []string
+	Changed []string
+	Deleted []string
+}
+
+// Diff is a wrapper around 'podman diff'.  It returns the changes in a
+// container or image since parent, another container or image, or since the
+// image it was created from if parent is empty.
+func Diff(container, parent string) (Changes, error) {
+	var stdout bytes.Buffer
+
+	logLevelString := LogLevel.String()
+	args := []string{"--log-level", logLevelString, "diff", "--format", "json", container}
+
+	if parent != "" {
+		args = append(args, parent)
+	}
+
+	if err := shell.Run("podman", nil, &stdout, nil, args...); err != nil {
+		return Changes{}, err
+	}
+
+	data := stdout.Bytes()
+	var changes Changes
+	if err := json.Unmarshal(data, &changes); err != nil {
+		return Changes{}, err
+	}
+
+	return changes, nil
+}
+
+// Len returns the number of paths that were added, changed or deleted.
+func (changes Changes) Len() int {
+	return len(changes.Added) + len(changes.Changed) + len(changes.Deleted)
+}
@@ -0,0 +1,124 @@
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package podman
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/containers/toolbox/pkg/shell"
+)
+
+// Event is an event of 'podman events --format json'.
+type Event struct {
+	Attributes map[string]string
+	ID         string
+	Image      string
+	Name       string
+	Status     string
+	Time       time.Time
+	Type       string
+}
+
+// EventsContext is a wrapper around 'podman events --format json'.  The
+// handler is called for every event, until ctx is cancelled or 'podman
+// events' exits.  The args are passed on, eg., for '--filter'.
+func EventsContext(ctx context.Context, handler func(Event), args ...string) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	logLevelString := LogLevel.String()
+	eventsArgs := []string{"--log-level", logLevelString, "events", "--format", "json"}
+	eventsArgs = append(eventsArgs, args...)
+
+	reader, writer := io.Pipe()
+	errCh := make(chan error, 1)
+
+	go func() {
+		err := shell.RunContext(ctx, "podman", nil, writer, nil, eventsArgs...)
+		writer.CloseWithError(err)
+		errCh <- err
+	}()
+
+	decoder := json.NewDecoder(reader)
+
+	for {
+		var event Event
+		if err := decoder.Decode(&event); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			// If 'podman events' failed, then err is its error.
+			// Otherwise, it needs to be stopped.
+			cancel()
+			reader.Close()
+			<-errCh
+			return err
+		}
+
+		handler(event)
+	}
+
+	if err := <-errCh; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (event *Event) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Attributes map[string]string
+		ID         string
+		Image      string
+		Name       string
+		Status     string
+		Time       json.RawMessage `json:"time"`
+		TimeNano   int64           `json:"timeNano"`
+		Type       string
+	}
+
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	event.Attributes = raw.Attributes
+	event.ID = raw.ID
+	event.Image = raw.Image
+	event.Name = raw.Name
+	event.Status = raw.Status
+	event.Type = raw.Type
+
+	// Podman 4 and later use the number of seconds and nanoseconds since
+	// the epoch, while older versions used a string.
+	var timeString string
+	var timeUnix int64
+
+	if raw.TimeNano != 0 {
+		event.Time = time.Unix(0, raw.TimeNano)
+	} else if err := json.Unmarshal(raw.Time, &timeUnix); err == nil {
+		event.Time = time.Unix(timeUnix, 0)
+	} else if err := json.Unmarshal(raw.Time, &timeString); err == nil {
+		event.Time, _ = time.Parse(time.RFC3339Nano, timeString)
+	}
+
+	return nil
+}
@@ -0,0 +1,109 @@
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package podman
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvent(t *testing.T) {
+	type expect struct {
+		id     string
+		image  string
+		name   string
+		status string
+		time   time.Time
+		typ    string
+	}
+
+	testCases := []struct {
+		name   string
+		data   string
+		expect expect
+	}{
+		{
+			name: "podman 3.4.4, start",
+			data: "" +
+				"{" +
+				"  \"ID\": \"b23f7c69ddec697f803b8acc40e85d212198c1baea9ffe193e7b3e0d2a020a39\"," +
+				"  \"Image\": \"registry.fedoraproject.org/fedora-toolbox:35\"," +
+				"  \"Name\": \"fedora-toolbox-35\"," +
+				"  \"Status\": \"start\"," +
+				"  \"Time\": \"2022-03-01T10:20:30.123456789+01:00\"," +
+				"  \"Type\": \"container\"," +
+				"  \"Attributes\": {" +
+				"    \"com.github.containers.toolbox\": \"true\"" +
+				"  }" +
+				"}",
+			expect: expect{
+				id:     "b23f7c69ddec697f803b8acc40e85d212198c1baea9ffe193e7b3e0d2a020a39",
+				image:  "registry.fedoraproject.org/fedora-toolbox:35",
+				name:   "fedora-toolbox-35",
+				status: "start",
+				time:   time.Date(2022, 3, 1, 9, 20, 30, 123456789, time.UTC),
+				typ:    "container",
+			},
+		},
+		{
+			name: "podman 5.2.2, remove",
+			data: "" +
+				"{" +
+				"  \"ID\": \"f62203a35f867cbdeb0d340741455cea23bd5fccff19c33ef453aaa163152142\"," +
+				"  \"Image\": \"registry.fedoraproject.org/fedora-toolbox:40\"," +
+				"  \"Name\": \"fedora-toolbox-40\"," +
+				"  \"Status\": \"remove\"," +
+				"  \"time\": 1727427474," +
+				"  \"timeNano\": 1727427474901234567," +
+				"  \"Type\": \"container\"," +
+				"  \"Attributes\": {" +
+				"    \"com.github.containers.toolbox\": \"true\"," +
+				"    \"image\": \"registry.fedoraproject.org/fedora-toolbox:40\"," +
+				"    \"name\": \"fedora-toolbox-40\"" +
+				"  }" +
+				"}",
+			expect: expect{
+				id:     "f62203a35f867cbdeb0d340741455cea23bd5fccff19c33ef453aaa163152142",
+				image:  "registry.fedoraproject.org/fedora-toolbox:40",
+				name:   "fedora-toolbox-40",
+				status: "remove",
+				time:   time.Unix(0, 1727427474901234567),
+				typ:    "container",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			data := []byte(tc.data)
+			var event Event
+			err := json.Unmarshal(data, &event)
+			assert.NoError(t, err)
+
+			assert.Equal(t, "true", event.Attributes["com.github.containers.toolbox"])
+			assert.Equal(t, tc.expect.id, event.ID)
+			assert.Equal(t, tc.expect.image, event.Image)
+			assert.Equal(t, tc.expect.name, event.Name)
+			assert.Equal(t, tc.expect.status, event.Status)
+			assert.True(t, tc.expect.time.Equal(event.Time))
+			assert.Equal(t, tc.expect.typ, event.Type)
+		})
+	}
+}
@@ -0,0 +1,74 @@
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package podman
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+
+	"github.com/containers/toolbox/pkg/shell"
+)
+
+// Farm is a group of Podman connections that 'podman farm build' spreads
+// the builds for different platforms over.
+type Farm struct {
+	Name        string
+	Connections []string
+	Default     bool
+}
+
+// Build is a wrapper around 'podman build', or 'podman farm build' if farm is
+// not empty.  The output of the build is written to stdout and stderr.
+func Build(farm string, stdout, stderr io.Writer, args ...string) error {
+	logLevelString := LogLevel.String()
+	buildArgs := []string{"--log-level", logLevelString}
+
+	if farm == "" {
+		buildArgs = append(buildArgs, "build")
+	} else {
+		buildArgs = append(buildArgs, "farm", "build", "--farm", farm)
+	}
+
+	buildArgs = append(buildArgs, args...)
+
+	if err := shell.Run("podman", nil, stdout, stderr, buildArgs...); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetFarms is a wrapper around 'podman farm list --format json'.
+func GetFarms() ([]Farm, error) {
+	var stdout bytes.Buffer
+
+	logLevelString := LogLevel.String()
+	args := []string{"--log-level", logLevelString, "farm", "list", "--format", "json"}
+
+	if err := shell.Run("podman", nil, &stdout, nil, args...); err != nil {
+		return nil, err
+	}
+
+	data := stdout.Bytes()
+	var farms []Farm
+	if err := json.Unmarshal(data, &farms); err != nil {
+		return nil, err
+	}
+
+	return farms, nil
+}
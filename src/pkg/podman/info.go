@@ -0,0 +1,74 @@
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package podman
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/containers/toolbox/pkg/shell"
+)
+
+// Info is the part of 'podman info' that describes where containers run,
+// which on macOS is the Podman machine.
+type Info struct {
+	Host struct {
+		Arch         string
+		Distribution struct {
+			Distribution string
+			Version      string
+		}
+		Kernel       string
+		OS           string
+		RemoteSocket struct {
+			Exists bool
+			Path   string
+		}
+	}
+	Store struct {
+		ContainerStore struct {
+			Number int
+		}
+		GraphDriverName string
+		ImageStore      struct {
+			Number int
+		}
+	}
+	Version struct {
+		Version string
+	}
+}
+
+// GetInfo is a wrapper around 'podman info'.
+func GetInfo() (Info, error) {
+	var stdout bytes.Buffer
+
+	logLevelString := LogLevel.String()
+	args := []string{"--log-level", logLevelString, "info", "--format", "json"}
+
+	if err := shell.Run("podman", nil, &stdout, nil, args...); err != nil {
+		return Info{}, err
+	}
+
+	data := stdout.Bytes()
+	var info Info
+	if err := json.Unmarshal(data, &info); err != nil {
+		return Info{}, err
+	}
+
+	return info, nil
+}
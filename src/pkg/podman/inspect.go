@@ -0,0 +1,96 @@
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package podman
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/containers/toolbox/pkg/shell"
+)
+
+// ContainerDetails is the part of 'podman inspect --type container' that
+// Container doesn't cover, with the timestamps and mounts left as they are.
+type ContainerDetails struct {
+	Config struct {
+		CreateCommand []string
+		Hostname      string
+		Labels        map[string]string
+	}
+	Created       time.Time
+	EffectiveCaps []string
+	HostConfig    struct {
+		CapAdd       []string
+		CapDrop      []string
+		NetworkMode  string
+		PortBindings map[string][]PortBinding
+		Privileged   bool
+		SecurityOpt  []string
+	}
+	ID              string `json:"Id"`
+	Image           string
+	ImageName       string
+	Mounts          []ContainerMount
+	Name            string
+	NetworkSettings struct {
+		Networks map[string]struct{}
+	}
+	State struct {
+		StartedAt time.Time
+		Status    string
+	}
+}
+
+type PortBinding struct {
+	HostIP   string `json:"HostIp"`
+	HostPort string
+}
+
+type ContainerMount struct {
+	Destination string
+	Name        string
+	RW          bool
+	Source      string
+	Type        string
+}
+
+// InspectContainerDetails is a wrapper around 'podman inspect --type
+// container', like InspectContainer, but returns more details.
+func InspectContainerDetails(container string) (ContainerDetails, error) {
+	var stdout bytes.Buffer
+
+	logLevelString := LogLevel.String()
+	args := []string{"--log-level", logLevelString, "inspect", "--format", "json", "--type", "container", container}
+
+	if err := shell.Run("podman", nil, &stdout, nil, args...); err != nil {
+		return ContainerDetails{}, err
+	}
+
+	output := stdout.Bytes()
+	var containers []ContainerDetails
+	if err := json.Unmarshal(output, &containers); err != nil {
+		return ContainerDetails{}, err
+	}
+
+	if len(containers) == 0 {
+		return ContainerDetails{}, fmt.Errorf("failed to inspect container %s", container)
+	}
+
+	return containers[0], nil
+}
@@ -0,0 +1,145 @@
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package podman
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/containers/toolbox/pkg/shell"
+)
+
+// Machine is the part of 'podman machine inspect' that describes the size and
+// state of a Podman machine, and how to log into it.  DiskSize is in GiB and
+// Memory in MiB.
+type Machine struct {
+	Name      string
+	Resources struct {
+		CPUs     int
+		DiskSize uint64
+		Memory   uint64
+	}
+	Rootful   bool
+	SSHConfig struct {
+		IdentityPath   string
+		Port           int
+		RemoteUsername string
+	}
+	State string
+}
+
+// MachineInit is a wrapper around 'podman machine init' for the default Podman
+// machine.  The progress of downloading the machine's image is shown on the
+// terminal, because it can take a while.
+func MachineInit(args ...string) error {
+	logLevelString := LogLevel.String()
+	initArgs := []string{"--log-level", logLevelString, "machine", "init"}
+	initArgs = append(initArgs, args...)
+
+	if err := shell.Run("podman", nil, os.Stdout, os.Stderr, initArgs...); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// MachineInspect is a wrapper around 'podman machine inspect' for the default
+// Podman machine.
+func MachineInspect() (Machine, error) {
+	var stdout bytes.Buffer
+
+	logLevelString := LogLevel.String()
+	args := []string{"--log-level", logLevelString, "machine", "inspect"}
+
+	if err := shell.Run("podman", nil, &stdout, nil, args...); err != nil {
+		return Machine{}, err
+	}
+
+	data := stdout.Bytes()
+	var machines []Machine
+	if err := json.Unmarshal(data, &machines); err != nil {
+		return Machine{}, err
+	}
+
+	if len(machines) == 0 {
+		return Machine{}, errors.New("no Podman machine")
+	}
+
+	return machines[0], nil
+}
+
+// MachineSSH runs a command inside the default Podman machine through
+// 'podman machine ssh'.
+//
+// The standard output of the command is written to stdout, if it is not nil.
+func MachineSSH(stdout io.Writer, command ...string) error {
+	logLevelString := LogLevel.String()
+	args := []string{"--log-level", logLevelString, "machine", "ssh"}
+	args = append(args, command...)
+
+	if err := shell.Run("podman", nil, stdout, nil, args...); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// MachineStart is a wrapper around 'podman machine start' for the default
+// Podman machine.
+func MachineStart() error {
+	logLevelString := LogLevel.String()
+	args := []string{"--log-level", logLevelString, "machine", "start"}
+
+	if err := shell.Run("podman", nil, nil, nil, args...); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// MachineState returns the state of the default Podman machine, eg., running
+// or stopped.
+func MachineState() (string, error) {
+	var stdout strings.Builder
+
+	logLevelString := LogLevel.String()
+	args := []string{"--log-level", logLevelString, "machine", "inspect", "--format", "{{.State}}"}
+
+	if err := shell.Run("podman", nil, &stdout, nil, args...); err != nil {
+		return "", err
+	}
+
+	state := strings.TrimSpace(stdout.String())
+	return state, nil
+}
+
+// MachineStop is a wrapper around 'podman machine stop' for the default
+// Podman machine.
+func MachineStop() error {
+	logLevelString := LogLevel.String()
+	args := []string{"--log-level", logLevelString, "machine", "stop"}
+
+	if err := shell.Run("podman", nil, nil, nil, args...); err != nil {
+		return err
+	}
+
+	return nil
+}
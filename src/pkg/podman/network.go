@@ -0,0 +1,133 @@
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package podman
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/containers/toolbox/pkg/shell"
+)
+
+// GetContainerIPAddress returns the IP address of a running container on a
+// network.
+func GetContainerIPAddress(container, network string) (string, error) {
+	var stdout strings.Builder
+
+	logLevelString := LogLevel.String()
+	format := fmt.Sprintf("{{(index .NetworkSettings.Networks %q).IPAddress}}", network)
+	args := []string{"--log-level", logLevelString, "container", "inspect", "--format", format, container}
+
+	if err := shell.Run("podman", nil, &stdout, nil, args...); err != nil {
+		return "", err
+	}
+
+	ipAddress := strings.TrimSpace(stdout.String())
+	if ipAddress == "" {
+		return "", fmt.Errorf("container %s has no IP address on network %s", container, network)
+	}
+
+	return ipAddress, nil
+}
+
+// GetContainerNetworkMode returns the network mode of a container, eg.,
+// bridge or slirp4netns.
+func GetContainerNetworkMode(container string) (string, error) {
+	var stdout strings.Builder
+
+	logLevelString := LogLevel.String()
+	args := []string{
+		"--log-level", logLevelString,
+		"container", "inspect",
+		"--format", "{{.HostConfig.NetworkMode}}",
+		container,
+	}
+
+	if err := shell.Run("podman", nil, &stdout, nil, args...); err != nil {
+		return "", err
+	}
+
+	networkMode := strings.TrimSpace(stdout.String())
+	return networkMode, nil
+}
+
+// NetworkConnect is a wrapper around 'podman network connect'.  It only works
+// with containers that use bridge networking.  Connecting a container that is
+// already connected is not an error.
+func NetworkConnect(network, container string, aliases ...string) error {
+	logLevelString := LogLevel.String()
+	args := []string{"--log-level", logLevelString, "network", "connect"}
+
+	for _, alias := range aliases {
+		args = append(args, "--alias", alias)
+	}
+
+	args = append(args, network, container)
+
+	var stderr strings.Builder
+	if err := shell.Run("podman", nil, nil, &stderr, args...); err != nil {
+		errString := strings.TrimSpace(stderr.String())
+		if strings.Contains(errString, "already connected") {
+			return nil
+		}
+
+		return fmt.Errorf("%w: %s", err, errString)
+	}
+
+	return nil
+}
+
+// NetworkCreate is a wrapper around 'podman network create'.
+func NetworkCreate(network string) error {
+	logLevelString := LogLevel.String()
+	args := []string{"--log-level", logLevelString, "network", "create", network}
+
+	if err := shell.Run("podman", nil, nil, nil, args...); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// NetworkDisconnect is a wrapper around 'podman network disconnect'.
+func NetworkDisconnect(network, container string) error {
+	logLevelString := LogLevel.String()
+	args := []string{"--log-level", logLevelString, "network", "disconnect", network, container}
+
+	if err := shell.Run("podman", nil, nil, nil, args...); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// NetworkExists is a wrapper around 'podman network exists'.
+func NetworkExists(network string) (bool, error) {
+	logLevelString := LogLevel.String()
+	args := []string{"--log-level", logLevelString, "network", "exists", network}
+
+	exitCode, err := shell.RunWithExitCode("podman", nil, nil, nil, args...)
+	if exitCode != 0 && err == nil {
+		err = fmt.Errorf("failed to find network %s", network)
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
@@ -43,6 +43,8 @@ type Image struct {
 type ImageSlice []Image
 
 var (
+	podmanRootless *bool
+
 	podmanVersion string
 )
 
@@ -144,6 +146,19 @@ func CheckVersion(requiredVersion string) bool {
 	return version.CompareSimple(currentVersion, requiredVersion) >= 0
 }
 
+// Commit is a wrapper around 'podman commit', which creates image from the
+// current state of container.
+func Commit(container, image string) error {
+	logLevelString := LogLevel.String()
+	args := []string{"--log-level", logLevelString, "commit", container, image}
+
+	if err := shell.Run("podman", nil, nil, nil, args...); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // ContainerExists checks using Podman if a container with given ID/name exists.
 //
 // Parameter container is a name or an id of a container.
@@ -163,6 +178,57 @@ func ContainerExists(container string) (bool, error) {
 	return true, nil
 }
 
+// CopyFromContainer is a wrapper around 'podman cp CONTAINER:SOURCE -'.  It
+// writes a tar archive of source to stdout, and works with containers that are
+// not running.
+func CopyFromContainer(container, source string, stdout io.Writer) error {
+	logLevelString := LogLevel.String()
+	sourceArg := container + ":" + source
+	args := []string{"--log-level", logLevelString, "cp", sourceArg, "-"}
+
+	if err := shell.Run("podman", nil, stdout, nil, args...); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// CopyToContainer is a wrapper around 'podman cp'.  It works with containers
+// that are not running, and with files on the host that the Podman machine
+// can't see.
+func CopyToContainer(source, container, destination string) error {
+	logLevelString := LogLevel.String()
+	destinationArg := container + ":" + destination
+	args := []string{"--log-level", logLevelString, "cp", source, destinationArg}
+
+	if err := shell.Run("podman", nil, nil, nil, args...); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ExecAsRoot runs a command as root inside a running container without
+// allocating a terminal. The command's standard input is read from stdin, if
+// it is not nil.
+func ExecAsRoot(container string, stdin io.Reader, command ...string) error {
+	logLevelString := LogLevel.String()
+	args := []string{"--log-level", logLevelString, "exec", "--user", "root:root"}
+
+	if stdin != nil {
+		args = append(args, "--interactive")
+	}
+
+	args = append(args, container)
+	args = append(args, command...)
+
+	if err := shell.Run("podman", stdin, nil, nil, args...); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // GetContainers is a wrapper function around `podman ps --format json` command.
 //
 // Parameter args accepts an array of strings to be passed to the wrapped command (eg. ["-a", "--filter", "123"]).
@@ -346,6 +412,40 @@ func InspectImage(image string) (map[string]interface{}, error) {
 	return info[0], nil
 }
 
+// IsRootless checks if Podman, or the Podman machine that it is connected to,
+// runs containers without root privileges.
+func IsRootless() (bool, error) {
+	if podmanRootless != nil {
+		return *podmanRootless, nil
+	}
+
+	var stdout bytes.Buffer
+
+	logLevelString := LogLevel.String()
+	args := []string{"--log-level", logLevelString, "info", "--format", "json"}
+
+	if err := shell.Run("podman", nil, &stdout, nil, args...); err != nil {
+		return false, err
+	}
+
+	output := stdout.Bytes()
+	var info struct {
+		Host struct {
+			Security struct {
+				Rootless bool
+			}
+		}
+	}
+
+	if err := json.Unmarshal(output, &info); err != nil {
+		return false, err
+	}
+
+	rootless := info.Host.Security.Rootless
+	podmanRootless = &rootless
+	return rootless, nil
+}
+
 func IsToolboxImage(image string) (bool, error) {
 	info, err := InspectImage(image)
 	if err != nil {
@@ -364,6 +464,34 @@ func IsToolboxImage(image string) (bool, error) {
 	return true, nil
 }
 
+// Load is a wrapper around 'podman load', and reads the archive from stdin.
+func Load(stdin io.Reader) error {
+	logLevelString := LogLevel.String()
+	args := []string{"--log-level", logLevelString, "load"}
+
+	if err := shell.Run("podman", stdin, nil, nil, args...); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// LoadImages is like Load, but returns the names of the images that were
+// loaded.  Images without a name are returned by their IDs.
+func LoadImages(stdin io.Reader) ([]string, error) {
+	var stdout bytes.Buffer
+
+	logLevelString := LogLevel.String()
+	args := []string{"--log-level", logLevelString, "load"}
+
+	if err := shell.Run("podman", stdin, &stdout, nil, args...); err != nil {
+		return nil, err
+	}
+
+	images := parseLoadedImages(stdout.String())
+	return images, nil
+}
+
 func Logs(container string, since time.Time, stderr io.Writer) error {
 	ctx := context.Background()
 	err := LogsContext(ctx, container, false, since, stderr)
@@ -479,6 +607,68 @@ func RemoveImage(image string, forceDelete bool) error {
 	return nil
 }
 
+// parseLoadedImages parses the output of 'podman load', which is either a
+// 'Loaded image: NAME' line for each image, or, with older versions, a single
+// 'Loaded image(s): NAME,NAME' line.
+func parseLoadedImages(output string) []string {
+	var images []string
+
+	for _, line := range strings.Split(output, "\n") {
+		_, names, found := strings.Cut(line, ": ")
+		if !found || !strings.HasPrefix(line, "Loaded image") {
+			continue
+		}
+
+		for _, name := range strings.Split(names, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				images = append(images, name)
+			}
+		}
+	}
+
+	return images
+}
+
+// Rename is a wrapper around 'podman rename'.
+func Rename(container, newName string) error {
+	logLevelString := LogLevel.String()
+	args := []string{"--log-level", logLevelString, "rename", container, newName}
+
+	if err := shell.Run("podman", nil, nil, nil, args...); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Save is a wrapper around 'podman save'.  The image is written to stdout as
+// an OCI archive.
+func Save(image string, stdout io.Writer) error {
+	logLevelString := LogLevel.String()
+	args := []string{"--log-level", logLevelString, "save", "--format", "oci-archive", image}
+
+	if err := shell.Run("podman", nil, stdout, nil, args...); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SaveImages is a wrapper around 'podman save'.  The images are written to
+// stdout as a Docker archive, which, unlike an OCI archive, can hold more
+// than one image.
+func SaveImages(images []string, stdout io.Writer) error {
+	logLevelString := LogLevel.String()
+	args := []string{"--log-level", logLevelString, "save", "--format", "docker-archive", "--multi-image-archive"}
+	args = append(args, images...)
+
+	if err := shell.Run("podman", nil, stdout, nil, args...); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func SetLogLevel(logLevel logrus.Level) {
 	LogLevel = logLevel
 }
@@ -496,6 +686,20 @@ func Start(container string, stderr io.Writer) error {
 	return nil
 }
 
+// Stop is a wrapper around 'podman stop'.  The container is given timeout
+// seconds to shut down before it is killed.
+func Stop(container string, timeout int) error {
+	logLevelString := LogLevel.String()
+	timeoutString := strconv.Itoa(timeout)
+	args := []string{"--log-level", logLevelString, "stop", "--time", timeoutString, container}
+
+	if err := shell.Run("podman", nil, nil, nil, args...); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func SystemMigrate(ociRuntimeRequired string) error {
 	logLevelString := LogLevel.String()
 	args := []string{"--log-level", logLevelString, "system", "migrate"}
@@ -509,3 +713,81 @@ func SystemMigrate(ociRuntimeRequired string) error {
 
 	return nil
 }
+
+// Tag is a wrapper around 'podman tag', which gives image another name.
+func Tag(image, newName string) error {
+	logLevelString := LogLevel.String()
+	args := []string{"--log-level", logLevelString, "tag", image, newName}
+
+	if err := shell.Run("podman", nil, nil, nil, args...); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Top is a wrapper around 'podman top', and returns the descriptors of each
+// process in a running container, without the heading.
+func Top(container string, descriptors ...string) ([]string, error) {
+	var stdout bytes.Buffer
+
+	logLevelString := LogLevel.String()
+	args := []string{"--log-level", logLevelString, "top", container}
+	args = append(args, descriptors...)
+
+	if err := shell.Run("podman", nil, &stdout, nil, args...); err != nil {
+		return nil, err
+	}
+
+	output := strings.TrimSpace(stdout.String())
+	lines := strings.Split(output, "\n")
+	if len(lines) <= 1 {
+		return nil, nil
+	}
+
+	processes := make([]string, 0, len(lines)-1)
+	for _, line := range lines[1:] {
+		processes = append(processes, strings.TrimSpace(line))
+	}
+
+	return processes, nil
+}
+
+// Untag is a wrapper around 'podman untag', which removes a name of image
+// without removing the image itself.
+func Untag(image, name string) error {
+	logLevelString := LogLevel.String()
+	args := []string{"--log-level", logLevelString, "untag", image, name}
+
+	if err := shell.Run("podman", nil, nil, nil, args...); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// VolumeExport is a wrapper around 'podman volume export', and writes a
+// tarball of the contents of the volume to stdout.
+func VolumeExport(volume string, stdout io.Writer) error {
+	logLevelString := LogLevel.String()
+	args := []string{"--log-level", logLevelString, "volume", "export", volume}
+
+	if err := shell.Run("podman", nil, stdout, nil, args...); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// VolumeImport is a wrapper around 'podman volume import', and reads a
+// tarball from stdin into the volume, which needs to exist.
+func VolumeImport(volume string, stdin io.Reader) error {
+	logLevelString := LogLevel.String()
+	args := []string{"--log-level", logLevelString, "volume", "import", volume, "-"}
+
+	if err := shell.Run("podman", stdin, nil, nil, args...); err != nil {
+		return err
+	}
+
+	return nil
+}
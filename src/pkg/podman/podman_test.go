@@ -0,0 +1,72 @@
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package podman
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLoadedImages(t *testing.T) {
+	testCases := []struct {
+		name     string
+		output   string
+		expected []string
+	}{
+		{
+			name:     "one image",
+			output:   "Loaded image: registry.fedoraproject.org/fedora-toolbox:42\n",
+			expected: []string{"registry.fedoraproject.org/fedora-toolbox:42"},
+		},
+		{
+			name: "several images",
+			output: "Getting image source signatures\n" +
+				"Loaded image: registry.fedoraproject.org/fedora-toolbox:42\n" +
+				"Loaded image: quay.io/toolbx/ubuntu-toolbox:24.04\n",
+			expected: []string{
+				"registry.fedoraproject.org/fedora-toolbox:42",
+				"quay.io/toolbx/ubuntu-toolbox:24.04",
+			},
+		},
+		{
+			name:   "older Podman",
+			output: "Loaded image(s): localhost/dev:latest,localhost/dev:1.0\n",
+			expected: []string{
+				"localhost/dev:latest",
+				"localhost/dev:1.0",
+			},
+		},
+		{
+			name:     "no name",
+			output:   "Loaded image: sha256:4c2a8e1f39d7a0b6c5e4d3f2a1b0c9d8e7f6a5b4c3d2e1f0a9b8c7d6e5f4a3b2\n",
+			expected: []string{"sha256:4c2a8e1f39d7a0b6c5e4d3f2a1b0c9d8e7f6a5b4c3d2e1f0a9b8c7d6e5f4a3b2"},
+		},
+		{
+			name:     "nothing",
+			output:   "",
+			expected: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			images := parseLoadedImages(tc.output)
+			assert.Equal(t, tc.expected, images)
+		})
+	}
+}
@@ -0,0 +1,60 @@
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package podman
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/containers/toolbox/pkg/shell"
+)
+
+// ContainerStats is a snapshot of the resource usage of a running container,
+// as formatted by 'podman stats'.
+type ContainerStats struct {
+	BlockIO    string `json:"block_io"`
+	CPUPercent string `json:"cpu_percent"`
+	ID         string `json:"id"`
+	MemPercent string `json:"mem_percent"`
+	MemUsage   string `json:"mem_usage"`
+	Name       string `json:"name"`
+	NetIO      string `json:"net_io"`
+	PIDs       string `json:"pids"`
+}
+
+// GetContainerStats is a wrapper around 'podman stats --no-stream'.  If no
+// containers are given, then all running containers are included.
+func GetContainerStats(containers ...string) ([]ContainerStats, error) {
+	var stdout bytes.Buffer
+
+	logLevelString := LogLevel.String()
+	args := []string{"--log-level", logLevelString, "stats", "--format", "json", "--no-stream"}
+
+	args = append(args, containers...)
+
+	if err := shell.Run("podman", nil, &stdout, nil, args...); err != nil {
+		return nil, err
+	}
+
+	data := stdout.Bytes()
+	var stats []ContainerStats
+	if err := json.Unmarshal(data, &stats); err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
@@ -27,6 +27,10 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+var (
+	logLevel = logrus.ErrorLevel
+)
+
 func Run(name string, stdin io.Reader, stdout, stderr io.Writer, arg ...string) error {
 	ctx := context.Background()
 	err := RunContext(ctx, name, stdin, stdout, stderr, arg...)
@@ -50,7 +54,6 @@ func RunContextWithExitCode(ctx context.Context,
 	stdout, stderr io.Writer,
 	arg ...string) (int, error) {
 
-	logLevel := logrus.GetLevel()
 	if stderr == nil && logLevel >= logrus.DebugLevel {
 		stderr = os.Stderr
 	}
@@ -86,3 +89,7 @@ func RunWithExitCode(name string, stdin io.Reader, stdout, stderr io.Writer, arg
 	exitCode, err := RunContextWithExitCode(ctx, name, stdin, stdout, stderr, arg...)
 	return exitCode, err
 }
+
+func SetLogLevel(level logrus.Level) {
+	logLevel = level
+}
@@ -28,14 +28,29 @@ type Layer struct {
 	Size json.Number
 }
 type Image struct {
-	LayersData []Layer
+	Architecture string
+	LayersData   []Layer
 }
 
 func Inspect(ctx context.Context, target string) (*Image, error) {
+	image, err := inspect(ctx, target)
+	return image, err
+}
+
+// InspectForArchitecture is like Inspect, but picks the Linux image for the
+// given architecture out of a manifest list, instead of the one for the host.
+func InspectForArchitecture(ctx context.Context, target, architecture string) (*Image, error) {
+	image, err := inspect(ctx, target, "--override-os", "linux", "--override-arch", architecture)
+	return image, err
+}
+
+func inspect(ctx context.Context, target string, options ...string) (*Image, error) {
 	var stdout bytes.Buffer
 
 	targetWithTransport := "docker://" + target
-	args := []string{"inspect", "--format", "json", targetWithTransport}
+	args := []string{"inspect", "--format", "json"}
+	args = append(args, options...)
+	args = append(args, targetWithTransport)
 
 	if err := shell.RunContext(ctx, "skopeo", nil, &stdout, nil, args...); err != nil {
 		return nil, err
@@ -0,0 +1,212 @@
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package toolbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/containers/toolbox/pkg/shell"
+	"github.com/containers/toolbox/pkg/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// CreateOptions are the options of CreateContainer.  The zero value creates a
+// container from the default image for the host, like a bare 'toolbox
+// create'.
+type CreateOptions struct {
+	AuthFile string
+	Distro   string
+	Env      []string
+	Image    string
+	Release  string
+
+	// Stdout and Stderr get the progress and errors of 'toolbox create',
+	// if they are not nil.
+	Stderr io.Writer
+	Stdout io.Writer
+}
+
+// ExecOptions are the options of Exec.
+type ExecOptions struct {
+	Command []string
+	Env     []string
+
+	// If User is empty, then the command runs as the user who owns the
+	// container.
+	User string
+
+	// If WorkDir is empty, then the command runs in the current directory,
+	// or the home directory, if the current one isn't shared with the
+	// container.
+	WorkDir string
+
+	Stderr io.Writer
+	Stdin  io.Reader
+	Stdout io.Writer
+}
+
+var (
+	executable string
+)
+
+// CreateContainer creates a Toolbx container with 'toolbox create', which
+// never asks any questions when used through this API.
+func CreateContainer(ctx context.Context, container string, options CreateOptions) error {
+	toolboxExecutable, err := getExecutable()
+	if err != nil {
+		return err
+	}
+
+	args := getCreateArgs(container, options)
+	logrus.Debugf("Running %s %s", toolboxExecutable, strings.Join(utils.RedactEnvOptions(args), " "))
+
+	if err := shell.RunContext(ctx, toolboxExecutable, nil, options.Stdout, options.Stderr, args...); err != nil {
+		return fmt.Errorf("failed to create container %s: %w", container, err)
+	}
+
+	return nil
+}
+
+// SetExecutable makes the functions that go through the toolbox executable use
+// the one at path.  Toolbx itself sets it to its own executable, so that
+// 'toolbox service' and plugins never run a different one from PATH.
+func SetExecutable(path string) {
+	executable = path
+}
+
+// Enter starts an interactive shell in a Toolbx container with 'toolbox
+// enter', on the terminal of the calling process, and returns its exit code.
+func Enter(ctx context.Context, container string) (int, error) {
+	toolboxExecutable, err := getExecutable()
+	if err != nil {
+		return 1, err
+	}
+
+	args := []string{"enter", container}
+	logrus.Debugf("Running %s %s", toolboxExecutable, strings.Join(utils.RedactEnvOptions(args), " "))
+
+	exitCode, err := shell.RunContextWithExitCode(ctx,
+		toolboxExecutable,
+		os.Stdin,
+		os.Stdout,
+		os.Stderr,
+		args...)
+
+	return exitCode, err
+}
+
+// Exec runs a command in a Toolbx container with 'toolbox run', and returns
+// its exit code.  The container is started, if it isn't running.
+func Exec(ctx context.Context, container string, options ExecOptions) (int, error) {
+	if len(options.Command) == 0 {
+		return 1, errors.New("command not specified")
+	}
+
+	toolboxExecutable, err := getExecutable()
+	if err != nil {
+		return 1, err
+	}
+
+	args := getExecArgs(container, options)
+	logrus.Debugf("Running %s %s", toolboxExecutable, strings.Join(utils.RedactEnvOptions(args), " "))
+
+	exitCode, err := shell.RunContextWithExitCode(ctx,
+		toolboxExecutable,
+		options.Stdin,
+		options.Stdout,
+		options.Stderr,
+		args...)
+
+	return exitCode, err
+}
+
+func getCreateArgs(container string, options CreateOptions) []string {
+	args := []string{"--assumeyes", "create"}
+
+	if options.AuthFile != "" {
+		args = append(args, "--authfile", options.AuthFile)
+	}
+
+	if options.Distro != "" {
+		args = append(args, "--distro", options.Distro)
+	}
+
+	for _, env := range options.Env {
+		args = append(args, "--env", env)
+	}
+
+	if options.Image != "" {
+		args = append(args, "--image", options.Image)
+	}
+
+	if options.Release != "" {
+		args = append(args, "--release", options.Release)
+	}
+
+	if container != "" {
+		args = append(args, "--container", container)
+	}
+
+	return args
+}
+
+func getExecArgs(container string, options ExecOptions) []string {
+	args := []string{"run", "--container", container}
+
+	for _, env := range options.Env {
+		args = append(args, "--env", env)
+	}
+
+	if options.User != "" {
+		args = append(args, "--user", options.User)
+	}
+
+	if options.WorkDir != "" {
+		args = append(args, "--workdir", options.WorkDir)
+	}
+
+	args = append(args, "--")
+	args = append(args, options.Command...)
+	return args
+}
+
+// getExecutable prefers the toolbox executable set with SetExecutable, and
+// then the one that is running, when called from inside Toolbx itself, eg.,
+// by a plugin, over the one in PATH.
+func getExecutable() (string, error) {
+	if executable != "" {
+		return executable, nil
+	}
+
+	if toolboxPath := os.Getenv("TOOLBOX_PATH"); toolboxPath != "" {
+		return toolboxPath, nil
+	}
+
+	toolboxExecutable, err := exec.LookPath("toolbox")
+	if err != nil {
+		logrus.Debugf("Looking up toolbox failed: %s", err)
+		return "", errors.New("toolbox(1) not found")
+	}
+
+	return toolboxExecutable, nil
+}
@@ -0,0 +1,113 @@
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package toolbox
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetCreateArgs(t *testing.T) {
+	testCases := []struct {
+		name      string
+		container string
+		options   CreateOptions
+		output    []string
+	}{
+		{
+			name:   "Default container",
+			output: []string{"--assumeyes", "create"},
+		},
+		{
+			name:      "Named container from an image",
+			container: "work",
+			options: CreateOptions{
+				Env:   []string{"EDITOR=vim"},
+				Image: "registry.fedoraproject.org/fedora-toolbox:40",
+			},
+			output: []string{
+				"--assumeyes", "create",
+				"--env", "EDITOR=vim",
+				"--image", "registry.fedoraproject.org/fedora-toolbox:40",
+				"--container", "work",
+			},
+		},
+		{
+			name: "Distro and release",
+			options: CreateOptions{
+				Distro:  "ubuntu",
+				Release: "24.04",
+			},
+			output: []string{"--assumeyes", "create", "--distro", "ubuntu", "--release", "24.04"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			output := getCreateArgs(tc.container, tc.options)
+			assert.Equal(t, tc.output, output)
+		})
+	}
+}
+
+func TestGetExecArgs(t *testing.T) {
+	testCases := []struct {
+		name    string
+		options ExecOptions
+		output  []string
+	}{
+		{
+			name: "Command only",
+			options: ExecOptions{
+				Command: []string{"ls", "-l"},
+			},
+			output: []string{"run", "--container", "work", "--", "ls", "-l"},
+		},
+		{
+			name: "Command with options of its own",
+			options: ExecOptions{
+				Command: []string{"--help"},
+			},
+			output: []string{"run", "--container", "work", "--", "--help"},
+		},
+		{
+			name: "All options",
+			options: ExecOptions{
+				Command: []string{"make"},
+				Env:     []string{"CC=clang"},
+				User:    "root",
+				WorkDir: "/src",
+			},
+			output: []string{
+				"run", "--container", "work",
+				"--env", "CC=clang",
+				"--user", "root",
+				"--workdir", "/src",
+				"--",
+				"make",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			output := getExecArgs("work", tc.options)
+			assert.Equal(t, tc.output, output)
+		})
+	}
+}
@@ -0,0 +1,264 @@
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package toolbox manages Toolbx containers without the command line, so
+// that programs like the graphical front-ends and menu bar apps of macOS can
+// embed it.  Listing and removing containers is done directly through Podman,
+// while creating and using them goes through the toolbox executable, because
+// that's what sets them up and keeps them in sync with the host.
+package toolbox
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/containers/toolbox/pkg/podman"
+	"github.com/sirupsen/logrus"
+)
+
+// OwnerLabel names the user that a container was set up for on the shared
+// machine of system mode.
+const OwnerLabel = "com.github.containers.toolbox.owner"
+
+// RemoveOptions are the options of Remove.
+type RemoveOptions struct {
+	// Force removes running and paused containers too.
+	Force bool
+
+	// ForceProtected removes containers protected with 'toolbox protect'
+	// too.
+	ForceProtected bool
+
+	// Owner, if set, refuses containers that were set up for a different
+	// user in system mode.
+	Owner string
+}
+
+var (
+	ErrContainerNotOwned = errors.New("container belongs to another user")
+
+	ErrContainerProtected = errors.New("container is protected")
+
+	// labels mark containers and images as compatible with Toolbx.
+	labels = map[string]string{
+		"com.github.debarshiray.toolbox": "true",
+		"com.github.containers.toolbox":  "true",
+	}
+)
+
+// GetLastUsed returns when the container with the given ID was last entered
+// or run in, and false if it never was.
+func GetLastUsed(id string) (time.Time, bool) {
+	stamp, err := GetLastUsedStamp(id)
+	if err != nil {
+		logrus.Debugf("Getting the last use of container %s: %s", id, err)
+		return time.Time{}, false
+	}
+
+	fileInfo, err := os.Stat(stamp)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			logrus.Debugf("Getting the last use of container %s: %s", id, err)
+		}
+
+		return time.Time{}, false
+	}
+
+	return fileInfo.ModTime(), true
+}
+
+// GetLastUsedStamp returns the file whose modification time is when the
+// container with the given ID was last entered or run in.  IDs are used
+// instead of names, so that a new container with the name of a removed one
+// isn't mistaken for it.  Podman can't change the labels of an existing
+// container, so the time is kept outside of it.
+func GetLastUsedStamp(id string) (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		logrus.Debugf("Getting the home directory failed: %s", err)
+		return "", errors.New("failed to find the home directory")
+	}
+
+	stamp := filepath.Join(homeDir, ".local", "share", "toolbox", "last-used", id)
+	return stamp, nil
+}
+
+// GetProtectedStamp returns the file whose existence marks the container with
+// the given ID as protected by 'toolbox protect'.  Podman can't change the
+// labels of an existing container, so the mark is kept outside of it.
+func GetProtectedStamp(id string) (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		logrus.Debugf("Getting the home directory failed: %s", err)
+		return "", errors.New("failed to find the home directory")
+	}
+
+	stamp := filepath.Join(homeDir, ".local", "share", "toolbox", "protected", id)
+	return stamp, nil
+}
+
+// MarkUsed updates the last-used time of the container with the given ID to
+// now.
+func MarkUsed(id string) error {
+	stamp, err := GetLastUsedStamp(id)
+	if err != nil {
+		return err
+	}
+
+	stampDir := filepath.Dir(stamp)
+	if err := os.MkdirAll(stampDir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", stampDir, err)
+	}
+
+	stampFile, err := os.OpenFile(stamp, os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", stamp, err)
+	}
+
+	stampFile.Close()
+
+	now := time.Now()
+	if err := os.Chtimes(stamp, now, now); err != nil {
+		return fmt.Errorf("failed to update %s: %w", stamp, err)
+	}
+
+	return nil
+}
+
+// IsProtected checks if 'toolbox protect' was used on the container, so that
+// Remove refuses to remove it.
+func IsProtected(containerObj podman.Container) bool {
+	stamp, err := GetProtectedStamp(containerObj.ID())
+	if err != nil {
+		logrus.Debugf("Checking if container %s is protected: %s", containerObj.Name(), err)
+		return false
+	}
+
+	if _, err := os.Stat(stamp); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return false
+		}
+
+		logrus.Debugf("Checking if container %s is protected: %s", containerObj.Name(), err)
+	}
+
+	return true
+}
+
+// List returns all Toolbx containers, sorted by name.
+func List() ([]podman.Container, error) {
+	logrus.Debug("Fetching all containers")
+	args := []string{"--all", "--sort", "names"}
+	containers, err := podman.GetContainers(args...)
+	if err != nil {
+		logrus.Debugf("Fetching all containers failed: %s", err)
+		return nil, errors.New("failed to get containers")
+	}
+
+	var toolboxContainers []podman.Container
+
+	for containers.Next() {
+		if container := containers.Get(); container.IsToolbx() {
+			toolboxContainers = append(toolboxContainers, container)
+		}
+	}
+
+	return toolboxContainers, nil
+}
+
+// ListImages returns all Toolbx images, with one entry for each of their
+// names, sorted by name.  If fillNameWithID is true, then images without a
+// name get their ID instead.
+func ListImages(fillNameWithID bool) ([]podman.Image, error) {
+	logrus.Debug("Fetching all images")
+	var args []string
+	images, err := podman.GetImages(args...)
+	if err != nil {
+		logrus.Debugf("Fetching all images failed: %s", err)
+		return nil, errors.New("failed to get images")
+	}
+
+	processed := make(map[string]struct{})
+	var toolboxImages []podman.Image
+
+	for _, image := range images {
+		if _, ok := processed[image.ID]; ok {
+			continue
+		}
+
+		processed[image.ID] = struct{}{}
+		var isToolboxImage bool
+
+		for label := range labels {
+			if _, ok := image.Labels[label]; ok {
+				isToolboxImage = true
+				break
+			}
+		}
+
+		if isToolboxImage {
+			flattenedImages := image.FlattenNames(fillNameWithID)
+			toolboxImages = append(toolboxImages, flattenedImages...)
+		}
+
+	}
+
+	sort.Sort(podman.ImageSlice(toolboxImages))
+	return toolboxImages, nil
+}
+
+// Remove removes a Toolbx container, and forgets that it was protected and
+// when it was last used.  A
+// protected container is refused with an error that wraps
+// ErrContainerProtected, unless options.ForceProtected is set, and a
+// container of somebody other than options.Owner with one that wraps
+// ErrContainerNotOwned.
+func Remove(containerObj podman.Container, options RemoveOptions) error {
+	container := containerObj.Name()
+
+	if !containerObj.IsToolbx() {
+		return fmt.Errorf("%s is not a Toolbx container", container)
+	}
+
+	if options.Owner != "" && containerObj.Labels()[OwnerLabel] != options.Owner {
+		return fmt.Errorf("%w: %s", ErrContainerNotOwned, container)
+	}
+
+	if !options.ForceProtected && IsProtected(containerObj) {
+		return fmt.Errorf("%w: %s", ErrContainerProtected, container)
+	}
+
+	if err := podman.RemoveContainer(containerObj.ID(), options.Force); err != nil {
+		return err
+	}
+
+	for _, getStamp := range []func(string) (string, error){GetLastUsedStamp, GetProtectedStamp} {
+		stamp, err := getStamp(containerObj.ID())
+		if err != nil {
+			continue
+		}
+
+		if err := os.Remove(stamp); err != nil && !errors.Is(err, os.ErrNotExist) {
+			logrus.Debugf("Removing %s failed: %s", stamp, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,89 @@
+//go:build darwin
+
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+func getDefaultReleaseAlpine() (string, error) {
+	release, err := getHostVersionID()
+	if err != nil {
+		return "", err
+	}
+
+	// VERSION_ID has the patch level, eg., 3.22.1, but the images are
+	// only tagged with the major and minor versions.
+	releaseParts := strings.SplitN(release, ".", 3)
+	if len(releaseParts) == 3 {
+		release = releaseParts[0] + "." + releaseParts[1]
+	}
+
+	return release, nil
+}
+
+func getFullyQualifiedImageAlpine(image, release string) string {
+	imageFull := "quay.io/toolbx-images/" + image
+	return imageFull
+}
+
+func getP11KitClientPathsAlpine() []string {
+	paths := []string{"/usr/lib/pkcs11/p11-kit-client.so"}
+	return paths
+}
+
+func parseReleaseAlpine(release string) (string, error) {
+	if release == "edge" {
+		return release, nil
+	}
+
+	releaseParts := strings.Split(release, ".")
+	if len(releaseParts) != 2 {
+		return "", &ParseReleaseError{"The release must be in the 'MAJOR.MINOR' format, or 'edge'."}
+	}
+
+	releaseMajor, err := strconv.Atoi(releaseParts[0])
+	if err != nil {
+		logrus.Debugf("Parsing release major %s as an integer failed: %s", releaseParts[0], err)
+		return "", &ParseReleaseError{"The release must be in the 'MAJOR.MINOR' format, or 'edge'."}
+	}
+
+	if releaseMajor < 3 {
+		return "", &ParseReleaseError{"The release major must be 3 or more."}
+	}
+
+	releaseMinor, err := strconv.Atoi(releaseParts[1])
+	if err != nil {
+		logrus.Debugf("Parsing release minor %s as an integer failed: %s", releaseParts[1], err)
+		return "", &ParseReleaseError{"The release must be in the 'MAJOR.MINOR' format, or 'edge'."}
+	}
+
+	if releaseMinor < 0 {
+		return "", &ParseReleaseError{"The release minor must be 0 or more."}
+	}
+
+	if releaseParts[0] != strconv.Itoa(releaseMajor) || releaseParts[1] != strconv.Itoa(releaseMinor) {
+		return "", &ParseReleaseError{"The release cannot have leading zeros."}
+	}
+
+	return release, nil
+}
@@ -0,0 +1,257 @@
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+var (
+	// deniedEnvironmentVariables are never forwarded from the host unless
+	// they are allowed by name in the environment section of the
+	// configuration.
+	// They describe the macOS login session or point at paths and sockets
+	// that don't exist inside the Podman machine.
+	deniedEnvironmentVariables = []string{
+		"Apple_*",
+		"COMMAND_MODE",
+		"LaunchInstanceID",
+		"SECURITYSESSIONID",
+		"SSH_AUTH_SOCK",
+		"TMPDIR",
+		"XPC_*",
+		"__CF*",
+	}
+)
+
+// GetEnvironmentFromOptions returns the environment variables given with
+// '--env-file' and '--env', in that order, so that '--env' overrides the
+// files.  Like with Podman, a variable without a value takes the value it has
+// on the host, and is left out if it's not set there.  Values are passed on
+// as they are, without any quotes being removed, because they never go
+// through a shell.
+func GetEnvironmentFromOptions(envs, envFiles []string) ([]string, error) {
+	var entries []string
+
+	for _, envFile := range envFiles {
+		data, err := os.ReadFile(envFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read environment file %s: %w", envFile, err)
+		}
+
+		fileEntries, err := parseEnvFile(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid environment file %s: %w", envFile, err)
+		}
+
+		entries = append(entries, fileEntries...)
+	}
+
+	entries = append(entries, envs...)
+
+	var environ []string
+
+	for _, entry := range entries {
+		env, ok, err := resolveEnv(entry)
+		if err != nil {
+			return nil, err
+		}
+
+		if !ok {
+			continue
+		}
+
+		environ = append(environ, env)
+	}
+
+	return environ, nil
+}
+
+// GetPreservedEnvironment returns the host's environment variables that are
+// forwarded into 'toolbox enter' and 'toolbox run' sessions.  These are the
+// ones that Toolbx always forwards, and the ones matching the patterns in
+// 'allow' in the environment section of the configuration, minus the ones
+// matching the patterns in 'deny' there.  Variables that only make sense on
+// macOS are left out, unless they are allowed by name instead of a pattern.
+func GetPreservedEnvironment() []string {
+	allow := viper.GetStringSlice("environment.allow")
+	deny := viper.GetStringSlice("environment.deny")
+	environ := getPreservedEnvironment(os.Environ(), allow, deny)
+	return environ
+}
+
+func getPreservedEnvironment(hostEnviron, allow, deny []string) []string {
+	values := make(map[string]string)
+	var names []string
+
+	for _, env := range hostEnviron {
+		name, value, _ := strings.Cut(env, "=")
+		if _, ok := values[name]; !ok {
+			names = append(names, name)
+		}
+
+		values[name] = value
+	}
+
+	sort.Strings(names)
+
+	var environ []string
+	preserved := make(map[string]struct{})
+
+	addIfPreserved := func(name string) {
+		if _, ok := preserved[name]; ok {
+			return
+		}
+
+		value, ok := values[name]
+		if !ok {
+			logrus.Debugf("%s is unset", name)
+			return
+		}
+
+		if matchEnvPatterns(deny, name) {
+			logrus.Debugf("%s is denied by the configuration", name)
+			return
+		}
+
+		allowedByName := slices.Contains(allow, name)
+		if !allowedByName && matchEnvPatterns(deniedEnvironmentVariables, name) {
+			logrus.Debugf("%s is not forwarded on macOS", name)
+			return
+		}
+
+		preserved[name] = struct{}{}
+		environ = append(environ, name+"="+value)
+	}
+
+	for _, name := range preservedEnvironmentVariables {
+		addIfPreserved(name)
+	}
+
+	for _, name := range names {
+		if matchEnvPatterns(allow, name) {
+			addIfPreserved(name)
+		}
+	}
+
+	return environ
+}
+
+func matchEnvPatterns(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		matched, err := path.Match(pattern, name)
+		if err != nil {
+			logrus.Debugf("Matching environment variable %s against %s failed: %s", name, pattern, err)
+			continue
+		}
+
+		if matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// RedactEnvOptions leaves out the values of the '--env' options in the
+// arguments of 'podman create', 'podman exec' or 'toolbox run', so that they
+// can be logged without leaking tokens and passwords.
+func RedactEnvOptions(args []string) []string {
+	redactedArgs := make([]string, 0, len(args))
+	afterEnv := false
+
+	for _, arg := range args {
+		redactedArg := arg
+
+		if afterEnv {
+			redactedArg = redactEnv(arg)
+		} else if env, found := strings.CutPrefix(arg, "--env="); found {
+			redactedArg = "--env=" + redactEnv(env)
+		}
+
+		redactedArgs = append(redactedArgs, redactedArg)
+		afterEnv = arg == "--env"
+	}
+
+	return redactedArgs
+}
+
+func redactEnv(env string) string {
+	name, _, found := strings.Cut(env, "=")
+	if !found {
+		return env
+	}
+
+	return name + "=<redacted>"
+}
+
+// parseEnvFile parses a file with a KEY=VALUE or KEY entry on each line, like
+// the ones understood by 'podman run --env-file'.  Empty lines and lines
+// starting with # are skipped.
+func parseEnvFile(data []byte) ([]string, error) {
+	var entries []string
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimLeft(scanner.Text(), " \t")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, _, _ := strings.Cut(line, "=")
+		if key == "" || strings.ContainsAny(key, " \t") {
+			return nil, fmt.Errorf("invalid variable on line %d", lineNumber)
+		}
+
+		entries = append(entries, line)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func resolveEnv(entry string) (string, bool, error) {
+	key, _, found := strings.Cut(entry, "=")
+	if key == "" || strings.ContainsAny(key, " \t") {
+		return "", false, fmt.Errorf("invalid environment variable %s", entry)
+	}
+
+	if found {
+		return entry, true, nil
+	}
+
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return "", false, nil
+	}
+
+	env := key + "=" + value
+	return env, true, nil
+}
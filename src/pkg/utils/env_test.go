@@ -0,0 +1,189 @@
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseEnvFile(t *testing.T) {
+	testCases := []struct {
+		name     string
+		data     string
+		expected []string
+		err      bool
+	}{
+		{
+			name:     "Empty",
+			data:     "",
+			expected: nil,
+		},
+		{
+			name:     "Comments and empty lines",
+			data:     "# Database\n\nDB_HOST=localhost\n  DB_PORT=5432\n",
+			expected: []string{"DB_HOST=localhost", "DB_PORT=5432"},
+		},
+		{
+			name:     "Values are kept as they are",
+			data:     "GREETING=\"hello, world\"\nEMPTY=\nPATH_LIKE=a=b\n",
+			expected: []string{"GREETING=\"hello, world\"", "EMPTY=", "PATH_LIKE=a=b"},
+		},
+		{
+			name:     "Variables without values",
+			data:     "HOME\nEDITOR\n",
+			expected: []string{"HOME", "EDITOR"},
+		},
+		{
+			name: "Missing key",
+			data: "=value\n",
+			err:  true,
+		},
+		{
+			name: "Whitespace in key",
+			data: "MY VAR=value\n",
+			err:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			entries, err := parseEnvFile([]byte(tc.data))
+			if tc.err {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, entries)
+		})
+	}
+}
+
+func TestGetEnvironmentFromOptions(t *testing.T) {
+	t.Setenv("TOOLBOX_TEST_SET", "from the host")
+	t.Setenv("TOOLBOX_TEST_OVERRIDDEN", "from the host")
+
+	envFile := t.TempDir() + "/env"
+	err := os.WriteFile(envFile, []byte("TOOLBOX_TEST_FILE=1\nTOOLBOX_TEST_OVERRIDDEN=file\n"), 0644)
+	assert.NoError(t, err)
+
+	envs := []string{
+		"TOOLBOX_TEST_SET",
+		"TOOLBOX_TEST_UNSET",
+		"TOOLBOX_TEST_OVERRIDDEN=flag",
+		"TOOLBOX_TEST_SPACES=a b 'c'",
+	}
+
+	environ, err := GetEnvironmentFromOptions(envs, []string{envFile})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"TOOLBOX_TEST_FILE=1",
+		"TOOLBOX_TEST_OVERRIDDEN=file",
+		"TOOLBOX_TEST_SET=from the host",
+		"TOOLBOX_TEST_OVERRIDDEN=flag",
+		"TOOLBOX_TEST_SPACES=a b 'c'",
+	}, environ)
+
+	_, err = GetEnvironmentFromOptions(nil, []string{envFile + ".missing"})
+	assert.Error(t, err)
+}
+
+func TestGetPreservedEnvironment(t *testing.T) {
+	hostEnviron := []string{
+		"HOME=/Users/jdoe",
+		"SHELL=/bin/zsh",
+		"SSH_AUTH_SOCK=/private/tmp/com.apple.launchd.abc/Listeners",
+		"TMPDIR=/var/folders/xy/T/",
+		"XPC_FLAGS=0x0",
+		"__CF_USER_TEXT_ENCODING=0x1F5:0x0:0x0",
+		"AWS_PROFILE=dev",
+		"AWS_REGION=eu-west-1",
+		"GOPATH=/Users/jdoe/go",
+	}
+
+	testCases := []struct {
+		name     string
+		allow    []string
+		deny     []string
+		expected []string
+	}{
+		{
+			name:     "Defaults",
+			expected: []string{"HOME=/Users/jdoe", "SHELL=/bin/zsh"},
+		},
+		{
+			name:     "Allowed by pattern",
+			allow:    []string{"AWS_*"},
+			expected: []string{"HOME=/Users/jdoe", "SHELL=/bin/zsh", "AWS_PROFILE=dev", "AWS_REGION=eu-west-1"},
+		},
+		{
+			name:     "Denied variables win",
+			allow:    []string{"AWS_*"},
+			deny:     []string{"AWS_PROFILE", "HOME"},
+			expected: []string{"SHELL=/bin/zsh", "AWS_REGION=eu-west-1"},
+		},
+		{
+			name:  "Everything",
+			allow: []string{"*"},
+			expected: []string{
+				"HOME=/Users/jdoe",
+				"SHELL=/bin/zsh",
+				"AWS_PROFILE=dev",
+				"AWS_REGION=eu-west-1",
+				"GOPATH=/Users/jdoe/go",
+			},
+		},
+		{
+			name:     "Variables of macOS allowed by name",
+			allow:    []string{"SSH_AUTH_SOCK"},
+			expected: []string{"HOME=/Users/jdoe", "SHELL=/bin/zsh", "SSH_AUTH_SOCK=/private/tmp/com.apple.launchd.abc/Listeners"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			environ := getPreservedEnvironment(hostEnviron, tc.allow, tc.deny)
+			assert.Equal(t, tc.expected, environ)
+		})
+	}
+}
+
+func TestRedactEnvOptions(t *testing.T) {
+	args := []string{
+		"create",
+		"--env", "NPM_TOKEN=secret",
+		"--env=GITHUB_TOKEN=secret",
+		"--env", "TERM",
+		"--name", "fedora-toolbox-42",
+		"fedora-toolbox:42",
+	}
+
+	expected := []string{
+		"create",
+		"--env", "NPM_TOKEN=<redacted>",
+		"--env=GITHUB_TOKEN=<redacted>",
+		"--env", "TERM",
+		"--name", "fedora-toolbox-42",
+		"fedora-toolbox:42",
+	}
+
+	assert.Equal(t, expected, RedactEnvOptions(args))
+	assert.Equal(t, "NPM_TOKEN=secret", args[2])
+}
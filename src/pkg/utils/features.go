@@ -0,0 +1,194 @@
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+// Feature is an experimental capability that is off unless the user opts
+// into it with the [experimental] section of toolbox.conf(5) or the
+// TOOLBOX_EXPERIMENTAL environment variable.
+type Feature struct {
+	Name        string
+	Description string
+}
+
+var (
+	features = []Feature{
+		{"gpu", "Allow 'toolbox create --gpu' to give containers the GPU of the Podman machine"},
+	}
+)
+
+var (
+	ErrFeatureUnknown = errors.New("unknown experimental feature")
+)
+
+func GetFeatures() []Feature {
+	return features
+}
+
+// IsFeatureEnabled checks TOOLBOX_EXPERIMENTAL, which is a comma separated
+// list of feature names, or 'all', before the configuration files.
+func IsFeatureEnabled(name string) bool {
+	if !IsFeatureKnown(name) {
+		panic("unknown experimental feature " + name)
+	}
+
+	if value, found := os.LookupEnv("TOOLBOX_EXPERIMENTAL"); found {
+		for _, feature := range strings.Split(value, ",") {
+			feature = strings.TrimSpace(feature)
+			if feature == name || feature == "all" {
+				logrus.Debugf("Experimental feature %s is enabled by TOOLBOX_EXPERIMENTAL", name)
+				return true
+			}
+		}
+	}
+
+	key := "experimental." + name
+	enabled := viper.GetBool(key)
+	return enabled
+}
+
+func IsFeatureKnown(name string) bool {
+	for _, feature := range features {
+		if feature.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SetFeatureEnabled updates the [experimental] section of the user's
+// toolbox.conf(5), and leaves the rest of the file as it is.
+func SetFeatureEnabled(name string, enabled bool) error {
+	if !IsFeatureKnown(name) {
+		return fmt.Errorf("%w %s", ErrFeatureUnknown, name)
+	}
+
+	value := fmt.Sprintf("%t", enabled)
+	if err := SetUserConfigValue("experimental", name, value); err != nil {
+		return err
+	}
+
+	key := "experimental." + name
+	viper.Set(key, enabled)
+	return nil
+}
+
+// SetUserConfigValue sets key in section of the user's toolbox.conf(5) to
+// value, which is written as it is, so strings need to be quoted.  The rest
+// of the file is left as it is.
+func SetUserConfigValue(section, key, value string) error {
+	userConfigPath, err := GetUserConfigPath()
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(userConfigPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to read file %s", userConfigPath)
+	}
+
+	data = setConfigValue(data, section, key, value)
+
+	userConfigDir := filepath.Dir(userConfigPath)
+	if err := os.MkdirAll(userConfigDir, 0700); err != nil {
+		return fmt.Errorf("failed to create directory %s", userConfigDir)
+	}
+
+	if err := os.WriteFile(userConfigPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write file %s", userConfigPath)
+	}
+
+	return nil
+}
+
+// setConfigValue sets key to value in section of a TOML document.  Only the
+// line with the key is touched, so that comments and formatting survive.
+func setConfigValue(data []byte, section, key, value string) []byte {
+	var builder strings.Builder
+	var blankLines int
+	var inSection, sectionFound, keyWritten bool
+
+	header := "[" + section + "]"
+	line := key + " = " + value + "\n"
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		text := scanner.Text()
+		trimmed := strings.TrimSpace(text)
+
+		// Blank lines at the end of the section are held back, so that a
+		// new key goes right after the last one.
+		if trimmed == "" && inSection && !keyWritten {
+			blankLines++
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "[") {
+			if inSection && !keyWritten {
+				builder.WriteString(line)
+				keyWritten = true
+			}
+
+			inSection = trimmed == header
+			if inSection {
+				sectionFound = true
+			}
+		} else if inSection && !keyWritten {
+			if name, _, found := strings.Cut(trimmed, "="); found && strings.TrimSpace(name) == key {
+				text = strings.TrimSuffix(line, "\n")
+				keyWritten = true
+			}
+		}
+
+		builder.WriteString(strings.Repeat("\n", blankLines))
+		blankLines = 0
+
+		builder.WriteString(text)
+		builder.WriteString("\n")
+	}
+
+	if !keyWritten {
+		if !sectionFound {
+			if builder.Len() != 0 {
+				builder.WriteString("\n")
+			}
+
+			builder.WriteString(header)
+			builder.WriteString("\n")
+		}
+
+		builder.WriteString(line)
+	}
+
+	builder.WriteString(strings.Repeat("\n", blankLines))
+
+	config := builder.String()
+	return []byte(config)
+}
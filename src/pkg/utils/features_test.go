@@ -0,0 +1,64 @@
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetConfigValue(t *testing.T) {
+	testCases := []struct {
+		name     string
+		data     string
+		expected string
+	}{
+		{
+			name:     "Empty file",
+			data:     "",
+			expected: "[experimental]\ngpu = true\n",
+		},
+		{
+			name:     "Other section",
+			data:     "[general]\ndistro = \"fedora\"\n",
+			expected: "[general]\ndistro = \"fedora\"\n\n[experimental]\ngpu = true\n",
+		},
+		{
+			name:     "Existing section",
+			data:     "[experimental]\nhost-exec = true\n\n[general]\ndistro = \"fedora\"\n",
+			expected: "[experimental]\nhost-exec = true\ngpu = true\n\n[general]\ndistro = \"fedora\"\n",
+		},
+		{
+			name:     "Existing key",
+			data:     "# Comment\n[experimental]\ngpu = false # old\nhost-exec = true\n",
+			expected: "# Comment\n[experimental]\ngpu = true\nhost-exec = true\n",
+		},
+		{
+			name:     "Key in other section",
+			data:     "[general]\ngpu = false\n",
+			expected: "[general]\ngpu = false\n\n[experimental]\ngpu = true\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			data := setConfigValue([]byte(tc.data), "experimental", "gpu", "true")
+			assert.Equal(t, tc.expected, string(data))
+		})
+	}
+}
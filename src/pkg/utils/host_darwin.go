@@ -0,0 +1,635 @@
+//go:build darwin
+
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/containers/toolbox/pkg/shell"
+	"github.com/sirupsen/logrus"
+)
+
+// GetHostResolvConf returns the contents of the host's resolv.conf(5), which
+// configd(8) keeps up to date with the primary resolver of macOS, including
+// the ones pushed by VPNs.  Name servers on the loopback interface are
+// dropped, because they can't be reached from inside the Podman machine.
+//
+// A nil slice is returned if no usable name server is left.
+func GetHostResolvConf() ([]byte, error) {
+	data, err := os.ReadFile("/etc/resolv.conf")
+	if err != nil {
+		return nil, err
+	}
+
+	resolvConf := filterResolvConf(data)
+	return resolvConf, nil
+}
+
+// HostGroup is a group of macOS that a user is a member of.
+type HostGroup struct {
+	GID  int
+	Name string
+}
+
+// GetHostGroups returns the groups of macOS that userName is a member of,
+// including the primary one.  They are asked for with id(1), because os/user
+// only reads /etc/group, while the memberships live in Open Directory.
+func GetHostGroups(userName string) ([]HostGroup, error) {
+	var gids bytes.Buffer
+	if err := shell.Run("id", nil, &gids, nil, "-G", userName); err != nil {
+		return nil, err
+	}
+
+	var names bytes.Buffer
+	if err := shell.Run("id", nil, &names, nil, "-Gn", userName); err != nil {
+		return nil, err
+	}
+
+	groups, err := parseHostGroups(gids.String(), names.String())
+	if err != nil {
+		return nil, err
+	}
+
+	return groups, nil
+}
+
+// GetHostUserShell returns the login shell of userName, which, like the
+// groups, lives in Open Directory, and is asked for with dscl(1).
+func GetHostUserShell(userName string) (string, error) {
+	var stdout bytes.Buffer
+	if err := shell.Run("dscl", nil, &stdout, nil, ".", "-read", "/Users/"+userName, "UserShell"); err != nil {
+		return "", err
+	}
+
+	output := stdout.String()
+	userShell, err := parseDsclUserShell(output)
+	if err != nil {
+		return "", err
+	}
+
+	return userShell, nil
+}
+
+// GetHostHosts returns the entries that were added to the host's /etc/hosts
+// by the user or by development tools, one per line.  The ones that macOS
+// ships with, for localhost and broadcasthost, and comments are dropped.
+func GetHostHosts() ([]byte, error) {
+	data, err := os.ReadFile("/etc/hosts")
+	if err != nil {
+		return nil, err
+	}
+
+	hosts := filterHosts(data)
+	return hosts, nil
+}
+
+// GetHostKeyboardEnvironment returns the host's current keyboard layout and
+// input method as environment variables.  TOOLBOX_KEYBOARD_LAYOUT and
+// TOOLBOX_INPUT_METHOD carry the input source IDs of macOS, while
+// XKB_DEFAULT_LAYOUT and XKB_DEFAULT_VARIANT carry the closest XKB layout, so
+// that programs which map keys by themselves pick the right one.
+func GetHostKeyboardEnvironment() []string {
+	var environ []string
+
+	var stdout bytes.Buffer
+	if err := shell.Run("defaults",
+		nil,
+		&stdout,
+		nil,
+		"read",
+		"com.apple.HIToolbox",
+		"AppleCurrentKeyboardLayoutInputSourceID"); err != nil {
+		logrus.Debugf("Reading AppleCurrentKeyboardLayoutInputSourceID failed: %s", err)
+	} else if inputSourceID := strings.TrimSpace(stdout.String()); inputSourceID != "" {
+		environ = append(environ, "TOOLBOX_KEYBOARD_LAYOUT="+inputSourceID)
+
+		if layout, variant := parseKeyboardLayout(inputSourceID); layout != "" {
+			environ = append(environ, "XKB_DEFAULT_LAYOUT="+layout)
+			if variant != "" {
+				environ = append(environ, "XKB_DEFAULT_VARIANT="+variant)
+			}
+		}
+	}
+
+	stdout.Reset()
+	if err := shell.Run("defaults",
+		nil,
+		&stdout,
+		nil,
+		"read",
+		"com.apple.HIToolbox",
+		"AppleSelectedInputSources"); err != nil {
+		logrus.Debugf("Reading AppleSelectedInputSources failed: %s", err)
+	} else if inputMethod := parseInputMethod(stdout.String()); inputMethod != "" {
+		environ = append(environ, "TOOLBOX_INPUT_METHOD="+inputMethod)
+	}
+
+	return environ
+}
+
+// GetHostLanguages returns the languages that messages should be shown in,
+// in order of preference.  LC_ALL, LC_MESSAGES and LANG take precedence, as
+// everywhere else, and otherwise the AppleLanguages preference is used,
+// because LANG is unset for programs not started from a terminal.
+func GetHostLanguages() []string {
+	for _, key := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if value := os.Getenv(key); value != "" {
+			return []string{value}
+		}
+	}
+
+	var stdout bytes.Buffer
+	if err := shell.Run("defaults", nil, &stdout, nil, "read", "-g", "AppleLanguages"); err != nil {
+		logrus.Debugf("Reading AppleLanguages failed: %s", err)
+		return nil
+	}
+
+	languages := parseAppleLanguages(stdout.String())
+	return languages
+}
+
+// GetHostLocaleEnvironment returns the host's LANG and LC_* variables in a
+// form understood by glibc.  If LANG is unset, as it is for programs not
+// started from a terminal, it is derived from the AppleLocale preference.
+func GetHostLocaleEnvironment() []string {
+	var environ []string
+	var langFound bool
+
+	for _, env := range os.Environ() {
+		key, value, _ := strings.Cut(env, "=")
+		if key != "LANG" && !strings.HasPrefix(key, "LC_") {
+			continue
+		}
+
+		if value == "" {
+			continue
+		}
+
+		if key == "LANG" {
+			langFound = true
+		}
+
+		value = normalizeLocale(value)
+		environ = append(environ, key+"="+value)
+	}
+
+	if !langFound {
+		var stdout bytes.Buffer
+		if err := shell.Run("defaults", nil, &stdout, nil, "read", "-g", "AppleLocale"); err != nil {
+			logrus.Debugf("Reading AppleLocale failed: %s", err)
+		} else if appleLocale := strings.TrimSpace(stdout.String()); appleLocale != "" {
+			lang := normalizeLocale(appleLocale)
+			environ = append(environ, "LANG="+lang)
+		}
+	}
+
+	return environ
+}
+
+// GetHostProxyEnvironment returns the system-wide HTTP and HTTPS proxies of
+// macOS as environment variables understood by most Linux programs.
+func GetHostProxyEnvironment() ([]string, error) {
+	var stdout bytes.Buffer
+	if err := shell.Run("scutil", nil, &stdout, nil, "--proxy"); err != nil {
+		return nil, err
+	}
+
+	output := stdout.String()
+	environ := parseScutilProxy(output)
+	return environ, nil
+}
+
+// GetHostTimeZone returns the name of the host's time zone from the IANA time
+// zone database, eg., Europe/Prague.  It falls back to systemsetup(8) if
+// /etc/localtime can't be resolved.
+func GetHostTimeZone() (string, error) {
+	var timeZone string
+
+	target, err := os.Readlink("/etc/localtime")
+	if err == nil {
+		timeZone, err = parseTimeZoneFromLocaltime(target)
+	}
+
+	if err != nil {
+		logrus.Debugf("Reading the time zone from /etc/localtime failed: %s", err)
+
+		var stdout bytes.Buffer
+		if err := shell.Run("systemsetup", nil, &stdout, nil, "-gettimezone"); err != nil {
+			return "", err
+		}
+
+		output := stdout.String()
+		timeZone, err = parseSystemsetupTimeZone(output)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	logrus.Debugf("Host time zone is %s", timeZone)
+	return timeZone, nil
+}
+
+func filterHosts(data []byte) []byte {
+	var hosts strings.Builder
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.IndexByte(line, '#'); i != -1 {
+			line = line[:i]
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+
+		if net.ParseIP(fields[0]) == nil {
+			continue
+		}
+
+		var names []string
+		for _, name := range fields[1:] {
+			if name == "localhost" || name == "broadcasthost" {
+				continue
+			}
+
+			names = append(names, name)
+		}
+
+		if len(names) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(&hosts, "%s %s\n", fields[0], strings.Join(names, " "))
+	}
+
+	return []byte(hosts.String())
+}
+
+func filterResolvConf(data []byte) []byte {
+	var builder strings.Builder
+	var nameServers int
+
+	reader := bytes.NewReader(data)
+	scanner := bufio.NewScanner(reader)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		fields := strings.Fields(line)
+
+		if len(fields) == 2 && fields[0] == "nameserver" {
+			if ip := net.ParseIP(fields[1]); ip != nil && ip.IsLoopback() {
+				logrus.Debugf("Skipping name server %s on the loopback interface", fields[1])
+				continue
+			}
+
+			nameServers++
+		}
+
+		builder.WriteString(line)
+		builder.WriteString("\n")
+	}
+
+	if nameServers == 0 {
+		return nil
+	}
+
+	resolvConf := builder.String()
+	return []byte(resolvConf)
+}
+
+func parseHostGroups(gids, names string) ([]HostGroup, error) {
+	gidFields := strings.Fields(gids)
+	nameFields := strings.Fields(names)
+	if len(gidFields) != len(nameFields) {
+		return nil, fmt.Errorf("%d GIDs for %d group names", len(gidFields), len(nameFields))
+	}
+
+	groups := make([]HostGroup, 0, len(gidFields))
+
+	for i, gidField := range gidFields {
+		gid, err := strconv.Atoi(gidField)
+		if err != nil {
+			return nil, fmt.Errorf("invalid GID %s", gidField)
+		}
+
+		groups = append(groups, HostGroup{GID: gid, Name: nameFields[i]})
+	}
+
+	return groups, nil
+}
+
+// parseAppleLanguages parses the output of 'defaults read -g AppleLanguages',
+// which looks like:
+//
+//	(
+//	    "en-GB",
+//	    "de-DE",
+//	    fr
+//	)
+func parseAppleLanguages(output string) []string {
+	var languages []string
+
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		line = strings.TrimSuffix(line, ",")
+		line = strings.Trim(line, `"`)
+		if line == "" || line == "(" || line == ")" {
+			continue
+		}
+
+		languages = append(languages, line)
+	}
+
+	return languages
+}
+
+// parseInputMethod returns the bundle ID of the first input method, like
+// com.apple.inputmethod.Kotoeri.RomajiTyping, in the output of 'defaults read
+// com.apple.HIToolbox AppleSelectedInputSources', which looks like:
+//
+//	(
+//	        {
+//	        InputSourceKind = "Keyboard Layout";
+//	        "KeyboardLayout ID" = 252;
+//	        "KeyboardLayout Name" = ABC;
+//	    },
+//	        {
+//	        "Bundle ID" = "com.apple.inputmethod.Kotoeri.RomajiTyping";
+//	        InputSourceKind = "Input Mode";
+//	    }
+//	)
+//
+// An empty string is returned if only keyboard layouts are selected.
+func parseInputMethod(output string) string {
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		key, value, found := strings.Cut(line, "=")
+		if !found || strings.Trim(strings.TrimSpace(key), `"`) != "Bundle ID" {
+			continue
+		}
+
+		value = strings.TrimSuffix(strings.TrimSpace(value), ";")
+		value = strings.Trim(value, `"`)
+		if strings.HasPrefix(value, "com.apple.inputmethod.") || !strings.HasPrefix(value, "com.apple.") {
+			return value
+		}
+	}
+
+	return ""
+}
+
+// parseKeyboardLayout maps an input source ID of macOS, like
+// com.apple.keylayout.German, to the closest XKB layout and variant.  Empty
+// strings are returned for layouts that aren't known.
+func parseKeyboardLayout(inputSourceID string) (string, string) {
+	name, found := strings.CutPrefix(inputSourceID, "com.apple.keylayout.")
+	if !found {
+		return "", ""
+	}
+
+	switch name {
+	case "ABC", "US", "USExtended":
+		return "us", ""
+	case "USInternational-PC":
+		return "us", "intl"
+	case "Dvorak":
+		return "us", "dvorak"
+	case "Colemak":
+		return "us", "colemak"
+	case "British", "British-PC":
+		return "gb", ""
+	case "Irish":
+		return "ie", ""
+	case "Canadian-CSA":
+		return "ca", "multix"
+	case "German":
+		return "de", "mac"
+	case "Austrian":
+		return "at", "mac"
+	case "SwissGerman":
+		return "ch", "de_mac"
+	case "SwissFrench":
+		return "ch", "fr_mac"
+	case "French":
+		return "fr", "mac"
+	case "French-PC":
+		return "fr", ""
+	case "Belgian":
+		return "be", ""
+	case "Spanish", "Spanish-ISO":
+		return "es", "mac"
+	case "Italian", "Italian-Pro":
+		return "it", "mac"
+	case "Portuguese":
+		return "pt", "mac"
+	case "Brazilian", "Brazilian-Pro":
+		return "br", ""
+	case "Dutch":
+		return "nl", "mac"
+	case "Danish":
+		return "dk", "mac"
+	case "Finnish":
+		return "fi", "mac"
+	case "Norwegian":
+		return "no", "mac"
+	case "Swedish", "Swedish-Pro":
+		return "se", "mac"
+	case "Icelandic":
+		return "is", "mac"
+	case "Polish", "PolishPro":
+		return "pl", ""
+	case "Czech", "Czech-QWERTY":
+		return "cz", ""
+	case "Hungarian":
+		return "hu", ""
+	case "Turkish", "Turkish-QWERTY", "Turkish-QWERTY-PC":
+		return "tr", ""
+	case "Greek":
+		return "gr", ""
+	case "Russian", "RussianWin", "Russian-Phonetic":
+		return "ru", ""
+	case "Ukrainian", "Ukrainian-PC":
+		return "ua", ""
+	case "Hebrew":
+		return "il", ""
+	case "Arabic":
+		return "ara", "mac"
+	}
+
+	return "", ""
+}
+
+// normalizeLocale converts a locale name from macOS to one that glibc
+// understands.  macOS accepts a bare codeset, like LC_CTYPE=UTF-8 as set by
+// Terminal, locale names without a codeset, and AppleLocale values with
+// modifiers, like en_GB@currency=EUR, none of which are valid on Linux.
+func normalizeLocale(locale string) string {
+	if locale == "C" || locale == "POSIX" {
+		return locale
+	}
+
+	if strings.EqualFold(locale, "UTF-8") {
+		return "C.UTF-8"
+	}
+
+	if i := strings.IndexAny(locale, "@"); i != -1 {
+		locale = locale[:i]
+	}
+
+	if !strings.Contains(locale, ".") {
+		locale = locale + ".UTF-8"
+	}
+
+	return locale
+}
+
+// parseScutilProxy parses the output of 'scutil --proxy', which looks like:
+//
+//	<dictionary> {
+//	  ExceptionsList : <array> {
+//	    0 : *.local
+//	  }
+//	  HTTPEnable : 1
+//	  HTTPPort : 3128
+//	  HTTPProxy : proxy.example.com
+//	}
+func parseScutilProxy(output string) []string {
+	values := make(map[string]string)
+	var exceptions []string
+	var inExceptions bool
+
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if inExceptions {
+			if line == "}" {
+				inExceptions = false
+				continue
+			}
+
+			if _, exception, found := strings.Cut(line, " : "); found {
+				exceptions = append(exceptions, exception)
+			}
+
+			continue
+		}
+
+		key, value, found := strings.Cut(line, " : ")
+		if !found {
+			continue
+		}
+
+		if key == "ExceptionsList" {
+			inExceptions = true
+			continue
+		}
+
+		values[key] = value
+	}
+
+	var environ []string
+
+	for _, proxy := range []struct {
+		prefix    string
+		variables []string
+	}{
+		{"HTTP", []string{"http_proxy", "HTTP_PROXY"}},
+		{"HTTPS", []string{"https_proxy", "HTTPS_PROXY"}},
+	} {
+		if values[proxy.prefix+"Enable"] != "1" {
+			continue
+		}
+
+		host := values[proxy.prefix+"Proxy"]
+		if host == "" {
+			continue
+		}
+
+		url := "http://" + host
+		if port := values[proxy.prefix+"Port"]; port != "" {
+			url = url + ":" + port
+		}
+
+		for _, variable := range proxy.variables {
+			environ = append(environ, variable+"="+url)
+		}
+	}
+
+	if len(environ) != 0 && len(exceptions) != 0 {
+		noProxy := strings.Join(exceptions, ",")
+		environ = append(environ, "no_proxy="+noProxy, "NO_PROXY="+noProxy)
+	}
+
+	return environ
+}
+
+// parseDsclUserShell parses the output of 'dscl . -read /Users/NAME
+// UserShell', which looks like 'UserShell: /bin/zsh'.
+func parseDsclUserShell(output string) (string, error) {
+	output = strings.TrimSpace(output)
+
+	_, userShell, found := strings.Cut(output, "UserShell: ")
+	if !found || !strings.HasPrefix(userShell, "/") {
+		return "", fmt.Errorf("failed to parse login shell from %q", output)
+	}
+
+	return userShell, nil
+}
+
+// parseSystemsetupTimeZone parses the output of 'systemsetup -gettimezone',
+// which looks like 'Time Zone: Europe/Prague'.
+func parseSystemsetupTimeZone(output string) (string, error) {
+	output = strings.TrimSpace(output)
+
+	_, timeZone, found := strings.Cut(output, "Time Zone: ")
+	if !found || timeZone == "" {
+		return "", fmt.Errorf("failed to parse time zone from %q", output)
+	}
+
+	return timeZone, nil
+}
+
+func parseTimeZoneFromLocaltime(target string) (string, error) {
+	const zoneInfo = "zoneinfo/"
+
+	i := strings.LastIndex(target, zoneInfo)
+	if i == -1 {
+		return "", fmt.Errorf("%s is not in the time zone database", target)
+	}
+
+	timeZone := target[i+len(zoneInfo):]
+	timeZone = filepath.Clean(timeZone)
+	if timeZone == "." || strings.HasPrefix(timeZone, "..") {
+		return "", fmt.Errorf("%s is not in the time zone database", target)
+	}
+
+	return timeZone, nil
+}
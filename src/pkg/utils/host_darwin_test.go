@@ -0,0 +1,454 @@
+//go:build darwin
+
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterHosts(t *testing.T) {
+	testCases := []struct {
+		name   string
+		input  string
+		output string
+	}{
+		{
+			name: "Entries shipped with macOS are dropped",
+			input: "##\n# Host Database\n##\n127.0.0.1\tlocalhost\n" +
+				"255.255.255.255\tbroadcasthost\n::1             localhost\n",
+			output: "",
+		},
+		{
+			name:   "Entries added by the user are kept",
+			input:  "127.0.0.1\tlocalhost\n127.0.0.1 app.test api.app.test # dev\n10.1.2.3\tgit.corp\n",
+			output: "127.0.0.1 app.test api.app.test\n10.1.2.3 git.corp\n",
+		},
+		{
+			name:   "localhost is dropped from an entry with other names",
+			input:  "127.0.0.1 localhost app.test\n",
+			output: "127.0.0.1 app.test\n",
+		},
+		{
+			name:   "Invalid addresses are dropped",
+			input:  "fe80::1%lo0 localhost\nnot-an-address foo\n",
+			output: "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			output := filterHosts([]byte(tc.input))
+			assert.Equal(t, tc.output, string(output))
+		})
+	}
+}
+
+func TestFilterResolvConf(t *testing.T) {
+	testCases := []struct {
+		name   string
+		input  string
+		output string
+	}{
+		{
+			name:   "Name servers are kept",
+			input:  "search example.com\nnameserver 192.168.1.1\n",
+			output: "search example.com\nnameserver 192.168.1.1\n",
+		},
+		{
+			name:   "Name servers on the loopback interface are dropped",
+			input:  "nameserver 127.0.0.1\nnameserver 10.0.0.53\n",
+			output: "nameserver 10.0.0.53\n",
+		},
+		{
+			name:   "Only name servers on the loopback interface",
+			input:  "nameserver 127.0.0.1\nnameserver ::1\n",
+			output: "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			output := filterResolvConf([]byte(tc.input))
+			assert.Equal(t, tc.output, string(output))
+		})
+	}
+}
+
+func TestParseHostGroups(t *testing.T) {
+	testCases := []struct {
+		name   string
+		gids   string
+		names  string
+		groups []HostGroup
+		errMsg string
+	}{
+		{
+			name:  "Administrator",
+			gids:  "20 12 61 79 80 81 98 701 33 100 204 250 395 398 399 400\n",
+			names: "staff everyone localaccounts _appserverusr admin _appserveradm _lpadmin com.apple.sharepoint.group.1 _appstore _lpoperator _developer _analyticsusers com.apple.access_ftp com.apple.access_screensharing com.apple.access_ssh com.apple.access_remote_ae\n",
+			groups: []HostGroup{
+				{20, "staff"},
+				{12, "everyone"},
+				{61, "localaccounts"},
+				{79, "_appserverusr"},
+				{80, "admin"},
+				{81, "_appserveradm"},
+				{98, "_lpadmin"},
+				{701, "com.apple.sharepoint.group.1"},
+				{33, "_appstore"},
+				{100, "_lpoperator"},
+				{204, "_developer"},
+				{250, "_analyticsusers"},
+				{395, "com.apple.access_ftp"},
+				{398, "com.apple.access_screensharing"},
+				{399, "com.apple.access_ssh"},
+				{400, "com.apple.access_remote_ae"},
+			},
+		},
+		{
+			name:   "Standard user",
+			gids:   "20 12 61\n",
+			names:  "staff everyone localaccounts\n",
+			groups: []HostGroup{{20, "staff"}, {12, "everyone"}, {61, "localaccounts"}},
+		},
+		{
+			name:   "Mismatched output",
+			gids:   "20 12\n",
+			names:  "staff\n",
+			errMsg: "2 GIDs for 1 group names",
+		},
+		{
+			name:   "Invalid GID",
+			gids:   "staff\n",
+			names:  "staff\n",
+			errMsg: "invalid GID staff",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			groups, err := parseHostGroups(tc.gids, tc.names)
+
+			if tc.errMsg == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.errMsg)
+			}
+
+			assert.Equal(t, tc.groups, groups)
+		})
+	}
+}
+
+func TestParseAppleLanguages(t *testing.T) {
+	testCases := []struct {
+		name     string
+		output   string
+		expected []string
+	}{
+		{
+			name:     "Empty",
+			output:   "(\n)\n",
+			expected: nil,
+		},
+		{
+			name: "Several",
+			output: `(
+    "en-GB",
+    "de-DE",
+    fr
+)
+`,
+			expected: []string{"en-GB", "de-DE", "fr"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			languages := parseAppleLanguages(tc.output)
+			assert.Equal(t, tc.expected, languages)
+		})
+	}
+}
+
+func TestParseInputMethod(t *testing.T) {
+	testCases := []struct {
+		name     string
+		output   string
+		expected string
+	}{
+		{
+			name: "Keyboard layouts only",
+			output: `(
+        {
+        InputSourceKind = "Keyboard Layout";
+        "KeyboardLayout ID" = 252;
+        "KeyboardLayout Name" = ABC;
+    },
+        {
+        "Bundle ID" = "com.apple.CharacterPaletteIM";
+        InputSourceKind = "Non Keyboard Input Method";
+    }
+)
+`,
+			expected: "",
+		},
+		{
+			name: "Japanese",
+			output: `(
+        {
+        InputSourceKind = "Keyboard Layout";
+        "KeyboardLayout ID" = 252;
+        "KeyboardLayout Name" = ABC;
+    },
+        {
+        "Bundle ID" = "com.apple.inputmethod.Kotoeri.RomajiTyping";
+        "Input Mode" = "com.apple.inputmethod.Japanese";
+        InputSourceKind = "Input Mode";
+    }
+)
+`,
+			expected: "com.apple.inputmethod.Kotoeri.RomajiTyping",
+		},
+		{
+			name: "Third party",
+			output: `(
+        {
+        "Bundle ID" = "com.google.inputmethod.Japanese";
+        InputSourceKind = "Keyboard Input Method";
+    }
+)
+`,
+			expected: "com.google.inputmethod.Japanese",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			inputMethod := parseInputMethod(tc.output)
+			assert.Equal(t, tc.expected, inputMethod)
+		})
+	}
+}
+
+func TestParseKeyboardLayout(t *testing.T) {
+	testCases := []struct {
+		inputSourceID string
+		layout        string
+		variant       string
+	}{
+		{"com.apple.keylayout.ABC", "us", ""},
+		{"com.apple.keylayout.US", "us", ""},
+		{"com.apple.keylayout.Dvorak", "us", "dvorak"},
+		{"com.apple.keylayout.British", "gb", ""},
+		{"com.apple.keylayout.German", "de", "mac"},
+		{"com.apple.keylayout.SwissGerman", "ch", "de_mac"},
+		{"com.apple.keylayout.Czech-QWERTY", "cz", ""},
+		{"com.apple.keylayout.Tibetan-Wylie", "", ""},
+		{"com.apple.inputmethod.Kotoeri.RomajiTyping", "", ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.inputSourceID, func(t *testing.T) {
+			layout, variant := parseKeyboardLayout(tc.inputSourceID)
+			assert.Equal(t, tc.layout, layout)
+			assert.Equal(t, tc.variant, variant)
+		})
+	}
+}
+
+func TestNormalizeLocale(t *testing.T) {
+	testCases := []struct {
+		locale   string
+		expected string
+	}{
+		{"C", "C"},
+		{"POSIX", "POSIX"},
+		{"UTF-8", "C.UTF-8"},
+		{"en_US.UTF-8", "en_US.UTF-8"},
+		{"cs_CZ", "cs_CZ.UTF-8"},
+		{"en_GB@currency=EUR", "en_GB.UTF-8"},
+		{"de_DE.ISO8859-1", "de_DE.ISO8859-1"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.locale, func(t *testing.T) {
+			locale := normalizeLocale(tc.locale)
+			assert.Equal(t, tc.expected, locale)
+		})
+	}
+}
+
+func TestParseScutilProxy(t *testing.T) {
+	testCases := []struct {
+		name    string
+		output  string
+		environ []string
+	}{
+		{
+			name:    "No proxies",
+			output:  "<dictionary> {\n  HTTPEnable : 0\n  HTTPSEnable : 0\n}\n",
+			environ: nil,
+		},
+		{
+			name: "HTTP proxy with exceptions",
+			output: "<dictionary> {\n" +
+				"  ExceptionsList : <array> {\n" +
+				"    0 : *.local\n" +
+				"    1 : 169.254/16\n" +
+				"  }\n" +
+				"  HTTPEnable : 1\n" +
+				"  HTTPPort : 3128\n" +
+				"  HTTPProxy : proxy.example.com\n" +
+				"  HTTPSEnable : 0\n" +
+				"}\n",
+			environ: []string{
+				"http_proxy=http://proxy.example.com:3128",
+				"HTTP_PROXY=http://proxy.example.com:3128",
+				"no_proxy=*.local,169.254/16",
+				"NO_PROXY=*.local,169.254/16",
+			},
+		},
+		{
+			name: "HTTPS proxy without port",
+			output: "<dictionary> {\n" +
+				"  HTTPSEnable : 1\n" +
+				"  HTTPSProxy : proxy.example.com\n" +
+				"}\n",
+			environ: []string{
+				"https_proxy=http://proxy.example.com",
+				"HTTPS_PROXY=http://proxy.example.com",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			environ := parseScutilProxy(tc.output)
+			assert.Equal(t, tc.environ, environ)
+		})
+	}
+}
+
+func TestParseDsclUserShell(t *testing.T) {
+	testCases := []struct {
+		output    string
+		userShell string
+		errMsg    string
+	}{
+		{
+			output:    "UserShell: /bin/zsh\n",
+			userShell: "/bin/zsh",
+		},
+		{
+			output:    "UserShell: /opt/homebrew/bin/fish\n",
+			userShell: "/opt/homebrew/bin/fish",
+		},
+		{
+			output: "No such key: UserShell\n",
+			errMsg: "failed to parse login shell from \"No such key: UserShell\"",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.output, func(t *testing.T) {
+			userShell, err := parseDsclUserShell(tc.output)
+
+			if tc.errMsg == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.errMsg)
+			}
+
+			assert.Equal(t, tc.userShell, userShell)
+		})
+	}
+}
+
+func TestParseSystemsetupTimeZone(t *testing.T) {
+	testCases := []struct {
+		output   string
+		timeZone string
+		errMsg   string
+	}{
+		{
+			output:   "Time Zone: Europe/Prague\n",
+			timeZone: "Europe/Prague",
+		},
+		{
+			output: "You need administrator access to run this tool... exiting!\n",
+			errMsg: "failed to parse time zone from \"You need administrator access to run this tool... exiting!\"",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.output, func(t *testing.T) {
+			timeZone, err := parseSystemsetupTimeZone(tc.output)
+
+			if tc.errMsg == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.errMsg)
+			}
+
+			assert.Equal(t, tc.timeZone, timeZone)
+		})
+	}
+}
+
+func TestParseTimeZoneFromLocaltime(t *testing.T) {
+	testCases := []struct {
+		target   string
+		timeZone string
+		errMsg   string
+	}{
+		{
+			target:   "/var/db/timezone/zoneinfo/Europe/Prague",
+			timeZone: "Europe/Prague",
+		},
+		{
+			target:   "/usr/share/zoneinfo/UTC",
+			timeZone: "UTC",
+		},
+		{
+			target: "/etc/foo",
+			errMsg: "/etc/foo is not in the time zone database",
+		},
+		{
+			target: "/usr/share/zoneinfo/",
+			errMsg: "/usr/share/zoneinfo/ is not in the time zone database",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.target, func(t *testing.T) {
+			timeZone, err := parseTimeZoneFromLocaltime(tc.target)
+
+			if tc.errMsg == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.errMsg)
+			}
+
+			assert.Equal(t, tc.timeZone, timeZone)
+		})
+	}
+}
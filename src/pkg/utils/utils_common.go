@@ -46,6 +46,11 @@ type Distro struct {
 	GetFullyQualifiedImage GetFullyQualifiedImageFunc
 	GetP11KitClientPaths   GetP11KitClientPathsFunc
 	ParseRelease           ParseReleaseFunc
+
+	// ReleaseFallback is the release used when the host can't tell, like
+	// macOS, which has no os-release file.  If it's empty, then a release
+	// is required.
+	ReleaseFallback string
 }
 
 type OptionValueSource int
@@ -61,6 +66,8 @@ const (
 	distroFallback              = "fedora"
 	idTruncLength               = 12
 	releaseFallback             = "42"
+	releaseFallbackAlpine       = "3.22"
+	releaseFallbackUbuntu       = "24.04"
 )
 
 const (
@@ -125,6 +132,16 @@ var (
 	runtimeDirectories map[string]string
 
 	supportedDistros = map[string]Distro{
+		"alpine": {
+			"alpine-toolbox",
+			"alpine-toolbox",
+			true,
+			getDefaultReleaseAlpine,
+			getFullyQualifiedImageAlpine,
+			getP11KitClientPathsAlpine,
+			parseReleaseAlpine,
+			releaseFallbackAlpine,
+		},
 		"arch": {
 			"arch-toolbox",
 			"arch-toolbox",
@@ -133,6 +150,7 @@ var (
 			getFullyQualifiedImageArch,
 			getP11KitClientPathsArch,
 			parseReleaseArch,
+			"",
 		},
 		"fedora": {
 			"fedora-toolbox",
@@ -142,6 +160,7 @@ var (
 			getFullyQualifiedImageFedora,
 			getP11KitClientPathsFedora,
 			parseReleaseFedora,
+			releaseFallback,
 		},
 		"rhel": {
 			"rhel-toolbox",
@@ -151,6 +170,7 @@ var (
 			getFullyQualifiedImageRHEL,
 			getP11KitClientPathsRHEL,
 			parseReleaseRHEL,
+			"",
 		},
 		"ubuntu": {
 			"ubuntu-toolbox",
@@ -160,6 +180,7 @@ var (
 			getFullyQualifiedImageUbuntu,
 			getP11KitClientPathsUbuntu,
 			parseReleaseUbuntu,
+			releaseFallbackUbuntu,
 		},
 	}
 )
@@ -279,26 +300,48 @@ func getDefaultReleaseForDistro(distro string) (string, error) {
 
 	release, err := distroObj.GetDefaultRelease()
 	if err != nil {
-		return "", err
+		if distroObj.ReleaseFallback == "" {
+			return "", err
+		}
+
+		logrus.Debugf("Getting the default release of %s failed: %s", distro, err)
+		logrus.Debugf("Using release %s of %s", distroObj.ReleaseFallback, distro)
+		release = distroObj.ReleaseFallback
 	}
 
 	return release, nil
 }
 
+// GetDistroAndReleaseForImage guesses the distro and release of a Toolbx
+// container from the name of its image.  Both are empty if the image isn't
+// one of the supported distros.
+func GetDistroAndReleaseForImage(image string) (string, string) {
+	basename := ImageReferenceGetBasename(image)
+	if basename == "" {
+		return "", ""
+	}
+
+	for distro, distroObj := range supportedDistros {
+		if distroObj.ImageBasename != basename {
+			continue
+		}
+
+		release := ImageReferenceGetTag(image)
+		return distro, release
+	}
+
+	return "", ""
+}
+
 func GetEnvOptionsForPreservedVariables() []string {
 	logrus.Debug("Creating list of environment variables to forward")
 
 	var envOptions []string
 
-	for _, variable := range preservedEnvironmentVariables {
-		value, found := os.LookupEnv(variable)
-		if !found {
-			logrus.Debugf("%s is unset", variable)
-			continue
-		}
-
-		logrus.Debugf("%s=%s", variable, value)
-		envOptions = append(envOptions, fmt.Sprintf("--env=%s=%s", variable, value))
+	for _, env := range GetPreservedEnvironment() {
+		name, _, _ := strings.Cut(env, "=")
+		logrus.Debugf("%s", name)
+		envOptions = append(envOptions, "--env="+env)
 	}
 
 	return envOptions
@@ -420,6 +463,81 @@ func GetP11KitServerSocketLock(targetUser *user.User) (string, error) {
 	return p11KitServerSocketLock, nil
 }
 
+// GetConfigurationFiles returns the configuration files in the order in which
+// they are read.  Later files override earlier ones.  They don't need to
+// exist.
+func GetConfigurationFiles() ([]string, error) {
+	configFiles := []string{
+		"/etc/containers/toolbox.conf",
+	}
+
+	userConfigPath, err := GetUserConfigPath()
+	if err != nil {
+		return nil, err
+	}
+
+	configFiles = append(configFiles, userConfigPath)
+	return configFiles, nil
+}
+
+// GetReleasesForDistro returns the releases of a distribution that are worth
+// offering for shell completion: the default release, if there is one, and
+// the releases of the distribution's images found among images. If distroCLI
+// is empty, then the distribution is taken from the configuration file or the
+// host.
+func GetReleasesForDistro(distroCLI string, images []string) ([]string, error) {
+	distro := distroCLI
+	if distro == "" {
+		distro = distroDefault
+		if viper.IsSet("general.distro") {
+			distro = viper.GetString("general.distro")
+		}
+	}
+
+	distroObj, supportedDistro := supportedDistros[distro]
+	if !supportedDistro {
+		return nil, &DistroError{distro, ErrDistroUnsupported}
+	}
+
+	var releases []string
+
+	if distro == distroDefault {
+		releases = append(releases, releaseDefault)
+	} else if release, err := getDefaultReleaseForDistro(distro); err == nil {
+		releases = append(releases, release)
+	}
+
+	for _, image := range images {
+		if ImageReferenceGetBasename(image) != distroObj.ImageBasename {
+			continue
+		}
+
+		tag := ImageReferenceGetTag(image)
+		if tag == "" || tag == "latest" {
+			continue
+		}
+
+		tagRelease, err := distroObj.ParseRelease(tag)
+		if err != nil {
+			continue
+		}
+
+		known := false
+		for _, release := range releases {
+			if release == tagRelease {
+				known = true
+				break
+			}
+		}
+
+		if !known {
+			releases = append(releases, tagRelease)
+		}
+	}
+
+	return releases, nil
+}
+
 // GetSupportedDistros returns a list of supported distributions
 func GetSupportedDistros() []string {
 	var distros []string
@@ -438,6 +556,19 @@ func HumanDuration(duration int64) string {
 	return units.HumanDuration(time.Since(time.Unix(duration, 0))) + " ago"
 }
 
+// GetUserConfigPath returns the path to the user's toolbox.conf(5), which
+// might not exist.
+func GetUserConfigPath() (string, error) {
+	userConfigDir, err := os.UserConfigDir()
+	if err != nil {
+		logrus.Debugf("Setting up configuration: failed to get the user config directory: %s", err)
+		return "", errors.New("failed to get the user config directory")
+	}
+
+	userConfigPath := userConfigDir + "/containers/toolbox.conf"
+	return userConfigPath, nil
+}
+
 // ImageReferenceCanBeID checks if 'image' might be the ID of an image
 func ImageReferenceCanBeID(image string) bool {
 	matched, err := regexp.MatchString("^[a-f0-9]{6,64}$", image)
@@ -541,24 +672,37 @@ func IsP11KitClientPresent() (bool, error) {
 	return false, err
 }
 
-func SetUpConfiguration() error {
-	logrus.Debug("Setting up configuration")
+// ReloadConfiguration reads the configuration files again, for long-running
+// processes that need to pick up changes.  If they can't be read, then the
+// previous configuration is kept.
+func ReloadConfiguration() error {
+	logrus.Debug("Reloading configuration")
 
-	configFiles := []string{
-		"/etc/containers/toolbox.conf",
+	settings := viper.AllSettings()
+	viper.Reset()
+
+	if err := SetUpConfiguration(); err != nil {
+		viper.Reset()
+		viper.SetConfigType("toml")
+
+		if err := viper.MergeConfigMap(settings); err != nil {
+			logrus.Debugf("Reloading configuration: failed to restore the previous one: %s", err)
+		}
+
+		return err
 	}
 
-	userConfigDir, err := os.UserConfigDir()
+	return nil
+}
+
+func SetUpConfiguration() error {
+	logrus.Debug("Setting up configuration")
+
+	configFiles, err := GetConfigurationFiles()
 	if err != nil {
-		logrus.Debugf("Setting up configuration: failed to get the user config directory: %s", err)
-		return errors.New("failed to get the user config directory")
+		return err
 	}
 
-	userConfigPath := userConfigDir + "/containers/toolbox.conf"
-	configFiles = append(configFiles, []string{
-		userConfigPath,
-	}...)
-
 	viper.SetConfigType("toml")
 
 	for _, configFile := range configFiles {
@@ -675,10 +819,12 @@ func ResolveContainerAndImageNames(container, distroCLI, imageCLI, releaseCLI st
 	} else {
 		if distroObj.ReleaseRequired {
 			if releaseCLI == "" && !viper.IsSet("general.release") {
-				return "", "", "", &DistroError{distro, ErrDistroWithoutRelease}
-			}
+				if distroObj.ReleaseFallback == "" {
+					return "", "", "", &DistroError{distro, ErrDistroWithoutRelease}
+				}
 
-			if releaseCLI == "" {
+				release = distroObj.ReleaseFallback
+			} else if releaseCLI == "" {
 				release = viper.GetString("general.release")
 			}
 
@@ -25,6 +25,7 @@ import (
 	"os/user"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"syscall"
 
 	"github.com/sirupsen/logrus"
@@ -215,4 +216,31 @@ func Flock(path string, how int) (*os.File, error) {
 // CallFlatpakSessionHelper returns an error on macOS since Flatpak is not available
 func CallFlatpakSessionHelper() (string, error) {
 	return "", errors.New("Flatpak is not available on macOS")
-}
\ No newline at end of file
+}
+
+// IsCaseSensitive checks if the file system holding the directory at path
+// tells apart file names that only differ in case.  APFS volumes on macOS are
+// case-insensitive by default.
+func IsCaseSensitive(path string) (bool, error) {
+	file, err := os.CreateTemp(path, ".toolbox-case-check-")
+	if err != nil {
+		return false, err
+	}
+
+	name := file.Name()
+	file.Close()
+	defer os.Remove(name)
+
+	base := filepath.Base(name)
+	nameUpper := filepath.Join(filepath.Dir(name), strings.ToUpper(base))
+
+	if _, err := os.Stat(nameUpper); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return true, nil
+		}
+
+		return false, err
+	}
+
+	return false, nil
+}
@@ -0,0 +1,176 @@
+//go:build darwin
+
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveContainerAndImageNamesUbuntu(t *testing.T) {
+	testCases := []struct {
+		name      string
+		release   string
+		container string
+		image     string
+		expected  string
+	}{
+		{
+			name:      "default release",
+			container: "ubuntu-toolbox-24.04",
+			image:     "ubuntu-toolbox:24.04",
+			expected:  "24.04",
+		},
+		{
+			name:      "release",
+			release:   "22.04",
+			container: "ubuntu-toolbox-22.04",
+			image:     "ubuntu-toolbox:22.04",
+			expected:  "22.04",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			container, image, release, err := ResolveContainerAndImageNames("", "ubuntu", "", tc.release)
+			require.NoError(t, err)
+			assert.Equal(t, tc.container, container)
+			assert.Equal(t, tc.image, image)
+			assert.Equal(t, tc.expected, release)
+
+			imageFull, err := GetFullyQualifiedImageFromDistros(image, release)
+			require.NoError(t, err)
+			assert.Equal(t, "quay.io/toolbx/"+tc.image, imageFull)
+		})
+	}
+
+	_, _, _, err := ResolveContainerAndImageNames("", "ubuntu", "", "24.4")
+	var errParseRelease *ParseReleaseError
+	require.True(t, errors.As(err, &errParseRelease))
+	assert.Equal(t, "The release month must have two digits.", errParseRelease.Hint)
+
+	_, _, _, err = ResolveContainerAndImageNames("", "rhel", "", "")
+	assert.ErrorIs(t, err, ErrDistroWithoutRelease)
+}
+
+func TestGetReleasesForDistroUbuntu(t *testing.T) {
+	images := []string{
+		"quay.io/toolbx/ubuntu-toolbox:22.04",
+		"quay.io/toolbx/ubuntu-toolbox:24.04",
+		"quay.io/toolbx/ubuntu-toolbox:latest",
+		"registry.fedoraproject.org/fedora-toolbox:42",
+	}
+
+	releases, err := GetReleasesForDistro("ubuntu", images)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"24.04", "22.04"}, releases)
+}
+
+func TestResolveContainerAndImageNamesAlpine(t *testing.T) {
+	testCases := []struct {
+		name      string
+		release   string
+		container string
+		image     string
+		expected  string
+	}{
+		{
+			name:      "default release",
+			container: "alpine-toolbox-3.22",
+			image:     "alpine-toolbox:3.22",
+			expected:  "3.22",
+		},
+		{
+			name:      "release",
+			release:   "3.20",
+			container: "alpine-toolbox-3.20",
+			image:     "alpine-toolbox:3.20",
+			expected:  "3.20",
+		},
+		{
+			name:      "edge",
+			release:   "edge",
+			container: "alpine-toolbox-edge",
+			image:     "alpine-toolbox:edge",
+			expected:  "edge",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			container, image, release, err := ResolveContainerAndImageNames("", "alpine", "", tc.release)
+			require.NoError(t, err)
+			assert.Equal(t, tc.container, container)
+			assert.Equal(t, tc.image, image)
+			assert.Equal(t, tc.expected, release)
+
+			imageFull, err := GetFullyQualifiedImageFromDistros(image, release)
+			require.NoError(t, err)
+			assert.Equal(t, "quay.io/toolbx-images/"+tc.image, imageFull)
+		})
+	}
+}
+
+func TestParseReleaseAlpine(t *testing.T) {
+	testCases := []struct {
+		release string
+		hint    string
+	}{
+		{
+			release: "3",
+			hint:    "The release must be in the 'MAJOR.MINOR' format, or 'edge'.",
+		},
+		{
+			release: "3.22.1",
+			hint:    "The release must be in the 'MAJOR.MINOR' format, or 'edge'.",
+		},
+		{
+			release: "three.22",
+			hint:    "The release must be in the 'MAJOR.MINOR' format, or 'edge'.",
+		},
+		{
+			release: "2.7",
+			hint:    "The release major must be 3 or more.",
+		},
+		{
+			release: "3.-1",
+			hint:    "The release minor must be 0 or more.",
+		},
+		{
+			release: "3.09",
+			hint:    "The release cannot have leading zeros.",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.release, func(t *testing.T) {
+			_, err := parseReleaseAlpine(tc.release)
+			var errParseRelease *ParseReleaseError
+			require.True(t, errors.As(err, &errParseRelease))
+			assert.Equal(t, tc.hint, errParseRelease.Hint)
+		})
+	}
+
+	release, err := parseReleaseAlpine("3.9")
+	require.NoError(t, err)
+	assert.Equal(t, "3.9", release)
+}